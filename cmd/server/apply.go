@@ -0,0 +1,60 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/root-ali/iris/internal/bootstrap"
+	"github.com/root-ali/iris/internal/config"
+	"github.com/root-ali/iris/pkg/declarative"
+)
+
+// paths collects the repeated -f flags.
+type paths []string
+
+func (p *paths) String() string { return strings.Join(*p, ",") }
+
+func (p *paths) Set(v string) error {
+	*p = append(*p, v)
+	return nil
+}
+
+// runApply is `iris apply`, it reconciles the database with declarative
+// YAML files and prints the plan. It returns the exit code.
+func runApply(args []string) int {
+	fs := flag.NewFlagSet("apply", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: iris apply -f <file or directory> [-f ...] [--plan] [--diff] [--prune]")
+		fmt.Fprintln(fs.Output(), "Running servers drop their cached providers when provider settings are applied.")
+		fs.PrintDefaults()
+	}
+	var files paths
+	fs.Var(&files, "f", "YAML file or directory of files to apply, may be repeated")
+	configPath := fs.String("config", "config.yml", "Iris config with the database settings")
+	planOnly := fs.Bool("plan", false, "print the plan without applying it")
+	diff := fs.Bool("diff", false, "print the changed fields of every object")
+	prune := fs.Bool("prune", false, "delete the objects of the declared kinds that are not in the files")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if len(files) == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	cfg := config.New(*configPath)
+	plan, err := bootstrap.Apply(cfg, files, declarative.Options{Prune: *prune, DryRun: *planOnly})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "apply: %v\n", err)
+		return 1
+	}
+	if err := plan.Write(os.Stdout, *diff); err != nil {
+		return 1
+	}
+	if *planOnly && !plan.Empty() {
+		fmt.Println("Nothing was applied, run without --plan to apply.")
+	}
+	return 0
+}
@@ -9,6 +9,10 @@ APIs:
 ** GET  /v0/alerts/latestResolved
 ** GET  /v0/alerts/?pages&pagination&status
 
+Commands:
+** iris apply -f config/ [--plan] [--diff] [--prune]   reconcile declarative YAML,
+   provider changes reach running servers through Postgres LISTEN/NOTIFY
+
 WebPages:
 ** GET  / (web pages return alerts and 5 latest resolved issues)
 
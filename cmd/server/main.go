@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
 
 	"github.com/root-ali/iris/internal/bootstrap"
 	"github.com/root-ali/iris/internal/config"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "apply" {
+		os.Exit(runApply(os.Args[2:]))
+	}
+
 	cfg := config.New("config.yml")
 	app, err := bootstrap.Init(cfg)
 	if err != nil {
@@ -16,6 +22,7 @@ func main() {
 
 	// Run HTTP
 	if err := app.Router.Run(":" + cfg.HTTP.Port); err != nil {
+		_ = app.Shutdown(context.Background())
 		app.Logger.Fatalw("http server failed", "error", err)
 	}
 }
@@ -0,0 +1,47 @@
+package bootstrap
+
+import (
+	"fmt"
+
+	"github.com/root-ali/iris/internal/config"
+	"github.com/root-ali/iris/internal/logging"
+	"github.com/root-ali/iris/internal/storage"
+	"github.com/root-ali/iris/pkg/declarative"
+	"go.uber.org/zap"
+)
+
+// Apply reconciles the database with the declarative YAML files at paths,
+// it is what `iris apply` runs. The default roles and the providers are
+// created by the server, so it has to have started once on the database.
+func Apply(cfg *config.Config, paths []string, opts declarative.Options) (*declarative.Plan, error) {
+	zl, err := logging.NewCLI()
+	if err != nil {
+		return nil, fmt.Errorf("logger init: %w", err)
+	}
+	logger := zl.Sugar()
+	declared, err := declarative.Load(paths...)
+	if err != nil {
+		return nil, err
+	}
+	repos, err := storage.Init(logger, postgresConfig(cfg))
+	if err != nil {
+		return nil, err
+	}
+	return declarative.NewService(repos.Postgres, logger).Apply(declared, opts)
+}
+
+// applyDeclarative applies the declarative config at startup.
+func applyDeclarative(cfg *config.Config, repo declarative.Repository, logger *zap.SugaredLogger) error {
+	declared, err := declarative.Load(cfg.Declarative.Path)
+	if err != nil {
+		return err
+	}
+	plan, err := declarative.NewService(repo, logger).Apply(declared, declarative.Options{Prune: cfg.Declarative.Prune})
+	if err != nil {
+		return err
+	}
+	for _, c := range plan.Changes {
+		logger.Infow("Declarative change applied", "action", c.Action, "kind", c.Kind, "name", c.Name)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package bootstrap
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -10,7 +11,16 @@ import (
 	"github.com/root-ali/iris/internal/schedulers"
 	"github.com/root-ali/iris/internal/server"
 	"github.com/root-ali/iris/internal/storage"
+	"github.com/root-ali/iris/pkg/alerts"
+	"github.com/root-ali/iris/pkg/alertstream"
 	"github.com/root-ali/iris/pkg/cache"
+	"github.com/root-ali/iris/pkg/chatops"
+	"github.com/root-ali/iris/pkg/cluster"
+	"github.com/root-ali/iris/pkg/contactmethods"
+	"github.com/root-ali/iris/pkg/contactrules"
+	"github.com/root-ali/iris/pkg/health_check"
+	"github.com/root-ali/iris/pkg/incidents"
+	"github.com/root-ali/iris/pkg/maintenance"
 	"github.com/root-ali/iris/pkg/message"
 	"github.com/root-ali/iris/pkg/notifications"
 	"github.com/root-ali/iris/pkg/notifications/asiatech"
@@ -19,8 +29,14 @@ import (
 	"github.com/root-ali/iris/pkg/notifications/mattermost"
 	"github.com/root-ali/iris/pkg/notifications/smsir"
 	"github.com/root-ali/iris/pkg/notifications/telegram"
+	"github.com/root-ali/iris/pkg/scheduler"
+	"github.com/root-ali/iris/pkg/scheduler/balance"
+	"github.com/root-ali/iris/pkg/scheduler/janitor"
 	"github.com/root-ali/iris/pkg/scheduler/message_status"
+	"github.com/root-ali/iris/pkg/statuspage"
 	"github.com/root-ali/iris/pkg/storage/postgresql"
+	"github.com/root-ali/iris/pkg/templates"
+	"github.com/root-ali/iris/pkg/tracing"
 	"github.com/root-ali/iris/pkg/util"
 
 	"go.uber.org/zap"
@@ -32,6 +48,8 @@ type App struct {
 	ProviderService notifications.ProviderServiceInterface
 	Services        []notifications.NotificationInterface
 	Router          *gin.Engine
+	// Shutdown flushes the spans not exported yet.
+	Shutdown func(ctx context.Context) error
 }
 
 func Init(cfg *config.Config) (*App, error) {
@@ -46,22 +64,47 @@ func Init(cfg *config.Config) (*App, error) {
 		logger.Panicw("JWT_SECRET not set")
 	}
 
+	// tracing, a no-op unless enabled
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Config{
+		Enabled:     cfg.Tracing.Enabled,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		ServiceName: cfg.Tracing.ServiceName,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	}, logger)
+	if err != nil {
+		return nil, fmt.Errorf("tracing init: %w", err)
+	}
+
 	// storage & migration
-	repos, err := storage.Init(logger, postgresql.Postgres{
-		Host:     cfg.Postgres.Host,
-		User:     cfg.Postgres.User,
-		SSLMode:  cfg.Postgres.SSL,
-		Password: cfg.Postgres.Pass,
-		Port:     cfg.Postgres.Port,
-		DBname:   cfg.Postgres.Name,
-	})
+	repos, err := storage.Init(logger, postgresConfig(cfg))
 	if err != nil {
 		return nil, err
 	}
 
+	// leader election, schedulers only do singleton work on the lock holder
+	clusterLockInterval, err := parseOptionalDuration(cfg.Cluster.LockInterval)
+	if err != nil {
+		return nil, fmt.Errorf("incorrect cluster config: %w", err)
+	}
+	clusterService, err := cluster.NewService(
+		repos.Postgres,
+		cfg.Cluster.InstanceID,
+		clusterLockInterval,
+		[]string{cluster.LockAlertScheduler, cluster.LockMessageStatus, cluster.LockBalance, cluster.LockJanitor,
+			cluster.LockTelegramUpdates},
+		logger,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cluster init: %w", err)
+	}
+	clusterService.Start(context.Background())
+
 	// notifications (providers + schedulers)
 	allServices := make([]notifications.NotificationInterface, 0)
 	deactiveProviders := make([]string, 0)
+	var telegramListener telegram.Listener
+	var mattermostIntegration mattermost.Integration
 
 	cacheReceptorsSchedulerStartAt, err := time.ParseDuration(cfg.Scheduler.MobileScheduler.StartAt)
 	cacheReceptorsSchedulerInterval, err := time.ParseDuration(cfg.Scheduler.MobileScheduler.Interval)
@@ -89,6 +132,7 @@ func Init(cfg *config.Config) (*App, error) {
 			cfg.Notifications.Smsir.ApiKey,
 			cfg.Notifications.Smsir.LineNumber,
 			cfg.Notifications.Smsir.Priority,
+			cfg.Notifications.Smsir.DlrToken,
 			logger,
 		)
 		allServices = append(allServices, smsirSvc)
@@ -107,6 +151,7 @@ func Init(cfg *config.Config) (*App, error) {
 			cfg.Notifications.Kavenegar.ApiToken,
 			cfg.Notifications.Kavenegar.Priority,
 			cfg.Notifications.Kavenegar.Sender,
+			cfg.Notifications.Kavenegar.DlrToken,
 			logger,
 		)
 		allServices = append(allServices, kv)
@@ -130,6 +175,9 @@ func Init(cfg *config.Config) (*App, error) {
 			logger.Errorw("telegram init failed", "error", err)
 		} else {
 			allServices = append(allServices, telegramSvc)
+			if l, ok := telegramSvc.(telegram.Listener); ok && cfg.Notifications.Telegram.Commands {
+				telegramListener = l
+			}
 		}
 	} else {
 		deactiveProviders = append(deactiveProviders, "Telegram")
@@ -162,20 +210,26 @@ func Init(cfg *config.Config) (*App, error) {
 			"bot token", cfg.Notifications.Mattermost.BotToken, "url", cfg.Notifications.Mattermost.Url)
 		mattermostSvc := mattermost.NewService(
 			mattermost.Config{
-				Url:      cfg.Notifications.Mattermost.Url,
-				BotToken: cfg.Notifications.Mattermost.BotToken,
-				Priority: cfg.Notifications.Mattermost.Priority,
+				Url:          cfg.Notifications.Mattermost.Url,
+				BotToken:     cfg.Notifications.Mattermost.BotToken,
+				Priority:     cfg.Notifications.Mattermost.Priority,
+				CallbackUrl:  cfg.Notifications.Mattermost.CallbackUrl,
+				ActionSecret: cfg.Notifications.Mattermost.ActionSecret,
+				SlashToken:   cfg.Notifications.Mattermost.SlashToken,
 			},
 			logger,
 		)
 		allServices = append(allServices, mattermostSvc)
+		if i, ok := mattermostSvc.(mattermost.Integration); ok {
+			mattermostIntegration = i
+		}
 	} else {
 		deactiveProviders = append(deactiveProviders, "Mattermost")
 	}
 
 	// Initialize asiatech notification provider
 	if cfg.Notifications.Asiatech.Enabled {
-		asiatechCache := cache.New[string, string](logger, cache.WithCapacity(1))
+		asiatechCache := cache.New[string, string](logger, cache.WithCapacity(1), cache.WithName("asiatech_token"))
 		asiatechSvc := asiatech.NewService(
 			cfg.Notifications.Asiatech.Username,
 			cfg.Notifications.Asiatech.Password,
@@ -183,6 +237,7 @@ func Init(cfg *config.Config) (*App, error) {
 			cfg.Notifications.Asiatech.Host,
 			cfg.Notifications.Asiatech.Sender,
 			cfg.Notifications.Asiatech.Priority,
+			cfg.Notifications.Asiatech.DlrToken,
 			asiatechCache,
 			logger,
 		)
@@ -197,8 +252,15 @@ func Init(cfg *config.Config) (*App, error) {
 	}
 
 	// provider registry
-	providerCache := cache.New[string, *[]notifications.Providers](logger, cache.WithCapacity(3))
-	providerService := notifications.NewProvidersService(repos.Postgres, allServices, providerCache, logger)
+	providerCache := cache.New[string, *[]notifications.Providers](logger, cache.WithCapacity(3), cache.WithName("providers"))
+	// Schedulers send through the guarded providers, callbacks and chat
+	// integrations keep using the providers themselves.
+	guardedServices, err := guardProviders(cfg, allServices, logger)
+	if err != nil {
+		return nil, err
+	}
+	providerService := notifications.NewProvidersService(repos.Postgres, guardedServices, providerCache, logger)
+	providerService.Watch(context.Background(), repos.Postgres)
 	for _, p := range allServices {
 		id, err := util.NewUUIDv7()
 		if err != nil {
@@ -227,18 +289,66 @@ func Init(cfg *config.Config) (*App, error) {
 		}
 	}
 
-	messageService := message.NewService(repos.Postgres, logger)
+	messageService := message.NewService(repos.Postgres, map[string]float64{
+		"Smsir":     cfg.Notifications.Smsir.CostPerMessage,
+		"Kavenegar": cfg.Notifications.Kavenegar.CostPerMessage,
+		"Asiatech":  cfg.Notifications.Asiatech.CostPerMessage,
+	}, logger)
 	alertSchedulerInterval, err := time.ParseDuration(cfg.Scheduler.AlertScheduler.Interval)
 	if err != nil {
 		return nil, fmt.Errorf("incorrect alert scheduler config: %w", err)
 	}
-	alertCache := cache.New[string, []string](logger, cache.WithCapacity(3))
+	maintenanceCache := cache.New[string, []*maintenance.Window](logger, cache.WithCapacity(1), cache.WithName("maintenance"))
+	maintenanceService := maintenance.NewService(repos.Postgres, maintenanceCache, logger)
+	contactRulesCache := cache.New[string, map[string]*contactrules.Preferences](logger, cache.WithCapacity(1), cache.WithName("contact_rules"))
+	contactRulesService := contactrules.NewService(repos.Postgres, contactRulesCache, logger)
+	contactMethodService := contactmethods.NewService(repos.Postgres, providerService, logger)
+	templatesCache := cache.New[string, []*templates.Template](logger, cache.WithCapacity(1), cache.WithName("notification_templates"))
+	templateService := templates.NewService(repos.Postgres, templatesCache, logger)
+	// Alert events reach the stream clients of every replica through
+	// Postgres LISTEN/NOTIFY
+	alertStream := alertstream.NewHub(repos.Postgres, logger)
+	alertStream.Start(context.Background())
+	alertService := alerts.NewAlertService(logger, repos.Postgres, alertStream)
+	chatopsService := chatops.NewService(repos.Postgres, alertService, contactMethodService, logger)
+	if telegramListener != nil {
+		if err := telegramListener.Listen(context.Background(), chatopsService, clusterService); err != nil {
+			logger.Errorw("telegram commands disabled", "error", err)
+		} else {
+			chatopsService.SetDeepLink(string(contactmethods.TypeTelegram), telegramListener.DeepLink)
+		}
+	}
+
+	incidentService := incidents.NewService(repos.Postgres, incidents.Config{
+		GroupBy: cfg.Incidents.GroupBy,
+	}, logger)
+
+	// The status page is public, its service is only built when enabled
+	var statusPageService statuspage.ServiceInterface
+	if cfg.StatusPage.Enabled {
+		statusPageCacheTTL, err := parseOptionalDuration(cfg.StatusPage.CacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("incorrect status page config: %w", err)
+		}
+		statusPageCache := cache.New[string, *statuspage.Page](logger, cache.WithCapacity(1), cache.WithName("status_page"))
+		statusPageService = statuspage.NewService(repos.Postgres, statusPageCache, statuspage.Config{
+			Title:       cfg.StatusPage.Title,
+			HistoryDays: cfg.StatusPage.HistoryDays,
+			CacheTTL:    statusPageCacheTTL,
+		}, logger)
+	}
+
+	alertCache := cache.New[string, []string](logger, cache.WithCapacity(3), cache.WithName("alerts"))
 	err = schedulers.StartAlertScheduler(logger,
 		repos.Postgres,
 		cr,
 		alertCache,
 		providerService,
-		messageService,
+		maintenanceService,
+		contactRulesService,
+		incidentService,
+		templateService,
+		clusterService,
 		alertSchedulerInterval,
 		cfg.Scheduler.AlertScheduler.Workers,
 		cfg.Scheduler.AlertScheduler.QueueSize)
@@ -257,6 +367,7 @@ func Init(cfg *config.Config) (*App, error) {
 	messageStatusScheduler, err := message_status.NewMessageStatusMessageService(
 		messageService,
 		providerService,
+		clusterService,
 		messageStatusConfig,
 		logger)
 	if err != nil {
@@ -266,6 +377,44 @@ func Init(cfg *config.Config) (*App, error) {
 	if err != nil {
 		return nil, fmt.Errorf("message status scheduler start: %w", err)
 	}
+	deliveryReportHandler, _ := messageStatusScheduler.(message_status.DeliveryReportHandler)
+
+	// Low SMS credit is raised as an alert to admins on other channels
+	balanceInterval, err := parseOptionalDuration(cfg.Scheduler.Balance.Interval)
+	if err != nil {
+		return nil, fmt.Errorf("incorrect balance scheduler config: %w", err)
+	}
+	balanceScheduler, err := balance.NewService(
+		allServices,
+		alertService,
+		repos.Postgres,
+		clusterService,
+		balance.Config{
+			Interval: balanceInterval,
+			Thresholds: map[string]float64{
+				"Smsir":     cfg.Notifications.Smsir.BalanceThreshold,
+				"Kavenegar": cfg.Notifications.Kavenegar.BalanceThreshold,
+				"Asiatech":  cfg.Notifications.Asiatech.BalanceThreshold,
+			},
+			Group:   cfg.Scheduler.Balance.Group,
+			Methods: cfg.Scheduler.Balance.Methods,
+		},
+		logger)
+	if err != nil {
+		return nil, fmt.Errorf("balance scheduler init: %w", err)
+	}
+	if err := balanceScheduler.Start(); err != nil {
+		return nil, fmt.Errorf("balance scheduler start: %w", err)
+	}
+
+	// Retention janitor, it also creates the monthly alert partitions
+	janitorScheduler, err := newJanitor(cfg, repos.Postgres, clusterService, logger)
+	if err != nil {
+		return nil, fmt.Errorf("janitor init: %w", err)
+	}
+	if err := janitorScheduler.Start(); err != nil {
+		return nil, fmt.Errorf("janitor start: %w", err)
+	}
 
 	// HTTP router (and default data bootstraps like roles/admin)
 	router := server.RegisterRoutes(server.Deps{
@@ -274,15 +423,162 @@ func Init(cfg *config.Config) (*App, error) {
 		JWTSecret:       []byte(cfg.JwtSecret),
 		SignupEnabled:   cfg.SignupEnabled,
 		ProviderService: providerService,
-		AdminPass:       cfg.HTTP.AdminPass,
-		GinMode:         cfg.Go.Mode, // reuse
+		Alerts:          alertService,
+		AlertStream:     alertStream,
+		ChatOps:         chatopsService,
+		Mattermost:      mattermostIntegration,
+		Providers:       allServices,
+		DeliveryReports: deliveryReportHandler,
+		Maintenance:     maintenanceService,
+		ContactRules:    contactRulesService,
+		ContactMethods:  contactMethodService,
+		Cluster:         clusterService,
+		Messages:        messageService,
+		Incidents:       incidentService,
+		StatusPage:      statusPageService,
+		Templates:       templateService,
+		Readiness: []health_check.ReadinessCheck{
+			{Name: "receptor_cache", Check: cr.Ready},
+		},
+		AdminPass: cfg.HTTP.AdminPass,
+		GinMode:   cfg.Go.Mode, // reuse
 	})
 
+	// Users, groups, routes, silences and templates declared in YAML, once
+	// the default roles and the providers exist
+	if cfg.Declarative.Path != "" {
+		if err := applyDeclarative(cfg, repos.Postgres, logger); err != nil {
+			return nil, fmt.Errorf("declarative config: %w", err)
+		}
+	}
+
 	return &App{
 		Logger:          logger,
 		Repos:           repos,
 		ProviderService: providerService,
 		Services:        allServices,
 		Router:          router,
+		Shutdown:        shutdownTracing,
 	}, nil
 }
+
+func postgresConfig(cfg *config.Config) postgresql.Postgres {
+	return postgresql.Postgres{
+		Host:     cfg.Postgres.Host,
+		User:     cfg.Postgres.User,
+		SSLMode:  cfg.Postgres.SSL,
+		Password: cfg.Postgres.Pass,
+		Port:     cfg.Postgres.Port,
+		DBname:   cfg.Postgres.Name,
+	}
+}
+
+// guardProviders wraps every provider with its rate limit, retry policy
+// and the shared circuit breaker settings.
+func guardProviders(cfg *config.Config, providers []notifications.NotificationInterface,
+	logger *zap.SugaredLogger) ([]notifications.NotificationInterface, error) {
+	openTimeout, err := parseOptionalDuration(cfg.Notifications.CircuitBreaker.OpenTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("incorrect circuit breaker config: %w", err)
+	}
+	n := cfg.Notifications
+	settings := map[string]struct {
+		rateLimit float64
+		burst     int
+		retry     config.Retry
+	}{
+		"Smsir":      {n.Smsir.RateLimit, n.Smsir.RateBurst, n.Smsir.Retry},
+		"Kavenegar":  {n.Kavenegar.RateLimit, n.Kavenegar.RateBurst, n.Kavenegar.Retry},
+		"Telegram":   {n.Telegram.RateLimit, n.Telegram.RateBurst, n.Telegram.Retry},
+		"Mail":       {n.Mail.RateLimit, n.Mail.RateBurst, n.Mail.Retry},
+		"Mattermost": {n.Mattermost.RateLimit, n.Mattermost.RateBurst, n.Mattermost.Retry},
+		"Asiatech":   {n.Asiatech.RateLimit, n.Asiatech.RateBurst, n.Asiatech.Retry},
+	}
+
+	guarded := make([]notifications.NotificationInterface, 0, len(providers))
+	for _, p := range providers {
+		st := settings[p.GetName()]
+		baseDelay, err := parseOptionalDuration(st.retry.BaseDelay)
+		if err != nil {
+			return nil, fmt.Errorf("incorrect %s retry config: %w", p.GetName(), err)
+		}
+		jitter := notifications.DefaultRetryPolicy.Jitter
+		if st.retry.Jitter != nil {
+			jitter = *st.retry.Jitter
+		}
+		guarded = append(guarded, notifications.Guard(p, notifications.GuardConfig{
+			RateLimit:        st.rateLimit,
+			Burst:            st.burst,
+			FailureThreshold: n.CircuitBreaker.FailureThreshold,
+			OpenTimeout:      openTimeout,
+			Retry: notifications.RetryPolicy{
+				BaseDelay:   baseDelay,
+				Factor:      st.retry.Factor,
+				Jitter:      jitter,
+				MaxAttempts: st.retry.MaxAttempts,
+			},
+		}, logger))
+	}
+	return guarded, nil
+}
+
+// newJanitor builds the retention janitor. Nothing is purged unless
+// retention is enabled, the alert partitions are managed either way.
+func newJanitor(cfg *config.Config, repo janitor.Repository, leader cluster.LeaderInterface,
+	logger *zap.SugaredLogger) (scheduler.ServiceInterface, error) {
+	r := cfg.Retention
+	interval, err := parseOptionalDuration(r.Interval)
+	if err != nil {
+		return nil, fmt.Errorf("incorrect retention interval: %w", err)
+	}
+	jc := janitor.Config{
+		Interval:        interval,
+		BatchSize:       r.BatchSize,
+		PartitionsAhead: r.PartitionsAhead,
+	}
+	if !r.Enabled {
+		return janitor.NewService(repo, nil, leader, jc, logger)
+	}
+	if jc.Alerts, err = parseOptionalDuration(r.Alerts); err != nil {
+		return nil, fmt.Errorf("incorrect alerts retention: %w", err)
+	}
+	if jc.Messages, err = parseOptionalDuration(r.Messages); err != nil {
+		return nil, fmt.Errorf("incorrect messages retention: %w", err)
+	}
+	if jc.SoftDeleted, err = parseOptionalDuration(r.SoftDeleted); err != nil {
+		return nil, fmt.Errorf("incorrect soft deleted retention: %w", err)
+	}
+
+	var archive janitor.Archive
+	switch r.Archive.Type {
+	case "local":
+		archive, err = janitor.NewLocalArchive(r.Archive.Path)
+	case "s3":
+		archive, err = janitor.NewS3Archive(janitor.S3Config{
+			Endpoint:  r.Archive.S3.Endpoint,
+			Region:    r.Archive.S3.Region,
+			Bucket:    r.Archive.S3.Bucket,
+			Prefix:    r.Archive.S3.Prefix,
+			AccessKey: r.Archive.S3.AccessKey,
+			SecretKey: r.Archive.S3.SecretKey,
+			Insecure:  r.Archive.S3.Insecure,
+		})
+	case "none":
+		logger.Warnw("Expired rows are deleted without being archived")
+	default:
+		return nil, fmt.Errorf("retention archive type must be local, s3 or none, got %q", r.Archive.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("retention archive: %w", err)
+	}
+	return janitor.NewService(repo, archive, leader, jc, logger)
+}
+
+// parseOptionalDuration parses settings that may be left out of
+// config.yml, an empty value is zero so the service default applies.
+func parseOptionalDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(s)
+}
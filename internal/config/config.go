@@ -18,27 +18,54 @@ type GoEnv struct {
 	Mode string `env:"GO_ENV" envDefault:"debug" koanf:"mode"`
 }
 
+// Retry is the backoff of failed sends of a provider. Zero values use the
+// defaults: 30s base delay, factor 2, 0.2 jitter and 5 attempts.
+type Retry struct {
+	BaseDelay   string   `env:"BASE_DELAY" koanf:"base_delay"`
+	Factor      float64  `env:"FACTOR" koanf:"factor"`
+	Jitter      *float64 `env:"JITTER" koanf:"jitter"`
+	MaxAttempts int      `env:"MAX_ATTEMPTS" koanf:"max_attempts"`
+}
+
 type Notifications struct {
 	Asiatech struct {
-		Host     string `env:"ASIATECH_HOST" koanf:"host"`
-		Username string `env:"ASIATECH_USERNAME" koanf:"username"`
-		Password string `env:"ASIATECH_PASSWORD" koanf:"password"`
-		Scope    string `env:"ASIATECH_SCOPE" koanf:"scope"`
-		Sender   string `env:"ASIATECH_SENDER" koanf:"sender"`
-		Priority int    `env:"ASIATECH_PRIORITY" envDefault:"4" koanf:"priority"`
-		Enabled  bool   `env:"ASIATECH_ENABLED" envDefault:"false" koanf:"enabled"`
+		Host             string  `env:"ASIATECH_HOST" koanf:"host"`
+		Username         string  `env:"ASIATECH_USERNAME" koanf:"username"`
+		Password         string  `env:"ASIATECH_PASSWORD" koanf:"password"`
+		Scope            string  `env:"ASIATECH_SCOPE" koanf:"scope"`
+		Sender           string  `env:"ASIATECH_SENDER" koanf:"sender"`
+		Priority         int     `env:"ASIATECH_PRIORITY" envDefault:"4" koanf:"priority"`
+		Enabled          bool    `env:"ASIATECH_ENABLED" envDefault:"false" koanf:"enabled"`
+		DlrToken         string  `env:"ASIATECH_DLR_TOKEN" koanf:"dlr_token"`
+		RateLimit        float64 `env:"ASIATECH_RATE_LIMIT" koanf:"rate_limit"`
+		RateBurst        int     `env:"ASIATECH_RATE_BURST" envDefault:"1" koanf:"rate_burst"`
+		Retry            Retry   `envPrefix:"ASIATECH_RETRY_" koanf:"retry"`
+		BalanceThreshold float64 `env:"ASIATECH_BALANCE_THRESHOLD" koanf:"balance_threshold"`
+		CostPerMessage   float64 `env:"ASIATECH_COST_PER_MESSAGE" koanf:"cost_per_message"`
 	} `knoanf:"asiatech"`
 	Smsir struct {
-		ApiKey     string `env:"SMSIR_API_TOKEN" koanf:"api_key"`
-		LineNumber string `env:"SMSIR_LINE_NUMBER" koanf:"line_number"`
-		Enabled    bool   `env:"SMSIR_ENABLED" envDefault:"false" koanf:"enabled"`
-		Priority   int    `env:"SMSIR_PRIORITY" envDefault:"2" koanf:"priority"`
+		ApiKey           string  `env:"SMSIR_API_TOKEN" koanf:"api_key"`
+		LineNumber       string  `env:"SMSIR_LINE_NUMBER" koanf:"line_number"`
+		Enabled          bool    `env:"SMSIR_ENABLED" envDefault:"false" koanf:"enabled"`
+		Priority         int     `env:"SMSIR_PRIORITY" envDefault:"2" koanf:"priority"`
+		DlrToken         string  `env:"SMSIR_DLR_TOKEN" koanf:"dlr_token"`
+		RateLimit        float64 `env:"SMSIR_RATE_LIMIT" koanf:"rate_limit"`
+		RateBurst        int     `env:"SMSIR_RATE_BURST" envDefault:"1" koanf:"rate_burst"`
+		Retry            Retry   `envPrefix:"SMSIR_RETRY_" koanf:"retry"`
+		BalanceThreshold float64 `env:"SMSIR_BALANCE_THRESHOLD" koanf:"balance_threshold"`
+		CostPerMessage   float64 `env:"SMSIR_COST_PER_MESSAGE" koanf:"cost_per_message"`
 	} `koanf:"smsir"`
 	Kavenegar struct {
-		ApiToken string `env:"KAVENEGAR_API_TOKEN" koanf:"api_token"`
-		Sender   string `env:"KAVENEGAR_SENDER" envDefault:"" koanf:"sender"`
-		Enabled  bool   `env:"KAVENEGAR_ENABLED" envDefault:"true" koanf:"enabled"`
-		Priority int    `env:"KAVENEGAR_PRIORITY" envDefault:"1" koanf:"priority"`
+		ApiToken         string  `env:"KAVENEGAR_API_TOKEN" koanf:"api_token"`
+		Sender           string  `env:"KAVENEGAR_SENDER" envDefault:"" koanf:"sender"`
+		Enabled          bool    `env:"KAVENEGAR_ENABLED" envDefault:"true" koanf:"enabled"`
+		Priority         int     `env:"KAVENEGAR_PRIORITY" envDefault:"1" koanf:"priority"`
+		DlrToken         string  `env:"KAVENEGAR_DLR_TOKEN" koanf:"dlr_token"`
+		RateLimit        float64 `env:"KAVENEGAR_RATE_LIMIT" koanf:"rate_limit"`
+		RateBurst        int     `env:"KAVENEGAR_RATE_BURST" envDefault:"1" koanf:"rate_burst"`
+		Retry            Retry   `envPrefix:"KAVENEGAR_RETRY_" koanf:"retry"`
+		BalanceThreshold float64 `env:"KAVENEGAR_BALANCE_THRESHOLD" koanf:"balance_threshold"`
+		CostPerMessage   float64 `env:"KAVENEGAR_COST_PER_MESSAGE" koanf:"cost_per_message"`
 	} `koanf:"kavenegar"`
 	Email struct {
 		Host     string `env:"EMAIL_HOST" koanf:"host"`
@@ -49,26 +76,47 @@ type Notifications struct {
 		Enabled  bool   `env:"EMAIL_ENABLED" envDefault:"false" koanf:"enabled"`
 	} `koanf:"email"`
 	Telegram struct {
-		BotToken string `env:"TELEGRAM_BOT_TOKEN" koanf:"bot_token"`
-		Proxy    string `env:"TELEGRAM_PROXY" envDefault:"" koanf:"proxy"`
-		Enabled  bool   `env:"TELEGRAM_ENABLED" envDefault:"false" koanf:"enabled"`
+		BotToken  string  `env:"TELEGRAM_BOT_TOKEN" koanf:"bot_token"`
+		Proxy     string  `env:"TELEGRAM_PROXY" envDefault:"" koanf:"proxy"`
+		Enabled   bool    `env:"TELEGRAM_ENABLED" envDefault:"false" koanf:"enabled"`
+		Commands  bool    `env:"TELEGRAM_COMMANDS_ENABLED" envDefault:"true" koanf:"commands_enabled"`
+		RateLimit float64 `env:"TELEGRAM_RATE_LIMIT" koanf:"rate_limit"`
+		RateBurst int     `env:"TELEGRAM_RATE_BURST" envDefault:"1" koanf:"rate_burst"`
+		Retry     Retry   `envPrefix:"TELEGRAM_RETRY_" koanf:"retry"`
 	} `env:"TELEGRAM_ENABLED" envDefault:"false" koanf:"telegram"`
 	Mail struct {
-		SMTPHost    string `env:"MAIL_SMTP_HOST" koanf:"smtp_host"`
-		SMTPPort    int    `env:"MAIL_SMTP_PORT" koanf:"smtp_port"`
-		Username    string `env:"MAIL_USERNAME" koanf:"username"`
-		Password    string `env:"MAIL_PASSWORD" koanf:"password"`
-		FromAddress string `env:"MAIL_FROM_ADDRESS" koanf:"from_address"`
-		FromName    string `env:"MAIL_FROM_NAME" koanf:"from_name"`
-		Enabled     bool   `env:"MAIL_ENABLED" envDefault:"false" koanf:"enabled"`
-		Priority    int    `env:"MAIL_PRIORITY" envDefault:"5" koanf:"priority"`
+		SMTPHost    string  `env:"MAIL_SMTP_HOST" koanf:"smtp_host"`
+		SMTPPort    int     `env:"MAIL_SMTP_PORT" koanf:"smtp_port"`
+		Username    string  `env:"MAIL_USERNAME" koanf:"username"`
+		Password    string  `env:"MAIL_PASSWORD" koanf:"password"`
+		FromAddress string  `env:"MAIL_FROM_ADDRESS" koanf:"from_address"`
+		FromName    string  `env:"MAIL_FROM_NAME" koanf:"from_name"`
+		Enabled     bool    `env:"MAIL_ENABLED" envDefault:"false" koanf:"enabled"`
+		Priority    int     `env:"MAIL_PRIORITY" envDefault:"5" koanf:"priority"`
+		RateLimit   float64 `env:"MAIL_RATE_LIMIT" koanf:"rate_limit"`
+		RateBurst   int     `env:"MAIL_RATE_BURST" envDefault:"1" koanf:"rate_burst"`
+		Retry       Retry   `envPrefix:"MAIL_RETRY_" koanf:"retry"`
 	} `koanf:"mail"`
 	Mattermost struct {
 		Url      string `env:"MATTERMOST_URL" koanf:"url"`
 		BotToken string `env:"MATTERMOST_BOT_TOKEN" koanf:"bot_token"`
 		Enabled  bool   `env:"MATTERMOST_ENABLED" envDefault:"false" koanf:"enabled"`
 		Priority int    `env:"MATTERMOST_PRIORITY" envDefault:"3" koanf:"priority"`
+		// CallbackUrl is the public Iris URL, interactive buttons are only
+		// added to alerts when it is set.
+		CallbackUrl  string  `env:"MATTERMOST_CALLBACK_URL" koanf:"callback_url"`
+		ActionSecret string  `env:"MATTERMOST_ACTION_SECRET" koanf:"action_secret"`
+		SlashToken   string  `env:"MATTERMOST_SLASH_TOKEN" koanf:"slash_token"`
+		RateLimit    float64 `env:"MATTERMOST_RATE_LIMIT" koanf:"rate_limit"`
+		RateBurst    int     `env:"MATTERMOST_RATE_BURST" envDefault:"1" koanf:"rate_burst"`
+		Retry        Retry   `envPrefix:"MATTERMOST_RETRY_" koanf:"retry"`
 	} `koanf:"mattermost"`
+	// CircuitBreaker applies to every provider, rate limits are set per
+	// provider in sends per second.
+	CircuitBreaker struct {
+		FailureThreshold int    `env:"CIRCUIT_BREAKER_FAILURE_THRESHOLD" envDefault:"5" koanf:"failure_threshold"`
+		OpenTimeout      string `env:"CIRCUIT_BREAKER_OPEN_TIMEOUT" envDefault:"30s" koanf:"open_timeout"`
+	} `koanf:"circuit_breaker"`
 }
 
 type Scheduler struct {
@@ -91,15 +139,93 @@ type Scheduler struct {
 		Workers   int    `env:"MESSAGE_STATUS_WORKERS" envDefault:"10" koanf:"workers"`
 		QueueSize int    `env:"MESSAGE_STATUS_QUEUE_SIZE" envDefault:"100" koanf:"queue_size"`
 	} `koanf:"message_status"`
+	// Balance polls the credit of SMS providers, low balance alerts go to
+	// Group through Methods.
+	Balance struct {
+		Interval string   `env:"BALANCE_SCHEDULER_INTERVAL" envDefault:"10m" koanf:"interval"`
+		Group    string   `env:"BALANCE_ALERT_GROUP" koanf:"group"`
+		Methods  []string `env:"BALANCE_ALERT_METHODS" envDefault:"mail,telegram,mattermost" koanf:"methods"`
+	} `koanf:"balance"`
 	Enabled bool `env:"SCHEDULER_ENABLED" envDefault:"false" koanf:"scheduler_enabled"`
 }
 
+// Cluster configures leader election between Iris replicas.
+type Cluster struct {
+	InstanceID   string `env:"IRIS_INSTANCE_ID" koanf:"instance_id"`
+	LockInterval string `env:"CLUSTER_LOCK_INTERVAL" envDefault:"5s" koanf:"lock_interval"`
+}
+
+// Tracing exports OpenTelemetry spans over OTLP/HTTP.
+type Tracing struct {
+	Enabled     bool    `env:"TRACING_ENABLED" envDefault:"false" koanf:"enabled"`
+	Endpoint    string  `env:"TRACING_ENDPOINT" koanf:"endpoint"`
+	Insecure    bool    `env:"TRACING_INSECURE" envDefault:"false" koanf:"insecure"`
+	ServiceName string  `env:"TRACING_SERVICE_NAME" envDefault:"iris" koanf:"service_name"`
+	SampleRatio float64 `env:"TRACING_SAMPLE_RATIO" envDefault:"1" koanf:"sample_ratio"`
+}
+
+// Incidents groups firing alerts with the same values of the GroupBy
+// labels into one incident, nothing is grouped when it is empty.
+type Incidents struct {
+	GroupBy []string `env:"INCIDENTS_GROUP_BY" koanf:"group_by"`
+}
+
+// StatusPage serves a public page at /status with the state of the
+// components and the incidents of the last HistoryDays.
+type StatusPage struct {
+	Enabled     bool   `env:"STATUS_PAGE_ENABLED" envDefault:"false" koanf:"enabled"`
+	Title       string `env:"STATUS_PAGE_TITLE" envDefault:"Status" koanf:"title"`
+	HistoryDays int    `env:"STATUS_PAGE_HISTORY_DAYS" envDefault:"14" koanf:"history_days"`
+	CacheTTL    string `env:"STATUS_PAGE_CACHE_TTL" envDefault:"30s" koanf:"cache_ttl"`
+}
+
+// Retention archives and then deletes resolved alerts, soft deleted alerts
+// and messages older than their retention. The monthly partitions of
+// alerts are managed even when it is disabled.
+type Retention struct {
+	Enabled         bool   `env:"RETENTION_ENABLED" envDefault:"false" koanf:"enabled"`
+	Interval        string `env:"RETENTION_INTERVAL" envDefault:"1h" koanf:"interval"`
+	BatchSize       int    `env:"RETENTION_BATCH_SIZE" envDefault:"1000" koanf:"batch_size"`
+	Alerts          string `env:"RETENTION_ALERTS" envDefault:"2160h" koanf:"alerts"`
+	Messages        string `env:"RETENTION_MESSAGES" envDefault:"2160h" koanf:"messages"`
+	SoftDeleted     string `env:"RETENTION_SOFT_DELETED" envDefault:"168h" koanf:"soft_deleted"`
+	PartitionsAhead int    `env:"RETENTION_PARTITIONS_AHEAD" envDefault:"2" koanf:"partitions_ahead"`
+	// Archive is local, s3 or none to delete without archiving.
+	Archive struct {
+		Type string `env:"RETENTION_ARCHIVE_TYPE" koanf:"type"`
+		Path string `env:"RETENTION_ARCHIVE_PATH" koanf:"path"`
+		S3   struct {
+			Endpoint  string `env:"RETENTION_S3_ENDPOINT" koanf:"endpoint"`
+			Region    string `env:"RETENTION_S3_REGION" envDefault:"us-east-1" koanf:"region"`
+			Bucket    string `env:"RETENTION_S3_BUCKET" koanf:"bucket"`
+			Prefix    string `env:"RETENTION_S3_PREFIX" koanf:"prefix"`
+			AccessKey string `env:"RETENTION_S3_ACCESS_KEY" koanf:"access_key"`
+			SecretKey string `env:"RETENTION_S3_SECRET_KEY" koanf:"secret_key"`
+			Insecure  bool   `env:"RETENTION_S3_INSECURE" envDefault:"false" koanf:"insecure"`
+		} `koanf:"s3"`
+	} `koanf:"archive"`
+}
+
+// Declarative applies the YAML files at Path, a file or a directory, at
+// startup like `iris apply -f`. With Prune the objects of the declared
+// kinds that are not in the files are deleted.
+type Declarative struct {
+	Path  string `env:"DECLARATIVE_PATH" koanf:"path"`
+	Prune bool   `env:"DECLARATIVE_PRUNE" envDefault:"false" koanf:"prune"`
+}
+
 type Config struct {
 	Postgres      Postgres      `koanf:"postgres"`
 	HTTP          HTTP          `koanf:"http"`
 	Go            GoEnv         `koanf:"go"`
 	Notifications Notifications `koanf:"notifications"`
 	Scheduler     Scheduler     `koanf:"scheduler"`
+	Cluster       Cluster       `koanf:"cluster"`
+	Tracing       Tracing       `koanf:"tracing"`
+	Incidents     Incidents     `koanf:"incidents"`
+	StatusPage    StatusPage    `koanf:"status_page"`
+	Retention     Retention     `koanf:"retention"`
+	Declarative   Declarative   `koanf:"declarative"`
 	JwtSecret     string        `env:"JWT_SECRET" koanf:"jwt_secret"`
 	SignupEnabled bool          `env:"SIGNUP_ENABLED" envDefault:"true" koanf:"signup_enabled"`
 }
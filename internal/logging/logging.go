@@ -18,3 +18,14 @@ func New(mode string) (*zap.Logger, error) {
 	cfg.ErrorOutputPaths = []string{"stderr"}
 	return cfg.Build()
 }
+
+// NewCLI logs warnings and errors to stderr, stdout is left to the output
+// of the command.
+func NewCLI() (*zap.Logger, error) {
+	cfg := zap.NewProductionConfig()
+	cfg.Level = zap.NewAtomicLevelAt(zap.WarnLevel)
+	cfg.Encoding = "console"
+	cfg.OutputPaths = []string{"stderr"}
+	cfg.ErrorOutputPaths = []string{"stderr"}
+	return cfg.Build()
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/root-ali/iris/pkg/cache"
+	"github.com/root-ali/iris/pkg/cluster"
 	"github.com/root-ali/iris/pkg/notifications"
 	"github.com/root-ali/iris/pkg/scheduler/alert"
 	"github.com/root-ali/iris/pkg/storage/postgresql"
@@ -16,7 +17,11 @@ func StartAlertScheduler(
 	receptor alert.ReceptorInterface,
 	cache cache.Interface[string, []string],
 	provider notifications.ProviderStatusInterface,
-	message alert.MessageInterface,
+	maintenance alert.MaintenanceInterface,
+	contactRules alert.ContactRulesInterface,
+	incidents alert.IncidentInterface,
+	templates alert.TemplateInterface,
+	leader cluster.LeaderInterface,
 	interval time.Duration,
 	workers, queue int,
 ) error {
@@ -25,7 +30,7 @@ func StartAlertScheduler(
 		Workers:   workers,
 		QueueSize: queue,
 	}
-	a := alert.NewScheduler(cache, receptor, repos, provider, message, logger, cfg)
+	a := alert.NewScheduler(cache, receptor, repos, provider, repos, maintenance, contactRules, incidents, templates, leader, logger, cfg)
 	return a.Start()
 	//return nil
 }
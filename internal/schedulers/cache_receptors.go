@@ -17,7 +17,7 @@ func StartCacheReceptors(
 	workers, queueSize, cacheCapacity int,
 ) (*cache_receptors.CacheReceptor, error) {
 	logger.Debug("Starting cache receptor service...")
-	c := cache.New[string, map[string]string](logger, cache.WithCapacity(cacheCapacity))
+	c := cache.New[string, map[string][]string](logger, cache.WithCapacity(cacheCapacity), cache.WithName("receptors"))
 	cfg := cache_receptors.Config{
 		StartAt:   time.Now().Add(startAtSeconds),
 		Interval:  interval,
@@ -3,14 +3,27 @@ package server
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/root-ali/iris/pkg/alerts"
+	"github.com/root-ali/iris/pkg/alertstream"
 	"github.com/root-ali/iris/pkg/auth"
 	"github.com/root-ali/iris/pkg/captcha"
+	"github.com/root-ali/iris/pkg/chatops"
+	"github.com/root-ali/iris/pkg/cluster"
+	"github.com/root-ali/iris/pkg/contactmethods"
+	"github.com/root-ali/iris/pkg/contactrules"
 	"github.com/root-ali/iris/pkg/groups"
 	"github.com/root-ali/iris/pkg/health_check"
 	"github.com/root-ali/iris/pkg/http"
+	"github.com/root-ali/iris/pkg/incidents"
+	"github.com/root-ali/iris/pkg/maintenance"
+	"github.com/root-ali/iris/pkg/message"
 	"github.com/root-ali/iris/pkg/notifications"
+	"github.com/root-ali/iris/pkg/notifications/mattermost"
+	"github.com/root-ali/iris/pkg/reports"
 	"github.com/root-ali/iris/pkg/roles"
+	"github.com/root-ali/iris/pkg/scheduler/message_status"
+	"github.com/root-ali/iris/pkg/statuspage"
 	"github.com/root-ali/iris/pkg/storage/postgresql"
+	"github.com/root-ali/iris/pkg/templates"
 	"github.com/root-ali/iris/pkg/user"
 	"go.uber.org/zap"
 )
@@ -21,18 +34,33 @@ type Deps struct {
 	JWTSecret       []byte
 	SignupEnabled   bool
 	ProviderService notifications.ProviderServiceInterface
+	Alerts          alerts.Service
+	AlertStream     alertstream.Interface
+	ChatOps         chatops.ServiceInterface
+	Mattermost      mattermost.Integration
+	Providers       []notifications.NotificationInterface
+	DeliveryReports message_status.DeliveryReportHandler
+	Maintenance     maintenance.ServiceInterface
+	ContactRules    contactrules.ServiceInterface
+	ContactMethods  contactmethods.ServiceInterface
+	Cluster         cluster.ServiceInterface
+	Messages        message.ServiceInterface
+	Incidents       incidents.ServiceInterface
+	StatusPage      statuspage.ServiceInterface
+	Templates       templates.ServiceInterface
+	Readiness       []health_check.ReadinessCheck
 	AdminPass       string
 	GinMode         string
 }
 
 func RegisterRoutes(d Deps) *gin.Engine {
-	alertService := alerts.NewAlertService(d.Logger, d.Repos)
-	healthService := health_check.NewHealthService(d.Logger, d.Repos)
+	healthService := health_check.NewHealthService(d.Logger, d.Repos, d.Providers, d.Readiness...)
 	roleService := roles.NewRolesService(d.Logger, d.Repos)
 	userService := user.NewUserService(d.Repos, roleService, d.Logger)
 	authService := auth.NewAuthService(d.JWTSecret, roleService, d.Logger)
 	groupService := groups.NewGroupService(d.Logger, d.Repos)
 	captchaSvc := captcha.NewCaptchaService(d.Logger)
+	reportService := reports.NewService(d.Repos, d.Logger)
 
 	if err := roleService.InitiateDefaultRoles(); err != nil {
 		d.Logger.Panicw("Cannot create default roles", "error", err)
@@ -42,13 +70,27 @@ func RegisterRoutes(d Deps) *gin.Engine {
 	}
 
 	h := http.HttpHandler{
-		AS:            alertService,
+		AS:            d.Alerts,
+		AST:           d.AlertStream,
 		HS:            healthService,
 		US:            userService,
 		ATHS:          authService,
 		GR:            groupService,
 		CS:            captchaSvc,
 		PS:            d.ProviderService,
+		MS:            d.Maintenance,
+		CRS:           d.ContactRules,
+		CMS:           d.ContactMethods,
+		COS:           d.ChatOps,
+		MMI:           d.Mattermost,
+		NP:            d.Providers,
+		DRH:           d.DeliveryReports,
+		CLS:           d.Cluster,
+		MES:           d.Messages,
+		RS:            reportService,
+		IS:            d.Incidents,
+		SP:            d.StatusPage,
+		TS:            d.Templates,
 		AdminPassword: d.AdminPass,
 		GinMode:       d.GinMode,
 		SignupEnabled: d.SignupEnabled,
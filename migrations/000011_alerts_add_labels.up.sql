@@ -0,0 +1,2 @@
+ALTER TABLE alerts
+    ADD COLUMN labels JSONB NOT NULL DEFAULT '{}'::jsonb;
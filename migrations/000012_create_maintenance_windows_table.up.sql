@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS maintenance_windows (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(50) NOT NULL,
+    description VARCHAR(255),
+    schedule_type VARCHAR(10) NOT NULL,
+    schedule TEXT NOT NULL,
+    duration_seconds BIGINT NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    matchers TEXT[] NOT NULL DEFAULT '{}',
+    groups TEXT[] NOT NULL DEFAULT '{}',
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP NOT NULL,
+    modified_at TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_maintenance_windows_deleted_at
+    ON maintenance_windows (deleted_at);
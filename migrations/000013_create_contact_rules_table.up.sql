@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS contact_rules (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(26) NOT NULL,
+    severity VARCHAR(50) NOT NULL DEFAULT '*',
+    methods TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL,
+    modified_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_contact_rules_user_id ON contact_rules (user_id);
+
+CREATE TABLE IF NOT EXISTS user_quiet_hours (
+    user_id VARCHAR(26) PRIMARY KEY,
+    start_time VARCHAR(5) NOT NULL,
+    end_time VARCHAR(5) NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    bypass_severities TEXT[] NOT NULL DEFAULT '{critical}',
+    created_at TIMESTAMP NOT NULL,
+    modified_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS contact_methods (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(26) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    label VARCHAR(50) NOT NULL DEFAULT '',
+    value VARCHAR(255) NOT NULL,
+    verified_at TIMESTAMP,
+    verification_code_hash VARCHAR(64) NOT NULL DEFAULT '',
+    verification_expires_at TIMESTAMP,
+    verification_attempts INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL,
+    modified_at TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_contact_methods_user_id ON contact_methods (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_contact_methods_user_type_value
+    ON contact_methods (user_id, type, value) WHERE deleted_at IS NULL;
+
+-- Existing contact points keep paging, so they are imported as verified.
+INSERT INTO contact_methods (id, user_id, type, label, value, verified_at, created_at, modified_at)
+SELECT gen_random_uuid()::text, u.id, m.type, 'primary', m.value, NOW(), NOW(), NOW()
+FROM users u
+CROSS JOIN LATERAL (VALUES
+    ('sms', u.mobile),
+    ('mail', u.email),
+    ('telegram', u.telegram_id),
+    ('mattermost', u.mattermost_id)
+) AS m(type, value)
+WHERE u.deleted_at IS NULL
+  AND m.value IS NOT NULL
+  AND m.value <> ''
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,4 @@
+ALTER TABLE alerts
+    ADD COLUMN acknowledged_at TIMESTAMP,
+    ADD COLUMN acknowledged_by VARCHAR(26),
+    ADD COLUMN silenced_until TIMESTAMP;
@@ -0,0 +1,4 @@
+-- acknowledged_by also holds chat usernames such as @alice. Setting the type
+-- again is a no-op, so databases that already have the wide column are fine
+ALTER TABLE alerts
+    ALTER COLUMN acknowledged_by TYPE VARCHAR(255);
@@ -0,0 +1,2 @@
+-- Delivery report callbacks look messages up by the provider message ID
+CREATE INDEX IF NOT EXISTS idx_message_sender_sender_id ON message (sender, sender_id);
@@ -0,0 +1,12 @@
+-- Messages are written as a pending outbox before they are sent
+ALTER TABLE message
+    ADD COLUMN alert_id VARCHAR(60),
+    ADD COLUMN subject TEXT,
+    ADD COLUMN state VARCHAR(20),
+    ADD COLUMN body TEXT,
+    ADD COLUMN idempotency_key VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_message_idempotency_key
+    ON message (idempotency_key) WHERE idempotency_key IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_message_pending
+    ON message (created_at) WHERE status = 'Pending';
@@ -0,0 +1,10 @@
+-- Heartbeats of Iris replicas and the scheduler locks each one holds
+CREATE TABLE IF NOT EXISTS cluster_members (
+    instance_id VARCHAR(255) PRIMARY KEY,
+    hostname VARCHAR(255) NOT NULL DEFAULT '',
+    locks TEXT[] NOT NULL DEFAULT '{}',
+    started_at TIMESTAMP NOT NULL,
+    heartbeat_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_cluster_members_heartbeat_at ON cluster_members (heartbeat_at);
@@ -0,0 +1,2 @@
+-- Failed sends go back to the outbox and wait for their backoff
+ALTER TABLE message ADD COLUMN next_attempt_at TIMESTAMP;
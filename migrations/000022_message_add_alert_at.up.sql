@@ -0,0 +1,2 @@
+-- When the notified alert state began, notification latency is measured from it
+ALTER TABLE message ADD COLUMN alert_at TIMESTAMP;
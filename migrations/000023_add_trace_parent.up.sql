@@ -0,0 +1,4 @@
+-- W3C traceparent of the request that received the alert, the scheduler and
+-- provider calls continue its trace
+ALTER TABLE alerts ADD COLUMN trace_parent TEXT;
+ALTER TABLE message ADD COLUMN trace_parent TEXT;
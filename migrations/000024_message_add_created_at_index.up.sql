@@ -0,0 +1,2 @@
+-- Message search and delivery analytics are bounded by the creation time
+CREATE INDEX IF NOT EXISTS idx_message_created_at ON message (created_at);
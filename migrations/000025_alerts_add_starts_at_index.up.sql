@@ -0,0 +1,2 @@
+-- Incident reports scan the alerts started in a range
+CREATE INDEX IF NOT EXISTS idx_alerts_starts_at ON alerts (starts_at);
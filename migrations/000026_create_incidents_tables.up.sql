@@ -0,0 +1,37 @@
+-- Incidents group related alerts, their notifications are sent per incident
+CREATE TABLE IF NOT EXISTS incidents (
+    id VARCHAR(60) PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'triggered',
+    severity VARCHAR(10) NOT NULL,
+    assignee VARCHAR(26),
+    -- Hash of the grouping labels of automatically created incidents
+    group_key VARCHAR(64),
+    acknowledged_at TIMESTAMP,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A group of alerts has a single open incident at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_incidents_open_group_key
+    ON incidents (group_key) WHERE status <> 'resolved' AND group_key IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_incidents_status_created_at ON incidents (status, created_at);
+
+CREATE TABLE IF NOT EXISTS incident_alerts (
+    incident_id VARCHAR(60) NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+    alert_id VARCHAR(100) NOT NULL,
+    linked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (incident_id, alert_id)
+);
+CREATE INDEX IF NOT EXISTS idx_incident_alerts_alert_id ON incident_alerts (alert_id);
+
+CREATE TABLE IF NOT EXISTS incident_events (
+    id VARCHAR(60) PRIMARY KEY,
+    incident_id VARCHAR(60) NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    author VARCHAR(26),
+    body TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_incident_events_incident_id ON incident_events (incident_id, created_at);
@@ -0,0 +1,12 @@
+-- Components of the public status page, their state follows the firing
+-- alerts matched by matchers
+CREATE TABLE IF NOT EXISTS status_components (
+    id VARCHAR(60) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(255),
+    matchers TEXT[] NOT NULL DEFAULT '{}',
+    position INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL
+);
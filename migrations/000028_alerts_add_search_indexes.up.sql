@@ -0,0 +1,15 @@
+-- Label matchers with = use jsonb containment on labels
+CREATE INDEX IF NOT EXISTS idx_alerts_labels ON alerts USING GIN (labels jsonb_path_ops);
+
+-- Full-text search on the name and description, the expression has to stay
+-- in sync with the one in the alert search query
+CREATE INDEX IF NOT EXISTS idx_alerts_search ON alerts
+    USING GIN (to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(description, '')));
+
+-- Keyset pagination orders by the sort column and the id. The starts_at
+-- index of the incident reports is a prefix of (starts_at, id)
+CREATE INDEX IF NOT EXISTS idx_alerts_starts_at_id ON alerts (starts_at, id);
+DROP INDEX IF EXISTS idx_alerts_starts_at;
+CREATE INDEX IF NOT EXISTS idx_alerts_created_at_id ON alerts (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_alerts_updated_at_id ON alerts (updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_alerts_status_severity ON alerts (status, severity);
@@ -0,0 +1,16 @@
+-- Bulk alert operations, one row per run
+CREATE TABLE IF NOT EXISTS alert_audit_log (
+    id VARCHAR(60) PRIMARY KEY,
+    action VARCHAR(20) NOT NULL,
+    actor VARCHAR(255),
+    -- Selector of the run: alert ids and label matchers
+    ids TEXT[],
+    filter TEXT,
+    silenced_until TIMESTAMP,
+    matched BIGINT NOT NULL DEFAULT 0,
+    affected BIGINT NOT NULL DEFAULT 0,
+    -- Alerts changed by the run
+    alert_ids TEXT[],
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_alert_audit_log_created_at ON alert_audit_log (created_at);
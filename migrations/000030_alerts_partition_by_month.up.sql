@@ -0,0 +1,56 @@
+-- alerts is partitioned by the month of created_at so queries on recent
+-- alerts and the retention janitor only touch the latest partitions. The
+-- unique keys of a partitioned table must hold the partition key, the
+-- primary key becomes (id, created_at).
+ALTER TABLE alerts RENAME TO alerts_unpartitioned;
+
+UPDATE alerts_unpartitioned SET created_at = coalesce(starts_at, now()) WHERE created_at IS NULL;
+
+CREATE TABLE alerts (LIKE alerts_unpartitioned INCLUDING DEFAULTS)
+    PARTITION BY RANGE (created_at);
+ALTER TABLE alerts ALTER COLUMN created_at SET NOT NULL;
+ALTER TABLE alerts ADD PRIMARY KEY (id, created_at);
+
+-- Catches rows outside of the monthly partitions, the janitor creates the
+-- partitions ahead of time so it stays empty
+CREATE TABLE alerts_default PARTITION OF alerts DEFAULT;
+
+-- One partition per month from the oldest alert to two months ahead,
+-- named alerts_pYYYY_MM
+DO $$
+DECLARE
+    m DATE;
+BEGIN
+    SELECT date_trunc('month', coalesce(min(created_at), now()))::date INTO m FROM alerts_unpartitioned;
+    WHILE m <= date_trunc('month', now() + interval '2 months') LOOP
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %I PARTITION OF alerts FOR VALUES FROM (%L) TO (%L)',
+            'alerts_p' || to_char(m, 'YYYY_MM'), m, (m + interval '1 month')::date);
+        m := (m + interval '1 month')::date;
+    END LOOP;
+END $$;
+
+INSERT INTO alerts SELECT * FROM alerts_unpartitioned;
+DROP TABLE alerts_unpartitioned;
+
+CREATE TRIGGER set_updated_at_trigger
+    BEFORE UPDATE ON alerts
+    FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+-- Indexes of the previous table, created on every partition
+CREATE INDEX IF NOT EXISTS idx_alerts_send_notif_false
+    ON alerts (id)
+    WHERE send_notif = false;
+CREATE INDEX IF NOT EXISTS idx_alerts_id_fingerprint_firing_status
+    ON alerts (id, fingerprint, status)
+    WHERE status = 'firing';
+CREATE INDEX IF NOT EXISTS idx_alerts_labels ON alerts USING GIN (labels jsonb_path_ops);
+CREATE INDEX IF NOT EXISTS idx_alerts_search ON alerts
+    USING GIN (to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(description, '')));
+CREATE INDEX IF NOT EXISTS idx_alerts_starts_at_id ON alerts (starts_at, id);
+CREATE INDEX IF NOT EXISTS idx_alerts_created_at_id ON alerts (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_alerts_updated_at_id ON alerts (updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_alerts_status_severity ON alerts (status, severity);
+
+-- Soft deleted alerts are purged by the janitor
+CREATE INDEX IF NOT EXISTS idx_alerts_deleted_at ON alerts (deleted_at) WHERE deleted_at IS NOT NULL;
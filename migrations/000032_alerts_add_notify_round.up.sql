@@ -0,0 +1,3 @@
+-- Renotifying an alert starts a new round, the round is part of the
+-- idempotency key of its messages so they are queued again
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS notify_round INT NOT NULL DEFAULT 0;
@@ -1,31 +1,98 @@
 package alerts
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/lib/pq"
 	"gorm.io/gorm"
 )
 
+// Alert is stored in the alerts table partitioned by month of created_at,
+// its primary key holds both so saves can upsert.
 type Alert struct {
-	Id          string         `json:"id" gorm:"column:id"`
-	FingerPrint string         `json:"fingerprint" gorm:"column:fingerprint"`
-	Name        string         `json:"name" gorm:"column:name"`
-	Severity    string         `json:"severity" gorm:"column:severity"`
-	Description string         `json:"description" gorm:"column:description"`
-	StartsAt    time.Time      `json:"starts_at" gorm:"column:starts_at"`
-	EndsAt      time.Time      `json:"ends_at" gorm:"column:ends_at"`
-	Status      string         `json:"status" gorm:"column:status"`
-	Method      pq.StringArray `json:"-" gorm:"column:method;type:text[]"`
-	Receptor    pq.StringArray `json:"-" gorm:"column:receptor;type:text[]"`
-	SendNotif   bool           `json:"-" gorm:"column:send_notif;default:false"`
-	Silenced    bool           `json:"-" gorm:"column:silenced;default:false"`
-	CreatedAt   time.Time      `json:"created_at" gorm:"column:created_at"`
-	UpdatedAt   time.Time      `json:"updated_at" gorm:"column:updated_at"`
-	gorm.DeletedAt
+	Id             string         `json:"id" gorm:"column:id;primaryKey"`
+	FingerPrint    string         `json:"fingerprint" gorm:"column:fingerprint"`
+	Name           string         `json:"name" gorm:"column:name"`
+	Severity       string         `json:"severity" gorm:"column:severity"`
+	Description    string         `json:"description" gorm:"column:description"`
+	StartsAt       time.Time      `json:"starts_at" gorm:"column:starts_at"`
+	EndsAt         time.Time      `json:"ends_at" gorm:"column:ends_at"`
+	Status         string         `json:"status" gorm:"column:status"`
+	Method         pq.StringArray `json:"-" gorm:"column:method;type:text[]"`
+	Receptor       pq.StringArray `json:"-" gorm:"column:receptor;type:text[]"`
+	Labels         Labels         `json:"labels" gorm:"column:labels;type:jsonb"`
+	SendNotif      bool           `json:"-" gorm:"column:send_notif;default:false"`
+	NotifyRound    int            `json:"-" gorm:"column:notify_round;default:0"`
+	Silenced       bool           `json:"-" gorm:"column:silenced;default:false"`
+	SilencedUntil  *time.Time     `json:"silenced_until,omitempty" gorm:"column:silenced_until"`
+	AcknowledgedAt *time.Time     `json:"acknowledged_at,omitempty" gorm:"column:acknowledged_at"`
+	AcknowledgedBy string         `json:"acknowledged_by,omitempty" gorm:"column:acknowledged_by"`
+	TraceParent    string         `json:"-" gorm:"column:trace_parent"`
+	CreatedAt      time.Time      `json:"created_at" gorm:"column:created_at;primaryKey"`
+	UpdatedAt      time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"column:deleted_at"`
+
+	// event is published when the alert is saved, NewAlert sets it when
+	// the alert is new or changed status.
+	event EventType
 }
 
+// LabelInternal marks alerts raised by Iris itself, such as low provider
+// credit. They only go out through the methods they list.
+const LabelInternal = "iris_internal"
+
 type AlertsBySeverity struct {
 	Severity string `json:"severity"`
 	Count    int64  `json:"count"`
 }
+
+// Labels holds every label received with the alert and is stored as jsonb.
+type Labels map[string]string
+
+func (l Labels) Value() (driver.Value, error) {
+	if l == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(l)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (l *Labels) Scan(src any) error {
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		*l = Labels{}
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errors.New("unsupported type for alert labels")
+	}
+	return json.Unmarshal(b, l)
+}
+
+// LabelSet returns the alert labels merged with the alert's own fields so
+// matchers can select on alertname, severity and status as well.
+func (a *Alert) LabelSet() map[string]string {
+	ls := make(map[string]string, len(a.Labels)+3)
+	for k, v := range a.Labels {
+		ls[k] = v
+	}
+	ls["alertname"] = a.Name
+	ls["severity"] = a.Severity
+	ls["status"] = a.Status
+	return ls
+}
+
+// Internal reports whether the alert was raised by Iris itself.
+func (a *Alert) Internal() bool {
+	return a.Labels[LabelInternal] == "true"
+}
@@ -1,42 +1,65 @@
 package alerts
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	iris_error "github.com/root-ali/iris/pkg/errors"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 type AlertRepository interface {
-	AddAlert(*Alert) (int64, error)
+	AddAlert(ctx context.Context, alert *Alert) (int64, error)
 	GetAlertById(id string) (*Alert, error)
 	AlertsBySeverity() ([]*AlertsBySeverity, error)
 	GetAlerts(string, string, int, int) ([]*Alert, error)
+	SearchAlerts(q Query) ([]*Alert, int64, error)
 	GetUnsentAlerts() ([]Alert, error)
 	MarkAlertAsSent(alertID string) error
+	MarkAlertAsSilenced(alertID string) error
+	AcknowledgeAlert(alertID, userID string, at time.Time) error
+	SilenceAlert(alertID string, until time.Time) error
+	ResolveAlert(alertID string, at time.Time) error
 	GetUnsentAlertID(alert Alert) (string, error)
 	GetAlertByFingerPrintAndStatus(fingerPrint, status string) (*Alert, error)
+	GetAlertByFingerPrintAndStartsAt(fingerPrint string, startsAt time.Time) (*Alert, error)
+	CountBulkAlerts(req BulkRequest) (int64, error)
+	// ApplyBulkAlerts applies req and saves entry in one transaction, it
+	// fills the counts and alert ids of entry and returns the changed alerts.
+	ApplyBulkAlerts(req BulkRequest, entry *AuditEntry) ([]*Alert, error)
+	GetAlertAuditLog(limit, page int) ([]*AuditEntry, error)
 }
 
 type Service interface {
-	AddAlertManagerAlerts([]Alert) (int64, error)
+	AddAlertManagerAlerts(ctx context.Context, alerts []Alert) (int64, error)
 	NewAlert(id, name, severity, description, status string, method []string,
 		startsAt, endsAt time.Time,
 		receptor []string) (Alert, error)
 	GetFiringAlertsBySeverity() ([]*AlertsBySeverity, error)
 	GetAlerts(string, string, int, int) ([]*Alert, error)
+	SearchAlerts(q Query) (*SearchResult, error)
+	AcknowledgeAlert(id, userID string) (*Alert, error)
+	SilenceAlert(id string, d time.Duration) (*Alert, error)
+	ResolveAlert(id string) (*Alert, error)
+	BulkAlerts(req BulkRequest) (*BulkResult, error)
+	GetAuditLog(limit, page int) ([]*AuditEntry, error)
+	ImportAlerts(ctx context.Context, records []Record, notify bool) *ImportResult
 }
 
 type alertsService struct {
-	log *zap.SugaredLogger
-	ar  AlertRepository
+	log    *zap.SugaredLogger
+	ar     AlertRepository
+	events EventPublisher
 }
 
-func NewAlertService(l *zap.SugaredLogger, ai AlertRepository) Service {
-	return &alertsService{l, ai}
+// NewAlertService returns the alert service, changes of alerts are sent to
+// events when it is not nil.
+func NewAlertService(l *zap.SugaredLogger, ai AlertRepository, events EventPublisher) Service {
+	return &alertsService{log: l, ar: ai, events: events}
 }
 
 func (as *alertsService) NewAlert(fingerprint, name, severity, description, status string,
@@ -61,6 +84,10 @@ func (as *alertsService) NewAlert(fingerprint, name, severity, description, stat
 		als.Silenced = false
 		als.CreatedAt = time.Now()
 		als.UpdatedAt = time.Now()
+		als.event = EventCreated
+		if status == "resolved" {
+			als.event = EventResolved
+		}
 		return als, nil
 	} else if err != nil {
 		as.log.Error("error checking alert in database", err)
@@ -76,31 +103,51 @@ func (as *alertsService) NewAlert(fingerprint, name, severity, description, stat
 	als.StartsAt = checkAlert.StartsAt
 	als.EndsAt = endsAt
 	als.Receptor = checkAlert.Receptor
+	als.Labels = checkAlert.Labels
 	als.Silenced = checkAlert.Silenced
+	als.SilencedUntil = checkAlert.SilencedUntil
+	als.AcknowledgedAt = checkAlert.AcknowledgedAt
+	als.AcknowledgedBy = checkAlert.AcknowledgedBy
 	als.CreatedAt = checkAlert.CreatedAt
 	als.UpdatedAt = time.Now()
 	if status != checkAlert.Status {
 		als.SendNotif = false
+		als.event = EventUpdated
+		if status == "resolved" {
+			als.event = EventResolved
+		}
 	} else {
 		als.SendNotif = checkAlert.SendNotif
 	}
 	return als, nil
 }
 
-func (as *alertsService) AddAlertManagerAlerts(alerts []Alert) (int64, error) {
+func (as *alertsService) AddAlertManagerAlerts(ctx context.Context, alerts []Alert) (int64, error) {
 	var num int64 = 0
+	statusChanged := false
+	defer func() {
+		if statusChanged {
+			as.publishFiringCount()
+		}
+	}()
 	as.log.Infow("we are going to save alerts", "alerts", alerts)
 	for _, alert := range alerts {
 
 		as.log.Info("we are going to save alerts")
 
-		r, err := as.ar.AddAlert(&alert)
+		r, err := as.ar.AddAlert(ctx, &alert)
 		if err != nil {
 			as.log.Error("Error in Saving to the Database ", err)
 			return num, err
 		} else {
 			as.log.Info(alert.Name, "is saved to the db")
+			alertsReceived.WithLabelValues(alert.source(), alert.Status).Inc()
 			num += r
+			// re-sent alerts without a change are not published
+			if alert.event != "" {
+				as.publish(alert.event, &alert)
+				statusChanged = true
+			}
 		}
 	}
 	return num, nil
@@ -125,3 +172,72 @@ func (as *alertsService) GetFiringAlertsBySeverity() ([]*AlertsBySeverity, error
 	}
 	return als, nil
 }
+
+// AcknowledgeAlert records that userID is working on the alert.
+func (as *alertsService) AcknowledgeAlert(id, userID string) (*Alert, error) {
+	if _, err := as.getAlert(id); err != nil {
+		return nil, err
+	}
+	if err := as.ar.AcknowledgeAlert(id, userID, time.Now()); err != nil {
+		as.log.Errorw("Error acknowledging alert", "id", id, "error", err)
+		return nil, err
+	}
+	as.log.Infow("Alert acknowledged", "id", id, "userID", userID)
+	return as.getAndPublish(id, EventUpdated)
+}
+
+// SilenceAlert stops notifications of the alert for d.
+func (as *alertsService) SilenceAlert(id string, d time.Duration) (*Alert, error) {
+	if d <= 0 {
+		return nil, errors.New("silence duration must be positive")
+	}
+	if _, err := as.getAlert(id); err != nil {
+		return nil, err
+	}
+	if err := as.ar.SilenceAlert(id, time.Now().Add(d)); err != nil {
+		as.log.Errorw("Error silencing alert", "id", id, "error", err)
+		return nil, err
+	}
+	as.log.Infow("Alert silenced", "id", id, "duration", d)
+	return as.getAndPublish(id, EventUpdated)
+}
+
+// ResolveAlert resolves a firing alert by hand. The resolved notification is
+// sent by the alert scheduler.
+func (as *alertsService) ResolveAlert(id string) (*Alert, error) {
+	al, err := as.getAlert(id)
+	if err != nil {
+		return nil, err
+	}
+	if al.Status != "firing" {
+		return nil, errors.New("alert is not firing")
+	}
+	if err := as.ar.ResolveAlert(id, time.Now()); err != nil {
+		as.log.Errorw("Error resolving alert", "id", id, "error", err)
+		return nil, err
+	}
+	as.log.Infow("Alert resolved", "id", id)
+	al, err = as.getAndPublish(id, EventResolved)
+	if err == nil {
+		as.publishFiringCount()
+	}
+	return al, err
+}
+
+func (as *alertsService) getAlert(id string) (*Alert, error) {
+	al, err := as.ar.GetAlertById(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, iris_error.ErrAlertNotFound
+	}
+	return al, err
+}
+
+// getAndPublish returns the alert as saved and publishes it.
+func (as *alertsService) getAndPublish(id string, t EventType) (*Alert, error) {
+	al, err := as.getAlert(id)
+	if err != nil {
+		return nil, err
+	}
+	as.publish(t, al)
+	return al, nil
+}
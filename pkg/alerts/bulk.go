@@ -0,0 +1,167 @@
+package alerts
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/root-ali/iris/pkg/matchers"
+)
+
+type BulkAction string
+
+const (
+	BulkResolve     BulkAction = "resolve"
+	BulkAcknowledge BulkAction = "ack"
+	BulkSilence     BulkAction = "silence"
+	BulkDelete      BulkAction = "delete"
+	BulkRenotify    BulkAction = "renotify"
+)
+
+func (a BulkAction) valid() bool {
+	switch a {
+	case BulkResolve, BulkAcknowledge, BulkSilence, BulkDelete, BulkRenotify:
+		return true
+	}
+	return false
+}
+
+// MaxBulkIDs is the largest list of alert ids a bulk request can select.
+const MaxBulkIDs = 1000
+
+// BulkRequest applies Action to the alerts selected by IDs and Matchers,
+// an alert must match both when both are set.
+type BulkRequest struct {
+	Action   BulkAction
+	IDs      []string
+	Matchers matchers.Matchers
+	// SilenceFor is how long BulkSilence silences the alerts.
+	SilenceFor time.Duration
+	// DryRun only counts the selected alerts.
+	DryRun bool
+	Actor  string
+}
+
+type BulkResult struct {
+	Action   BulkAction `json:"action"`
+	DryRun   bool       `json:"dry_run"`
+	Matched  int64      `json:"matched"`
+	Affected int64      `json:"affected"`
+	AuditID  string     `json:"audit_id,omitempty"`
+}
+
+// AuditEntry records a bulk run. Matched counts the selected alerts and
+// AlertIDs the ones the action changed, alerts already resolved are not
+// resolved again for instance.
+type AuditEntry struct {
+	ID            string         `json:"id" gorm:"column:id"`
+	Action        BulkAction     `json:"action" gorm:"column:action"`
+	Actor         string         `json:"actor" gorm:"column:actor"`
+	IDs           pq.StringArray `json:"ids,omitempty" gorm:"column:ids;type:text[]"`
+	Filter        string         `json:"filter,omitempty" gorm:"column:filter"`
+	SilencedUntil *time.Time     `json:"silenced_until,omitempty" gorm:"column:silenced_until"`
+	Matched       int64          `json:"matched" gorm:"column:matched"`
+	Affected      int64          `json:"affected" gorm:"column:affected"`
+	AlertIDs      pq.StringArray `json:"alert_ids" gorm:"column:alert_ids;type:text[]"`
+	CreatedAt     time.Time      `json:"created_at" gorm:"column:created_at"`
+}
+
+func (AuditEntry) TableName() string { return "alert_audit_log" }
+
+func validateBulkRequest(req *BulkRequest) error {
+	if !req.Action.valid() {
+		return fmt.Errorf("%w: unknown action %q", iris_error.ErrInvalidBulkRequest, req.Action)
+	}
+	// An empty selector would apply the action to every alert
+	if len(req.IDs) == 0 && len(req.Matchers) == 0 {
+		return fmt.Errorf("%w: ids or filter is required", iris_error.ErrInvalidBulkRequest)
+	}
+	if len(req.IDs) > MaxBulkIDs {
+		return fmt.Errorf("%w: at most %d ids can be selected", iris_error.ErrInvalidBulkRequest, MaxBulkIDs)
+	}
+	if req.Action == BulkSilence && req.SilenceFor <= 0 {
+		return fmt.Errorf("%w: silence duration must be positive", iris_error.ErrInvalidBulkRequest)
+	}
+	return nil
+}
+
+// BulkAlerts applies the action of req to the selected alerts in a single
+// transaction and records the run in the audit log. A dry run returns how
+// many alerts are selected without changing or recording anything.
+func (as *alertsService) BulkAlerts(req BulkRequest) (*BulkResult, error) {
+	if err := validateBulkRequest(&req); err != nil {
+		return nil, err
+	}
+	if req.DryRun {
+		matched, err := as.ar.CountBulkAlerts(req)
+		if err != nil {
+			as.log.Errorw("Error counting alerts of bulk request", "action", req.Action, "error", err)
+			return nil, err
+		}
+		return &BulkResult{Action: req.Action, DryRun: true, Matched: matched}, nil
+	}
+
+	now := time.Now()
+	entry := &AuditEntry{
+		ID:        uuid.New().String(),
+		Action:    req.Action,
+		Actor:     req.Actor,
+		IDs:       req.IDs,
+		Filter:    strings.Join(req.Matchers.Strings(), ","),
+		CreatedAt: now,
+	}
+	if req.Action == BulkSilence {
+		until := now.Add(req.SilenceFor)
+		entry.SilencedUntil = &until
+	}
+	changed, err := as.ar.ApplyBulkAlerts(req, entry)
+	if err != nil {
+		as.log.Errorw("Error applying bulk alert action", "action", req.Action, "error", err)
+		return nil, err
+	}
+	bulkOperations.WithLabelValues(string(req.Action)).Inc()
+	as.log.Infow("Bulk alert action applied", "action", req.Action, "actor", req.Actor,
+		"matched", entry.Matched, "affected", entry.Affected, "audit_id", entry.ID)
+
+	t := EventUpdated
+	switch req.Action {
+	case BulkResolve:
+		t = EventResolved
+	case BulkDelete:
+		t = EventDeleted
+	}
+	for _, al := range changed {
+		as.publish(t, al)
+	}
+	if len(changed) > 0 && (req.Action == BulkResolve || req.Action == BulkDelete) {
+		as.publishFiringCount()
+	}
+	return &BulkResult{
+		Action:   req.Action,
+		Matched:  entry.Matched,
+		Affected: entry.Affected,
+		AuditID:  entry.ID,
+	}, nil
+}
+
+// GetAuditLog returns a page of the bulk runs, the latest first.
+func (as *alertsService) GetAuditLog(limit, page int) ([]*AuditEntry, error) {
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if page < 1 {
+		page = 1
+	}
+	entries, err := as.ar.GetAlertAuditLog(limit, page)
+	if err != nil {
+		as.log.Errorw("Error getting alert audit log", "error", err)
+		return nil, err
+	}
+	if entries == nil {
+		entries = []*AuditEntry{}
+	}
+	return entries, nil
+}
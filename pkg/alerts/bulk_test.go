@@ -0,0 +1,90 @@
+package alerts
+
+import (
+	"errors"
+	"testing"
+
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/root-ali/iris/pkg/matchers"
+	"go.uber.org/zap"
+)
+
+type bulkRepo struct {
+	memAlerts
+	applied []*AuditEntry
+	changed []*Alert
+	counted int
+}
+
+func (r *bulkRepo) CountBulkAlerts(BulkRequest) (int64, error) {
+	r.counted++
+	return 3, nil
+}
+
+func (r *bulkRepo) ApplyBulkAlerts(req BulkRequest, entry *AuditEntry) ([]*Alert, error) {
+	entry.Matched = 3
+	entry.Affected = int64(len(r.changed))
+	r.applied = append(r.applied, entry)
+	return r.changed, nil
+}
+
+func TestBulkAlertsValidatesRequest(t *testing.T) {
+	as := NewAlertService(zap.NewNop().Sugar(), &bulkRepo{}, nil)
+	ms, _ := matchers.ParseList(`{team="db"}`)
+	for name, req := range map[string]BulkRequest{
+		"action":   {Action: "archive", IDs: []string{"a1"}},
+		"selector": {Action: BulkResolve},
+		"too many": {Action: BulkResolve, IDs: make([]string, MaxBulkIDs+1)},
+		"silence":  {Action: BulkSilence, Matchers: ms},
+	} {
+		if _, err := as.BulkAlerts(req); !errors.Is(err, iris_error.ErrInvalidBulkRequest) {
+			t.Errorf("%s: err = %v, want ErrInvalidBulkRequest", name, err)
+		}
+	}
+}
+
+func TestBulkAlertsDryRunDoesNotApply(t *testing.T) {
+	repo := &bulkRepo{}
+	as := NewAlertService(zap.NewNop().Sugar(), repo, nil)
+	res, err := as.BulkAlerts(BulkRequest{Action: BulkDelete, IDs: []string{"a1"}, DryRun: true})
+	if err != nil {
+		t.Fatalf("BulkAlerts: %v", err)
+	}
+	if !res.DryRun || res.Matched != 3 || res.AuditID != "" {
+		t.Errorf("result = %+v", res)
+	}
+	if repo.counted != 1 || len(repo.applied) != 0 {
+		t.Errorf("counted %d, applied %d", repo.counted, len(repo.applied))
+	}
+}
+
+func TestBulkAlertsRecordsAndPublishes(t *testing.T) {
+	repo := &bulkRepo{changed: []*Alert{{Id: "a1"}, {Id: "a2"}}}
+	rec := &recorder{}
+	as := NewAlertService(zap.NewNop().Sugar(), repo, rec)
+	ms, _ := matchers.ParseList(`{team="db"}`)
+	res, err := as.BulkAlerts(BulkRequest{Action: BulkResolve, Matchers: ms, Actor: "u1"})
+	if err != nil {
+		t.Fatalf("BulkAlerts: %v", err)
+	}
+	if len(repo.applied) != 1 {
+		t.Fatalf("applied %d runs, want 1", len(repo.applied))
+	}
+	entry := repo.applied[0]
+	if entry.ID != res.AuditID || entry.Actor != "u1" || entry.Filter != `team="db"` {
+		t.Errorf("audit entry = %+v", entry)
+	}
+	if res.Matched != 3 || res.Affected != 2 {
+		t.Errorf("result = %+v", res)
+	}
+	want := []EventType{EventResolved, EventResolved, EventFiringCount}
+	got := rec.types()
+	if len(got) != len(want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("events = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package alerts
+
+import "time"
+
+type EventType string
+
+const (
+	EventCreated     EventType = "created"
+	EventUpdated     EventType = "updated"
+	EventResolved    EventType = "resolved"
+	EventDeleted     EventType = "deleted"
+	EventFiringCount EventType = "firing_count"
+)
+
+// Event is a change of an alert saved by the service, or the new firing
+// count per severity after alerts started or stopped firing.
+type Event struct {
+	Type        EventType           `json:"type"`
+	Alert       *Alert              `json:"alert,omitempty"`
+	FiringCount []*AlertsBySeverity `json:"firing_count,omitempty"`
+	At          time.Time           `json:"at"`
+}
+
+// EventPublisher receives the events of the service, it must not block.
+type EventPublisher interface {
+	Publish(ev Event)
+}
+
+func (as *alertsService) publish(t EventType, al *Alert) {
+	if as.events == nil {
+		return
+	}
+	as.events.Publish(Event{Type: t, Alert: al, At: time.Now()})
+}
+
+// publishFiringCount sends the firing count after alerts changed status.
+func (as *alertsService) publishFiringCount() {
+	if as.events == nil {
+		return
+	}
+	counts, err := as.ar.AlertsBySeverity()
+	if err != nil {
+		as.log.Errorw("Error counting firing alerts", "error", err)
+		return
+	}
+	as.events.Publish(Event{Type: EventFiringCount, FiringCount: counts, At: time.Now()})
+}
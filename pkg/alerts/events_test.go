@@ -0,0 +1,77 @@
+package alerts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type memAlerts struct {
+	AlertRepository
+	byFingerprint map[string]*Alert
+}
+
+func (r *memAlerts) GetAlertByFingerPrintAndStatus(fp, status string) (*Alert, error) {
+	if al, ok := r.byFingerprint[fp]; ok && al.Status == status {
+		return al, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memAlerts) AddAlert(_ context.Context, al *Alert) (int64, error) {
+	c := *al
+	r.byFingerprint[al.FingerPrint] = &c
+	return 1, nil
+}
+
+func (r *memAlerts) AlertsBySeverity() ([]*AlertsBySeverity, error) {
+	return []*AlertsBySeverity{{Severity: "critical", Count: 1}}, nil
+}
+
+type recorder struct{ events []Event }
+
+func (r *recorder) Publish(ev Event) { r.events = append(r.events, ev) }
+
+func (r *recorder) types() []EventType {
+	ts := make([]EventType, 0, len(r.events))
+	for _, ev := range r.events {
+		ts = append(ts, ev.Type)
+	}
+	return ts
+}
+
+func TestAddAlertManagerAlertsPublishesChanges(t *testing.T) {
+	rec := &recorder{}
+	as := NewAlertService(zap.NewNop().Sugar(), &memAlerts{byFingerprint: map[string]*Alert{}}, rec)
+	save := func(status string) {
+		t.Helper()
+		al, err := as.NewAlert("fp1", "Down", "critical", "", status, nil, time.Now(), time.Now(), nil)
+		if err != nil {
+			t.Fatalf("NewAlert: %v", err)
+		}
+		if _, err := as.AddAlertManagerAlerts(context.Background(), []Alert{al}); err != nil {
+			t.Fatalf("AddAlertManagerAlerts: %v", err)
+		}
+	}
+
+	save("firing")
+	save("firing") // re-sent by Alertmanager without a change
+	save("resolved")
+
+	want := []EventType{EventCreated, EventFiringCount, EventResolved, EventFiringCount}
+	got := rec.types()
+	if len(got) != len(want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("events = %v, want %v", got, want)
+		}
+	}
+	if rec.events[0].Alert == nil || rec.events[0].Alert.Name != "Down" {
+		t.Errorf("created event alert = %+v", rec.events[0].Alert)
+	}
+}
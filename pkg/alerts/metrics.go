@@ -0,0 +1,29 @@
+package alerts
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var alertsReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "iris_alerts_received_total",
+	Help: "Alerts saved by source, alertmanager or internal, and status.",
+}, []string{"source", "status"})
+
+var alertsImported = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "iris_alerts_imported_total",
+	Help: "Alerts saved by imports, by status.",
+}, []string{"status"})
+
+// source returns where the alert came from for metrics.
+func (a *Alert) source() string {
+	if a.Internal() {
+		return "internal"
+	}
+	return "alertmanager"
+}
+
+var bulkOperations = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "iris_alert_bulk_operations_total",
+	Help: "Bulk alert operations applied, dry runs excluded, by action.",
+}, []string{"action"})
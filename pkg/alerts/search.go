@@ -0,0 +1,162 @@
+package alerts
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/root-ali/iris/pkg/matchers"
+)
+
+type SortField string
+
+const (
+	SortStartsAt  SortField = "starts_at"
+	SortCreatedAt SortField = "created_at"
+	SortUpdatedAt SortField = "updated_at"
+	SortSeverity  SortField = "severity"
+	SortName      SortField = "name"
+)
+
+// Time reports whether the field is a timestamp column, cursor values of
+// those are kept as RFC3339 and compared as timestamps.
+func (f SortField) Time() bool {
+	return f == SortStartsAt || f == SortCreatedAt || f == SortUpdatedAt
+}
+
+func (f SortField) valid() bool {
+	return f.Time() || f == SortSeverity || f == SortName
+}
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 500
+)
+
+// Query selects alerts by label matchers, a full-text search on the name
+// and description and a range of starts_at. Results are ordered by Sort
+// and the alert id, and paged with the opaque Cursor of the previous page.
+type Query struct {
+	Matchers matchers.Matchers
+	Text     string
+	From     *time.Time
+	To       *time.Time
+	Sort     SortField
+	Desc     bool
+	Limit    int
+	Cursor   string
+	// After is decoded from Cursor by the service.
+	After *Cursor
+}
+
+// Cursor is the position of the last alert of a page.
+type Cursor struct {
+	Sort  SortField `json:"s"`
+	Desc  bool      `json:"d"`
+	Value string    `json:"v"`
+	ID    string    `json:"i"`
+}
+
+type SearchResult struct {
+	Alerts     []*Alert `json:"alerts"`
+	Total      int64    `json:"total"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+func (c Cursor) encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// sortValue returns the value of f for a, in the form the cursor keeps it.
+func (a *Alert) sortValue(f SortField) string {
+	switch f {
+	case SortCreatedAt:
+		return a.CreatedAt.Format(time.RFC3339Nano)
+	case SortUpdatedAt:
+		return a.UpdatedAt.Format(time.RFC3339Nano)
+	case SortSeverity:
+		return a.Severity
+	case SortName:
+		return a.Name
+	default:
+		return a.StartsAt.Format(time.RFC3339Nano)
+	}
+}
+
+func normalizeQuery(q *Query) error {
+	if q.Sort == "" {
+		q.Sort = SortStartsAt
+	}
+	if !q.Sort.valid() {
+		return fmt.Errorf("%w: unknown sort field %q", iris_error.ErrInvalidAlertQuery, q.Sort)
+	}
+	if q.From != nil && q.To != nil && !q.From.Before(*q.To) {
+		return fmt.Errorf("%w: from must be before to", iris_error.ErrInvalidAlertQuery)
+	}
+	if q.Limit <= 0 {
+		q.Limit = defaultSearchLimit
+	}
+	if q.Limit > maxSearchLimit {
+		q.Limit = maxSearchLimit
+	}
+	q.After = nil
+	if q.Cursor == "" {
+		return nil
+	}
+	c, err := decodeCursor(q.Cursor)
+	if err != nil {
+		return fmt.Errorf("%w: malformed cursor", iris_error.ErrInvalidAlertQuery)
+	}
+	// A cursor only makes sense in the order it was taken in
+	if c.Sort != q.Sort || c.Desc != q.Desc {
+		return fmt.Errorf("%w: cursor does not match the sort order", iris_error.ErrInvalidAlertQuery)
+	}
+	if c.Sort.Time() {
+		if _, err := time.Parse(time.RFC3339Nano, c.Value); err != nil {
+			return fmt.Errorf("%w: malformed cursor", iris_error.ErrInvalidAlertQuery)
+		}
+	}
+	q.After = c
+	return nil
+}
+
+// SearchAlerts returns a page of the alerts selected by q and their total
+// count.
+func (as *alertsService) SearchAlerts(q Query) (*SearchResult, error) {
+	if err := normalizeQuery(&q); err != nil {
+		return nil, err
+	}
+	// One extra row tells whether there is a next page
+	limit := q.Limit
+	q.Limit++
+	als, total, err := as.ar.SearchAlerts(q)
+	if err != nil {
+		as.log.Errorw("Error searching alerts", "error", err)
+		return nil, err
+	}
+	if als == nil {
+		als = []*Alert{}
+	}
+	res := &SearchResult{Alerts: als, Total: total}
+	if len(als) > limit {
+		res.Alerts = als[:limit]
+		last := res.Alerts[limit-1]
+		res.NextCursor = Cursor{Sort: q.Sort, Desc: q.Desc, Value: last.sortValue(q.Sort), ID: last.Id}.encode()
+	}
+	return res, nil
+}
@@ -0,0 +1,112 @@
+package alerts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// pageRepo serves the alerts in order after the cursor, like the keyset
+// query of the storage.
+type pageRepo struct {
+	AlertRepository
+	alerts []*Alert
+	last   Query
+}
+
+func (r *pageRepo) SearchAlerts(q Query) ([]*Alert, int64, error) {
+	r.last = q
+	start := 0
+	if q.After != nil {
+		for i, a := range r.alerts {
+			if a.Id == q.After.ID {
+				start = i + 1
+			}
+		}
+	}
+	end := start + q.Limit
+	if end > len(r.alerts) {
+		end = len(r.alerts)
+	}
+	return r.alerts[start:end], int64(len(r.alerts)), nil
+}
+
+func newPageRepo(n int) *pageRepo {
+	r := &pageRepo{}
+	now := time.Now()
+	for i := 0; i < n; i++ {
+		r.alerts = append(r.alerts, &Alert{Id: fmt.Sprintf("a%d", i), StartsAt: now.Add(-time.Duration(i) * time.Minute)})
+	}
+	return r
+}
+
+func TestSearchAlertsPagesWithCursor(t *testing.T) {
+	repo := newPageRepo(5)
+	as := &alertsService{log: zap.NewNop().Sugar(), ar: repo}
+
+	var ids []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("cursor does not advance")
+		}
+		res, err := as.SearchAlerts(Query{Desc: true, Limit: 2, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("SearchAlerts: %v", err)
+		}
+		if res.Total != 5 {
+			t.Errorf("total = %d, want 5", res.Total)
+		}
+		for _, a := range res.Alerts {
+			ids = append(ids, a.Id)
+		}
+		if res.NextCursor == "" {
+			break
+		}
+		cursor = res.NextCursor
+	}
+	if got := fmt.Sprint(ids); got != "[a0 a1 a2 a3 a4]" {
+		t.Errorf("ids = %s", got)
+	}
+	if repo.last.Sort != SortStartsAt {
+		t.Errorf("default sort = %q, want starts_at", repo.last.Sort)
+	}
+}
+
+func TestSearchAlertsRejectsInvalidQueries(t *testing.T) {
+	as := &alertsService{log: zap.NewNop().Sugar(), ar: newPageRepo(3)}
+	res, err := as.SearchAlerts(Query{Sort: SortName, Limit: 1})
+	if err != nil {
+		t.Fatalf("SearchAlerts: %v", err)
+	}
+	now := time.Now()
+	before := now.Add(-time.Hour)
+	for name, q := range map[string]Query{
+		"sort":           {Sort: "fingerprint"},
+		"range":          {From: &now, To: &before},
+		"cursor":         {Cursor: "not a cursor"},
+		"cursor changed": {Sort: SortName, Desc: true, Cursor: res.NextCursor},
+	} {
+		if _, err := as.SearchAlerts(q); !errors.Is(err, iris_error.ErrInvalidAlertQuery) {
+			t.Errorf("%s: err = %v, want ErrInvalidAlertQuery", name, err)
+		}
+	}
+}
+
+// Search results are returned as alerts, a promoted MarshalJSON would
+// encode every alert as null.
+func TestAlertMarshalsFields(t *testing.T) {
+	b, err := json.Marshal(&Alert{Id: "a1", Labels: Labels{"team": "db"}})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if !strings.Contains(string(b), `"id":"a1"`) || !strings.Contains(string(b), `"team":"db"`) {
+		t.Errorf("alert json = %s", b)
+	}
+}
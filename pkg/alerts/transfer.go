@@ -0,0 +1,266 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// Record is an alert as it is exported and imported, with the routing
+// fields the API leaves out so an import notifies the same receptors.
+type Record struct {
+	ID             string     `json:"id"`
+	Fingerprint    string     `json:"fingerprint"`
+	Name           string     `json:"name"`
+	Severity       string     `json:"severity"`
+	Description    string     `json:"description"`
+	Status         string     `json:"status"`
+	StartsAt       time.Time  `json:"starts_at"`
+	EndsAt         time.Time  `json:"ends_at"`
+	Labels         Labels     `json:"labels"`
+	Method         []string   `json:"method"`
+	Receptor       []string   `json:"receptor"`
+	SilencedUntil  *time.Time `json:"silenced_until,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy string     `json:"acknowledged_by,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func (a *Alert) Record() Record {
+	return Record{
+		ID:             a.Id,
+		Fingerprint:    a.FingerPrint,
+		Name:           a.Name,
+		Severity:       a.Severity,
+		Description:    a.Description,
+		Status:         a.Status,
+		StartsAt:       a.StartsAt,
+		EndsAt:         a.EndsAt,
+		Labels:         a.Labels,
+		Method:         a.Method,
+		Receptor:       a.Receptor,
+		SilencedUntil:  a.SilencedUntil,
+		AcknowledgedAt: a.AcknowledgedAt,
+		AcknowledgedBy: a.AcknowledgedBy,
+		CreatedAt:      a.CreatedAt,
+		UpdatedAt:      a.UpdatedAt,
+	}
+}
+
+// CSVColumns are the columns of an alert in CSV exports, labels are a JSON
+// object and method and receptor are comma separated.
+var CSVColumns = []string{
+	"id", "fingerprint", "name", "severity", "description", "status",
+	"starts_at", "ends_at", "labels", "method", "receptor",
+	"silenced_until", "acknowledged_at", "acknowledged_by",
+	"created_at", "updated_at",
+}
+
+func (r Record) CSV() []string {
+	labels, _ := json.Marshal(r.Labels)
+	return []string{
+		r.ID, r.Fingerprint, r.Name, r.Severity, r.Description, r.Status,
+		formatTime(&r.StartsAt), formatTime(&r.EndsAt), string(labels),
+		strings.Join(r.Method, ","), strings.Join(r.Receptor, ","),
+		formatTime(r.SilencedUntil), formatTime(r.AcknowledgedAt), r.AcknowledgedBy,
+		formatTime(&r.CreatedAt), formatTime(&r.UpdatedAt),
+	}
+}
+
+// RecordFromCSV reads a row by the column names of header, unknown columns
+// are ignored.
+func RecordFromCSV(header, row []string) (Record, error) {
+	var r Record
+	var err error
+	for i, col := range header {
+		if i >= len(row) {
+			break
+		}
+		v := row[i]
+		switch col {
+		case "id":
+			r.ID = v
+		case "fingerprint":
+			r.Fingerprint = v
+		case "name":
+			r.Name = v
+		case "severity":
+			r.Severity = v
+		case "description":
+			r.Description = v
+		case "status":
+			r.Status = v
+		case "labels":
+			if v != "" {
+				err = json.Unmarshal([]byte(v), &r.Labels)
+			}
+		case "method":
+			r.Method = splitList(v)
+		case "receptor":
+			r.Receptor = splitList(v)
+		case "acknowledged_by":
+			r.AcknowledgedBy = v
+		case "starts_at":
+			err = parseTime(v, &r.StartsAt)
+		case "ends_at":
+			err = parseTime(v, &r.EndsAt)
+		case "created_at":
+			err = parseTime(v, &r.CreatedAt)
+		case "updated_at":
+			err = parseTime(v, &r.UpdatedAt)
+		case "silenced_until":
+			r.SilencedUntil, err = parseOptionalTime(v)
+		case "acknowledged_at":
+			r.AcknowledgedAt, err = parseOptionalTime(v)
+		}
+		if err != nil {
+			return r, fmt.Errorf("invalid %s: %w", col, err)
+		}
+	}
+	return r, nil
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+func parseTime(v string, dst *time.Time) error {
+	if v == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return err
+	}
+	*dst = t
+	return nil
+}
+
+func parseOptionalTime(v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, v)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func splitList(v string) []string {
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
+
+type ImportResult struct {
+	Imported int `json:"imported"`
+	// Skipped counts the records of alerts that were already stored.
+	Skipped int           `json:"skipped"`
+	Failed  int           `json:"failed"`
+	Errors  []ImportError `json:"errors,omitempty"`
+}
+
+// ImportError is the error of the record at Index of an import.
+type ImportError struct {
+	Index   int    `json:"index"`
+	Message string `json:"message"`
+}
+
+func validateRecord(r *Record) error {
+	switch {
+	case r.Fingerprint == "":
+		return fmt.Errorf("%w: fingerprint is required", iris_error.ErrInvalidAlertRecord)
+	case r.Name == "":
+		return fmt.Errorf("%w: name is required", iris_error.ErrInvalidAlertRecord)
+	case r.Severity == "":
+		return fmt.Errorf("%w: severity is required", iris_error.ErrInvalidAlertRecord)
+	case r.Status != "firing" && r.Status != "resolved":
+		return fmt.Errorf("%w: status must be firing or resolved", iris_error.ErrInvalidAlertRecord)
+	case r.StartsAt.IsZero():
+		return fmt.Errorf("%w: starts_at is required", iris_error.ErrInvalidAlertRecord)
+	}
+	return nil
+}
+
+// ImportAlerts saves the records in order like alerts received from
+// Alertmanager. A record of an alert stored with the same fingerprint and
+// start is skipped, the others are added as new alerts and a firing alert
+// of the fingerprint is left as it is. Imported alerts are marked as notified
+// unless notify is set, so a replayed history does not page anyone.
+func (as *alertsService) ImportAlerts(ctx context.Context, records []Record, notify bool) *ImportResult {
+	res := &ImportResult{}
+	for i, r := range records {
+		imported, err := as.importAlert(ctx, r, notify)
+		switch {
+		case err != nil:
+			res.Failed++
+			res.Errors = append(res.Errors, ImportError{Index: i, Message: err.Error()})
+		case imported:
+			res.Imported++
+		default:
+			res.Skipped++
+		}
+	}
+	if res.Imported > 0 {
+		as.publishFiringCount()
+	}
+	return res
+}
+
+func (as *alertsService) importAlert(ctx context.Context, r Record, notify bool) (bool, error) {
+	if err := validateRecord(&r); err != nil {
+		return false, err
+	}
+	// stored timestamps have microsecond precision
+	r.StartsAt = r.StartsAt.Truncate(time.Microsecond)
+	_, err := as.ar.GetAlertByFingerPrintAndStartsAt(r.Fingerprint, r.StartsAt)
+	if err == nil {
+		return false, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+	// A record is history and is stored as an alert of its own. Merging it
+	// into the firing alert of the fingerprint would rewrite a live alert.
+	al := Alert{
+		Id:             uuid.New().String(),
+		FingerPrint:    r.Fingerprint,
+		Name:           r.Name,
+		Severity:       strings.ToLower(strings.TrimSpace(r.Severity)),
+		Description:    r.Description,
+		Status:         r.Status,
+		Method:         r.Method,
+		StartsAt:       r.StartsAt,
+		EndsAt:         r.EndsAt,
+		Receptor:       r.Receptor,
+		Labels:         r.Labels,
+		SendNotif:      !notify,
+		SilencedUntil:  r.SilencedUntil,
+		AcknowledgedAt: r.AcknowledgedAt,
+		AcknowledgedBy: r.AcknowledgedBy,
+		// imported alerts keep their place in history
+		CreatedAt: r.StartsAt,
+		UpdatedAt: time.Now(),
+	}
+	if !r.CreatedAt.IsZero() {
+		al.CreatedAt = r.CreatedAt.Truncate(time.Microsecond)
+	}
+	if _, err := as.ar.AddAlert(ctx, &al); err != nil {
+		as.log.Errorw("Error saving imported alert", "fingerprint", r.Fingerprint, "error", err)
+		return false, err
+	}
+	alertsImported.WithLabelValues(al.Status).Inc()
+	return true, nil
+}
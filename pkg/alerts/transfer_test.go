@@ -0,0 +1,108 @@
+package alerts
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type importRepo struct {
+	memAlerts
+	saved []*Alert
+}
+
+func (r *importRepo) GetAlertByFingerPrintAndStartsAt(fp string, startsAt time.Time) (*Alert, error) {
+	for _, al := range r.saved {
+		if al.FingerPrint == fp && al.StartsAt.Equal(startsAt) {
+			return al, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *importRepo) AddAlert(ctx context.Context, al *Alert) (int64, error) {
+	c := *al
+	r.saved = append(r.saved, &c)
+	return r.memAlerts.AddAlert(ctx, al)
+}
+
+func TestRecordCSVRoundTrip(t *testing.T) {
+	start := time.Date(2026, 10, 19, 3, 0, 0, 123000, time.UTC)
+	silenced := start.Add(time.Hour)
+	rec := Record{
+		ID: "a1", Fingerprint: "fp1", Name: "DiskFull", Severity: "critical",
+		Description: "disk, full", Status: "firing", StartsAt: start, EndsAt: start,
+		Labels: Labels{"team": "db"}, Method: []string{"sms", "call"}, Receptor: []string{"ops"},
+		SilencedUntil: &silenced, CreatedAt: start, UpdatedAt: start,
+	}
+	got, err := RecordFromCSV(CSVColumns, rec.CSV())
+	if err != nil {
+		t.Fatalf("RecordFromCSV: %v", err)
+	}
+	if !reflect.DeepEqual(got, rec) {
+		t.Errorf("record = %+v, want %+v", got, rec)
+	}
+	if _, err := RecordFromCSV([]string{"starts_at"}, []string{"yesterday"}); err == nil {
+		t.Error("invalid time is accepted")
+	}
+}
+
+func TestImportAlertsSkipsStoredAlerts(t *testing.T) {
+	repo := &importRepo{memAlerts: memAlerts{byFingerprint: map[string]*Alert{}}}
+	as := NewAlertService(zap.NewNop().Sugar(), repo, nil)
+	start := time.Now().Add(-time.Hour)
+	records := []Record{
+		{Fingerprint: "fp1", Name: "DiskFull", Severity: "critical", Status: "resolved", StartsAt: start, EndsAt: start.Add(time.Minute)},
+		{Fingerprint: "fp1", Name: "DiskFull", Severity: "critical", Status: "resolved", StartsAt: start, EndsAt: start.Add(time.Minute)},
+		{Fingerprint: "fp2", Name: "Down", Severity: "warning", Status: "unknown", StartsAt: start},
+	}
+	res := as.ImportAlerts(context.Background(), records, false)
+	if res.Imported != 1 || res.Skipped != 1 || res.Failed != 1 {
+		t.Fatalf("result = %+v", res)
+	}
+	if res.Errors[0].Index != 2 {
+		t.Errorf("error index = %d, want 2", res.Errors[0].Index)
+	}
+	al := repo.saved[0]
+	if !al.SendNotif {
+		t.Error("imported alert would be notified")
+	}
+	if !al.CreatedAt.Equal(start.Truncate(time.Microsecond)) {
+		t.Errorf("created_at = %v, want the start of the alert", al.CreatedAt)
+	}
+}
+
+func TestImportAlertsDoesNotMergeIntoFiringAlert(t *testing.T) {
+	repo := &importRepo{memAlerts: memAlerts{byFingerprint: map[string]*Alert{}}}
+	live := &Alert{Id: "live", FingerPrint: "fp1", Name: "DiskFull", Status: "firing",
+		StartsAt: time.Now().Add(-time.Minute), SendNotif: false}
+	repo.byFingerprint["fp1"] = live
+	as := NewAlertService(zap.NewNop().Sugar(), repo, nil)
+
+	start := time.Now().Add(-24 * time.Hour)
+	for _, notify := range []bool{false, true} {
+		records := []Record{
+			{Fingerprint: "fp1", Name: "DiskFull", Severity: "critical", Status: "resolved",
+				StartsAt: start, EndsAt: start.Add(time.Minute)},
+		}
+		start = start.Add(time.Hour)
+		if res := as.ImportAlerts(context.Background(), records, notify); res.Imported != 1 {
+			t.Fatalf("notify=%v: result = %+v", notify, res)
+		}
+	}
+	for _, al := range repo.saved {
+		if al.Id == live.Id {
+			t.Errorf("history record was merged into the firing alert: %+v", al)
+		}
+		if al.Status != "resolved" {
+			t.Errorf("status = %q, want the status of the record", al.Status)
+		}
+	}
+	if live.Status != "firing" || live.SendNotif {
+		t.Errorf("live alert changed: %+v", live)
+	}
+}
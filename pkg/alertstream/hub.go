@@ -0,0 +1,119 @@
+package alertstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/root-ali/iris/pkg/alerts"
+	"go.uber.org/zap"
+)
+
+func NewHub(n Notifier, logger *zap.SugaredLogger) *Hub {
+	return &Hub{
+		notifier: n,
+		logger:   logger,
+		clients:  make(map[chan alerts.Event]struct{}),
+	}
+}
+
+// Start listens for the events of every replica until ctx is done.
+func (h *Hub) Start(ctx context.Context) {
+	if h.notifier == nil {
+		return
+	}
+	go func() {
+		for {
+			err := h.notifier.Listen(ctx, Channel, h.receive)
+			if ctx.Err() != nil {
+				return
+			}
+			h.logger.Errorw("Alert event listener stopped, listening again", "error", err, "delay", relistenDelay)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(relistenDelay):
+			}
+		}
+	}()
+}
+
+// Publish sends ev to the clients of every replica through the notifier,
+// the clients of this instance receive it from the listener as well.
+func (h *Hub) Publish(ev alerts.Event) {
+	if h.notifier == nil {
+		h.broadcast(ev)
+		return
+	}
+	payload, err := encode(ev)
+	if err == nil {
+		err = h.notifier.Notify(Channel, payload)
+	}
+	if err != nil {
+		h.logger.Errorw("Failed to notify alert event, only local clients get it", "type", ev.Type, "error", err)
+		h.broadcast(ev)
+	}
+}
+
+// Subscribe registers a client, cancel must be called when it leaves.
+func (h *Hub) Subscribe() (<-chan alerts.Event, func()) {
+	ch := make(chan alerts.Event, clientBuffer)
+	h.mu.Lock()
+	h.clients[ch] = struct{}{}
+	streamClients.Set(float64(len(h.clients)))
+	h.mu.Unlock()
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.clients, ch)
+		streamClients.Set(float64(len(h.clients)))
+		h.mu.Unlock()
+	}
+}
+
+func (h *Hub) receive(payload string) {
+	var ev alerts.Event
+	if err := json.Unmarshal([]byte(payload), &ev); err != nil {
+		h.logger.Errorw("Invalid alert event payload", "error", err)
+		return
+	}
+	h.broadcast(ev)
+}
+
+// broadcast never blocks, a client that does not keep up misses events.
+func (h *Hub) broadcast(ev alerts.Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for ch := range h.clients {
+		select {
+		case ch <- ev:
+		default:
+			streamDropped.Inc()
+		}
+	}
+}
+
+// encode marshals ev for NOTIFY, the labels and description of the alert
+// are left out when the payload would be too long.
+func encode(ev alerts.Event) (string, error) {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return "", err
+	}
+	if len(b) <= maxPayload {
+		return string(b), nil
+	}
+	if ev.Alert != nil {
+		al := *ev.Alert
+		al.Labels = nil
+		al.Description = ""
+		ev.Alert = &al
+		if b, err = json.Marshal(ev); err != nil {
+			return "", err
+		}
+		if len(b) <= maxPayload {
+			return string(b), nil
+		}
+	}
+	return "", errors.New("alert event is too large to notify")
+}
@@ -0,0 +1,137 @@
+package alertstream
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/root-ali/iris/pkg/alerts"
+	"go.uber.org/zap"
+)
+
+// loopback delivers notifications to the listeners of every hub sharing
+// it, like replicas sharing a database.
+type loopback struct {
+	mu        sync.Mutex
+	listeners []func(string)
+	fail      bool
+}
+
+func (l *loopback) Notify(channel, payload string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.fail {
+		return errors.New("connection refused")
+	}
+	for _, fn := range l.listeners {
+		fn(payload)
+	}
+	return nil
+}
+
+func (l *loopback) Listen(ctx context.Context, channel string, fn func(string)) error {
+	l.mu.Lock()
+	l.listeners = append(l.listeners, fn)
+	l.mu.Unlock()
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (l *loopback) waitListeners(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		l.mu.Lock()
+		got := len(l.listeners)
+		l.mu.Unlock()
+		if got == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("listeners did not start")
+}
+
+func receive(t *testing.T, ch <-chan alerts.Event) alerts.Event {
+	t.Helper()
+	select {
+	case ev := <-ch:
+		return ev
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+	}
+	return alerts.Event{}
+}
+
+func TestPublishReachesOtherReplicas(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lb := &loopback{}
+	a := NewHub(lb, zap.NewNop().Sugar())
+	b := NewHub(lb, zap.NewNop().Sugar())
+	a.Start(ctx)
+	b.Start(ctx)
+	lb.waitListeners(t, 2)
+
+	chA, cancelA := a.Subscribe()
+	defer cancelA()
+	chB, cancelB := b.Subscribe()
+	defer cancelB()
+
+	a.Publish(alerts.Event{Type: alerts.EventCreated, Alert: &alerts.Alert{Id: "1", Name: "Down"}})
+	for _, ch := range []<-chan alerts.Event{chA, chB} {
+		ev := receive(t, ch)
+		if ev.Type != alerts.EventCreated || ev.Alert == nil || ev.Alert.Id != "1" {
+			t.Errorf("event = %+v", ev)
+		}
+	}
+}
+
+func TestPublishFallsBackToLocalClients(t *testing.T) {
+	h := NewHub(&loopback{fail: true}, zap.NewNop().Sugar())
+	ch, cancel := h.Subscribe()
+	defer cancel()
+	h.Publish(alerts.Event{Type: alerts.EventResolved})
+	if ev := receive(t, ch); ev.Type != alerts.EventResolved {
+		t.Errorf("event = %+v", ev)
+	}
+}
+
+func TestSlowClientDoesNotBlock(t *testing.T) {
+	h := NewHub(nil, zap.NewNop().Sugar())
+	_, cancel := h.Subscribe()
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < clientBuffer*2; i++ {
+			h.Publish(alerts.Event{Type: alerts.EventUpdated})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("publish blocked on a client that does not read")
+	}
+}
+
+func TestEncodeDropsLongFields(t *testing.T) {
+	ev := alerts.Event{Type: alerts.EventCreated, Alert: &alerts.Alert{
+		Id:          "1",
+		Description: strings.Repeat("x", maxPayload),
+		Labels:      alerts.Labels{"team": "db"},
+	}}
+	payload, err := encode(ev)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if len(payload) > maxPayload || strings.Contains(payload, "team") {
+		t.Errorf("payload not shortened: %d bytes", len(payload))
+	}
+	if ev.Alert.Description == "" {
+		t.Error("encode changed the published alert")
+	}
+}
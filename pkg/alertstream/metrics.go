@@ -0,0 +1,17 @@
+package alertstream
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	streamClients = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "iris_alert_stream_clients",
+		Help: "Clients connected to the alert stream of this instance.",
+	})
+	streamDropped = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "iris_alert_stream_dropped_total",
+		Help: "Alert events not delivered to a stream client that fell behind.",
+	})
+)
@@ -0,0 +1,47 @@
+package alertstream
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/root-ali/iris/pkg/alerts"
+	"go.uber.org/zap"
+)
+
+// Channel is the Postgres NOTIFY channel alert events go through so the
+// clients of every replica receive them.
+const Channel = "iris_alert_events"
+
+const (
+	// NOTIFY payloads must be shorter than 8000 bytes.
+	maxPayload = 7900
+	// Events for a client that is this far behind are dropped.
+	clientBuffer = 64
+	// Delay before listening again after the connection was lost.
+	relistenDelay = 5 * time.Second
+)
+
+// Notifier delivers payloads to every listener of a channel, on this and
+// the other replicas.
+type Notifier interface {
+	Notify(channel, payload string) error
+	// Listen calls fn with every payload sent to channel until ctx is done
+	// or the connection fails.
+	Listen(ctx context.Context, channel string, fn func(payload string)) error
+}
+
+// Hub fans alert events out to the stream clients. Without a notifier
+// events only reach the clients of this instance.
+type Hub struct {
+	notifier Notifier
+	logger   *zap.SugaredLogger
+
+	mu      sync.RWMutex
+	clients map[chan alerts.Event]struct{}
+}
+
+type Interface interface {
+	alerts.EventPublisher
+	Subscribe() (<-chan alerts.Event, func())
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
 
@@ -33,7 +34,11 @@ type Cache[K comparable, V any] struct {
 	// Options
 	cleanupInterval time.Duration
 	janitorEnabled  bool
-	Logger          *zap.SugaredLogger
+
+	// Metrics
+	hits   prometheus.Counter
+	misses prometheus.Counter
+	Logger *zap.SugaredLogger
 }
 
 // node represents one cache entry.
@@ -98,6 +103,8 @@ func New[K comparable, V any](logger *zap.SugaredLogger, opts ...Option) *Cache[
 		wakeCh:          make(chan struct{}, 1),
 		cleanupInterval: o.CleanupInterval,
 		janitorEnabled:  o.EnableJanitor,
+		hits:            cacheRequests.WithLabelValues(o.Name, "hit"),
+		misses:          cacheRequests.WithLabelValues(o.Name, "miss"),
 		Logger:          logger,
 	}
 	heap.Init(&c.expHeap)
@@ -181,6 +188,7 @@ func (c *Cache[K, V]) Get(key K) (V, bool) {
 
 	n, ok := c.items[key]
 	if !ok {
+		c.misses.Inc()
 		return zero, false
 	}
 
@@ -192,11 +200,13 @@ func (c *Cache[K, V]) Get(key K) (V, bool) {
 		if n.hidx >= 0 {
 			c.expHeap.remove(n)
 		}
+		c.misses.Inc()
 		return zero, false
 	}
 
 	// Move to front (mark as recently used)
 	c.moveToFront(n)
+	c.hits.Inc()
 	return n.value, true
 }
 
@@ -0,0 +1,13 @@
+package cache
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// cacheRequests counts lookups by result, the hit ratio of a cache is
+// rate(hit) / rate(hit + miss).
+var cacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "iris_cache_requests_total",
+	Help: "Cache lookups by cache and result, hit or miss.",
+}, []string{"cache", "result"})
@@ -16,6 +16,10 @@ type Options struct {
 	// EnableJanitor starts a lightweight background goroutine that removes
 	// expired items to free memory proactively. Defaults to true.
 	EnableJanitor bool
+
+	// Name labels the hit and miss metrics of the cache. Defaults to
+	// "default".
+	Name string
 }
 
 // Option mutates Options.
@@ -36,10 +40,16 @@ func WithJanitor(enable bool) Option {
 	return func(o *Options) { o.EnableJanitor = enable }
 }
 
+// WithName sets the name the cache is reported under in metrics.
+func WithName(name string) Option {
+	return func(o *Options) { o.Name = name }
+}
+
 func defaultOptions() Options {
 	return Options{
 		Capacity:        1024,
 		CleanupInterval: time.Minute,
 		EnableJanitor:   true,
+		Name:            "default",
 	}
 }
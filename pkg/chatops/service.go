@@ -0,0 +1,204 @@
+package chatops
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/root-ali/iris/pkg/alerts"
+	"github.com/root-ali/iris/pkg/contactmethods"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const helpText = `Available commands:
+/alerts - list firing alerts
+/ack <id> - acknowledge an alert
+/silence <id> <duration> - silence an alert, e.g. /silence <id> 1h
+/resolve <id> - resolve an alert`
+
+func NewService(repo Repository, as AlertService, cms ContactMethodService, logger *zap.SugaredLogger) *Service {
+	return &Service{
+		repo:      repo,
+		alerts:    as,
+		contacts:  cms,
+		deepLinks: make(map[string]func(token string) string),
+		logger:    logger,
+	}
+}
+
+// SetDeepLink registers how a provider turns a link token into a URL.
+func (s *Service) SetDeepLink(provider string, fn func(token string) string) {
+	s.deepLinks[provider] = fn
+}
+
+// CreateLinkToken returns a one-time token for userID and, when the
+// provider supports it, a deep link URL that sends it to the bot.
+func (s *Service) CreateLinkToken(userID, provider string) (string, string, error) {
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	now := time.Now()
+	err := s.repo.AddLinkToken(&LinkToken{
+		TokenHash: hashToken(token),
+		UserID:    userID,
+		Provider:  provider,
+		ExpiresAt: now.Add(linkTokenTTL),
+		CreatedAt: now,
+	})
+	if err != nil {
+		s.logger.Errorw("Failed to save link token", "userID", userID, "provider", provider, "error", err)
+		return "", "", err
+	}
+	link := ""
+	if fn, ok := s.deepLinks[provider]; ok {
+		link = fn(token)
+	}
+	return token, link, nil
+}
+
+// HandleCommand runs a chat command received by provider from address and
+// returns the reply to send back. Apart from /start, commands are only
+// accepted from chats linked to a user.
+func (s *Service) HandleCommand(provider, address, text string) string {
+	cmd, args := parseCommand(text)
+	s.logger.Infow("Chat command received", "provider", provider, "address", address, "command", cmd)
+
+	if cmd == "start" || cmd == "link" {
+		if len(args) == 0 {
+			return "Welcome to Iris. Open your profile page and use \"Link Telegram\" to connect this chat.\n\n" + helpText
+		}
+		return s.link(provider, address, args[0])
+	}
+
+	userID, err := s.contacts.FindUserID(contactmethods.Type(provider), address)
+	if err != nil {
+		return iris_error.ErrChatNotLinked.Error() + ", open your profile page to link it."
+	}
+	return s.run(userID, cmd, args)
+}
+
+// Execute runs a command on behalf of actor, for providers that
+// authenticate the request themselves such as Mattermost slash commands.
+// An empty command lists firing alerts.
+func (s *Service) Execute(actor, text string) string {
+	cmd, args := parseCommand(text)
+	if cmd == "" {
+		cmd = "alerts"
+	}
+	s.logger.Infow("Chat command received", "actor", actor, "command", cmd)
+	return s.run(actor, cmd, args)
+}
+
+// HandleAction applies an interactive message action to an alert.
+func (s *Service) HandleAction(alertID, action, actor string) (*alerts.Alert, error) {
+	s.logger.Infow("Chat action received", "alertID", alertID, "action", action, "actor", actor)
+	switch action {
+	case ActionAck:
+		return s.alerts.AcknowledgeAlert(alertID, actor)
+	case ActionSilence:
+		return s.alerts.SilenceAlert(alertID, actionSilenceFor)
+	case ActionResolve:
+		return s.alerts.ResolveAlert(alertID)
+	default:
+		return nil, fmt.Errorf("unknown action %q", action)
+	}
+}
+
+func (s *Service) run(actor, cmd string, args []string) string {
+	switch cmd {
+	case "alerts":
+		return s.listAlerts()
+	case "ack":
+		if len(args) != 1 {
+			return "Usage: /ack <id>"
+		}
+		if _, err := s.alerts.AcknowledgeAlert(args[0], actor); err != nil {
+			return "Cannot acknowledge alert: " + err.Error()
+		}
+		return "Alert " + args[0] + " acknowledged."
+	case "silence":
+		if len(args) != 2 {
+			return "Usage: /silence <id> <duration>"
+		}
+		d, err := time.ParseDuration(args[1])
+		if err != nil {
+			return "Invalid duration " + args[1] + ", use values like 30m or 2h."
+		}
+		if _, err := s.alerts.SilenceAlert(args[0], d); err != nil {
+			return "Cannot silence alert: " + err.Error()
+		}
+		return fmt.Sprintf("Alert %s silenced for %s.", args[0], d)
+	case "resolve":
+		if len(args) != 1 {
+			return "Usage: /resolve <id>"
+		}
+		if _, err := s.alerts.ResolveAlert(args[0]); err != nil {
+			return "Cannot resolve alert: " + err.Error()
+		}
+		return "Alert " + args[0] + " resolved."
+	default:
+		return helpText
+	}
+}
+
+// parseCommand splits text into a lower case command without the leading
+// slash and its arguments. Telegram appends the bot name in groups:
+// /ack@iris_bot
+func parseCommand(text string) (string, []string) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return "", nil
+	}
+	cmd, _, _ := strings.Cut(strings.ToLower(fields[0]), "@")
+	return strings.TrimPrefix(cmd, "/"), fields[1:]
+}
+
+func (s *Service) link(provider, address, token string) string {
+	t, err := s.repo.ConsumeLinkToken(hashToken(token), provider, time.Now())
+	if err != nil {
+		s.logger.Warnw("Invalid link token", "provider", provider, "address", address, "error", err)
+		return iris_error.ErrLinkTokenInvalid.Error() + ", generate a new one from your profile page."
+	}
+	if _, err := s.contacts.Link(t.UserID, contactmethods.Type(provider), address, provider); err != nil {
+		if errors.Is(err, iris_error.ErrContactMethodAlreadyExists) {
+			return "This chat is already linked to another Iris user."
+		}
+		s.logger.Errorw("Failed to link chat", "provider", provider, "userID", t.UserID, "error", err)
+		return "Cannot link this chat, please try again later."
+	}
+	return "This chat is now linked to your Iris account.\n\n" + helpText
+}
+
+func (s *Service) listAlerts() string {
+	als, err := s.alerts.GetAlerts("firing", "", 10, 0)
+	if err != nil {
+		s.logger.Errorw("Failed to get firing alerts", "error", err)
+		return "Cannot get alerts, please try again later."
+	}
+	if len(als) == 0 {
+		return "No firing alerts."
+	}
+	var b strings.Builder
+	b.WriteString("Firing alerts:\n")
+	for _, al := range als {
+		state := ""
+		if al.AcknowledgedAt != nil {
+			state = " (acked)"
+		}
+		fmt.Fprintf(&b, "\n[%s] %s%s\n%s\n", al.Severity, al.Name, state, al.Id)
+	}
+	return b.String()
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
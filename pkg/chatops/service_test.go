@@ -0,0 +1,118 @@
+package chatops
+
+import (
+	"testing"
+	"time"
+
+	"github.com/root-ali/iris/pkg/alerts"
+	"github.com/root-ali/iris/pkg/contactmethods"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeRepo struct {
+	tokens map[string]*LinkToken
+}
+
+func (f *fakeRepo) AddLinkToken(t *LinkToken) error {
+	f.tokens[t.TokenHash] = t
+	return nil
+}
+
+func (f *fakeRepo) ConsumeLinkToken(hash, provider string, at time.Time) (*LinkToken, error) {
+	t, ok := f.tokens[hash]
+	if !ok || t.Provider != provider || t.UsedAt != nil || !t.ExpiresAt.After(at) {
+		return nil, iris_error.ErrLinkTokenInvalid
+	}
+	t.UsedAt = &at
+	return t, nil
+}
+
+type fakeAlerts struct {
+	acked    map[string]string
+	silenced map[string]time.Duration
+	resolved map[string]bool
+}
+
+func (f *fakeAlerts) GetAlerts(status string, severity string, limit int, page int) ([]*alerts.Alert, error) {
+	return []*alerts.Alert{{Id: "a1", Name: "DiskFull", Severity: "critical"}}, nil
+}
+
+func (f *fakeAlerts) AcknowledgeAlert(id, userID string) (*alerts.Alert, error) {
+	f.acked[id] = userID
+	return &alerts.Alert{Id: id}, nil
+}
+
+func (f *fakeAlerts) SilenceAlert(id string, d time.Duration) (*alerts.Alert, error) {
+	f.silenced[id] = d
+	return &alerts.Alert{Id: id}, nil
+}
+
+func (f *fakeAlerts) ResolveAlert(id string) (*alerts.Alert, error) {
+	f.resolved[id] = true
+	return &alerts.Alert{Id: id, Status: "resolved"}, nil
+}
+
+type fakeContacts struct {
+	linked map[string]string
+}
+
+func (f *fakeContacts) Link(userID string, t contactmethods.Type, value, label string) (*contactmethods.ContactMethod, error) {
+	f.linked[value] = userID
+	return &contactmethods.ContactMethod{UserID: userID, Type: t, Value: value}, nil
+}
+
+func (f *fakeContacts) FindUserID(t contactmethods.Type, value string) (string, error) {
+	if id, ok := f.linked[value]; ok {
+		return id, nil
+	}
+	return "", iris_error.ErrContactMethodNotFound
+}
+
+func TestHandleCommand(t *testing.T) {
+	fa := &fakeAlerts{acked: map[string]string{}, silenced: map[string]time.Duration{}, resolved: map[string]bool{}}
+	fc := &fakeContacts{linked: map[string]string{}}
+	s := NewService(&fakeRepo{tokens: map[string]*LinkToken{}}, fa, fc, zap.NewNop().Sugar())
+	s.SetDeepLink("telegram", func(token string) string { return "https://t.me/iris_bot?start=" + token })
+
+	assert.Contains(t, s.HandleCommand("telegram", "42", "/alerts"), "not linked")
+
+	token, url, err := s.CreateLinkToken("u1", "telegram")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://t.me/iris_bot?start="+token, url)
+
+	assert.Contains(t, s.HandleCommand("telegram", "42", "/start "+token), "now linked")
+	assert.Equal(t, "u1", fc.linked["42"])
+	// Tokens are single use
+	assert.Contains(t, s.HandleCommand("telegram", "43", "/start "+token), "invalid")
+
+	assert.Contains(t, s.HandleCommand("telegram", "42", "/alerts@iris_bot"), "DiskFull")
+
+	s.HandleCommand("telegram", "42", "/ack a1")
+	assert.Equal(t, "u1", fa.acked["a1"])
+
+	assert.Contains(t, s.HandleCommand("telegram", "42", "/silence a1 soon"), "Invalid duration")
+	s.HandleCommand("telegram", "42", "/silence a1 1h")
+	assert.Equal(t, time.Hour, fa.silenced["a1"])
+}
+
+func TestExecuteAndHandleAction(t *testing.T) {
+	fa := &fakeAlerts{acked: map[string]string{}, silenced: map[string]time.Duration{}, resolved: map[string]bool{}}
+	s := NewService(&fakeRepo{tokens: map[string]*LinkToken{}}, fa, &fakeContacts{linked: map[string]string{}}, zap.NewNop().Sugar())
+
+	assert.Contains(t, s.Execute("@alice", ""), "DiskFull")
+	assert.Contains(t, s.Execute("@alice", "ack a1"), "acknowledged")
+	assert.Equal(t, "@alice", fa.acked["a1"])
+
+	_, err := s.HandleAction("a2", ActionSilence, "@bob")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, fa.silenced["a2"])
+
+	al, err := s.HandleAction("a3", ActionResolve, "@bob")
+	assert.NoError(t, err)
+	assert.Equal(t, "resolved", al.Status)
+
+	_, err = s.HandleAction("a3", "escalate", "@bob")
+	assert.Error(t, err)
+}
@@ -0,0 +1,68 @@
+package chatops
+
+import (
+	"time"
+
+	"github.com/root-ali/iris/pkg/alerts"
+	"github.com/root-ali/iris/pkg/contactmethods"
+	"go.uber.org/zap"
+)
+
+const (
+	linkTokenTTL     = 15 * time.Minute
+	actionSilenceFor = time.Hour
+)
+
+// Actions offered by interactive chat messages.
+const (
+	ActionAck     = "ack"
+	ActionSilence = "silence"
+	ActionResolve = "resolve"
+)
+
+// LinkToken is a one-time token a user sends to a chat bot to link the
+// chat to their account. Only the hash of the token is stored.
+type LinkToken struct {
+	TokenHash string     `gorm:"column:token_hash;primary_key"`
+	UserID    string     `gorm:"column:user_id"`
+	Provider  string     `gorm:"column:provider"`
+	ExpiresAt time.Time  `gorm:"column:expires_at"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	CreatedAt time.Time  `gorm:"column:created_at"`
+}
+
+func (LinkToken) TableName() string { return "link_tokens" }
+
+type Repository interface {
+	AddLinkToken(t *LinkToken) error
+	ConsumeLinkToken(hash, provider string, at time.Time) (*LinkToken, error)
+}
+
+type AlertService interface {
+	GetAlerts(status string, severity string, limit int, page int) ([]*alerts.Alert, error)
+	AcknowledgeAlert(id, userID string) (*alerts.Alert, error)
+	SilenceAlert(id string, d time.Duration) (*alerts.Alert, error)
+	ResolveAlert(id string) (*alerts.Alert, error)
+}
+
+type ContactMethodService interface {
+	Link(userID string, t contactmethods.Type, value, label string) (*contactmethods.ContactMethod, error)
+	FindUserID(t contactmethods.Type, value string) (string, error)
+}
+
+// ServiceInterface is shared by every chat provider that accepts commands.
+type ServiceInterface interface {
+	CreateLinkToken(userID, provider string) (string, string, error)
+	SetDeepLink(provider string, fn func(token string) string)
+	HandleCommand(provider, address, text string) string
+	Execute(actor, text string) string
+	HandleAction(alertID, action, actor string) (*alerts.Alert, error)
+}
+
+type Service struct {
+	repo      Repository
+	alerts    AlertService
+	contacts  ContactMethodService
+	deepLinks map[string]func(token string) string
+	logger    *zap.SugaredLogger
+}
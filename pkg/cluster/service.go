@@ -0,0 +1,181 @@
+package cluster
+
+import (
+	"context"
+	"hash/fnv"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/root-ali/iris/pkg/util"
+	"go.uber.org/zap"
+)
+
+// NewService returns an elector for the named locks. Every Iris replica
+// competes for each lock and only the holder runs the matching scheduler.
+// An empty instanceID is derived from the hostname.
+func NewService(repo Repository, instanceID string, interval time.Duration, names []string, logger *zap.SugaredLogger) (*Service, error) {
+	hostname, _ := os.Hostname()
+	if instanceID == "" {
+		id, err := util.NewUUIDv7()
+		if err != nil {
+			return nil, err
+		}
+		instanceID = hostname + "-" + id[len(id)-8:]
+	}
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	return &Service{
+		repo: repo,
+		member: Member{
+			InstanceID: instanceID,
+			Hostname:   hostname,
+			StartedAt:  time.Now(),
+		},
+		names:    names,
+		interval: interval,
+		logger:   logger,
+		held:     make(map[string]bool),
+	}, nil
+}
+
+// Start runs a first election round so schedulers started right after know
+// whether they lead, then keeps renewing locks until ctx is done.
+func (s *Service) Start(ctx context.Context) {
+	s.logger.Infow("Starting cluster leader election", "instanceID", s.member.InstanceID, "locks", s.names)
+	s.elect(ctx)
+	go func() {
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				s.release()
+				return
+			case <-ticker.C:
+				s.elect(ctx)
+			}
+		}
+	}()
+}
+
+func (s *Service) InstanceID() string {
+	return s.member.InstanceID
+}
+
+// IsLeader reports whether this instance holds the named lock.
+func (s *Service) IsLeader(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.held[name]
+}
+
+// Status lists the instances that sent a heartbeat recently and which of
+// them holds each lock.
+func (s *Service) Status() (*Status, error) {
+	members, err := s.repo.GetMembers(time.Now().Add(-memberTimeoutBeats * s.interval))
+	if err != nil {
+		s.logger.Errorw("Failed to get cluster members", "error", err)
+		return nil, err
+	}
+	locks := make(map[string]string, len(s.names))
+	for _, name := range s.names {
+		locks[name] = ""
+	}
+	for _, m := range members {
+		for _, name := range m.Locks {
+			locks[name] = m.InstanceID
+		}
+	}
+	return &Status{
+		InstanceID: s.member.InstanceID,
+		Members:    members,
+		Locks:      locks,
+	}, nil
+}
+
+// elect makes sure the lock session is alive, tries to take every lock not
+// held yet and records the result as a heartbeat.
+func (s *Service) elect(ctx context.Context) {
+	s.mu.Lock()
+	if s.locker != nil {
+		if err := s.locker.Ping(ctx); err != nil {
+			// Locks die with the session, another replica may hold them now
+			s.logger.Warnw("Cluster lock session lost", "error", err)
+			_ = s.locker.Close()
+			s.locker = nil
+			clear(s.held)
+		}
+	}
+	if s.locker == nil {
+		l, err := s.repo.NewLocker(ctx)
+		if err != nil {
+			s.mu.Unlock()
+			s.logger.Errorw("Failed to open cluster lock session", "error", err)
+			return
+		}
+		s.locker = l
+	}
+	for _, name := range s.names {
+		if s.held[name] {
+			continue
+		}
+		ok, err := s.locker.TryLock(ctx, lockKey(name))
+		if err != nil {
+			s.logger.Errorw("Failed to take cluster lock", "lock", name, "error", err)
+			continue
+		}
+		if ok {
+			s.logger.Infow("Cluster lock acquired", "lock", name, "instanceID", s.member.InstanceID)
+			s.held[name] = true
+		}
+	}
+	s.member.Locks = s.heldLocks()
+	s.member.HeartbeatAt = time.Now()
+	member := s.member
+	s.mu.Unlock()
+
+	if err := s.repo.SaveMember(&member); err != nil {
+		s.logger.Errorw("Failed to save cluster heartbeat", "error", err)
+	}
+	if err := s.repo.DeleteMembers(member.HeartbeatAt.Add(-memberRetention)); err != nil {
+		s.logger.Errorw("Failed to delete stale cluster members", "error", err)
+	}
+}
+
+// release hands the locks over on shutdown instead of waiting for the
+// session to time out.
+func (s *Service) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.locker == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.interval)
+	defer cancel()
+	for name := range s.held {
+		if err := s.locker.Unlock(ctx, lockKey(name)); err != nil {
+			s.logger.Warnw("Failed to release cluster lock", "lock", name, "error", err)
+		}
+	}
+	clear(s.held)
+	_ = s.locker.Close()
+	s.locker = nil
+}
+
+func (s *Service) heldLocks() []string {
+	locks := make([]string, 0, len(s.held))
+	for name := range s.held {
+		locks = append(locks, name)
+	}
+	slices.Sort(locks)
+	return locks
+}
+
+// lockKey maps a lock name to a Postgres advisory lock key.
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("iris:" + name))
+	return int64(h.Sum64())
+}
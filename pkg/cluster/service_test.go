@@ -0,0 +1,120 @@
+package cluster
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// fakeDB shares advisory locks between the sessions of several instances.
+type fakeDB struct {
+	mu      sync.Mutex
+	owners  map[int64]*fakeLocker
+	members map[string]*Member
+}
+
+type fakeLocker struct {
+	db   *fakeDB
+	dead bool
+}
+
+func (l *fakeLocker) TryLock(ctx context.Context, key int64) (bool, error) {
+	l.db.mu.Lock()
+	defer l.db.mu.Unlock()
+	if o, ok := l.db.owners[key]; ok && o != l {
+		return false, nil
+	}
+	l.db.owners[key] = l
+	return true, nil
+}
+
+func (l *fakeLocker) Unlock(ctx context.Context, key int64) error {
+	l.db.mu.Lock()
+	defer l.db.mu.Unlock()
+	delete(l.db.owners, key)
+	return nil
+}
+
+func (l *fakeLocker) Ping(ctx context.Context) error {
+	if l.dead {
+		return errors.New("connection reset")
+	}
+	return nil
+}
+
+func (l *fakeLocker) Close() error {
+	l.db.mu.Lock()
+	defer l.db.mu.Unlock()
+	for k, o := range l.db.owners {
+		if o == l {
+			delete(l.db.owners, k)
+		}
+	}
+	return nil
+}
+
+func (db *fakeDB) NewLocker(ctx context.Context) (Locker, error) {
+	return &fakeLocker{db: db}, nil
+}
+
+func (db *fakeDB) SaveMember(m *Member) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.members[m.InstanceID] = m
+	return nil
+}
+
+func (db *fakeDB) GetMembers(since time.Time) ([]*Member, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	var out []*Member
+	for _, m := range db.members {
+		if !m.HeartbeatAt.Before(since) {
+			out = append(out, m)
+		}
+	}
+	return out, nil
+}
+
+func (db *fakeDB) DeleteMembers(before time.Time) error {
+	return nil
+}
+
+func TestElection(t *testing.T) {
+	db := &fakeDB{owners: map[int64]*fakeLocker{}, members: map[string]*Member{}}
+	names := []string{LockAlertScheduler, LockMessageStatus}
+	a, err := NewService(db, "a", time.Second, names, zap.NewNop().Sugar())
+	assert.NoError(t, err)
+	b, err := NewService(db, "b", time.Second, names, zap.NewNop().Sugar())
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	a.elect(ctx)
+	b.elect(ctx)
+	assert.True(t, a.IsLeader(LockAlertScheduler))
+	assert.True(t, a.IsLeader(LockMessageStatus))
+	assert.False(t, b.IsLeader(LockAlertScheduler))
+
+	st, err := b.Status()
+	assert.NoError(t, err)
+	assert.Equal(t, "b", st.InstanceID)
+	assert.Len(t, st.Members, 2)
+	assert.Equal(t, "a", st.Locks[LockAlertScheduler])
+
+	// a loses its session, its locks are released by the database
+	a.locker.(*fakeLocker).dead = true
+	a.locker.Close()
+	b.elect(ctx)
+	a.elect(ctx)
+	assert.True(t, b.IsLeader(LockAlertScheduler))
+	assert.False(t, a.IsLeader(LockAlertScheduler))
+
+	b.release()
+	a.elect(ctx)
+	assert.True(t, a.IsLeader(LockMessageStatus))
+}
@@ -0,0 +1,85 @@
+package cluster
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// Locks taken by schedulers that must only run on one replica at a time.
+const (
+	LockAlertScheduler = "alert_scheduler"
+	LockMessageStatus  = "message_status"
+	LockBalance        = "balance"
+	LockJanitor        = "janitor"
+	// Telegram allows a single getUpdates poller per bot
+	LockTelegramUpdates = "telegram_updates"
+)
+
+const (
+	defaultInterval = 5 * time.Second
+	// A member that missed this many heartbeats is no longer listed.
+	memberTimeoutBeats = 3
+	// Members are forgotten a day after their last heartbeat.
+	memberRetention = 24 * time.Hour
+)
+
+// Member is an Iris instance and the locks it held at its last heartbeat.
+type Member struct {
+	InstanceID  string         `json:"instance_id" gorm:"column:instance_id;primary_key"`
+	Hostname    string         `json:"hostname" gorm:"column:hostname"`
+	Locks       pq.StringArray `json:"locks" gorm:"column:locks;type:text[]"`
+	StartedAt   time.Time      `json:"started_at" gorm:"column:started_at"`
+	HeartbeatAt time.Time      `json:"heartbeat_at" gorm:"column:heartbeat_at"`
+}
+
+func (Member) TableName() string { return "cluster_members" }
+
+// Status is the view of the cluster from one instance.
+type Status struct {
+	InstanceID string            `json:"instance_id"`
+	Members    []*Member         `json:"members"`
+	Locks      map[string]string `json:"locks"`
+}
+
+// Locker holds session level locks, they are released by the database when
+// the session ends so a crashed instance never keeps a lock.
+type Locker interface {
+	TryLock(ctx context.Context, key int64) (bool, error)
+	Unlock(ctx context.Context, key int64) error
+	Ping(ctx context.Context) error
+	Close() error
+}
+
+type Repository interface {
+	NewLocker(ctx context.Context) (Locker, error)
+	SaveMember(m *Member) error
+	GetMembers(since time.Time) ([]*Member, error)
+	DeleteMembers(before time.Time) error
+}
+
+// LeaderInterface is what schedulers need to know whether to run.
+type LeaderInterface interface {
+	IsLeader(name string) bool
+}
+
+type ServiceInterface interface {
+	LeaderInterface
+	InstanceID() string
+	Status() (*Status, error)
+}
+
+type Service struct {
+	repo     Repository
+	member   Member
+	names    []string
+	interval time.Duration
+	logger   *zap.SugaredLogger
+
+	mu     sync.RWMutex
+	locker Locker
+	held   map[string]bool
+}
@@ -0,0 +1,79 @@
+package contactmethods
+
+import (
+	"time"
+
+	"github.com/root-ali/iris/pkg/util"
+)
+
+// LabelPrimary marks the contact methods that mirror the mobile, email,
+// telegram_id and mattermost_id columns of a user.
+const LabelPrimary = "primary"
+
+// PrimaryTypes are the types that have a contact column on users.
+var PrimaryTypes = []Type{TypeSMS, TypeMail, TypeTelegram, TypeMattermost}
+
+// PrimarySync lists the changes to the contact methods of a user.
+type PrimarySync struct {
+	Remove []*ContactMethod
+	Verify []*ContactMethod
+	Add    []*ContactMethod
+}
+
+// SyncPrimary returns the changes that make the contact methods of a user
+// follow its contact columns. Primary methods whose column changed or was
+// cleared are removed. When an admin set the columns, verified is true and
+// new values are added verified, like migration 000014 imported them, and
+// an unverified method the user added with the same value is verified.
+// Otherwise new values are added unverified and wait for a code.
+func SyncPrimary(userID string, columns map[Type]string, current []*ContactMethod, verified bool, now time.Time) (*PrimarySync, error) {
+	sync := &PrimarySync{}
+	for _, t := range PrimaryTypes {
+		value := columns[t]
+		var found *ContactMethod
+		for _, cm := range current {
+			if cm.Type != t {
+				continue
+			}
+			if value != "" && cm.Value == value {
+				found = cm
+				continue
+			}
+			if cm.Label == LabelPrimary {
+				sync.Remove = append(sync.Remove, cm)
+			}
+		}
+		if value == "" {
+			continue
+		}
+		if found != nil {
+			if verified && !found.Verified() {
+				found.VerifiedAt = &now
+				found.CodeHash = ""
+				found.CodeExpiresAt = nil
+				found.CodeAttempts = 0
+				found.ModifiedAt = now
+				sync.Verify = append(sync.Verify, found)
+			}
+			continue
+		}
+		id, err := util.NewUUIDv7()
+		if err != nil {
+			return nil, err
+		}
+		cm := &ContactMethod{
+			ID:         id,
+			UserID:     userID,
+			Type:       t,
+			Label:      LabelPrimary,
+			Value:      value,
+			CreatedAt:  now,
+			ModifiedAt: now,
+		}
+		if verified {
+			cm.VerifiedAt = &now
+		}
+		sync.Add = append(sync.Add, cm)
+	}
+	return sync, nil
+}
@@ -0,0 +1,84 @@
+package contactmethods
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncPrimaryAddsNewUser(t *testing.T) {
+	now := time.Now()
+	sync, err := SyncPrimary("u1", map[Type]string{
+		TypeSMS:  "09120000000",
+		TypeMail: "a@example.com",
+	}, nil, true, now)
+	require.NoError(t, err)
+
+	assert.Empty(t, sync.Remove)
+	assert.Empty(t, sync.Verify)
+	require.Len(t, sync.Add, 2)
+	for _, cm := range sync.Add {
+		assert.Equal(t, "u1", cm.UserID)
+		assert.Equal(t, LabelPrimary, cm.Label)
+		assert.True(t, cm.Verified(), "%s should page right away", cm.Type)
+		assert.NotEmpty(t, cm.ID)
+	}
+	assert.Equal(t, TypeSMS, sync.Add[0].Type)
+	assert.Equal(t, "09120000000", sync.Add[0].Value)
+}
+
+func TestSyncPrimaryReplacesChangedColumns(t *testing.T) {
+	verified := time.Now().Add(-time.Hour)
+	oldMobile := &ContactMethod{ID: "m1", UserID: "u1", Type: TypeSMS, Label: LabelPrimary, Value: "09120000000", VerifiedAt: &verified}
+	oldMail := &ContactMethod{ID: "m2", UserID: "u1", Type: TypeMail, Label: LabelPrimary, Value: "a@example.com", VerifiedAt: &verified}
+	work := &ContactMethod{ID: "m3", UserID: "u1", Type: TypeSMS, Label: "work", Value: "09350000000", VerifiedAt: &verified}
+	pending := &ContactMethod{ID: "m4", UserID: "u1", Type: TypeTelegram, Label: "phone", Value: "42", CodeAttempts: 2}
+
+	sync, err := SyncPrimary("u1", map[Type]string{
+		TypeSMS:      "09121111111",
+		TypeTelegram: "42",
+	}, []*ContactMethod{oldMobile, oldMail, work, pending}, true, time.Now())
+	require.NoError(t, err)
+
+	// the old number and the cleared email stop paging, methods the user
+	// added keep theirs
+	assert.Equal(t, []*ContactMethod{oldMobile, oldMail}, sync.Remove)
+	require.Len(t, sync.Add, 1)
+	assert.Equal(t, "09121111111", sync.Add[0].Value)
+	assert.Equal(t, []*ContactMethod{pending}, sync.Verify)
+	assert.True(t, pending.Verified())
+	assert.Zero(t, pending.CodeAttempts)
+}
+
+func TestSyncPrimaryUnchanged(t *testing.T) {
+	verified := time.Now()
+	mobile := &ContactMethod{ID: "m1", UserID: "u1", Type: TypeSMS, Label: LabelPrimary, Value: "09120000000", VerifiedAt: &verified}
+
+	sync, err := SyncPrimary("u1", map[Type]string{TypeSMS: "09120000000"}, []*ContactMethod{mobile}, true, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, sync.Remove)
+	assert.Empty(t, sync.Verify)
+	assert.Empty(t, sync.Add)
+}
+
+// Users editing their own profile prove new addresses with a code.
+func TestSyncPrimaryUserUpdateIsNotVerified(t *testing.T) {
+	verified := time.Now().Add(-time.Hour)
+	oldMobile := &ContactMethod{ID: "m1", UserID: "u1", Type: TypeSMS, Label: LabelPrimary, Value: "09120000000", VerifiedAt: &verified}
+	pending := &ContactMethod{ID: "m2", UserID: "u1", Type: TypeTelegram, Label: "phone", Value: "42"}
+
+	sync, err := SyncPrimary("u1", map[Type]string{
+		TypeSMS:      "09121111111",
+		TypeTelegram: "42",
+	}, []*ContactMethod{oldMobile, pending}, false, time.Now())
+	require.NoError(t, err)
+
+	assert.Equal(t, []*ContactMethod{oldMobile}, sync.Remove)
+	assert.Empty(t, sync.Verify)
+	assert.False(t, pending.Verified())
+	require.Len(t, sync.Add, 1)
+	assert.Equal(t, "09121111111", sync.Add[0].Value)
+	assert.False(t, sync.Add[0].Verified(), "the new number must not page before it is verified")
+}
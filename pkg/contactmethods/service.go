@@ -0,0 +1,235 @@
+package contactmethods
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/root-ali/iris/pkg/notifications"
+	"github.com/root-ali/iris/pkg/util"
+	"go.uber.org/zap"
+)
+
+func NewService(repo Repository, provider notifications.ProviderStatusInterface, logger *zap.SugaredLogger) *Service {
+	return &Service{
+		repo:     repo,
+		provider: provider,
+		logger:   logger,
+	}
+}
+
+// Add stores an unverified contact method and sends it a verification code.
+func (s *Service) Add(cm *ContactMethod) error {
+	switch cm.Type {
+	case TypeSMS, TypeMail, TypeTelegram, TypeMattermost:
+	default:
+		return fmt.Errorf("unknown contact method type %q", cm.Type)
+	}
+	if cm.Value == "" {
+		return errors.New("contact method value is required")
+	}
+	id, err := util.NewUUIDv7()
+	if err != nil {
+		return err
+	}
+	cm.ID = id
+	cm.VerifiedAt = nil
+	cm.CreatedAt = time.Now()
+	cm.ModifiedAt = time.Now()
+	if err := s.repo.AddContactMethod(cm); err != nil {
+		s.logger.Errorw("Failed to add contact method", "userID", cm.UserID, "type", cm.Type, "error", err)
+		return err
+	}
+	s.logger.Infow("Contact method added", "id", cm.ID, "userID", cm.UserID, "type", cm.Type)
+	return s.sendCode(cm)
+}
+
+func (s *Service) List(userID string) ([]*ContactMethod, error) {
+	return s.repo.GetUserContactMethods(userID)
+}
+
+func (s *Service) Delete(userID, id string) error {
+	return s.repo.DeleteContactMethod(userID, id)
+}
+
+// SendCode issues a fresh verification code for an unverified method.
+func (s *Service) SendCode(userID, id string) error {
+	cm, err := s.repo.GetContactMethod(userID, id)
+	if err != nil {
+		return err
+	}
+	if cm.Verified() {
+		return iris_error.ErrContactMethodAlreadyVerified
+	}
+	return s.sendCode(cm)
+}
+
+// SendPendingCodes sends a verification code to the unverified primary
+// methods of a user that were not sent one yet, such as the addresses users
+// set on their own profile.
+func (s *Service) SendPendingCodes(userID string) error {
+	cms, err := s.repo.GetUserContactMethods(userID)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, cm := range cms {
+		if cm.Label != LabelPrimary || cm.Verified() || cm.CodeExpiresAt != nil {
+			continue
+		}
+		if err := s.sendCode(cm); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Service) Verify(userID, id, code string) (*ContactMethod, error) {
+	cm, err := s.repo.GetContactMethod(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if cm.Verified() {
+		return cm, nil
+	}
+	if cm.CodeExpiresAt == nil || time.Now().After(*cm.CodeExpiresAt) || cm.CodeAttempts >= maxCodeAttempt {
+		return nil, iris_error.ErrVerificationCodeExpired
+	}
+	if subtle.ConstantTimeCompare([]byte(hashCode(code)), []byte(cm.CodeHash)) != 1 {
+		cm.CodeAttempts++
+		if err := s.repo.UpdateContactMethod(cm); err != nil {
+			return nil, err
+		}
+		return nil, iris_error.ErrVerificationCodeInvalid
+	}
+	now := time.Now()
+	cm.VerifiedAt = &now
+	cm.CodeHash = ""
+	cm.CodeExpiresAt = nil
+	cm.CodeAttempts = 0
+	cm.ModifiedAt = now
+	if err := s.repo.UpdateContactMethod(cm); err != nil {
+		return nil, err
+	}
+	s.logger.Infow("Contact method verified", "id", cm.ID, "userID", cm.UserID, "type", cm.Type)
+	return cm, nil
+}
+
+// Link stores a contact method that was proven by other means, such as a
+// chat bot deep link, and marks it verified.
+func (s *Service) Link(userID string, t Type, value, label string) (*ContactMethod, error) {
+	now := time.Now()
+	cm, err := s.repo.GetContactMethodByValue(t, value)
+	if err == nil && cm.UserID == userID {
+		if !cm.Verified() {
+			cm.VerifiedAt = &now
+			cm.ModifiedAt = now
+			if err := s.repo.UpdateContactMethod(cm); err != nil {
+				return nil, err
+			}
+		}
+		return cm, nil
+	}
+	if err == nil {
+		return nil, iris_error.ErrContactMethodAlreadyExists
+	}
+	if !errors.Is(err, iris_error.ErrContactMethodNotFound) {
+		return nil, err
+	}
+	id, err := util.NewUUIDv7()
+	if err != nil {
+		return nil, err
+	}
+	cm = &ContactMethod{
+		ID:         id,
+		UserID:     userID,
+		Type:       t,
+		Label:      label,
+		Value:      value,
+		VerifiedAt: &now,
+		CreatedAt:  now,
+		ModifiedAt: now,
+	}
+	if err := s.repo.AddContactMethod(cm); err != nil {
+		return nil, err
+	}
+	s.logger.Infow("Contact method linked", "id", cm.ID, "userID", userID, "type", t)
+	return cm, nil
+}
+
+// FindUserID returns the owner of a verified contact method.
+func (s *Service) FindUserID(t Type, value string) (string, error) {
+	cm, err := s.repo.GetContactMethodByValue(t, value)
+	if err != nil {
+		return "", err
+	}
+	if !cm.Verified() {
+		return "", iris_error.ErrContactMethodNotFound
+	}
+	return cm.UserID, nil
+}
+
+func (s *Service) sendCode(cm *ContactMethod) error {
+	code, err := newCode()
+	if err != nil {
+		return err
+	}
+	expires := time.Now().Add(codeTTL)
+	cm.CodeHash = hashCode(code)
+	cm.CodeExpiresAt = &expires
+	cm.CodeAttempts = 0
+	cm.ModifiedAt = time.Now()
+	if err := s.repo.UpdateContactMethod(cm); err != nil {
+		return err
+	}
+
+	p, err := s.providerFor(cm.Type)
+	if err != nil {
+		return err
+	}
+	_, err = p.Send(notifications.Message{
+		Subject:   "Iris verification code",
+		Message:   fmt.Sprintf("Your Iris verification code is %s. It expires in %d minutes.", code, int(codeTTL.Minutes())),
+		Time:      time.Now().Format(time.DateTime),
+		Receptors: []string{cm.Value},
+	})
+	if err != nil {
+		s.logger.Errorw("Failed to send verification code", "id", cm.ID, "provider", p.GetName(), "error", err)
+		return iris_error.ErrVerificationCodeNotSent
+	}
+	s.logger.Infow("Verification code sent", "id", cm.ID, "provider", p.GetName())
+	return nil
+}
+
+// providerFor returns the highest priority active provider for t.
+func (s *Service) providerFor(t Type) (notifications.NotificationInterface, error) {
+	providers, err := s.provider.GetProvidersPriority()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range providers {
+		if p.Status && p.Flag == string(t) && p.Provider != nil {
+			return p.Provider, nil
+		}
+	}
+	return nil, fmt.Errorf("%w for %s", iris_error.ErrProviderNotFound, t)
+}
+
+func newCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", codeLength, n.Int64()), nil
+}
+
+func hashCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
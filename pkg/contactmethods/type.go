@@ -0,0 +1,74 @@
+package contactmethods
+
+import (
+	"time"
+
+	"github.com/root-ali/iris/pkg/notifications"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Type is the provider flag a contact method is reachable through.
+type Type string
+
+const (
+	TypeSMS        Type = "sms"
+	TypeMail       Type = "mail"
+	TypeTelegram   Type = "telegram"
+	TypeMattermost Type = "mattermost"
+)
+
+const (
+	codeLength     = 6
+	codeTTL        = 10 * time.Minute
+	maxCodeAttempt = 5
+)
+
+// ContactMethod is a single address a user can be paged on. Only verified
+// contact methods are used by the alert scheduler.
+type ContactMethod struct {
+	ID             string     `json:"id" gorm:"column:id;primary_key"`
+	UserID         string     `json:"user_id" gorm:"column:user_id"`
+	Type           Type       `json:"type" gorm:"column:type"`
+	Label          string     `json:"label" gorm:"column:label"`
+	Value          string     `json:"value" gorm:"column:value"`
+	VerifiedAt     *time.Time `json:"verified_at" gorm:"column:verified_at"`
+	CodeHash       string     `json:"-" gorm:"column:verification_code_hash"`
+	CodeExpiresAt  *time.Time `json:"-" gorm:"column:verification_expires_at"`
+	CodeAttempts   int        `json:"-" gorm:"column:verification_attempts"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"column:created_at"`
+	ModifiedAt     time.Time  `json:"modified_at" gorm:"column:modified_at"`
+	gorm.DeletedAt `json:"-"`
+}
+
+func (ContactMethod) TableName() string { return "contact_methods" }
+
+func (c *ContactMethod) Verified() bool {
+	return c.VerifiedAt != nil
+}
+
+type Repository interface {
+	AddContactMethod(cm *ContactMethod) error
+	GetContactMethod(userID, id string) (*ContactMethod, error)
+	GetUserContactMethods(userID string) ([]*ContactMethod, error)
+	GetContactMethodByValue(t Type, value string) (*ContactMethod, error)
+	UpdateContactMethod(cm *ContactMethod) error
+	DeleteContactMethod(userID, id string) error
+}
+
+type ServiceInterface interface {
+	Add(cm *ContactMethod) error
+	List(userID string) ([]*ContactMethod, error)
+	Delete(userID, id string) error
+	SendCode(userID, id string) error
+	SendPendingCodes(userID string) error
+	Verify(userID, id, code string) (*ContactMethod, error)
+	Link(userID string, t Type, value, label string) (*ContactMethod, error)
+	FindUserID(t Type, value string) (string, error)
+}
+
+type Service struct {
+	repo     Repository
+	provider notifications.ProviderStatusInterface
+	logger   *zap.SugaredLogger
+}
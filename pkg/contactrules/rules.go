@@ -0,0 +1,99 @@
+package contactrules
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+var knownMethods = []string{"sms", "mail", "telegram", "mattermost"}
+
+func (r *Rule) Validate() error {
+	if r.Severity == "" {
+		return fmt.Errorf("severity is required")
+	}
+	if len(r.Methods) == 0 {
+		return fmt.Errorf("rule for %s has no methods", r.Severity)
+	}
+	for _, m := range r.Methods {
+		if !slices.Contains(knownMethods, m) {
+			return fmt.Errorf("unknown method %q, expected one of %s", m, strings.Join(knownMethods, ", "))
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the rule applies to an alert of severity.
+func (r *Rule) Matches(severity string) bool {
+	return r.Severity == AnySeverity || strings.EqualFold(r.Severity, severity)
+}
+
+func (q *QuietHours) Validate() error {
+	if _, err := parseClock(q.Start); err != nil {
+		return fmt.Errorf("invalid start: %w", err)
+	}
+	if _, err := parseClock(q.End); err != nil {
+		return fmt.Errorf("invalid end: %w", err)
+	}
+	if _, err := time.LoadLocation(q.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone: %w", err)
+	}
+	return nil
+}
+
+// Silences reports whether an alert of severity must not be delivered at t.
+func (q *QuietHours) Silences(severity string, t time.Time) bool {
+	for _, s := range q.BypassSeverities {
+		if strings.EqualFold(s, severity) {
+			return false
+		}
+	}
+	loc, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	start, err := parseClock(q.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(q.End)
+	if err != nil {
+		return false
+	}
+	local := t.In(loc)
+	now := local.Hour()*60 + local.Minute()
+	if start <= end {
+		return now >= start && now < end
+	}
+	return now >= start || now < end
+}
+
+// channels returns the provider flags for severity at t. ok is false when
+// no rule matches and the alert's own methods apply.
+func (p *Preferences) channels(severity string, t time.Time) ([]string, bool) {
+	if p.QuietHours != nil && p.QuietHours.Silences(severity, t) {
+		return []string{}, true
+	}
+	var out []string
+	for _, r := range p.Rules {
+		if !r.Matches(severity) {
+			continue
+		}
+		for _, m := range r.Methods {
+			if !slices.Contains(out, m) {
+				out = append(out, m)
+			}
+		}
+	}
+	return out, out != nil
+}
+
+// parseClock returns minutes since midnight for HH:MM.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,57 @@
+package contactrules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuietHoursSilences(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Tehran")
+	q := &QuietHours{Start: "23:00", End: "07:00", Timezone: "Asia/Tehran", BypassSeverities: []string{"critical"}}
+	assert.NoError(t, q.Validate())
+
+	assert.True(t, q.Silences("warning", time.Date(2026, 3, 3, 23, 30, 0, 0, loc)))
+	assert.True(t, q.Silences("warning", time.Date(2026, 3, 3, 6, 59, 0, 0, loc)))
+	assert.False(t, q.Silences("warning", time.Date(2026, 3, 3, 7, 0, 0, 0, loc)))
+	assert.False(t, q.Silences("critical", time.Date(2026, 3, 3, 2, 0, 0, 0, loc)))
+
+	q = &QuietHours{Start: "12:00", End: "13:00", Timezone: "UTC"}
+	assert.True(t, q.Silences("info", time.Date(2026, 3, 3, 12, 30, 0, 0, time.UTC)))
+	assert.False(t, q.Silences("info", time.Date(2026, 3, 3, 13, 30, 0, 0, time.UTC)))
+}
+
+func TestPreferencesChannels(t *testing.T) {
+	p := &Preferences{
+		Rules: []*Rule{
+			{Severity: "critical", Methods: []string{"sms", "telegram"}},
+			{Severity: "warning", Methods: []string{"mail"}},
+		},
+		QuietHours: &QuietHours{Start: "23:00", End: "07:00", Timezone: "UTC", BypassSeverities: []string{"critical"}},
+	}
+	day := time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC)
+	night := time.Date(2026, 3, 3, 1, 0, 0, 0, time.UTC)
+
+	methods, ok := p.channels("critical", night)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"sms", "telegram"}, methods)
+
+	methods, ok = p.channels("warning", day)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"mail"}, methods)
+
+	methods, ok = p.channels("warning", night)
+	assert.True(t, ok)
+	assert.Empty(t, methods)
+
+	// no rule for info: fall back to the alert methods
+	_, ok = p.channels("info", day)
+	assert.False(t, ok)
+}
+
+func TestRuleValidate(t *testing.T) {
+	assert.NoError(t, (&Rule{Severity: "*", Methods: []string{"mail"}}).Validate())
+	assert.Error(t, (&Rule{Severity: "critical", Methods: []string{"pigeon"}}).Validate())
+	assert.Error(t, (&Rule{Severity: "critical"}).Validate())
+}
@@ -0,0 +1,146 @@
+package contactrules
+
+import (
+	"slices"
+	"time"
+
+	"github.com/root-ali/iris/pkg/cache"
+	"github.com/root-ali/iris/pkg/util"
+	"go.uber.org/zap"
+)
+
+func NewService(repo Repository, c cache.Interface[string, map[string]*Preferences], logger *zap.SugaredLogger) *Service {
+	return &Service{
+		repo:   repo,
+		cache:  c,
+		logger: logger,
+	}
+}
+
+func (s *Service) GetRules(userID string) ([]*Rule, error) {
+	return s.repo.GetUserContactRules(userID)
+}
+
+// SetRules replaces every contact rule of userID with rules.
+func (s *Service) SetRules(userID string, rules []*Rule) error {
+	now := time.Now()
+	for _, r := range rules {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+		id, err := util.NewUUIDv7()
+		if err != nil {
+			return err
+		}
+		r.ID = id
+		r.UserID = userID
+		r.CreatedAt = now
+		r.ModifiedAt = now
+	}
+	if err := s.repo.ReplaceUserContactRules(userID, rules); err != nil {
+		s.logger.Errorw("Failed to save contact rules", "userID", userID, "error", err)
+		return err
+	}
+	s.cache.Delete(preferencesCacheKey)
+	s.logger.Infow("Contact rules updated", "userID", userID, "rules", len(rules))
+	return nil
+}
+
+func (s *Service) GetQuietHours(userID string) (*QuietHours, error) {
+	return s.repo.GetUserQuietHours(userID)
+}
+
+func (s *Service) SetQuietHours(q *QuietHours) error {
+	if q.Timezone == "" {
+		q.Timezone = "UTC"
+	}
+	if q.BypassSeverities == nil {
+		q.BypassSeverities = []string{"critical"}
+	}
+	if err := q.Validate(); err != nil {
+		return err
+	}
+	now := time.Now()
+	if q.CreatedAt.IsZero() {
+		q.CreatedAt = now
+	}
+	q.ModifiedAt = now
+	if err := s.repo.SaveQuietHours(q); err != nil {
+		s.logger.Errorw("Failed to save quiet hours", "userID", q.UserID, "error", err)
+		return err
+	}
+	s.cache.Delete(preferencesCacheKey)
+	return nil
+}
+
+func (s *Service) DeleteQuietHours(userID string) error {
+	if err := s.repo.DeleteQuietHours(userID); err != nil {
+		return err
+	}
+	s.cache.Delete(preferencesCacheKey)
+	return nil
+}
+
+// Channels returns the provider flags userID wants alerts of severity on at
+// t. The second value is false when the user has no matching rule, in which
+// case the alert's own method label applies. An empty list with true means
+// the user must not be notified (quiet hours).
+func (s *Service) Channels(userID, severity string, at time.Time) ([]string, bool) {
+	p, ok := s.preferences()[userID]
+	if !ok {
+		return nil, false
+	}
+	return p.channels(severity, at)
+}
+
+// Methods returns every provider flag some user asked for on severity.
+func (s *Service) Methods(severity string) []string {
+	out := make([]string, 0)
+	for _, p := range s.preferences() {
+		for _, r := range p.Rules {
+			if !r.Matches(severity) {
+				continue
+			}
+			for _, m := range r.Methods {
+				if !slices.Contains(out, m) {
+					out = append(out, m)
+				}
+			}
+		}
+	}
+	return out
+}
+
+func (s *Service) preferences() map[string]*Preferences {
+	if prefs, ok := s.cache.Get(preferencesCacheKey); ok {
+		return prefs
+	}
+	prefs := make(map[string]*Preferences)
+	rules, err := s.repo.GetContactRules()
+	if err != nil {
+		s.logger.Errorw("Failed to get contact rules", "error", err)
+		return prefs
+	}
+	quiet, err := s.repo.GetAllQuietHours()
+	if err != nil {
+		s.logger.Errorw("Failed to get quiet hours", "error", err)
+		return prefs
+	}
+	get := func(userID string) *Preferences {
+		if prefs[userID] == nil {
+			prefs[userID] = &Preferences{}
+		}
+		return prefs[userID]
+	}
+	for _, r := range rules {
+		p := get(r.UserID)
+		p.Rules = append(p.Rules, r)
+	}
+	for _, q := range quiet {
+		get(q.UserID).QuietHours = q
+	}
+	if err := s.cache.Set(preferencesCacheKey, prefs, time.Minute); err != nil {
+		s.logger.Errorw("Failed to cache contact preferences", "error", err)
+	}
+	return prefs
+}
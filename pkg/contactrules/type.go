@@ -0,0 +1,74 @@
+package contactrules
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/root-ali/iris/pkg/cache"
+	"go.uber.org/zap"
+)
+
+// AnySeverity matches alerts of every severity.
+const AnySeverity = "*"
+
+// Rule routes alerts of Severity to the provider flags in Methods
+// (sms, mail, telegram, mattermost) for a single user.
+type Rule struct {
+	ID         string         `json:"id" gorm:"column:id;primary_key"`
+	UserID     string         `json:"user_id" gorm:"column:user_id"`
+	Severity   string         `json:"severity" gorm:"column:severity"`
+	Methods    pq.StringArray `json:"methods" gorm:"column:methods;type:text[]"`
+	CreatedAt  time.Time      `json:"created_at" gorm:"column:created_at"`
+	ModifiedAt time.Time      `json:"modified_at" gorm:"column:modified_at"`
+}
+
+func (Rule) TableName() string { return "contact_rules" }
+
+// QuietHours mutes every notification to a user between Start and End
+// (HH:MM in Timezone) unless the alert severity is in BypassSeverities.
+// End before Start wraps around midnight.
+type QuietHours struct {
+	UserID           string         `json:"user_id" gorm:"column:user_id;primary_key"`
+	Start            string         `json:"start" gorm:"column:start_time"`
+	End              string         `json:"end" gorm:"column:end_time"`
+	Timezone         string         `json:"timezone" gorm:"column:timezone"`
+	BypassSeverities pq.StringArray `json:"bypass_severities" gorm:"column:bypass_severities;type:text[]"`
+	CreatedAt        time.Time      `json:"created_at" gorm:"column:created_at"`
+	ModifiedAt       time.Time      `json:"modified_at" gorm:"column:modified_at"`
+}
+
+func (QuietHours) TableName() string { return "user_quiet_hours" }
+
+// Preferences is everything a user configured about how they get paged.
+type Preferences struct {
+	Rules      []*Rule     `json:"rules"`
+	QuietHours *QuietHours `json:"quiet_hours"`
+}
+
+type Repository interface {
+	GetContactRules() ([]*Rule, error)
+	GetUserContactRules(userID string) ([]*Rule, error)
+	ReplaceUserContactRules(userID string, rules []*Rule) error
+	GetAllQuietHours() ([]*QuietHours, error)
+	GetUserQuietHours(userID string) (*QuietHours, error)
+	SaveQuietHours(q *QuietHours) error
+	DeleteQuietHours(userID string) error
+}
+
+type ServiceInterface interface {
+	GetRules(userID string) ([]*Rule, error)
+	SetRules(userID string, rules []*Rule) error
+	GetQuietHours(userID string) (*QuietHours, error)
+	SetQuietHours(q *QuietHours) error
+	DeleteQuietHours(userID string) error
+	Channels(userID, severity string, at time.Time) ([]string, bool)
+	Methods(severity string) []string
+}
+
+type Service struct {
+	repo   Repository
+	cache  cache.Interface[string, map[string]*Preferences]
+	logger *zap.SugaredLogger
+}
+
+const preferencesCacheKey = "contact_preferences"
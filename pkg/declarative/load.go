@@ -0,0 +1,113 @@
+package declarative
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Load reads the config from the YAML files at paths. Directories are read
+// for *.yml and *.yaml files in lexical order, the lists of every file and
+// document are concatenated. Unknown keys are an error so typos do not go
+// unnoticed.
+func Load(paths ...string) (*Config, error) {
+	files, err := expand(paths)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{declared: make(map[Kind]bool)}
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		if err := cfg.decode(b); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", iris_error.ErrInvalidDeclaredConfig, f, err)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func expand(paths []string) ([]string, error) {
+	files := make([]string, 0)
+	for _, p := range paths {
+		st, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !st.IsDir() {
+			files = append(files, p)
+			continue
+		}
+		found := make([]string, 0)
+		err = filepath.WalkDir(p, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			ext := strings.ToLower(filepath.Ext(path))
+			if !d.IsDir() && (ext == ".yml" || ext == ".yaml") {
+				found = append(found, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(found)
+		files = append(files, found...)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: no YAML files in %s", iris_error.ErrInvalidDeclaredConfig, strings.Join(paths, ", "))
+	}
+	return files, nil
+}
+
+// decode appends the documents of b to cfg.
+func (cfg *Config) decode(b []byte) error {
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	for {
+		var part Config
+		if err := dec.Decode(&part); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return err
+		}
+		cfg.Users = append(cfg.Users, part.Users...)
+		cfg.Groups = append(cfg.Groups, part.Groups...)
+		cfg.Providers = append(cfg.Providers, part.Providers...)
+		cfg.Routes = append(cfg.Routes, part.Routes...)
+		cfg.Silences = append(cfg.Silences, part.Silences...)
+		cfg.Templates = append(cfg.Templates, part.Templates...)
+	}
+
+	// sections are declared even when empty, an empty list prunes them all
+	keys := yaml.NewDecoder(bytes.NewReader(b))
+	for {
+		var doc map[string]yaml.Node
+		if err := keys.Decode(&doc); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		for k := range doc {
+			cfg.declared[sections[k]] = true
+		}
+	}
+}
+
+// Declared reports whether some file has a section for kind.
+func (cfg *Config) Declared(kind Kind) bool {
+	return cfg.declared[kind]
+}
@@ -0,0 +1,678 @@
+package declarative
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/root-ali/iris/pkg/contactrules"
+	"github.com/root-ali/iris/pkg/groups"
+	"github.com/root-ali/iris/pkg/maintenance"
+	"github.com/root-ali/iris/pkg/notifications"
+	"github.com/root-ali/iris/pkg/templates"
+	"github.com/root-ali/iris/pkg/user"
+	"github.com/root-ali/iris/pkg/util"
+)
+
+// state is what the database holds, indexed the way the config names it.
+type state struct {
+	roleIDs    map[string]string // role name to id
+	roleNames  map[string]string // role id to name
+	users      map[string]*user.User
+	userIDs    map[string]string // user name to id, users to create included
+	userNames  map[string]string // user id to name
+	groups     map[string]*groups.Group
+	members    map[string][]string // group id to user ids
+	providers  map[string]notifications.Providers
+	rules      map[string][]*contactrules.Rule // by user id
+	quietHours map[string]*contactrules.QuietHours
+	windows    []*maintenance.Window
+	templates  map[string]*templates.Template
+}
+
+func loadState(r Repository) (*state, error) {
+	st := &state{
+		roleIDs:    make(map[string]string),
+		roleNames:  make(map[string]string),
+		users:      make(map[string]*user.User),
+		userIDs:    make(map[string]string),
+		userNames:  make(map[string]string),
+		groups:     make(map[string]*groups.Group),
+		members:    make(map[string][]string),
+		providers:  make(map[string]notifications.Providers),
+		rules:      make(map[string][]*contactrules.Rule),
+		quietHours: make(map[string]*contactrules.QuietHours),
+		templates:  make(map[string]*templates.Template),
+	}
+	rs, err := r.GetRoles()
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range rs {
+		st.roleIDs[role.Name] = role.ID
+		st.roleNames[role.ID] = role.Name
+	}
+	us, err := r.GetAllUsers()
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range us {
+		st.users[u.UserName] = u
+		st.userIDs[u.UserName] = u.ID
+		st.userNames[u.ID] = u.UserName
+	}
+	gs, err := r.GetAllGroups()
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range gs {
+		st.groups[g.Name] = g
+	}
+	ms, err := r.GetGroupMembers()
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range ms {
+		st.members[m.GId] = append(st.members[m.GId], m.UId)
+	}
+	ps, err := r.GetProviders()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range ps {
+		st.providers[p.Name] = p
+	}
+	rules, err := r.GetContactRules()
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		st.rules[rule.UserID] = append(st.rules[rule.UserID], rule)
+	}
+	qs, err := r.GetAllQuietHours()
+	if err != nil {
+		return nil, err
+	}
+	for _, q := range qs {
+		st.quietHours[q.UserID] = q
+	}
+	if st.windows, err = r.GetMaintenanceWindows(); err != nil {
+		return nil, err
+	}
+	ts, err := r.GetTemplates()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range ts {
+		st.templates[t.Name] = t
+	}
+	return st, nil
+}
+
+type planner struct {
+	cfg   *Config
+	st    *state
+	prune bool
+	now   time.Time
+
+	changes []Change
+	deletes map[Kind][]Change
+	// pruned holds the users to delete, nothing declared may refer to them
+	pruned map[string]bool
+}
+
+// buildPlan compares cfg with st. Creates and updates come first, with
+// users and groups before what refers to them, and deletes last in the
+// reverse order.
+func buildPlan(cfg *Config, st *state, prune bool, now time.Time) (*Plan, error) {
+	p := &planner{cfg: cfg, st: st, prune: prune, now: now,
+		deletes: make(map[Kind][]Change), pruned: make(map[string]bool)}
+	for _, step := range []func() error{p.users, p.groups, p.providers, p.routes, p.silences, p.templates} {
+		if err := step(); err != nil {
+			return nil, err
+		}
+	}
+	plan := &Plan{Changes: p.changes}
+	for _, kind := range []Kind{KindTemplate, KindSilence, KindRoute, KindGroup, KindUser} {
+		ds := p.deletes[kind]
+		sort.SliceStable(ds, func(i, j int) bool { return ds[i].Name < ds[j].Name })
+		plan.Changes = append(plan.Changes, ds...)
+	}
+	return plan, nil
+}
+
+func (p *planner) pruning(kind Kind) bool {
+	return p.prune && p.cfg.Declared(kind)
+}
+
+func (p *planner) add(c Change) {
+	if c.Action == ActionDelete {
+		p.deletes[c.Kind] = append(p.deletes[c.Kind], c)
+		return
+	}
+	p.changes = append(p.changes, c)
+}
+
+// userID resolves a user name of the config, the user must exist or be
+// created and must not be pruned.
+func (p *planner) userID(kind Kind, name, userName string) (string, error) {
+	id, ok := p.st.userIDs[userName]
+	if !ok {
+		return "", invalid("%s %q: user %q does not exist", kind, name, userName)
+	}
+	if p.pruned[userName] {
+		return "", invalid("%s %q: user %q is pruned", kind, name, userName)
+	}
+	return id, nil
+}
+
+func (p *planner) userName(id string) string {
+	if name, ok := p.st.userNames[id]; ok {
+		return name
+	}
+	return id
+}
+
+func (p *planner) users() error {
+	declared := make(map[string]bool)
+	for _, u := range p.cfg.Users {
+		declared[u.UserName] = true
+		role := u.Role
+		if role == "" {
+			role = "viewer"
+		}
+		roleID, ok := p.st.roleIDs[role]
+		if !ok {
+			return invalid("user %q: role %q does not exist", u.UserName, role)
+		}
+		want := user.User{
+			UserName:     u.UserName,
+			FirstName:    u.FirstName,
+			LastName:     u.LastName,
+			Email:        u.Email,
+			Mobile:       u.Mobile,
+			TelegramID:   u.TelegramID,
+			MattermostId: u.MattermostID,
+			Role:         roleID,
+			ModifiedAt:   p.now,
+		}
+		cur, ok := p.st.users[u.UserName]
+		if !ok {
+			password := ""
+			if u.PasswordEnv != "" {
+				if password = os.Getenv(u.PasswordEnv); password == "" {
+					return invalid("user %q: %s is not set", u.UserName, u.PasswordEnv)
+				}
+			}
+			want.ID = ulid.Make().String()
+			want.Status = "Verified"
+			want.CreatedAt = p.now
+			p.st.userIDs[u.UserName] = want.ID
+			p.add(Change{Kind: KindUser, Name: u.UserName, Action: ActionCreate,
+				Fields: p.userFields(&user.User{}, &want),
+				apply: func(r Repository) error {
+					nu := want
+					pw := password
+					if pw == "" {
+						var err error
+						if pw, err = user.GeneratePassword(); err != nil {
+							return err
+						}
+					}
+					if err := nu.SetPassword(pw); err != nil {
+						return err
+					}
+					return r.AddUser(&nu)
+				}})
+			continue
+		}
+		fields := p.userFields(cur, &want)
+		if len(fields) == 0 {
+			continue
+		}
+		want.ID = cur.ID
+		p.add(Change{Kind: KindUser, Name: u.UserName, Action: ActionUpdate, Fields: fields,
+			apply: func(r Repository) error { return r.UpdateUserProfile(&want) }})
+	}
+
+	if !p.pruning(KindUser) {
+		return nil
+	}
+	for name, cur := range p.st.users {
+		if declared[name] || name == DefaultAdmin {
+			continue
+		}
+		p.pruned[name] = true
+		id := cur.ID
+		p.add(Change{Kind: KindUser, Name: name, Action: ActionDelete,
+			apply: func(r Repository) error { return r.DeleteUser(id) }})
+	}
+	return nil
+}
+
+func (p *planner) userFields(cur, want *user.User) []FieldChange {
+	var d diff
+	d.str("first_name", cur.FirstName, want.FirstName)
+	d.str("last_name", cur.LastName, want.LastName)
+	d.str("email", cur.Email, want.Email)
+	d.str("mobile", cur.Mobile, want.Mobile)
+	d.str("telegram_id", cur.TelegramID, want.TelegramID)
+	d.str("mattermost_id", cur.MattermostId, want.MattermostId)
+	d.str("role", p.st.roleNames[cur.Role], p.st.roleNames[want.Role])
+	return d
+}
+
+func (p *planner) groups() error {
+	declared := make(map[string]bool)
+	for _, g := range p.cfg.Groups {
+		declared[g.Name] = true
+		wantIDs := make([]string, 0, len(g.Members))
+		for _, m := range g.Members {
+			id, err := p.userID(KindGroup, g.Name, m)
+			if err != nil {
+				return err
+			}
+			wantIDs = append(wantIDs, id)
+		}
+		wantNames := sortedCopy(g.Members)
+
+		cur, ok := p.st.groups[g.Name]
+		if !ok {
+			id, err := util.NewUUIDv7()
+			if err != nil {
+				return err
+			}
+			want := groups.Group{ID: id, Name: g.Name, Description: g.Description, CreatedAt: p.now, ModifiedAt: p.now}
+			var d diff
+			d.str("description", "", g.Description)
+			d.list("members", nil, wantNames)
+			p.add(Change{Kind: KindGroup, Name: g.Name, Action: ActionCreate, Fields: d,
+				apply: func(r Repository) error {
+					if err := r.AddGroup(&want); err != nil {
+						return err
+					}
+					for _, uid := range wantIDs {
+						if err := r.AddUserToGroup(uid, want.ID); err != nil {
+							return err
+						}
+					}
+					return nil
+				}})
+			continue
+		}
+
+		curIDs := p.st.members[cur.ID]
+		curNames := make([]string, 0, len(curIDs))
+		for _, id := range curIDs {
+			curNames = append(curNames, p.userName(id))
+		}
+		sort.Strings(curNames)
+		var d diff
+		d.str("description", cur.Description, g.Description)
+		d.list("members", curNames, wantNames)
+		if len(d) == 0 {
+			continue
+		}
+		groupID := cur.ID
+		updated := groups.Group{ID: cur.ID, Name: cur.Name, Description: g.Description, ModifiedAt: p.now}
+		describe := cur.Description != g.Description
+		added := subtract(wantIDs, curIDs)
+		removed := subtract(curIDs, wantIDs)
+		p.add(Change{Kind: KindGroup, Name: g.Name, Action: ActionUpdate, Fields: d,
+			apply: func(r Repository) error {
+				if describe {
+					if err := r.UpdateGroup(&updated); err != nil {
+						return err
+					}
+				}
+				for _, uid := range removed {
+					if err := r.RemoveUserFromGroup(uid, groupID); err != nil {
+						return err
+					}
+				}
+				for _, uid := range added {
+					if err := r.AddUserToGroup(uid, groupID); err != nil {
+						return err
+					}
+				}
+				return nil
+			}})
+	}
+
+	if !p.pruning(KindGroup) {
+		return nil
+	}
+	for name, cur := range p.st.groups {
+		if declared[name] {
+			continue
+		}
+		g := cur
+		members := p.st.members[cur.ID]
+		p.add(Change{Kind: KindGroup, Name: name, Action: ActionDelete,
+			apply: func(r Repository) error {
+				for _, uid := range members {
+					if err := r.RemoveUserFromGroup(uid, g.ID); err != nil {
+						return err
+					}
+				}
+				return r.DeleteGroup(g)
+			}})
+	}
+	return nil
+}
+
+func (p *planner) providers() error {
+	for _, pr := range p.cfg.Providers {
+		cur, ok := p.st.providers[pr.Name]
+		if !ok {
+			return invalid("provider %q is not registered, providers come from the notifications config", pr.Name)
+		}
+		want := notifications.Providers{Name: cur.Name, Priority: cur.Priority, Status: cur.Status}
+		if pr.Priority != nil {
+			want.Priority = *pr.Priority
+		}
+		if pr.Enabled != nil {
+			want.Status = *pr.Enabled
+		}
+		var d diff
+		d.value("priority", strconv.Itoa(cur.Priority), strconv.Itoa(want.Priority))
+		d.value("enabled", strconv.FormatBool(cur.Status), strconv.FormatBool(want.Status))
+		if len(d) == 0 {
+			continue
+		}
+		p.add(Change{Kind: KindProvider, Name: pr.Name, Action: ActionUpdate, Fields: d,
+			apply: func(r Repository) error { return r.UpdateProviderSettings(&want) }})
+	}
+	return nil
+}
+
+func (p *planner) routes() error {
+	declared := make(map[string]bool)
+	for _, rt := range p.cfg.Routes {
+		userID, err := p.userID(KindRoute, rt.User, rt.User)
+		if err != nil {
+			return err
+		}
+		declared[userID] = true
+		wantRules := rt.rules()
+		wantQuiet := rt.quietHours(userID)
+		curRules := p.st.rules[userID]
+		curQuiet := p.st.quietHours[userID]
+
+		rulesChanged := !slices.Equal(ruleStrings(curRules), ruleStrings(wantRules))
+		quietChanged := quietString(curQuiet) != quietString(wantQuiet)
+		if !rulesChanged && !quietChanged {
+			continue
+		}
+		var d diff
+		d.list("rules", ruleStrings(curRules), ruleStrings(wantRules))
+		d.value("quiet_hours", quietString(curQuiet), quietString(wantQuiet))
+		action := ActionUpdate
+		if len(curRules) == 0 && curQuiet == nil {
+			action = ActionCreate
+		}
+		now := p.now
+		p.add(Change{Kind: KindRoute, Name: rt.User, Action: action, Fields: d,
+			apply: func(r Repository) error {
+				if rulesChanged {
+					for _, rule := range wantRules {
+						id, err := util.NewUUIDv7()
+						if err != nil {
+							return err
+						}
+						rule.ID = id
+						rule.UserID = userID
+						rule.CreatedAt = now
+						rule.ModifiedAt = now
+					}
+					if err := r.ReplaceUserContactRules(userID, wantRules); err != nil {
+						return err
+					}
+				}
+				if !quietChanged {
+					return nil
+				}
+				if wantQuiet == nil {
+					return r.DeleteQuietHours(userID)
+				}
+				wantQuiet.CreatedAt = now
+				wantQuiet.ModifiedAt = now
+				return r.SaveQuietHours(wantQuiet)
+			}})
+	}
+
+	if !p.pruning(KindRoute) {
+		return nil
+	}
+	routed := make(map[string]bool)
+	for id := range p.st.rules {
+		routed[id] = true
+	}
+	for id := range p.st.quietHours {
+		routed[id] = true
+	}
+	for id := range routed {
+		if declared[id] {
+			continue
+		}
+		userID := id
+		hasRules := len(p.st.rules[id]) > 0
+		hasQuiet := p.st.quietHours[id] != nil
+		p.add(Change{Kind: KindRoute, Name: p.userName(id), Action: ActionDelete,
+			apply: func(r Repository) error {
+				if hasRules {
+					if err := r.ReplaceUserContactRules(userID, nil); err != nil {
+						return err
+					}
+				}
+				if hasQuiet {
+					return r.DeleteQuietHours(userID)
+				}
+				return nil
+			}})
+	}
+	return nil
+}
+
+func (p *planner) silences() error {
+	declared := make(map[string]bool)
+	current := make(map[string]*maintenance.Window)
+	for _, w := range p.st.windows {
+		// windows sharing a name are not managed, prune deletes them
+		if _, ok := current[w.Name]; !ok {
+			current[w.Name] = w
+		}
+	}
+	for _, s := range p.cfg.Silences {
+		declared[s.Name] = true
+		want, err := s.window()
+		if err != nil {
+			return invalid("silence %q: %v", s.Name, err)
+		}
+		if want.Timezone == "" {
+			want.Timezone = "UTC"
+		}
+		want.ModifiedAt = p.now
+		cur, ok := current[s.Name]
+		if !ok {
+			if want.ID, err = util.NewUUIDv7(); err != nil {
+				return err
+			}
+			if want.StartsAt.IsZero() {
+				want.StartsAt = p.now
+			}
+			want.CreatedAt = p.now
+			p.add(Change{Kind: KindSilence, Name: s.Name, Action: ActionCreate,
+				Fields: windowFields(&maintenance.Window{}, want),
+				apply:  func(r Repository) error { return r.AddMaintenanceWindow(want) }})
+			continue
+		}
+		want.ID = cur.ID
+		if s.StartsAt == nil {
+			want.StartsAt = cur.StartsAt
+		}
+		fields := windowFields(cur, want)
+		if len(fields) == 0 {
+			continue
+		}
+		p.add(Change{Kind: KindSilence, Name: s.Name, Action: ActionUpdate, Fields: fields,
+			apply: func(r Repository) error { return r.UpdateMaintenanceWindow(want) }})
+	}
+
+	if !p.pruning(KindSilence) {
+		return nil
+	}
+	for _, w := range p.st.windows {
+		if declared[w.Name] && current[w.Name] == w {
+			continue
+		}
+		id := w.ID
+		p.add(Change{Kind: KindSilence, Name: w.Name, Action: ActionDelete,
+			apply: func(r Repository) error { return r.DeleteMaintenanceWindow(id) }})
+	}
+	return nil
+}
+
+func windowFields(cur, want *maintenance.Window) []FieldChange {
+	var d diff
+	d.str("description", cur.Description, want.Description)
+	d.str("schedule_type", string(cur.ScheduleType), string(want.ScheduleType))
+	d.str("schedule", cur.Schedule, want.Schedule)
+	d.value("duration", durationString(cur.DurationSeconds), durationString(want.DurationSeconds))
+	d.str("timezone", cur.Timezone, want.Timezone)
+	d.list("matchers", cur.Matchers, want.Matchers)
+	d.list("groups", cur.Groups, want.Groups)
+	d.time("starts_at", &cur.StartsAt, &want.StartsAt)
+	d.time("ends_at", cur.EndsAt, want.EndsAt)
+	return d
+}
+
+func (p *planner) templates() error {
+	declared := make(map[string]bool)
+	for _, t := range p.cfg.Templates {
+		declared[t.Name] = true
+		want := t.template()
+		want.ModifiedAt = p.now
+		cur, ok := p.st.templates[t.Name]
+		if !ok {
+			id, err := util.NewUUIDv7()
+			if err != nil {
+				return err
+			}
+			want.ID = id
+			want.CreatedAt = p.now
+			p.add(Change{Kind: KindTemplate, Name: t.Name, Action: ActionCreate,
+				Fields: templateFields(&templates.Template{}, want),
+				apply:  func(r Repository) error { return r.AddTemplate(want) }})
+			continue
+		}
+		want.ID = cur.ID
+		fields := templateFields(cur, want)
+		if len(fields) == 0 {
+			continue
+		}
+		p.add(Change{Kind: KindTemplate, Name: t.Name, Action: ActionUpdate, Fields: fields,
+			apply: func(r Repository) error { return r.UpdateTemplate(want) }})
+	}
+
+	if !p.pruning(KindTemplate) {
+		return nil
+	}
+	for name, cur := range p.st.templates {
+		if declared[name] {
+			continue
+		}
+		id := cur.ID
+		p.add(Change{Kind: KindTemplate, Name: name, Action: ActionDelete,
+			apply: func(r Repository) error { return r.DeleteTemplate(id) }})
+	}
+	return nil
+}
+
+func templateFields(cur, want *templates.Template) []FieldChange {
+	var d diff
+	d.str("description", cur.Description, want.Description)
+	d.list("matchers", cur.Matchers, want.Matchers)
+	d.str("subject", cur.Subject, want.Subject)
+	d.str("body", cur.Body, want.Body)
+	return d
+}
+
+// diff collects the fields whose value changes.
+type diff []FieldChange
+
+func (d *diff) str(field, old, new string) {
+	if old != new {
+		*d = append(*d, FieldChange{Field: field, Old: strconv.Quote(old), New: strconv.Quote(new)})
+	}
+}
+
+// value is for numbers, flags and other values that read fine unquoted.
+func (d *diff) value(field, old, new string) {
+	if old != new {
+		*d = append(*d, FieldChange{Field: field, Old: old, New: new})
+	}
+}
+
+func (d *diff) list(field string, old, new []string) {
+	if !slices.Equal(old, new) && (len(old) > 0 || len(new) > 0) {
+		*d = append(*d, FieldChange{Field: field, Old: fmt.Sprintf("%q", old), New: fmt.Sprintf("%q", new)})
+	}
+}
+
+// time compares to the microsecond, the precision of Postgres.
+func (d *diff) time(field string, old, new *time.Time) {
+	format := func(t *time.Time) string {
+		if t == nil || t.IsZero() {
+			return "none"
+		}
+		return t.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano)
+	}
+	if format(old) != format(new) {
+		*d = append(*d, FieldChange{Field: field, Old: format(old), New: format(new)})
+	}
+}
+
+func ruleStrings(rules []*contactrules.Rule) []string {
+	out := make([]string, 0, len(rules))
+	for _, r := range rules {
+		out = append(out, r.Severity+": "+strings.Join(r.Methods, ","))
+	}
+	return out
+}
+
+func quietString(q *contactrules.QuietHours) string {
+	if q == nil {
+		return "none"
+	}
+	return fmt.Sprintf("%s-%s %s, bypass %s", q.Start, q.End, q.Timezone, strings.Join(q.BypassSeverities, ","))
+}
+
+func durationString(seconds int64) string {
+	if seconds == 0 {
+		return "none"
+	}
+	return (time.Duration(seconds) * time.Second).String()
+}
+
+func sortedCopy(ss []string) []string {
+	out := slices.Clone(ss)
+	sort.Strings(out)
+	return out
+}
+
+// subtract returns the values of a that are not in b.
+func subtract(a, b []string) []string {
+	out := make([]string, 0)
+	for _, v := range a {
+		if !slices.Contains(b, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,105 @@
+package declarative
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func NewService(repo Repository, logger *zap.SugaredLogger) *Service {
+	return &Service{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Apply makes the database match cfg in a single transaction and returns
+// the plan it applied, or would apply with DryRun. Nothing is changed when
+// a single change fails.
+func (s *Service) Apply(cfg *Config, opts Options) (*Plan, error) {
+	var plan *Plan
+	err := s.repo.DeclarativeTransaction(func(tx Repository) error {
+		st, err := loadState(tx)
+		if err != nil {
+			return err
+		}
+		if plan, err = buildPlan(cfg, st, opts.Prune, time.Now()); err != nil {
+			return err
+		}
+		if opts.DryRun {
+			return nil
+		}
+		for _, c := range plan.Changes {
+			if err := c.apply(tx); err != nil {
+				return fmt.Errorf("%s %s %q: %w", c.Action, c.Kind, c.Name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Errorw("Failed to apply declarative config", "error", err)
+		return nil, err
+	}
+	create, update, del := plan.Counts()
+	s.logger.Infow("Declarative config applied", "dryRun", opts.DryRun, "prune", opts.Prune,
+		"create", create, "update", update, "delete", del)
+	return plan, nil
+}
+
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Counts returns the number of objects to create, update and delete.
+func (p *Plan) Counts() (create, update, del int) {
+	for _, c := range p.Changes {
+		switch c.Action {
+		case ActionCreate:
+			create++
+		case ActionUpdate:
+			update++
+		case ActionDelete:
+			del++
+		}
+	}
+	return create, update, del
+}
+
+var actionSigns = map[Action]string{
+	ActionCreate: "+",
+	ActionUpdate: "~",
+	ActionDelete: "-",
+}
+
+// Write prints a line per change, with the changed fields when diff is
+// set, and a summary.
+func (p *Plan) Write(w io.Writer, diff bool) error {
+	if p.Empty() {
+		_, err := fmt.Fprintln(w, "No changes.")
+		return err
+	}
+	for _, c := range p.Changes {
+		if _, err := fmt.Fprintf(w, "%s %s %s\n", actionSigns[c.Action], c.Kind, c.Name); err != nil {
+			return err
+		}
+		if !diff {
+			continue
+		}
+		for _, f := range c.Fields {
+			var err error
+			if c.Action == ActionCreate {
+				_, err = fmt.Fprintf(w, "    %s: %s\n", f.Field, f.New)
+			} else {
+				_, err = fmt.Fprintf(w, "    %s: %s -> %s\n", f.Field, f.Old, f.New)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	create, update, del := p.Counts()
+	_, err := fmt.Fprintf(w, "Plan: %d to create, %d to update, %d to delete.\n", create, update, del)
+	return err
+}
@@ -0,0 +1,219 @@
+package declarative
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/root-ali/iris/pkg/contactrules"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/root-ali/iris/pkg/groups"
+	"github.com/root-ali/iris/pkg/maintenance"
+	"github.com/root-ali/iris/pkg/notifications"
+	"github.com/root-ali/iris/pkg/roles"
+	"github.com/root-ali/iris/pkg/templates"
+	"github.com/root-ali/iris/pkg/user"
+	"go.uber.org/zap"
+)
+
+// memRepo serves a fixed state and records the changes made to it.
+type memRepo struct {
+	Repository
+	users     []*user.User
+	groups    []*groups.Group
+	members   []*groups.UserGroup
+	providers []notifications.Providers
+	templates []*templates.Template
+	calls     []string
+}
+
+func (r *memRepo) record(format string, args ...any) error {
+	r.calls = append(r.calls, fmt.Sprintf(format, args...))
+	return nil
+}
+
+func (r *memRepo) GetRoles() ([]*roles.Role, error) {
+	return []*roles.Role{{ID: "r-admin", Name: "admin"}, {ID: "r-viewer", Name: "viewer"}}, nil
+}
+func (r *memRepo) GetAllUsers() ([]*user.User, error)            { return r.users, nil }
+func (r *memRepo) GetAllGroups() ([]*groups.Group, error)        { return r.groups, nil }
+func (r *memRepo) GetGroupMembers() ([]*groups.UserGroup, error) { return r.members, nil }
+func (r *memRepo) GetProviders() ([]notifications.Providers, error) {
+	return r.providers, nil
+}
+func (r *memRepo) GetContactRules() ([]*contactrules.Rule, error) { return nil, nil }
+func (r *memRepo) GetAllQuietHours() ([]*contactrules.QuietHours, error) {
+	return nil, nil
+}
+func (r *memRepo) GetMaintenanceWindows() ([]*maintenance.Window, error) { return nil, nil }
+func (r *memRepo) GetTemplates() ([]*templates.Template, error)          { return r.templates, nil }
+
+func (r *memRepo) AddUser(u *user.User) error {
+	if u.Password == "" || u.Salt == "" {
+		return errors.New("user without password")
+	}
+	return r.record("add user %s", u.UserName)
+}
+func (r *memRepo) UpdateUserProfile(u *user.User) error {
+	return r.record("update user %s %s", u.ID, u.Email)
+}
+func (r *memRepo) DeleteUser(id string) error        { return r.record("delete user %s", id) }
+func (r *memRepo) AddGroup(g *groups.Group) error    { return r.record("add group %s", g.Name) }
+func (r *memRepo) DeleteGroup(g *groups.Group) error { return r.record("delete group %s", g.ID) }
+func (r *memRepo) DeleteTemplate(id string) error    { return r.record("delete template %s", id) }
+func (r *memRepo) AddTemplate(t *templates.Template) error {
+	return r.record("add template %s", t.Name)
+}
+func (r *memRepo) AddUserToGroup(userID, groupID string) error {
+	return r.record("add member %s", userID)
+}
+func (r *memRepo) RemoveUserFromGroup(userID, groupID string) error {
+	return r.record("remove member %s", userID)
+}
+func (r *memRepo) UpdateProviderSettings(p *notifications.Providers) error {
+	return r.record("update provider %s %d %t", p.Name, p.Priority, p.Status)
+}
+
+func (r *memRepo) DeclarativeTransaction(fn func(tx Repository) error) error {
+	return fn(r)
+}
+
+func newMemRepo() *memRepo {
+	return &memRepo{
+		users: []*user.User{
+			{ID: "u-admin", UserName: "admin", Role: "r-admin"},
+			{ID: "u-alice", UserName: "alice", Email: "alice@old.example", Role: "r-viewer"},
+			{ID: "u-bob", UserName: "bob", Role: "r-viewer"},
+		},
+		groups:    []*groups.Group{{ID: "g-dba", Name: "dba"}},
+		members:   []*groups.UserGroup{{GId: "g-dba", UId: "u-bob"}},
+		providers: []notifications.Providers{{Name: "Kavenegar", Priority: 1, Status: true}},
+		templates: []*templates.Template{{ID: "t-old", Name: "old"}},
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadMergesFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.yml", "users:\n  - username: alice\n")
+	writeFile(t, dir, "b.yaml", "groups:\n  - name: dba\n    members: [alice]\n---\ntemplates: []\n")
+	writeFile(t, dir, "notes.txt", "not: yaml")
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(cfg.Users) != 1 || len(cfg.Groups) != 1 {
+		t.Errorf("config = %+v", cfg)
+	}
+	if !cfg.Declared(KindTemplate) || cfg.Declared(KindSilence) {
+		t.Errorf("declared = %v", cfg.declared)
+	}
+
+	writeFile(t, dir, "c.yml", "users:\n  - username: bob\n    phone: '0912'\n")
+	if _, err := Load(dir); !errors.Is(err, iris_error.ErrInvalidDeclaredConfig) {
+		t.Errorf("unknown key: err = %v", err)
+	}
+}
+
+func TestApplyCreatesUpdatesAndPrunes(t *testing.T) {
+	repo := newMemRepo()
+	priority := 3
+	cfg := &Config{
+		Users: []User{
+			{UserName: "alice", Email: "alice@example.com"},
+			{UserName: "carol"},
+		},
+		Groups:    []Group{{Name: "dba", Members: []string{"alice", "carol"}}},
+		Providers: []Provider{{Name: "Kavenegar", Priority: &priority}},
+		declared:  map[Kind]bool{KindUser: true, KindGroup: true, KindProvider: true},
+	}
+	plan, err := NewService(repo, zap.NewNop().Sugar()).Apply(cfg, Options{Prune: true})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if c, u, d := plan.Counts(); c != 1 || u != 3 || d != 1 {
+		t.Errorf("counts = %d, %d, %d", c, u, d)
+	}
+	// members are added once carol exists, bob leaves dba before being
+	// deleted, the default admin and the old template are kept
+	calls := strings.Join(repo.calls, "\n")
+	want := []string{
+		"update user u-alice alice@example.com",
+		"add user carol",
+		"remove member u-bob",
+		"add member u-alice",
+		"update provider Kavenegar 3 true",
+		"delete user u-bob",
+	}
+	if got := strings.Join(want, "\n"); !strings.HasPrefix(calls, want[0]) || len(repo.calls) != len(want)+1 {
+		t.Errorf("calls =\n%s\nwant\n%s\nand the member carol", calls, got)
+	}
+	for _, c := range want {
+		if !strings.Contains(calls, c) {
+			t.Errorf("missing call %q in\n%s", c, calls)
+		}
+	}
+	if strings.Index(calls, "add user carol") > strings.LastIndex(calls, "add member") {
+		t.Errorf("members added before carol:\n%s", calls)
+	}
+
+	var out strings.Builder
+	if err := plan.Write(&out, true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `    email: "alice@old.example" -> "alice@example.com"`) ||
+		!strings.Contains(out.String(), "- user bob") {
+		t.Errorf("plan output:\n%s", out.String())
+	}
+}
+
+func TestApplyPrunesOnlyDeclaredKinds(t *testing.T) {
+	repo := newMemRepo()
+	cfg := &Config{declared: map[Kind]bool{KindTemplate: true}}
+	if _, err := NewService(repo, zap.NewNop().Sugar()).Apply(cfg, Options{Prune: true}); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if got := strings.Join(repo.calls, ", "); got != "delete template t-old" {
+		t.Errorf("calls = %s", got)
+	}
+}
+
+func TestApplyDryRunAndInvalidReferences(t *testing.T) {
+	repo := newMemRepo()
+	svc := NewService(repo, zap.NewNop().Sugar())
+	cfg := &Config{Templates: []Template{{Name: "db", Subject: "{{.Name}}"}}}
+	plan, err := svc.Apply(cfg, Options{DryRun: true})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(plan.Changes) != 1 || len(repo.calls) != 0 {
+		t.Errorf("plan = %+v, calls = %v", plan.Changes, repo.calls)
+	}
+
+	for name, cfg := range map[string]*Config{
+		"member":   {Groups: []Group{{Name: "dba", Members: []string{"dave"}}}},
+		"provider": {Providers: []Provider{{Name: "Pigeon"}}},
+		"pruned": {
+			Users:    []User{{UserName: "alice"}},
+			Routes:   []Route{{User: "bob"}},
+			declared: map[Kind]bool{KindUser: true},
+		},
+	} {
+		_, err := svc.Apply(cfg, Options{Prune: true})
+		if !errors.Is(err, iris_error.ErrInvalidDeclaredConfig) {
+			t.Errorf("%s: err = %v, want ErrInvalidDeclaredConfig", name, err)
+		}
+	}
+	if len(repo.calls) != 0 {
+		t.Errorf("calls = %v", repo.calls)
+	}
+}
@@ -0,0 +1,212 @@
+package declarative
+
+import (
+	"time"
+
+	"github.com/root-ali/iris/pkg/contactrules"
+	"github.com/root-ali/iris/pkg/groups"
+	"github.com/root-ali/iris/pkg/maintenance"
+	"github.com/root-ali/iris/pkg/notifications"
+	"github.com/root-ali/iris/pkg/roles"
+	"github.com/root-ali/iris/pkg/templates"
+	"github.com/root-ali/iris/pkg/user"
+	"go.uber.org/zap"
+)
+
+// Kind is the type of a declared object.
+type Kind string
+
+const (
+	KindUser     Kind = "user"
+	KindGroup    Kind = "group"
+	KindProvider Kind = "provider"
+	KindRoute    Kind = "route"
+	KindSilence  Kind = "silence"
+	KindTemplate Kind = "template"
+)
+
+// sections maps the top-level keys of the YAML files to their kind.
+var sections = map[string]Kind{
+	"users":     KindUser,
+	"groups":    KindGroup,
+	"providers": KindProvider,
+	"routes":    KindRoute,
+	"silences":  KindSilence,
+	"templates": KindTemplate,
+}
+
+// DefaultAdmin is the user created at the first start, it is never pruned
+// so a config without it cannot lock everyone out.
+const DefaultAdmin = "admin"
+
+// Config is the declared state of Iris, read from one or more YAML files.
+type Config struct {
+	Users     []User     `yaml:"users"`
+	Groups    []Group    `yaml:"groups"`
+	Providers []Provider `yaml:"providers"`
+	Routes    []Route    `yaml:"routes"`
+	Silences  []Silence  `yaml:"silences"`
+	Templates []Template `yaml:"templates"`
+
+	// declared holds the kinds with a section in some file, prune only
+	// deletes objects of those kinds.
+	declared map[Kind]bool
+}
+
+type User struct {
+	UserName     string `yaml:"username"`
+	FirstName    string `yaml:"first_name"`
+	LastName     string `yaml:"last_name"`
+	Email        string `yaml:"email"`
+	Mobile       string `yaml:"mobile"`
+	TelegramID   string `yaml:"telegram_id"`
+	MattermostID string `yaml:"mattermost_id"`
+	// Role is admin or viewer, viewer when empty.
+	Role string `yaml:"role"`
+	// PasswordEnv names the environment variable holding the password of
+	// a new user, users created without one get a random password.
+	// Passwords of existing users are never changed.
+	PasswordEnv string `yaml:"password_env"`
+}
+
+// Group lists its members by user name, they replace the members stored.
+type Group struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Members     []string `yaml:"members"`
+}
+
+// Provider sets the priority and status of a provider. Providers are
+// registered from the notifications config at startup, so they are never
+// created or pruned, and fields left out are not changed.
+type Provider struct {
+	Name     string `yaml:"name"`
+	Priority *int   `yaml:"priority"`
+	Enabled  *bool  `yaml:"enabled"`
+}
+
+// Route is how a user is paged: contact rules by severity and quiet hours.
+type Route struct {
+	User       string      `yaml:"user"`
+	Rules      []Rule      `yaml:"rules"`
+	QuietHours *QuietHours `yaml:"quiet_hours"`
+}
+
+type Rule struct {
+	Severity string   `yaml:"severity"`
+	Methods  []string `yaml:"methods"`
+}
+
+type QuietHours struct {
+	Start    string `yaml:"start"`
+	End      string `yaml:"end"`
+	Timezone string `yaml:"timezone"`
+	// BypassSeverities defaults to critical.
+	BypassSeverities []string `yaml:"bypass_severities"`
+}
+
+// Silence is a recurring maintenance window, identified by its name.
+type Silence struct {
+	Name         string   `yaml:"name"`
+	Description  string   `yaml:"description"`
+	ScheduleType string   `yaml:"schedule_type"`
+	Schedule     string   `yaml:"schedule"`
+	Duration     string   `yaml:"duration"`
+	Timezone     string   `yaml:"timezone"`
+	Matchers     []string `yaml:"matchers"`
+	Groups       []string `yaml:"groups"`
+	// StartsAt is left as stored when it is not set, new silences start
+	// when they are applied.
+	StartsAt *time.Time `yaml:"starts_at"`
+	EndsAt   *time.Time `yaml:"ends_at"`
+}
+
+type Template struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Matchers    []string `yaml:"matchers"`
+	Subject     string   `yaml:"subject"`
+	Body        string   `yaml:"body"`
+}
+
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// FieldChange is the old and new value of a field, formatted for display.
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// Change is a single object to create, update or delete.
+type Change struct {
+	Kind   Kind
+	Name   string
+	Action Action
+	Fields []FieldChange
+
+	apply func(r Repository) error
+}
+
+// Plan is the changes that make the database match a config, in the order
+// they are applied.
+type Plan struct {
+	Changes []Change
+}
+
+// Options of an apply. With Prune the objects of the declared kinds that
+// are not in the config are deleted, with DryRun nothing is changed.
+type Options struct {
+	Prune  bool
+	DryRun bool
+}
+
+type Repository interface {
+	GetAllUsers() ([]*user.User, error)
+	GetRoles() ([]*roles.Role, error)
+	GetAllGroups() ([]*groups.Group, error)
+	GetGroupMembers() ([]*groups.UserGroup, error)
+	GetProviders() ([]notifications.Providers, error)
+	GetContactRules() ([]*contactrules.Rule, error)
+	GetAllQuietHours() ([]*contactrules.QuietHours, error)
+	GetMaintenanceWindows() ([]*maintenance.Window, error)
+	GetTemplates() ([]*templates.Template, error)
+
+	AddUser(u *user.User) error
+	UpdateUserProfile(u *user.User) error
+	DeleteUser(id string) error
+	AddGroup(g *groups.Group) error
+	UpdateGroup(g *groups.Group) error
+	DeleteGroup(g *groups.Group) error
+	AddUserToGroup(userID, groupID string) error
+	RemoveUserFromGroup(userID, groupID string) error
+	UpdateProviderSettings(p *notifications.Providers) error
+	ReplaceUserContactRules(userID string, rules []*contactrules.Rule) error
+	SaveQuietHours(q *contactrules.QuietHours) error
+	DeleteQuietHours(userID string) error
+	AddMaintenanceWindow(w *maintenance.Window) error
+	UpdateMaintenanceWindow(w *maintenance.Window) error
+	DeleteMaintenanceWindow(id string) error
+	AddTemplate(t *templates.Template) error
+	UpdateTemplate(t *templates.Template) error
+	DeleteTemplate(id string) error
+
+	// DeclarativeTransaction runs fn in a transaction that holds a lock,
+	// so applies from several replicas or the CLI run one at a time.
+	DeclarativeTransaction(fn func(tx Repository) error) error
+}
+
+type ServiceInterface interface {
+	Apply(cfg *Config, opts Options) (*Plan, error)
+}
+
+type Service struct {
+	repo   Repository
+	logger *zap.SugaredLogger
+}
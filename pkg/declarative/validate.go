@@ -0,0 +1,174 @@
+package declarative
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/root-ali/iris/pkg/contactrules"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/root-ali/iris/pkg/maintenance"
+	"github.com/root-ali/iris/pkg/matchers"
+	"github.com/root-ali/iris/pkg/templates"
+)
+
+var knownRoles = []string{"admin", "viewer"}
+
+// Validate checks the config on its own, references to objects that are
+// only in the database are checked when planning.
+func (cfg *Config) Validate() error {
+	seen := make(map[Kind]map[string]bool)
+	check := func(kind Kind, name string) error {
+		if name == "" {
+			return invalid("%s without a name", kind)
+		}
+		if seen[kind] == nil {
+			seen[kind] = make(map[string]bool)
+		}
+		if seen[kind][name] {
+			return invalid("%s %q is declared twice", kind, name)
+		}
+		seen[kind][name] = true
+		return nil
+	}
+
+	for _, u := range cfg.Users {
+		if err := check(KindUser, u.UserName); err != nil {
+			return err
+		}
+		if u.Role != "" && !slices.Contains(knownRoles, u.Role) {
+			return invalid("user %q: role must be admin or viewer", u.UserName)
+		}
+	}
+	for _, g := range cfg.Groups {
+		if err := check(KindGroup, g.Name); err != nil {
+			return err
+		}
+		members := make(map[string]bool)
+		for _, m := range g.Members {
+			if members[m] {
+				return invalid("group %q: member %q is listed twice", g.Name, m)
+			}
+			members[m] = true
+		}
+	}
+	for _, p := range cfg.Providers {
+		if err := check(KindProvider, p.Name); err != nil {
+			return err
+		}
+	}
+	for _, r := range cfg.Routes {
+		if err := check(KindRoute, r.User); err != nil {
+			return err
+		}
+		for _, rule := range r.rules() {
+			if err := rule.Validate(); err != nil {
+				return invalid("route %q: %v", r.User, err)
+			}
+		}
+		if q := r.quietHours(""); q != nil {
+			if err := q.Validate(); err != nil {
+				return invalid("route %q: quiet hours: %v", r.User, err)
+			}
+		}
+	}
+	for _, s := range cfg.Silences {
+		if err := check(KindSilence, s.Name); err != nil {
+			return err
+		}
+		w, err := s.window()
+		if err == nil {
+			err = w.Validate()
+		}
+		if err == nil {
+			_, err = matchers.ParseStrings(w.Matchers)
+		}
+		if err != nil {
+			return invalid("silence %q: %v", s.Name, err)
+		}
+	}
+	for _, t := range cfg.Templates {
+		if err := check(KindTemplate, t.Name); err != nil {
+			return err
+		}
+		if err := t.template().Validate(); err != nil {
+			return invalid("template %q: %v", t.Name, err)
+		}
+	}
+	return nil
+}
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", iris_error.ErrInvalidDeclaredConfig, fmt.Sprintf(format, args...))
+}
+
+func (r Route) rules() []*contactrules.Rule {
+	rules := make([]*contactrules.Rule, 0, len(r.Rules))
+	for _, rule := range r.Rules {
+		rules = append(rules, &contactrules.Rule{Severity: rule.Severity, Methods: rule.Methods})
+	}
+	return rules
+}
+
+// quietHours applies the defaults of the contact rules service.
+func (r Route) quietHours(userID string) *contactrules.QuietHours {
+	if r.QuietHours == nil {
+		return nil
+	}
+	q := &contactrules.QuietHours{
+		UserID:           userID,
+		Start:            r.QuietHours.Start,
+		End:              r.QuietHours.End,
+		Timezone:         r.QuietHours.Timezone,
+		BypassSeverities: r.QuietHours.BypassSeverities,
+	}
+	if q.Timezone == "" {
+		q.Timezone = "UTC"
+	}
+	if q.BypassSeverities == nil {
+		q.BypassSeverities = []string{"critical"}
+	}
+	return q
+}
+
+func (s Silence) window() (*maintenance.Window, error) {
+	d, err := time.ParseDuration(s.Duration)
+	if err != nil {
+		return nil, fmt.Errorf("invalid duration: %w", err)
+	}
+	w := &maintenance.Window{
+		Name:            s.Name,
+		Description:     s.Description,
+		ScheduleType:    maintenance.ScheduleType(s.ScheduleType),
+		Schedule:        s.Schedule,
+		DurationSeconds: int64(d / time.Second),
+		Timezone:        s.Timezone,
+		Matchers:        s.Matchers,
+		Groups:          s.Groups,
+		EndsAt:          s.EndsAt,
+	}
+	if w.Matchers == nil {
+		w.Matchers = []string{}
+	}
+	if w.Groups == nil {
+		w.Groups = []string{}
+	}
+	if s.StartsAt != nil {
+		w.StartsAt = *s.StartsAt
+	}
+	return w, nil
+}
+
+func (t Template) template() *templates.Template {
+	tmpl := &templates.Template{
+		Name:        t.Name,
+		Description: t.Description,
+		Matchers:    t.Matchers,
+		Subject:     t.Subject,
+		Body:        t.Body,
+	}
+	if tmpl.Matchers == nil {
+		tmpl.Matchers = []string{}
+	}
+	return tmpl
+}
@@ -13,9 +13,51 @@ var (
 	ErrInvalidToken             = errors.New("invalid token")
 	ErrUserNotVerified          = errors.New("user is not verified yet")
 	ErrRoleNotFound             = errors.New("role not found")
+	ErrUserNotFound             = errors.New("user not found")
+	ErrDefaultAdminUser         = errors.New("the default admin user cannot be deleted or lose the admin role")
 
 	ErrGroupAlreadyexisted = errors.New("group already exists")
 	ErrGroupNotFound       = errors.New("group not found")
 
 	ErrProviderNotFound = errors.New("provider not found")
+
+	ErrAlertNotFound = errors.New("alert not found")
+
+	ErrMaintenanceWindowNotFound = errors.New("maintenance window not found")
+
+	ErrQuietHoursNotFound = errors.New("quiet hours not found")
+
+	ErrContactMethodNotFound        = errors.New("contact method not found")
+	ErrContactMethodAlreadyExists   = errors.New("contact method already exists")
+	ErrContactMethodAlreadyVerified = errors.New("contact method already verified")
+	ErrVerificationCodeInvalid      = errors.New("verification code is invalid")
+	ErrVerificationCodeExpired      = errors.New("verification code expired, request a new one")
+	ErrVerificationCodeNotSent      = errors.New("failed to send verification code")
+
+	ErrLinkTokenInvalid  = errors.New("link token is invalid or expired")
+	ErrChatNotLinked     = errors.New("this chat is not linked to an iris user")
+	ErrSlashTokenInvalid = errors.New("slash command token is invalid")
+
+	ErrCallbackTokenInvalid = errors.New("callback token is invalid")
+	ErrMessageNotFound      = errors.New("message not found")
+
+	ErrCircuitOpen = errors.New("provider circuit breaker is open")
+
+	ErrInvalidReportQuery = errors.New("invalid report query")
+
+	ErrIncidentNotFound      = errors.New("incident not found")
+	ErrIncidentResolved      = errors.New("incident is already resolved")
+	ErrIncidentAcknowledged  = errors.New("incident is already acknowledged")
+	ErrIncidentInvalidStatus = errors.New("invalid incident status")
+
+	ErrStatusComponentNotFound = errors.New("status component not found")
+
+	ErrInvalidAlertQuery = errors.New("invalid alert query")
+
+	ErrInvalidBulkRequest = errors.New("invalid bulk alert request")
+	ErrInvalidAlertRecord = errors.New("invalid alert record")
+
+	ErrTemplateNotFound      = errors.New("notification template not found")
+	ErrTemplateAlreadyExists = errors.New("notification template already exists")
+	ErrInvalidDeclaredConfig = errors.New("invalid declarative config")
 )
@@ -5,6 +5,7 @@ import (
 	iris_error "github.com/root-ali/iris/pkg/errors"
 	"github.com/root-ali/iris/pkg/util"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 	"time"
 )
 
@@ -43,6 +44,27 @@ func (gr *GroupService) GetGroup(name string) (*Group, error) {
 	return gr.gr.GetGroupByName(name)
 }
 
+func (gr *GroupService) GetGroupByID(id string) (*Group, error) {
+	g, err := gr.gr.GetGroupById(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, iris_error.ErrGroupNotFound
+	}
+	return g, err
+}
+
+// UpdateGroup sets the name and description of the group with the id of g.
+func (gr *GroupService) UpdateGroup(g *Group) error {
+	existing, err := gr.gr.GetGroupByName(g.Name)
+	if err != nil && !errors.Is(err, iris_error.ErrGroupNotFound) {
+		return err
+	}
+	if existing != nil && existing.ID != g.ID {
+		return iris_error.ErrGroupAlreadyexisted
+	}
+	g.ModifiedAt = time.Now()
+	return gr.gr.UpdateGroup(g)
+}
+
 func (gr *GroupService) DeleteGroup(g *Group) error {
 	return gr.gr.DeleteGroup(g)
 }
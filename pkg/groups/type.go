@@ -12,6 +12,7 @@ type GroupRepositoryInterface interface {
 	GetGroupByName(string) (*Group, error)
 	GetAllGroups() ([]*Group, error)
 	DeleteGroup(*Group) error
+	UpdateGroup(*Group) error
 	AddUserToGroup(string, string) error
 	RemoveUserFromGroup(string, string) error
 	FindUsersById(string) ([]string, error)
@@ -21,6 +22,8 @@ type GroupRepositoryInterface interface {
 type GroupServiceInterface interface {
 	CreateGroup(*Group) error
 	GetGroup(string) (*Group, error)
+	GetGroupByID(string) (*Group, error)
+	UpdateGroup(*Group) error
 	GetAllGroups() ([]*Group, error)
 	DeleteGroup(*Group) error
 	RemoveUser(*Group, string) error
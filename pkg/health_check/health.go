@@ -1,11 +1,22 @@
 package health_check
 
+import "time"
+
 type Health struct {
 	Checks []Checks `json:"checks"`
 	Status string   `json:"status"`
 }
 
 type Checks struct {
-	Name   string `json:"name"`
-	Status string `json:"status"`
+	Name      string     `json:"name"`
+	Status    string     `json:"status"`
+	Message   string     `json:"message,omitempty"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+}
+
+// ReadinessCheck is a component that has to be ready before Iris takes
+// traffic, Check returns an error until it is.
+type ReadinessCheck struct {
+	Name  string
+	Check func() error
 }
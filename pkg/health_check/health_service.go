@@ -1,9 +1,27 @@
 package health_check
 
-import "go.uber.org/zap"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/root-ali/iris/pkg/notifications"
+	"go.uber.org/zap"
+)
+
+const (
+	// probeTTL is how long provider probe results are reused, probes call
+	// the provider APIs and must not run on every request.
+	probeTTL     = time.Minute
+	probeTimeout = 5 * time.Second
+)
 
 type HealthRepostiry interface {
 	Health() error
+	Migrated() error
 }
 
 type HealthService interface {
@@ -12,14 +30,28 @@ type HealthService interface {
 }
 
 type healthService struct {
-	log *zap.SugaredLogger
-	hr  HealthRepostiry
+	log       *zap.SugaredLogger
+	hr        HealthRepostiry
+	providers []notifications.NotificationInterface
+	readiness []ReadinessCheck
+
+	mu     sync.Mutex
+	probes map[string]Checks
 }
 
-func NewHealthService(l *zap.SugaredLogger, hr HealthRepostiry) HealthService {
-	return &healthService{log: l, hr: hr}
+func NewHealthService(l *zap.SugaredLogger, hr HealthRepostiry, providers []notifications.NotificationInterface,
+	readiness ...ReadinessCheck) HealthService {
+	return &healthService{
+		log:       l,
+		hr:        hr,
+		providers: providers,
+		readiness: readiness,
+		probes:    make(map[string]Checks),
+	}
 }
 
+// Healthy is down when the database is, a failing provider only degrades
+// it since alerts still go out through the others.
 func (hs *healthService) Healthy() (*Health, error) {
 	err := hs.hr.Health()
 	var chks []Checks
@@ -31,16 +63,82 @@ func (hs *healthService) Healthy() (*Health, error) {
 			Status: "cannot get connection from database",
 		})
 		return &h, err
-	} else {
-		h.Status = "UP"
-		h.Checks = append(chks, Checks{
-			Name:   "postgresql",
-			Status: "database is UP",
-		})
-		return &h, nil
 	}
+	h.Status = "UP"
+	h.Checks = append(chks, Checks{
+		Name:   "postgresql",
+		Status: "database is UP",
+	})
+	for _, c := range hs.providerChecks() {
+		if c.Status != "UP" {
+			h.Status = "Degraded"
+		}
+		h.Checks = append(h.Checks, c)
+	}
+	return &h, nil
 }
 
+// Ready fails until migrations are applied and every readiness check
+// passes.
 func (hs *healthService) Ready() error {
+	var pending []string
+	if err := hs.hr.Migrated(); err != nil {
+		hs.log.Warnw("Not ready", "component", "migrations", "error", err)
+		pending = append(pending, "migrations")
+	}
+	for _, r := range hs.readiness {
+		if err := r.Check(); err != nil {
+			hs.log.Warnw("Not ready", "component", r.Name, "error", err)
+			pending = append(pending, r.Name)
+		}
+	}
+	if len(pending) > 0 {
+		return errors.New("waiting for " + strings.Join(pending, ", "))
+	}
 	return nil
 }
+
+// providerChecks returns the cached probe of every provider and probes the
+// stale ones concurrently.
+func (hs *healthService) providerChecks() []Checks {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	now := time.Now()
+	var wg sync.WaitGroup
+	var resMu sync.Mutex
+	for _, p := range hs.providers {
+		if c, ok := hs.probes[p.GetName()]; ok && now.Sub(*c.CheckedAt) < probeTTL {
+			continue
+		}
+		wg.Add(1)
+		go func(p notifications.NotificationInterface) {
+			defer wg.Done()
+			c := hs.probe(p)
+			resMu.Lock()
+			hs.probes[p.GetName()] = c
+			resMu.Unlock()
+		}(p)
+	}
+	wg.Wait()
+
+	checks := make([]Checks, 0, len(hs.providers))
+	for _, p := range hs.providers {
+		checks = append(checks, hs.probes[p.GetName()])
+	}
+	return checks
+}
+
+func (hs *healthService) probe(p notifications.NotificationInterface) Checks {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+	err := notifications.Probe(ctx, p)
+	now := time.Now()
+	c := Checks{Name: fmt.Sprintf("provider:%s", p.GetName()), Status: "UP", CheckedAt: &now}
+	if err != nil {
+		hs.log.Warnw("Provider health check failed", "provider", p.GetName(), "error", err)
+		c.Status = "Down"
+		c.Message = err.Error()
+	}
+	return c
+}
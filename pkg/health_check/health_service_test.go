@@ -0,0 +1,68 @@
+package health_check
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/root-ali/iris/pkg/notifications"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeRepo struct {
+	migrated error
+}
+
+func (f *fakeRepo) Health() error   { return nil }
+func (f *fakeRepo) Migrated() error { return f.migrated }
+
+type fakeProvider struct {
+	name   string
+	err    error
+	probes int
+}
+
+func (f *fakeProvider) Send(notifications.Message) ([]string, error) { return nil, nil }
+func (f *fakeProvider) Status(string) (notifications.MessageStatusType, error) {
+	return notifications.TypeMessageStatusDelivered, nil
+}
+func (f *fakeProvider) Verify() (string, error) {
+	f.probes++
+	return "", f.err
+}
+func (f *fakeProvider) GetName() string  { return f.name }
+func (f *fakeProvider) GetFlag() string  { return "sms" }
+func (f *fakeProvider) GetPriority() int { return 1 }
+
+func TestHealthyProviders(t *testing.T) {
+	ok := &fakeProvider{name: "Kavenegar"}
+	down := &fakeProvider{name: "Smsir", err: errors.New("401 unauthorized")}
+	hs := NewHealthService(zap.NewNop().Sugar(), &fakeRepo{}, []notifications.NotificationInterface{ok, down})
+
+	h, err := hs.Healthy()
+	assert.NoError(t, err)
+	assert.Equal(t, "Degraded", h.Status)
+	assert.Len(t, h.Checks, 3)
+	assert.Equal(t, "provider:Kavenegar", h.Checks[1].Name)
+	assert.Equal(t, "UP", h.Checks[1].Status)
+	assert.Equal(t, "Down", h.Checks[2].Status)
+	assert.Equal(t, "401 unauthorized", h.Checks[2].Message)
+
+	// Probe results are cached
+	_, _ = hs.Healthy()
+	assert.Equal(t, 1, ok.probes)
+}
+
+func TestReady(t *testing.T) {
+	repo := &fakeRepo{migrated: errors.New("migration 20 is dirty")}
+	cached := errors.New("receptors are not cached yet")
+	hs := NewHealthService(zap.NewNop().Sugar(), repo, nil,
+		ReadinessCheck{Name: "receptor_cache", Check: func() error { return cached }})
+
+	err := hs.Ready()
+	assert.EqualError(t, err, "waiting for migrations, receptor_cache")
+
+	repo.migrated = nil
+	cached = nil
+	assert.NoError(t, hs.Ready())
+}
@@ -0,0 +1,326 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/root-ali/iris/pkg/http/middlewares"
+	"github.com/root-ali/iris/pkg/http/rest"
+	v1 "github.com/root-ali/iris/pkg/http/rest/v1"
+	"github.com/root-ali/iris/pkg/openapi"
+)
+
+const apiV1Prefix = "/api/v1"
+
+// apiV1 routes /api/v1 and documents every route it adds, requests are
+// validated against the same document that is served.
+type apiV1 struct {
+	ht     *HttpHandler
+	router *gin.RouterGroup
+	doc    *openapi.Document
+}
+
+// resource is a collection served under path. Handlers left nil are not
+// routed.
+type resource struct {
+	path        string
+	tag         string
+	name        string
+	noun        string
+	model       any
+	request     any
+	createBody  any
+	readRoles   string
+	writeRoles  string
+	list        gin.HandlerFunc
+	create      gin.HandlerFunc
+	get         gin.HandlerFunc
+	update      gin.HandlerFunc
+	delete      gin.HandlerFunc
+	description string
+}
+
+func (ht *HttpHandler) registerAPIv1(router *gin.Engine) {
+	api := &apiV1{
+		ht:     ht,
+		router: router.Group(apiV1Prefix),
+		doc: openapi.NewDocument(openapi.Info{
+			Title:   "Iris API",
+			Version: "1.0.0",
+			Description: "Resources are listed with GET, created with POST and read, replaced (PUT), " +
+				"changed (PATCH) and deleted by id.",
+		}),
+	}
+	api.doc.Servers = []openapi.Server{{URL: apiV1Prefix}}
+	api.doc.Components.SecuritySchemes["bearerAuth"] = &openapi.SecurityScheme{
+		Type:         "http",
+		Scheme:       "bearer",
+		BearerFormat: "JWT",
+		Description:  "token returned by POST /v0/users/signin",
+	}
+	api.doc.Components.SecuritySchemes["basicAuth"] = &openapi.SecurityScheme{
+		Type:        "http",
+		Scheme:      "basic",
+		Description: "the admin user and the admin password of the configuration",
+	}
+	api.doc.Security = []openapi.SecurityRequirement{{"bearerAuth": {}}}
+	api.doc.Components.Schemas["Error"] = &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"status":  {Type: "string"},
+			"message": {Type: "string"},
+			"errors":  {Type: "array", Items: &openapi.Schema{Type: "string"}, Description: "the values that failed validation"},
+		},
+		Required: []string{"status", "message"},
+	}
+
+	api.resource(resource{
+		path: "/users", tag: "users", name: "User", noun: "user",
+		model: v1.User{}, request: v1.UserRequest{}, createBody: v1.NewUserRequest{},
+		readRoles: "admin", writeRoles: "admin",
+		list:        v1.ListUsersHandler(ht.US, ht.Logger),
+		create:      v1.CreateUserHandler(ht.US, ht.Logger),
+		get:         v1.GetUserHandler(ht.US, ht.Logger),
+		update:      v1.UpdateUserHandler(ht.US, ht.Logger),
+		delete:      v1.DeleteUserHandler(ht.US, ht.Logger),
+		description: "The username cannot change. The default admin user cannot be deleted or lose the admin role.",
+	})
+	api.resource(resource{
+		path: "/groups", tag: "groups", name: "Group", noun: "group",
+		model: v1.Group{}, request: v1.GroupRequest{},
+		readRoles: "admin", writeRoles: "admin",
+		list:   v1.ListGroupsHandler(ht.GR, ht.Logger),
+		create: v1.CreateGroupHandler(ht.GR, ht.Logger),
+		get:    v1.GetGroupHandler(ht.GR, ht.Logger),
+		update: v1.UpdateGroupHandler(ht.GR, ht.Logger),
+		delete: v1.DeleteGroupHandler(ht.GR, ht.Logger),
+	})
+	api.handle(http.MethodGet, "/groups/:id/members", "admin", &openapi.Operation{
+		OperationID: "listGroupMembers",
+		Summary:     "List the members of a group",
+		Tags:        []string{"groups"},
+		Responses:   map[string]*openapi.Response{"200": api.listResponse(v1.Member{})},
+	}, v1.ListGroupMembersHandler(ht.GR, ht.US, ht.Logger))
+	api.handle(http.MethodPut, "/groups/:id/members/:user_id", "admin", &openapi.Operation{
+		OperationID: "addGroupMember",
+		Summary:     "Add a user to a group",
+		Description: "Adding a member twice is not an error.",
+		Tags:        []string{"groups"},
+		Responses:   map[string]*openapi.Response{"204": openapi.JSONResponse("The user is a member", nil)},
+	}, v1.AddGroupMemberHandler(ht.GR, ht.US, ht.Logger))
+	api.handle(http.MethodDelete, "/groups/:id/members/:user_id", "admin", &openapi.Operation{
+		OperationID: "removeGroupMember",
+		Summary:     "Remove a user from a group",
+		Tags:        []string{"groups"},
+		Responses:   map[string]*openapi.Response{"204": openapi.JSONResponse("The user was removed", nil)},
+	}, v1.RemoveGroupMemberHandler(ht.GR, ht.Logger))
+	api.resource(resource{
+		path: "/providers", tag: "providers", name: "Provider", noun: "provider",
+		model: v1.Provider{}, request: v1.ProviderRequest{},
+		readRoles: "admin", writeRoles: "admin",
+		list:        v1.ListProvidersHandler(ht.PS, ht.Logger),
+		get:         v1.GetProviderHandler(ht.PS, ht.Logger),
+		update:      v1.UpdateProviderHandler(ht.PS, ht.Logger),
+		description: "Providers come from the configuration, only their priority and status change.",
+	})
+	api.resource(resource{
+		path: "/maintenance-windows", tag: "maintenance", name: "MaintenanceWindow", noun: "maintenance window",
+		model: v1.MaintenanceWindow{}, request: v1.MaintenanceWindowRequest{},
+		readRoles: "admin,viewer", writeRoles: "admin",
+		list:   v1.ListMaintenanceWindowsHandler(ht.MS, ht.Logger),
+		create: v1.CreateMaintenanceWindowHandler(ht.MS, ht.Logger),
+		get:    v1.GetMaintenanceWindowHandler(ht.MS, ht.Logger),
+		update: v1.UpdateMaintenanceWindowHandler(ht.MS, ht.Logger),
+		delete: v1.DeleteMaintenanceWindowHandler(ht.MS, ht.Logger),
+	})
+	if ht.TS != nil {
+		api.resource(resource{
+			path: "/templates", tag: "templates", name: "Template", noun: "notification template",
+			model: v1.Template{}, request: v1.TemplateRequest{},
+			readRoles: "admin", writeRoles: "admin",
+			list:   v1.ListTemplatesHandler(ht.TS, ht.Logger),
+			create: v1.CreateTemplateHandler(ht.TS, ht.Logger),
+			get:    v1.GetTemplateHandler(ht.TS, ht.Logger),
+			update: v1.UpdateTemplateHandler(ht.TS, ht.Logger),
+			delete: v1.DeleteTemplateHandler(ht.TS, ht.Logger),
+		})
+	}
+	if ht.SP != nil {
+		api.resource(resource{
+			path: "/status-components", tag: "status page", name: "StatusComponent", noun: "status page component",
+			model: v1.StatusComponent{}, request: v1.StatusComponentRequest{},
+			readRoles: "admin", writeRoles: "admin",
+			list:   v1.ListStatusComponentsHandler(ht.SP, ht.Logger),
+			create: v1.CreateStatusComponentHandler(ht.SP, ht.Logger),
+			get:    v1.GetStatusComponentHandler(ht.SP, ht.Logger),
+			update: v1.UpdateStatusComponentHandler(ht.SP, ht.Logger),
+			delete: v1.DeleteStatusComponentHandler(ht.SP, ht.Logger),
+		})
+	}
+
+	// The webhook payload belongs to Alertmanager, it is documented but
+	// not validated so newer Alertmanager fields are accepted
+	api.doc.Add(http.MethodPost, "/alertmanager", &openapi.Operation{
+		OperationID: "receiveAlertmanagerWebhook",
+		Summary:     "Receive alerts from the Alertmanager webhook receiver",
+		Tags:        []string{"alerts"},
+		RequestBody: openapi.JSONBody(&openapi.Schema{Type: "object", Description: "Alertmanager webhook payload"}),
+		Responses: map[string]*openapi.Response{
+			"200":     openapi.JSONResponse("The alerts were received", nil),
+			"default": api.errorResponse(),
+		},
+		Security: []openapi.SecurityRequirement{{"basicAuth": {}}},
+	})
+	api.router.POST("/alertmanager",
+		middlewares.BasicAuth("admin", ht.AdminPassword),
+		rest.AlertManagerHandler(ht.AS))
+
+	spec, err := json.Marshal(api.doc)
+	if err != nil {
+		ht.Logger.Errorw("Failed to encode the OpenAPI document", "error", err)
+	}
+	api.router.GET("/openapi.json", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", spec)
+	})
+}
+
+// resource routes and documents the operations of r.
+func (a *apiV1) resource(r resource) {
+	item := a.path(r.path) + "/:" + v1.ID
+	create := r.createBody
+	if create == nil {
+		create = r.request
+	}
+	if r.list != nil {
+		a.handle(http.MethodGet, r.path, r.readRoles, &openapi.Operation{
+			OperationID: "list" + r.name + "s",
+			Summary:     "List " + r.tag,
+			Tags:        []string{r.tag},
+			Responses:   map[string]*openapi.Response{"200": a.listResponse(r.model)},
+		}, r.list)
+	}
+	if r.create != nil {
+		a.handle(http.MethodPost, r.path, r.writeRoles, &openapi.Operation{
+			OperationID: "create" + r.name,
+			Summary:     "Create a " + r.noun,
+			Description: r.description,
+			Tags:        []string{r.tag},
+			RequestBody: openapi.JSONBody(a.doc.SchemaOf(create)),
+			Responses:   map[string]*openapi.Response{"201": a.dataResponse("Created", r.model)},
+		}, r.create)
+	}
+	if r.get != nil {
+		a.handle(http.MethodGet, item, r.readRoles, &openapi.Operation{
+			OperationID: "get" + r.name,
+			Summary:     "Get a " + r.noun,
+			Tags:        []string{r.tag},
+			Responses:   map[string]*openapi.Response{"200": a.dataResponse("OK", r.model)},
+		}, r.get)
+	}
+	if r.update != nil {
+		a.handle(http.MethodPut, item, r.writeRoles, &openapi.Operation{
+			OperationID: "replace" + r.name,
+			Summary:     "Replace a " + r.noun,
+			Description: r.description,
+			Tags:        []string{r.tag},
+			RequestBody: openapi.JSONBody(a.doc.SchemaOf(r.request)),
+			Responses:   map[string]*openapi.Response{"200": a.dataResponse("Replaced", r.model)},
+		}, r.update)
+		a.handle(http.MethodPatch, item, r.writeRoles, &openapi.Operation{
+			OperationID: "update" + r.name,
+			Summary:     "Change fields of a " + r.noun,
+			Description: strings.TrimSpace("Fields missing from the body keep their value. " + r.description),
+			Tags:        []string{r.tag},
+			RequestBody: openapi.JSONBody(a.doc.PartialOf(r.request)),
+			Responses:   map[string]*openapi.Response{"200": a.dataResponse("Updated", r.model)},
+		}, r.update)
+	}
+	if r.delete != nil {
+		a.handle(http.MethodDelete, item, r.writeRoles, &openapi.Operation{
+			OperationID: "delete" + r.name,
+			Summary:     "Delete a " + r.noun,
+			Description: r.description,
+			Tags:        []string{r.tag},
+			Responses:   map[string]*openapi.Response{"204": openapi.JSONResponse("Deleted", nil)},
+		}, r.delete)
+	}
+}
+
+// handle documents op at path and routes it behind the JWT check for roles
+// and the validation of the request.
+func (a *apiV1) handle(method, path, roles string, op *openapi.Operation, h gin.HandlerFunc) {
+	for _, segment := range strings.Split(path, "/") {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			description := "id of the resource"
+			if name != v1.ID {
+				description = "id of the " + strings.TrimSuffix(name, "_id")
+			}
+			op.Parameters = append(op.Parameters, openapi.PathParameter(name, description))
+		}
+	}
+	if roles != "" {
+		op.Description = strings.TrimSpace(op.Description + " Requires the " +
+			strings.ReplaceAll(roles, ",", " or ") + " role.")
+	}
+	op.Responses["400"] = a.errorResponseWith("The request does not match the schema")
+	op.Responses["401"] = a.errorResponseWith("The token is missing, invalid or lacks the role")
+	if len(op.Parameters) > 0 {
+		op.Responses["404"] = a.errorResponseWith("Not found")
+	}
+	if method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch {
+		op.Responses["409"] = a.errorResponseWith("Conflicts with an existing resource")
+	}
+	op.Responses["default"] = a.errorResponse()
+	a.doc.Add(method, a.path(path), op)
+
+	handlers := []gin.HandlerFunc{middlewares.ValidateJWTToken(a.ht.ATHS, roles, a.ht.Logger)}
+	if op.RequestBody != nil {
+		handlers = append(handlers, middlewares.CheckContentTypeHeader("application/json", a.ht.Logger))
+	}
+	handlers = append(handlers, middlewares.ValidateRequest(a.doc, op, a.ht.Logger), h)
+	a.router.Handle(method, path, handlers...)
+}
+
+// path converts the :param segments of a gin path to {param}.
+func (a *apiV1) path(p string) string {
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func (a *apiV1) dataResponse(description string, model any) *openapi.Response {
+	return openapi.JSONResponse(description, &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"status": {Type: "string", Enum: []any{"success"}},
+			"data":   a.doc.SchemaOf(model),
+		},
+		Required: []string{"status", "data"},
+	})
+}
+
+func (a *apiV1) listResponse(model any) *openapi.Response {
+	return openapi.JSONResponse("OK", &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"status": {Type: "string", Enum: []any{"success"}},
+			"data":   {Type: "array", Items: a.doc.SchemaOf(model)},
+			"count":  {Type: "integer"},
+		},
+		Required: []string{"status", "data", "count"},
+	})
+}
+
+func (a *apiV1) errorResponse() *openapi.Response {
+	return a.errorResponseWith("Error")
+}
+
+func (a *apiV1) errorResponseWith(description string) *openapi.Response {
+	return openapi.JSONResponse(description, &openapi.Schema{Ref: "#/components/schemas/Error"})
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/root-ali/iris/pkg/groups"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type adminAuth struct{}
+
+func (adminAuth) GenerateToken(string, string) (string, error) { return "token", nil }
+func (adminAuth) ValidateToken(string) (string, string, error) { return "admin", "admin", nil }
+
+// groupStore keeps a single group.
+type groupStore struct {
+	groups.GroupServiceInterface
+	group *groups.Group
+}
+
+func (s *groupStore) CreateGroup(g *groups.Group) error {
+	g.ID = "g1"
+	s.group = g
+	return nil
+}
+
+func (s *groupStore) GetGroupByID(string) (*groups.Group, error) { return s.group, nil }
+
+func (s *groupStore) UpdateGroup(g *groups.Group) error {
+	s.group = g
+	return nil
+}
+
+func TestAPIv1(t *testing.T) {
+	gs := &groupStore{}
+	ht := &HttpHandler{ATHS: adminAuth{}, GR: gs, GinMode: "test", Logger: zap.NewNop().Sugar()}
+	router := ht.Handler()
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer token")
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("document", func(t *testing.T) {
+		w := serve(http.MethodGet, "/api/v1/openapi.json", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var doc struct {
+			OpenAPI string                                `json:"openapi"`
+			Paths   map[string]map[string]json.RawMessage `json:"paths"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+		assert.Equal(t, "3.0.3", doc.OpenAPI)
+		assert.Contains(t, doc.Paths["/groups"], "post")
+		for _, method := range []string{"get", "put", "patch", "delete"} {
+			assert.Contains(t, doc.Paths["/groups/{id}"], method)
+		}
+		assert.Contains(t, doc.Paths["/groups/{id}/members/{user_id}"], "put")
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		w := serve(http.MethodPost, "/api/v1/groups", `{"name":"ab","owner":"x"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var resp struct {
+			Errors []string `json:"errors"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, []string{"body.name must be at least 3 characters", "body.owner is not a known field"}, resp.Errors)
+		assert.Nil(t, gs.group)
+	})
+
+	t.Run("create and patch", func(t *testing.T) {
+		w := serve(http.MethodPost, "/api/v1/groups", `{"name":"oncall","description":"pager"}`)
+		require.Equal(t, http.StatusCreated, w.Code)
+
+		// PATCH leaves out the required name, PUT does not
+		w = serve(http.MethodPut, "/api/v1/groups/g1", `{"description":"night"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		w = serve(http.MethodPatch, "/api/v1/groups/g1", `{"description":"night"}`)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "oncall", gs.group.Name)
+		assert.Equal(t, "night", gs.group.Description)
+	})
+}
@@ -70,9 +70,6 @@ func (ht *HttpHandler) Handler() *gin.Engine {
 	captchaRouter := router.Group("/v0/captcha")
 	captchaRouter.GET("/generate",
 		rest.GenerateCaptchaHandler(ht.CS, ht.Logger))
-	// captchaRouter.POST("/verify",
-	// 	middlewares.CheckContentTypeHeader("application/json", ht.Logger),
-	// 	rest.VerifyCaptchaHandler(ht.CS, ht.Logger))
 
 	// Message handler routes
 	messageRouter := router.Group("v1/messages",
@@ -91,6 +88,27 @@ func (ht *HttpHandler) Handler() *gin.Engine {
 	alertRouter.GET("/firingCount",
 		middlewares.ValidateJWTToken(ht.ATHS, "admin,viewer", ht.Logger),
 		rest.GetFiringAlertsBySeverity(ht.AS))
+	alertRouter.GET("/search",
+		middlewares.ValidateJWTToken(ht.ATHS, "admin,viewer", ht.Logger),
+		rest.SearchAlertsHandler(ht.AS, ht.Logger))
+	alertRouter.POST("/bulk",
+		middlewares.ValidateJWTToken(ht.ATHS, "admin", ht.Logger),
+		middlewares.CheckContentTypeHeader("application/json", ht.Logger),
+		rest.BulkAlertsHandler(ht.AS, ht.US, ht.Logger))
+	alertRouter.GET("/bulk/audit",
+		middlewares.ValidateJWTToken(ht.ATHS, "admin", ht.Logger),
+		rest.GetAlertAuditLogHandler(ht.AS, ht.Logger))
+	alertRouter.GET("/export",
+		middlewares.ValidateJWTToken(ht.ATHS, "admin", ht.Logger),
+		rest.ExportAlertsHandler(ht.AS, ht.MES, ht.Logger))
+	alertRouter.POST("/import",
+		middlewares.ValidateJWTToken(ht.ATHS, "admin", ht.Logger),
+		rest.ImportAlertsHandler(ht.AS, ht.Logger))
+	if ht.AST != nil {
+		alertRouter.GET("/stream",
+			middlewares.ValidateJWTToken(ht.ATHS, "admin,viewer", ht.Logger),
+			rest.AlertStreamHandler(ht.AS, ht.AST, ht.Logger))
+	}
 
 	// User handler routes
 	userRouter := router.Group("v0/users")
@@ -111,7 +129,7 @@ func (ht *HttpHandler) Handler() *gin.Engine {
 	userRouter.PUT("",
 		middlewares.ValidateJWTToken(ht.ATHS, "admin,viewer", ht.Logger),
 		middlewares.CheckContentTypeHeader("application/json", ht.Logger),
-		rest.UpdateUserHandler(ht.US, ht.Logger),
+		rest.UpdateUserHandler(ht.US, ht.CMS, ht.Logger),
 	)
 	userRouter.GET("",
 		middlewares.ValidateJWTToken(ht.ATHS, "admin", ht.Logger),
@@ -134,6 +152,62 @@ func (ht *HttpHandler) Handler() *gin.Engine {
 		middlewares.ValidateJWTToken(ht.ATHS, "", ht.Logger),
 		rest.GetUserGroupsHandler(ht.GR, ht.Logger),
 	)
+	userRouter.GET("/:user_id/contact-rules",
+		middlewares.ValidateJWTToken(ht.ATHS, "", ht.Logger),
+		rest.GetContactPreferencesHandler(ht.CRS, ht.US, ht.Logger),
+	)
+	userRouter.PUT("/:user_id/contact-rules",
+		middlewares.ValidateJWTToken(ht.ATHS, "", ht.Logger),
+		middlewares.CheckContentTypeHeader("application/json", ht.Logger),
+		rest.SetContactRulesHandler(ht.CRS, ht.US, ht.Logger),
+	)
+	userRouter.PUT("/:user_id/quiet-hours",
+		middlewares.ValidateJWTToken(ht.ATHS, "", ht.Logger),
+		middlewares.CheckContentTypeHeader("application/json", ht.Logger),
+		rest.SetQuietHoursHandler(ht.CRS, ht.US, ht.Logger),
+	)
+	userRouter.DELETE("/:user_id/quiet-hours",
+		middlewares.ValidateJWTToken(ht.ATHS, "", ht.Logger),
+		rest.DeleteQuietHoursHandler(ht.CRS, ht.US, ht.Logger),
+	)
+	userRouter.GET("/:user_id/contact-methods",
+		middlewares.ValidateJWTToken(ht.ATHS, "", ht.Logger),
+		rest.GetContactMethodsHandler(ht.CMS, ht.US, ht.Logger),
+	)
+	userRouter.POST("/:user_id/contact-methods",
+		middlewares.ValidateJWTToken(ht.ATHS, "", ht.Logger),
+		middlewares.CheckContentTypeHeader("application/json", ht.Logger),
+		rest.AddContactMethodHandler(ht.CMS, ht.US, ht.Logger),
+	)
+	userRouter.DELETE("/:user_id/contact-methods/:method_id",
+		middlewares.ValidateJWTToken(ht.ATHS, "", ht.Logger),
+		rest.DeleteContactMethodHandler(ht.CMS, ht.US, ht.Logger),
+	)
+	userRouter.POST("/:user_id/contact-methods/:method_id/resend",
+		middlewares.ValidateJWTToken(ht.ATHS, "", ht.Logger),
+		rest.ResendContactMethodCodeHandler(ht.CMS, ht.US, ht.Logger),
+	)
+	userRouter.POST("/:user_id/contact-methods/:method_id/verify",
+		middlewares.ValidateJWTToken(ht.ATHS, "", ht.Logger),
+		middlewares.CheckContentTypeHeader("application/json", ht.Logger),
+		rest.VerifyContactMethodHandler(ht.CMS, ht.US, ht.Logger),
+	)
+	userRouter.POST("/:user_id/telegram-link",
+		middlewares.ValidateJWTToken(ht.ATHS, "", ht.Logger),
+		rest.CreateTelegramLinkHandler(ht.COS, ht.US, ht.Logger),
+	)
+
+	// Mattermost integration routes, authenticated by mattermost tokens
+	if ht.MMI != nil {
+		integrationRouter := router.Group("v0/integrations/mattermost")
+		integrationRouter.POST("/actions",
+			middlewares.CheckContentTypeHeader("application/json", ht.Logger),
+			rest.MattermostActionHandler(ht.MMI, ht.COS, ht.Logger),
+		)
+		integrationRouter.POST("/command",
+			rest.MattermostSlashCommandHandler(ht.MMI, ht.COS, ht.Logger),
+		)
+	}
 
 	// Group handler routes
 	groupRouter := router.Group("v0/groups")
@@ -160,6 +234,13 @@ func (ht *HttpHandler) Handler() *gin.Engine {
 		rest.GetUsersInGroupHandler(ht.GR, ht.US, ht.Logger),
 	)
 
+	// Delivery report callbacks, authenticated by the provider's callback token
+	if ht.DRH != nil {
+		dlrRouter := router.Group("v1/providers")
+		dlrRouter.GET("/:name/dlr", rest.DeliveryReportHandler(ht.NP, ht.DRH, ht.Logger))
+		dlrRouter.POST("/:name/dlr", rest.DeliveryReportHandler(ht.NP, ht.DRH, ht.Logger))
+	}
+
 	providerRouter := router.Group("v0/providers")
 	providerRouter.GET("",
 		middlewares.ValidateJWTToken(ht.ATHS, "admin", ht.Logger),
@@ -169,6 +250,106 @@ func (ht *HttpHandler) Handler() *gin.Engine {
 		middlewares.CheckContentTypeHeader("application/json", ht.Logger),
 		rest.ModifyProviderHandler(ht.PS))
 
+	clusterRouter := router.Group("v0/cluster")
+	clusterRouter.GET("",
+		middlewares.ValidateJWTToken(ht.ATHS, "admin", ht.Logger),
+		rest.GetClusterStatusHandler(ht.CLS, ht.Logger))
+
+	// Sent notifications and their delivery analytics
+	notificationRouter := router.Group("v0/messages")
+	notificationRouter.GET("",
+		middlewares.ValidateJWTToken(ht.ATHS, "admin,viewer", ht.Logger),
+		rest.GetMessagesHandler(ht.MES, ht.Logger))
+	notificationRouter.GET("/analytics",
+		middlewares.ValidateJWTToken(ht.ATHS, "admin,viewer", ht.Logger),
+		rest.GetMessageAnalyticsHandler(ht.MES, ht.Logger))
+
+	// Incidents and their timeline
+	incidentRouter := router.Group("v0/incidents")
+	incidentRouter.GET("",
+		middlewares.ValidateJWTToken(ht.ATHS, "admin,viewer", ht.Logger),
+		rest.GetIncidentsHandler(ht.IS, ht.Logger))
+	incidentRouter.POST("",
+		middlewares.ValidateJWTToken(ht.ATHS, "admin,viewer", ht.Logger),
+		middlewares.CheckContentTypeHeader("application/json", ht.Logger),
+		rest.CreateIncidentHandler(ht.IS, ht.US, ht.Logger))
+	incidentRouter.GET("/:incident_id",
+		middlewares.ValidateJWTToken(ht.ATHS, "admin,viewer", ht.Logger),
+		rest.GetIncidentHandler(ht.IS, ht.Logger))
+	incidentRouter.PUT("/:incident_id/acknowledge",
+		middlewares.ValidateJWTToken(ht.ATHS, "admin,viewer", ht.Logger),
+		rest.AcknowledgeIncidentHandler(ht.IS, ht.US, ht.Logger))
+	incidentRouter.PUT("/:incident_id/resolve",
+		middlewares.ValidateJWTToken(ht.ATHS, "admin,viewer", ht.Logger),
+		rest.ResolveIncidentHandler(ht.IS, ht.US, ht.Logger))
+	incidentRouter.PUT("/:incident_id/assignee",
+		middlewares.ValidateJWTToken(ht.ATHS, "admin,viewer", ht.Logger),
+		middlewares.CheckContentTypeHeader("application/json", ht.Logger),
+		rest.AssignIncidentHandler(ht.IS, ht.US, ht.Logger))
+	incidentRouter.POST("/:incident_id/notes",
+		middlewares.ValidateJWTToken(ht.ATHS, "admin,viewer", ht.Logger),
+		middlewares.CheckContentTypeHeader("application/json", ht.Logger),
+		rest.AddIncidentNoteHandler(ht.IS, ht.US, ht.Logger))
+	incidentRouter.POST("/:incident_id/alerts",
+		middlewares.ValidateJWTToken(ht.ATHS, "admin,viewer", ht.Logger),
+		middlewares.CheckContentTypeHeader("application/json", ht.Logger),
+		rest.LinkIncidentAlertsHandler(ht.IS, ht.US, ht.Logger))
+
+	// MTTA/MTTR and paging reports
+	reportRouter := router.Group("v0/reports")
+	reportRouter.GET("/incidents",
+		middlewares.ValidateJWTToken(ht.ATHS, "admin,viewer", ht.Logger),
+		rest.GetIncidentReportHandler(ht.RS, ht.Logger))
+
+	// Public status page, served without authentication when enabled
+	if ht.SP != nil {
+		statusRouter := router.Group("/status")
+		statusRouter.GET("", rest.StatusPageHandler(ht.SP, ht.Logger))
+		statusRouter.GET("/index.json", rest.StatusPageJSONHandler(ht.SP, ht.Logger))
+		statusRouter.GET("/feed.rss", rest.StatusPageFeedHandler(ht.SP, ht.Logger))
+
+		componentRouter := router.Group("v0/status-components")
+		componentRouter.GET("",
+			middlewares.ValidateJWTToken(ht.ATHS, "admin", ht.Logger),
+			rest.GetStatusComponentsHandler(ht.SP, ht.Logger))
+		componentRouter.GET("/:component_id",
+			middlewares.ValidateJWTToken(ht.ATHS, "admin", ht.Logger),
+			rest.GetStatusComponentHandler(ht.SP, ht.Logger))
+		componentRouter.POST("",
+			middlewares.ValidateJWTToken(ht.ATHS, "admin", ht.Logger),
+			middlewares.CheckContentTypeHeader("application/json", ht.Logger),
+			rest.CreateStatusComponentHandler(ht.SP, ht.Logger))
+		componentRouter.PUT("/:component_id",
+			middlewares.ValidateJWTToken(ht.ATHS, "admin", ht.Logger),
+			middlewares.CheckContentTypeHeader("application/json", ht.Logger),
+			rest.UpdateStatusComponentHandler(ht.SP, ht.Logger))
+		componentRouter.DELETE("/:component_id",
+			middlewares.ValidateJWTToken(ht.ATHS, "admin", ht.Logger),
+			rest.DeleteStatusComponentHandler(ht.SP, ht.Logger))
+	}
+
+	// Maintenance window routes
+	maintenanceRouter := router.Group("v0/maintenance")
+	maintenanceRouter.GET("",
+		middlewares.ValidateJWTToken(ht.ATHS, "admin,viewer", ht.Logger),
+		rest.GetMaintenanceWindowsHandler(ht.MS, ht.Logger))
+	maintenanceRouter.GET("/upcoming",
+		middlewares.ValidateJWTToken(ht.ATHS, "admin,viewer", ht.Logger),
+		rest.GetUpcomingMaintenanceHandler(ht.MS, ht.Logger))
+	maintenanceRouter.GET("/:window_id",
+		middlewares.ValidateJWTToken(ht.ATHS, "admin,viewer", ht.Logger),
+		rest.GetMaintenanceWindowHandler(ht.MS, ht.Logger))
+	maintenanceRouter.POST("",
+		middlewares.ValidateJWTToken(ht.ATHS, "admin", ht.Logger),
+		middlewares.CheckContentTypeHeader("application/json", ht.Logger),
+		rest.CreateMaintenanceWindowHandler(ht.MS, ht.Logger))
+	maintenanceRouter.DELETE("/:window_id",
+		middlewares.ValidateJWTToken(ht.ATHS, "admin", ht.Logger),
+		rest.DeleteMaintenanceWindowHandler(ht.MS, ht.Logger))
+
+	// Versioned API, documented at /api/v1/openapi.json
+	ht.registerAPIv1(router)
+
 	// Serve static files from web/build
 	router.Use(static.Serve("/", static.LocalFile("./web/build", true)))
 
@@ -0,0 +1,88 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/root-ali/iris/pkg/openapi"
+	"go.uber.org/zap"
+)
+
+// maxRequestBody bounds the bodies read for validation.
+const maxRequestBody = 1 << 20
+
+// ValidateRequest checks the parameters and the JSON body of the request
+// against the schemas of op. The body is put back for the handler.
+func ValidateRequest(doc *openapi.Document, op *openapi.Operation, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var errs []string
+		collect := func(err error) {
+			var ve *openapi.ValidationError
+			if errors.As(err, &ve) {
+				errs = append(errs, ve.Errors...)
+			}
+		}
+		for _, p := range op.Parameters {
+			var raw string
+			var ok bool
+			switch p.In {
+			case openapi.InPath:
+				raw = c.Param(p.Name)
+				ok = raw != ""
+			case openapi.InQuery:
+				raw, ok = c.GetQuery(p.Name)
+			}
+			if !ok {
+				if p.Required {
+					errs = append(errs, p.Name+" is required")
+				}
+				continue
+			}
+			collect(doc.Validate(p.Name, p.Schema, p.Decode(raw)))
+		}
+
+		if s := op.RequestBody.Schema(); s != nil {
+			body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxRequestBody))
+			if err != nil {
+				logger.Infow("Failed to read request body", "path", c.FullPath(), "error", err)
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+					"status":  "error",
+					"message": "Invalid request body",
+				})
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+			var v any
+			dec := json.NewDecoder(bytes.NewReader(body))
+			dec.UseNumber()
+			if err := dec.Decode(&v); err != nil {
+				logger.Infow("Invalid request body", "path", c.FullPath(), "error", err)
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+					"status":  "error",
+					"message": "Invalid request body",
+				})
+				return
+			}
+			collect(doc.Validate("body", s, v))
+		}
+
+		if len(errs) > 0 {
+			logger.Infow("Request does not match the API schema",
+				"method", c.Request.Method,
+				"path", c.FullPath(),
+				"errors", errs)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Validation failed",
+				"errors":  errs,
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,92 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/root-ali/iris/pkg/alerts"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/root-ali/iris/pkg/matchers"
+	"github.com/root-ali/iris/pkg/user"
+	"go.uber.org/zap"
+)
+
+type BulkAlertsRequestBody struct {
+	Action string   `json:"action" validate:"required,oneof=resolve ack silence delete renotify"`
+	IDs    []string `json:"ids,omitempty" validate:"max=1000,dive,required,max=100"`
+	// Filter is a list of label matchers like {severity="critical",team="db"}
+	Filter string `json:"filter,omitempty" validate:"max=2000"`
+	// Duration of the silence action, such as 1h or 30m
+	Duration string `json:"duration,omitempty"`
+	DryRun   bool   `json:"dry_run"`
+}
+
+// BulkAlertsHandler applies an action to the alerts selected by ids, a
+// filter or both. With dry_run it only returns the matched count.
+func BulkAlertsHandler(as alerts.Service, us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req BulkAlertsRequestBody
+		if !bindAndValidate(c, &req) {
+			return
+		}
+		ms, err := matchers.ParseList(req.Filter)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid filter: " + err.Error()})
+			return
+		}
+		var d time.Duration
+		if req.Duration != "" {
+			if d, err = time.ParseDuration(req.Duration); err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid duration"})
+				return
+			}
+		}
+		res, err := as.BulkAlerts(alerts.BulkRequest{
+			Action:     alerts.BulkAction(req.Action),
+			IDs:        req.IDs,
+			Matchers:   ms,
+			SilenceFor: d,
+			DryRun:     req.DryRun,
+			Actor:      currentUserID(c, us),
+		})
+		if errors.Is(err, iris_error.ErrInvalidBulkRequest) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		if err != nil {
+			logger.Errorw("Failed to apply bulk alert action", "action", req.Action, "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "result": res})
+	}
+}
+
+func GetAlertAuditLogHandler(as alerts.Service, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var limit, page int
+		var err error
+		if v := c.Query("limit"); v != "" {
+			if limit, err = strconv.Atoi(v); err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid limit in query param"})
+				return
+			}
+		}
+		if v := c.Query("page"); v != "" {
+			if page, err = strconv.Atoi(v); err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid page in query param"})
+				return
+			}
+		}
+		entries, err := as.GetAuditLog(limit, page)
+		if err != nil {
+			logger.Errorw("Failed to get alert audit log", "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "entries": entries, "count": len(entries)})
+	}
+}
@@ -0,0 +1,60 @@
+package rest
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/root-ali/iris/pkg/alerts"
+	"github.com/root-ali/iris/pkg/alertstream"
+	"go.uber.org/zap"
+)
+
+// streamHeartbeat keeps idle streams open through proxies.
+const streamHeartbeat = 25 * time.Second
+
+// AlertStreamHandler streams alert events as Server-Sent Events, starting
+// with the current firing count. The event name is the event type and
+// the data its JSON.
+func AlertStreamHandler(as alerts.Service, hub alertstream.Interface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		events, cancel := hub.Subscribe()
+		defer cancel()
+
+		counts, err := as.GetFiringAlertsBySeverity()
+		if err != nil {
+			logger.Errorw("Failed to get firing count for alert stream", "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		// nginx buffers responses unless told otherwise
+		c.Header("X-Accel-Buffering", "no")
+		c.SSEvent(string(alerts.EventFiringCount), alerts.Event{
+			Type:        alerts.EventFiringCount,
+			FiringCount: counts,
+			At:          time.Now(),
+		})
+		c.Writer.Flush()
+
+		heartbeat := time.NewTicker(streamHeartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case ev := <-events:
+				c.SSEvent(string(ev.Type), ev)
+			case <-heartbeat.C:
+				if _, err := io.WriteString(c.Writer, ": ping\n\n"); err != nil {
+					return
+				}
+			}
+			c.Writer.Flush()
+		}
+	}
+}
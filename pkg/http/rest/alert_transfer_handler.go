@@ -0,0 +1,281 @@
+package rest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/root-ali/iris/pkg/alerts"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/root-ali/iris/pkg/message"
+	"go.uber.org/zap"
+)
+
+const (
+	formatNDJSON = "ndjson"
+	formatCSV    = "csv"
+
+	exportPageSize  = 500
+	importBatchSize = 500
+	maxImportSize   = 100 << 20
+	maxImportLine   = 1 << 20
+	// Only the first errors of an import are listed.
+	maxImportErrors = 100
+)
+
+type exportedAlert struct {
+	alerts.Record
+	Messages []MessageResponse `json:"messages"`
+}
+
+type ImportLineError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// ExportAlertsHandler streams the alerts matching the filters of the alert
+// search, with their messages, as NDJSON or CSV (format query param).
+func ExportAlertsHandler(as alerts.Service, ms message.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		q, err := alertQueryFromQuery(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		format := c.DefaultQuery("format", formatNDJSON)
+		if format != formatNDJSON && format != formatCSV {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid format in query param"})
+			return
+		}
+		q.Limit = exportPageSize
+		q.Cursor = ""
+		res, err := as.SearchAlerts(q)
+		if errors.Is(err, iris_error.ErrInvalidAlertQuery) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		if err != nil {
+			logger.Errorw("Failed to export alerts", "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+
+		filename := fmt.Sprintf("alerts-%s.%s", time.Now().UTC().Format("20060102T150405Z"), format)
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+		var write func(e exportedAlert) error
+		var flush func() error
+		if format == formatCSV {
+			c.Header("Content-Type", "text/csv; charset=utf-8")
+			w := csv.NewWriter(c.Writer)
+			write = func(e exportedAlert) error {
+				msgs, err := json.Marshal(e.Messages)
+				if err != nil {
+					return err
+				}
+				return w.Write(append(e.Record.CSV(), string(msgs)))
+			}
+			flush = func() error {
+				w.Flush()
+				return w.Error()
+			}
+			if err := w.Write(append(append([]string{}, alerts.CSVColumns...), "messages")); err != nil {
+				return
+			}
+		} else {
+			c.Header("Content-Type", "application/x-ndjson")
+			enc := json.NewEncoder(c.Writer)
+			write = func(e exportedAlert) error { return enc.Encode(e) }
+			flush = func() error { return nil }
+		}
+		c.Status(http.StatusOK)
+
+		count := 0
+		for {
+			if err := writeExportPage(res.Alerts, ms, write); err != nil {
+				// the response has started, the client sees a truncated file
+				logger.Errorw("Failed to write alert export", "alerts", count, "error", err)
+				return
+			}
+			if err := flush(); err != nil {
+				logger.Errorw("Failed to write alert export", "alerts", count, "error", err)
+				return
+			}
+			c.Writer.Flush()
+			count += len(res.Alerts)
+			if res.NextCursor == "" || c.Request.Context().Err() != nil {
+				break
+			}
+			q.Cursor = res.NextCursor
+			if res, err = as.SearchAlerts(q); err != nil {
+				logger.Errorw("Failed to export alerts", "alerts", count, "error", err)
+				return
+			}
+		}
+		logger.Infow("Alerts exported", "format", format, "alerts", count)
+	}
+}
+
+func writeExportPage(als []*alerts.Alert, ms message.ServiceInterface, write func(e exportedAlert) error) error {
+	ids := make([]string, 0, len(als))
+	for _, a := range als {
+		ids = append(ids, a.Id)
+	}
+	byAlert, err := ms.ByAlerts(ids)
+	if err != nil {
+		return err
+	}
+	for _, a := range als {
+		e := exportedAlert{Record: a.Record(), Messages: make([]MessageResponse, 0, len(byAlert[a.Id]))}
+		for _, m := range byAlert[a.Id] {
+			e.Messages = append(e.Messages, toMessageResponse(m))
+		}
+		if err := write(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportAlertsHandler replays an NDJSON or CSV file, such as an export,
+// through the alert service. The format is taken from the format query
+// param or the Content-Type. Imported alerts are not notified unless
+// notify=true, messages of the file are not imported.
+func ImportAlertsHandler(as alerts.Service, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format := c.Query("format")
+		if format == "" {
+			format = formatNDJSON
+			if strings.HasPrefix(c.ContentType(), "text/csv") {
+				format = formatCSV
+			}
+		}
+		if format != formatNDJSON && format != formatCSV {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid format in query param"})
+			return
+		}
+		notify := c.Query("notify") == "true"
+
+		total := alerts.ImportResult{}
+		var lineErrors []ImportLineError
+		addError := func(line int, msg string) {
+			total.Failed++
+			if len(lineErrors) < maxImportErrors {
+				lineErrors = append(lineErrors, ImportLineError{Line: line, Message: msg})
+			}
+		}
+		var batch []alerts.Record
+		var lines []int
+		importBatch := func() {
+			if len(batch) == 0 {
+				return
+			}
+			res := as.ImportAlerts(c.Request.Context(), batch, notify)
+			total.Imported += res.Imported
+			total.Skipped += res.Skipped
+			for _, e := range res.Errors {
+				addError(lines[e.Index], e.Message)
+			}
+			batch, lines = batch[:0], lines[:0]
+		}
+		add := func(line int, r alerts.Record) {
+			batch = append(batch, r)
+			lines = append(lines, line)
+			if len(batch) >= importBatchSize {
+				importBatch()
+			}
+		}
+
+		body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+		var err error
+		if format == formatCSV {
+			err = readCSVRecords(body, add, addError)
+		} else {
+			err = readNDJSONRecords(body, add, addError)
+		}
+		importBatch()
+		logger.Infow("Alerts imported", "format", format, "imported", total.Imported,
+			"skipped", total.Skipped, "failed", total.Failed)
+
+		response := gin.H{
+			"status":   "success",
+			"imported": total.Imported,
+			"skipped":  total.Skipped,
+			"failed":   total.Failed,
+			"errors":   lineErrors,
+		}
+		if err != nil {
+			// the records read before the error are imported
+			response["status"] = "error"
+			response["message"] = err.Error()
+			c.AbortWithStatusJSON(http.StatusBadRequest, response)
+			return
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func readNDJSONRecords(r io.Reader, add func(int, alerts.Record), addError func(int, string)) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), maxImportLine)
+	line := 0
+	for sc.Scan() {
+		line++
+		b := bytes.TrimSpace(sc.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		var rec alerts.Record
+		if err := json.Unmarshal(b, &rec); err != nil {
+			addError(line, err.Error())
+			continue
+		}
+		add(line, rec)
+	}
+	return sc.Err()
+}
+
+// readCSVRecords reads a CSV file with a header row, lines are counted
+// from the header.
+func readCSVRecords(r io.Reader, add func(int, alerts.Record), addError func(int, string)) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		line, _ := cr.FieldPos(0)
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) && parseErr.Err == csv.ErrFieldCount {
+			addError(line, err.Error())
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		rec, err := alerts.RecordFromCSV(header, row)
+		if err != nil {
+			addError(line, err.Error())
+			continue
+		}
+		add(line, rec)
+	}
+}
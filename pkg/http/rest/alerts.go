@@ -1,12 +1,17 @@
 package rest
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/root-ali/iris/pkg/alerts"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/root-ali/iris/pkg/matchers"
+	"go.uber.org/zap"
 )
 
 type AlertResponse struct {
@@ -104,3 +109,72 @@ func toAlertResponse(alert []*alerts.Alert) []AlertResponse {
 	}
 	return alertResponses
 }
+
+// SearchAlertsHandler searches alerts with filter, a list of label matchers
+// like {severity="critical",team=~"db|infra"}, and the full-text query q.
+// The next page is fetched with the next_cursor of the response.
+func SearchAlertsHandler(as alerts.Service, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		q, err := alertQueryFromQuery(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		res, err := as.SearchAlerts(q)
+		if errors.Is(err, iris_error.ErrInvalidAlertQuery) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		if err != nil {
+			logger.Errorw("Failed to search alerts", "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"status":      "success",
+			"alerts":      res.Alerts,
+			"count":       len(res.Alerts),
+			"total":       res.Total,
+			"next_cursor": res.NextCursor,
+		})
+	}
+}
+
+func alertQueryFromQuery(c *gin.Context) (alerts.Query, error) {
+	q := alerts.Query{
+		Text:   c.Query("q"),
+		Sort:   alerts.SortField(c.Query("sort")),
+		Cursor: c.Query("cursor"),
+	}
+	ms, err := matchers.ParseList(c.Query("filter"))
+	if err != nil {
+		return q, fmt.Errorf("Invalid filter in query param: %w", err)
+	}
+	q.Matchers = ms
+	switch c.DefaultQuery("order", "desc") {
+	case "desc":
+		q.Desc = true
+	case "asc":
+	default:
+		return q, errors.New("Invalid order in query param")
+	}
+	for name, dst := range map[string]**time.Time{"from": &q.From, "to": &q.To} {
+		v := c.Query(name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return q, fmt.Errorf("Invalid %s in query param", name)
+		}
+		*dst = &t
+	}
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return q, errors.New("Invalid limit in query param")
+		}
+		q.Limit = n
+	}
+	return q, nil
+}
@@ -0,0 +1,28 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/root-ali/iris/pkg/cluster"
+	"go.uber.org/zap"
+)
+
+// GetClusterStatusHandler lists the live Iris instances and the instance
+// holding each scheduler lock, an empty holder means the lock is free.
+func GetClusterStatusHandler(cls cluster.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		st, err := cls.Status()
+		if err != nil {
+			logger.Errorw("Failed to get cluster status", "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"status":      "success",
+			"instance_id": st.InstanceID,
+			"members":     st.Members,
+			"locks":       st.Locks,
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/root-ali/iris/pkg/chatops"
+	"github.com/root-ali/iris/pkg/contactmethods"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/root-ali/iris/pkg/user"
+	"go.uber.org/zap"
+)
+
+type ContactMethodRequestBody struct {
+	Type  string `json:"type" validate:"required,oneof=sms mail telegram mattermost"`
+	Label string `json:"label,omitempty" validate:"omitempty,max=50"`
+	Value string `json:"value" validate:"required,max=255"`
+}
+
+type VerifyContactMethodRequestBody struct {
+	Code string `json:"code" validate:"required,numeric,len=6"`
+}
+
+func GetContactMethodsHandler(cms contactmethods.ServiceInterface, us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.Param("user_id")
+		if !authorizeUserAccess(c, us, userID, logger) {
+			return
+		}
+		methods, err := cms.List(userID)
+		if err != nil {
+			logger.Errorw("Failed to list contact methods", "userID", userID, "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "contact_methods": methods, "count": len(methods)})
+	}
+}
+
+func AddContactMethodHandler(cms contactmethods.ServiceInterface, us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.Param("user_id")
+		if !authorizeUserAccess(c, us, userID, logger) {
+			return
+		}
+		var req ContactMethodRequestBody
+		if !bindAndValidate(c, &req) {
+			return
+		}
+		cm := &contactmethods.ContactMethod{
+			UserID: userID,
+			Type:   contactmethods.Type(req.Type),
+			Label:  req.Label,
+			Value:  req.Value,
+		}
+		err := cms.Add(cm)
+		if errors.Is(err, iris_error.ErrContactMethodAlreadyExists) {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		if errors.Is(err, iris_error.ErrVerificationCodeNotSent) || errors.Is(err, iris_error.ErrProviderNotFound) {
+			// The contact method is stored, the code can be resent later
+			c.JSON(http.StatusAccepted, gin.H{"status": "created", "contact_method": cm, "message": err.Error()})
+			return
+		}
+		if err != nil {
+			logger.Errorw("Failed to add contact method", "userID", userID, "error", err)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"status": "created", "contact_method": cm})
+	}
+}
+
+func DeleteContactMethodHandler(cms contactmethods.ServiceInterface, us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.Param("user_id")
+		if !authorizeUserAccess(c, us, userID, logger) {
+			return
+		}
+		err := cms.Delete(userID, c.Param("method_id"))
+		if errors.Is(err, iris_error.ErrContactMethodNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		if err != nil {
+			logger.Errorw("Failed to delete contact method", "userID", userID, "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+	}
+}
+
+func ResendContactMethodCodeHandler(cms contactmethods.ServiceInterface, us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.Param("user_id")
+		if !authorizeUserAccess(c, us, userID, logger) {
+			return
+		}
+		err := cms.SendCode(userID, c.Param("method_id"))
+		switch {
+		case errors.Is(err, iris_error.ErrContactMethodNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		case errors.Is(err, iris_error.ErrContactMethodAlreadyVerified):
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"status": "error", "message": err.Error()})
+		case err != nil:
+			logger.Errorw("Failed to send verification code", "userID", userID, "error", err)
+			c.AbortWithStatusJSON(http.StatusBadGateway, gin.H{"status": "error", "message": err.Error()})
+		default:
+			c.JSON(http.StatusOK, gin.H{"status": "sent"})
+		}
+	}
+}
+
+func VerifyContactMethodHandler(cms contactmethods.ServiceInterface, us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.Param("user_id")
+		if !authorizeUserAccess(c, us, userID, logger) {
+			return
+		}
+		var req VerifyContactMethodRequestBody
+		if !bindAndValidate(c, &req) {
+			return
+		}
+		cm, err := cms.Verify(userID, c.Param("method_id"), req.Code)
+		switch {
+		case errors.Is(err, iris_error.ErrContactMethodNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		case errors.Is(err, iris_error.ErrVerificationCodeInvalid), errors.Is(err, iris_error.ErrVerificationCodeExpired):
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		case err != nil:
+			logger.Errorw("Failed to verify contact method", "userID", userID, "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		default:
+			c.JSON(http.StatusOK, gin.H{"status": "verified", "contact_method": cm})
+		}
+	}
+}
+
+func CreateTelegramLinkHandler(cos chatops.ServiceInterface, us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.Param("user_id")
+		if !authorizeUserAccess(c, us, userID, logger) {
+			return
+		}
+		token, url, err := cos.CreateLinkToken(userID, string(contactmethods.TypeTelegram))
+		if err != nil {
+			logger.Errorw("Failed to create telegram link token", "userID", userID, "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"status": "created", "token": token, "url": url})
+	}
+}
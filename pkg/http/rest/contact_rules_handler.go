@@ -0,0 +1,172 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/root-ali/iris/pkg/contactrules"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/root-ali/iris/pkg/user"
+	"go.uber.org/zap"
+)
+
+type ContactRuleBody struct {
+	Severity string   `json:"severity" validate:"required,max=50"`
+	Methods  []string `json:"methods" validate:"required,min=1,dive,oneof=sms mail telegram mattermost"`
+}
+
+type ContactRulesRequestBody struct {
+	Rules []ContactRuleBody `json:"rules" validate:"dive"`
+}
+
+type QuietHoursRequestBody struct {
+	Start            string   `json:"start" validate:"required,len=5"`
+	End              string   `json:"end" validate:"required,len=5"`
+	Timezone         string   `json:"timezone,omitempty"`
+	BypassSeverities []string `json:"bypass_severities,omitempty"`
+}
+
+func GetContactPreferencesHandler(crs contactrules.ServiceInterface, us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.Param("user_id")
+		if !authorizeUserAccess(c, us, userID, logger) {
+			return
+		}
+		rules, err := crs.GetRules(userID)
+		if err != nil {
+			logger.Errorw("Failed to get contact rules", "userID", userID, "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		quietHours, err := crs.GetQuietHours(userID)
+		if err != nil && !errors.Is(err, iris_error.ErrQuietHoursNotFound) {
+			logger.Errorw("Failed to get quiet hours", "userID", userID, "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "preferences": contactrules.Preferences{
+			Rules:      rules,
+			QuietHours: quietHours,
+		}})
+	}
+}
+
+func SetContactRulesHandler(crs contactrules.ServiceInterface, us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.Param("user_id")
+		if !authorizeUserAccess(c, us, userID, logger) {
+			return
+		}
+		var req ContactRulesRequestBody
+		if !bindAndValidate(c, &req) {
+			return
+		}
+		rules := make([]*contactrules.Rule, 0, len(req.Rules))
+		for _, r := range req.Rules {
+			rules = append(rules, &contactrules.Rule{Severity: r.Severity, Methods: r.Methods})
+		}
+		if err := crs.SetRules(userID, rules); err != nil {
+			logger.Errorw("Failed to set contact rules", "userID", userID, "error", err)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "rules": rules})
+	}
+}
+
+func SetQuietHoursHandler(crs contactrules.ServiceInterface, us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.Param("user_id")
+		if !authorizeUserAccess(c, us, userID, logger) {
+			return
+		}
+		var req QuietHoursRequestBody
+		if !bindAndValidate(c, &req) {
+			return
+		}
+		q := &contactrules.QuietHours{
+			UserID:           userID,
+			Start:            req.Start,
+			End:              req.End,
+			Timezone:         req.Timezone,
+			BypassSeverities: req.BypassSeverities,
+		}
+		if err := crs.SetQuietHours(q); err != nil {
+			logger.Errorw("Failed to set quiet hours", "userID", userID, "error", err)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "quiet_hours": q})
+	}
+}
+
+func DeleteQuietHoursHandler(crs contactrules.ServiceInterface, us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.Param("user_id")
+		if !authorizeUserAccess(c, us, userID, logger) {
+			return
+		}
+		err := crs.DeleteQuietHours(userID)
+		if errors.Is(err, iris_error.ErrQuietHoursNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		if err != nil {
+			logger.Errorw("Failed to delete quiet hours", "userID", userID, "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+	}
+}
+
+// authorizeUserAccess lets admins manage anyone and other users manage
+// only themselves. It aborts the request and returns false otherwise.
+func authorizeUserAccess(c *gin.Context, us user.UserInterfaceService, userID string, logger *zap.SugaredLogger) bool {
+	role, _ := c.Get("role")
+	if role == "admin" {
+		return true
+	}
+	userName, ok := c.Get("username")
+	if !ok {
+		logger.Errorw("cannot get username from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "error": "token is invalid"})
+		return false
+	}
+	u, err := us.GetByUserName(userName.(string))
+	if err != nil || u.ID != userID {
+		logger.Errorw("user not authorize to do this actions", "username", userName, "userID", userID)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"status":  "unauthorized",
+			"message": "user not valid to do this action",
+		})
+		return false
+	}
+	return true
+}
+
+// bindAndValidate decodes the JSON body into req and runs struct
+// validation. It aborts the request and returns false on failure.
+func bindAndValidate(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return false
+	}
+	if err := validate.Struct(req); err != nil {
+		errorMessages := make([]string, 0)
+		for _, e := range err.(validator.ValidationErrors) {
+			errorMessages = append(errorMessages, fmt.Sprintf("Field '%s' failed validation: %s",
+				e.Field(), e.Tag()))
+		}
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Validation failed",
+			"errors":  errorMessages,
+		})
+		return false
+	}
+	return true
+}
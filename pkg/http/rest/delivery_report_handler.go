@@ -0,0 +1,61 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/root-ali/iris/pkg/notifications"
+	"github.com/root-ali/iris/pkg/scheduler/message_status"
+	"go.uber.org/zap"
+)
+
+// DeliveryReportHandler receives delivery report callbacks of the provider
+// in the name parameter, e.g. /v1/providers/kavenegar/dlr?token=...
+func DeliveryReportHandler(providers []notifications.NotificationInterface, drh message_status.DeliveryReportHandler,
+	logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+		var dr notifications.DeliveryReportInterface
+		var provider notifications.NotificationInterface
+		for _, p := range providers {
+			if d, ok := notifications.DeliveryReportsOf(p); ok && strings.EqualFold(p.GetName(), name) {
+				dr, provider = d, p
+				break
+			}
+		}
+		if dr == nil || !dr.DeliveryReportsEnabled() {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"status": "error", "message": iris_error.ErrProviderNotFound.Error()})
+			return
+		}
+
+		reports, err := dr.DeliveryReports(c.Request)
+		if errors.Is(err, iris_error.ErrCallbackTokenInvalid) {
+			logger.Warnw("Rejected delivery report callback", "provider", name, "IP", c.ClientIP())
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		if err != nil {
+			logger.Errorw("Invalid delivery report callback", "provider", name, "error", err)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+
+		processed := 0
+		for _, r := range reports {
+			err := drh.HandleDeliveryReport(provider, r)
+			if errors.Is(err, iris_error.ErrMessageNotFound) {
+				logger.Warnw("Delivery report for unknown message", "provider", name, "messageID", r.MessageID)
+				continue
+			}
+			if err != nil {
+				logger.Errorw("Failed to apply delivery report", "provider", name, "messageID", r.MessageID, "error", err)
+				continue
+			}
+			processed++
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "received": len(reports), "processed": processed})
+	}
+}
@@ -21,8 +21,9 @@ func Ready(hs health_check.HealthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		err := hs.Ready()
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"status": "error",
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"status":  "error",
+				"message": err.Error(),
 			})
 		} else {
 			c.JSON(http.StatusOK, gin.H{
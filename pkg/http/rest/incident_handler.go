@@ -0,0 +1,183 @@
+package rest
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/root-ali/iris/pkg/incidents"
+	"github.com/root-ali/iris/pkg/user"
+	"go.uber.org/zap"
+)
+
+type CreateIncidentRequestBody struct {
+	Title    string   `json:"title" validate:"required,min=3,max=255"`
+	Severity string   `json:"severity" validate:"required,max=10"`
+	Assignee string   `json:"assignee,omitempty" validate:"omitempty,max=26"`
+	AlertIDs []string `json:"alert_ids,omitempty" validate:"dive,required,max=100"`
+}
+
+type AssignIncidentRequestBody struct {
+	Assignee string `json:"assignee" validate:"max=26"`
+}
+
+type IncidentNoteRequestBody struct {
+	Body string `json:"body" validate:"required,max=4000"`
+}
+
+type LinkIncidentAlertsRequestBody struct {
+	AlertIDs []string `json:"alert_ids" validate:"required,min=1,dive,required,max=100"`
+}
+
+func GetIncidentsHandler(is incidents.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var limit, page int
+		var err error
+		if v := c.Query("limit"); v != "" {
+			if limit, err = strconv.Atoi(v); err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid limit in query param"})
+				return
+			}
+		}
+		if v := c.Query("page"); v != "" {
+			if page, err = strconv.Atoi(v); err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid page in query param"})
+				return
+			}
+		}
+		incs, err := is.List(c.Query("status"), limit, page)
+		if err != nil {
+			abortIncidentError(c, err, logger, "Failed to list incidents")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "incidents": incs, "count": len(incs)})
+	}
+}
+
+func CreateIncidentHandler(is incidents.ServiceInterface, us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req CreateIncidentRequestBody
+		if !bindAndValidate(c, &req) {
+			return
+		}
+		inc, err := is.Create(&incidents.Incident{
+			Title:    req.Title,
+			Severity: req.Severity,
+			Assignee: req.Assignee,
+		}, req.AlertIDs, currentUserID(c, us))
+		if err != nil {
+			abortIncidentError(c, err, logger, "Failed to create incident")
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"status": "created", "incident": inc})
+	}
+}
+
+func GetIncidentHandler(is incidents.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		inc, err := is.Get(c.Param("incident_id"))
+		if err != nil {
+			abortIncidentError(c, err, logger, "Failed to get incident")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "incident": inc})
+	}
+}
+
+func AcknowledgeIncidentHandler(is incidents.ServiceInterface, us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		inc, err := is.Acknowledge(c.Param("incident_id"), currentUserID(c, us))
+		if err != nil {
+			abortIncidentError(c, err, logger, "Failed to acknowledge incident")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "incident": inc})
+	}
+}
+
+func ResolveIncidentHandler(is incidents.ServiceInterface, us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		inc, err := is.Resolve(c.Param("incident_id"), currentUserID(c, us))
+		if err != nil {
+			abortIncidentError(c, err, logger, "Failed to resolve incident")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "incident": inc})
+	}
+}
+
+func AssignIncidentHandler(is incidents.ServiceInterface, us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req AssignIncidentRequestBody
+		if !bindAndValidate(c, &req) {
+			return
+		}
+		inc, err := is.Assign(c.Param("incident_id"), req.Assignee, currentUserID(c, us))
+		if err != nil {
+			abortIncidentError(c, err, logger, "Failed to assign incident")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "incident": inc})
+	}
+}
+
+func AddIncidentNoteHandler(is incidents.ServiceInterface, us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req IncidentNoteRequestBody
+		if !bindAndValidate(c, &req) {
+			return
+		}
+		ev, err := is.AddNote(c.Param("incident_id"), currentUserID(c, us), req.Body)
+		if err != nil {
+			abortIncidentError(c, err, logger, "Failed to add incident note")
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"status": "created", "event": ev})
+	}
+}
+
+func LinkIncidentAlertsHandler(is incidents.ServiceInterface, us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req LinkIncidentAlertsRequestBody
+		if !bindAndValidate(c, &req) {
+			return
+		}
+		inc, err := is.LinkAlerts(c.Param("incident_id"), req.AlertIDs, currentUserID(c, us))
+		if err != nil {
+			abortIncidentError(c, err, logger, "Failed to link alerts to incident")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "incident": inc})
+	}
+}
+
+func abortIncidentError(c *gin.Context, err error, logger *zap.SugaredLogger, msg string) {
+	switch {
+	case errors.Is(err, iris_error.ErrIncidentNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+	case errors.Is(err, iris_error.ErrIncidentResolved),
+		errors.Is(err, iris_error.ErrIncidentAcknowledged):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"status": "error", "message": err.Error()})
+	case errors.Is(err, iris_error.ErrIncidentInvalidStatus):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+	default:
+		logger.Errorw(msg, "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+	}
+}
+
+// currentUserID returns the id of the authenticated user, or an empty
+// string when it cannot be looked up.
+func currentUserID(c *gin.Context, us user.UserInterfaceService) string {
+	userName, ok := c.Get("username")
+	if !ok {
+		return ""
+	}
+	u, err := us.GetByUserName(userName.(string))
+	if err != nil {
+		return ""
+	}
+	return u.ID
+}
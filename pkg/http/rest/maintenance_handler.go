@@ -0,0 +1,194 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/root-ali/iris/pkg/maintenance"
+	"go.uber.org/zap"
+)
+
+type MaintenanceWindowRequestBody struct {
+	Name         string     `json:"name" validate:"required,min=3,max=50"`
+	Description  string     `json:"description,omitempty" validate:"omitempty,max=255"`
+	ScheduleType string     `json:"schedule_type" validate:"required,oneof=cron rrule"`
+	Schedule     string     `json:"schedule" validate:"required"`
+	Duration     string     `json:"duration" validate:"required"`
+	Timezone     string     `json:"timezone,omitempty"`
+	Matchers     []string   `json:"matchers,omitempty"`
+	Groups       []string   `json:"groups,omitempty"`
+	StartsAt     *time.Time `json:"starts_at,omitempty"`
+	EndsAt       *time.Time `json:"ends_at,omitempty"`
+}
+
+type MaintenanceWindowResponse struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	ScheduleType string     `json:"schedule_type"`
+	Schedule     string     `json:"schedule"`
+	Duration     string     `json:"duration"`
+	Timezone     string     `json:"timezone"`
+	Matchers     []string   `json:"matchers"`
+	Groups       []string   `json:"groups"`
+	StartsAt     time.Time  `json:"starts_at"`
+	EndsAt       *time.Time `json:"ends_at,omitempty"`
+	Active       bool       `json:"active"`
+}
+
+func CreateMaintenanceWindowHandler(ms maintenance.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req MaintenanceWindowRequestBody
+		if !bindAndValidate(c, &req) {
+			return
+		}
+		w, err := req.toWindow()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		if err := ms.CreateWindow(w); err != nil {
+			logger.Errorw("Failed to create maintenance window", "error", err)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"status": "created", "window": toMaintenanceWindowResponse(ms, w)})
+	}
+}
+
+func GetMaintenanceWindowsHandler(ms maintenance.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		windows, err := ms.ListWindows()
+		if err != nil {
+			logger.Errorw("Failed to list maintenance windows", "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		response := make([]MaintenanceWindowResponse, 0, len(windows))
+		for _, w := range windows {
+			response = append(response, toMaintenanceWindowResponse(ms, w))
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "windows": response, "count": len(response)})
+	}
+}
+
+func GetMaintenanceWindowHandler(ms maintenance.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w, err := ms.GetWindow(c.Param("window_id"))
+		if errors.Is(err, iris_error.ErrMaintenanceWindowNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		if err != nil {
+			logger.Errorw("Failed to get maintenance window", "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "window": toMaintenanceWindowResponse(ms, w)})
+	}
+}
+
+func DeleteMaintenanceWindowHandler(ms maintenance.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := ms.DeleteWindow(c.Param("window_id"))
+		if errors.Is(err, iris_error.ErrMaintenanceWindowNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		if err != nil {
+			logger.Errorw("Failed to delete maintenance window", "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+	}
+}
+
+// GetUpcomingMaintenanceHandler lists occurrences between from and until
+// (RFC3339). Defaults to the next seven days.
+func GetUpcomingMaintenanceHandler(ms maintenance.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		from := time.Now()
+		until := from.Add(7 * 24 * time.Hour)
+		limit := 50
+		var err error
+		if v := c.Query("from"); v != "" {
+			if from, err = time.Parse(time.RFC3339, v); err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid from in query param"})
+				return
+			}
+		}
+		if v := c.Query("until"); v != "" {
+			if until, err = time.Parse(time.RFC3339, v); err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid until in query param"})
+				return
+			}
+		}
+		if v := c.Query("limit"); v != "" {
+			if limit, err = strconv.Atoi(v); err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid limit in query param"})
+				return
+			}
+		}
+		occurrences, err := ms.Upcoming(from, until, limit)
+		if err != nil {
+			logger.Errorw("Failed to get upcoming maintenance windows", "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "upcoming": occurrences, "count": len(occurrences)})
+	}
+}
+
+func (r *MaintenanceWindowRequestBody) toWindow() (*maintenance.Window, error) {
+	d, err := time.ParseDuration(r.Duration)
+	if err != nil {
+		return nil, fmt.Errorf("invalid duration: %w", err)
+	}
+	w := &maintenance.Window{
+		Name:            r.Name,
+		Description:     r.Description,
+		ScheduleType:    maintenance.ScheduleType(r.ScheduleType),
+		Schedule:        r.Schedule,
+		DurationSeconds: int64(d.Seconds()),
+		Timezone:        r.Timezone,
+		Matchers:        r.Matchers,
+		Groups:          r.Groups,
+		EndsAt:          r.EndsAt,
+	}
+	if w.Timezone == "" {
+		w.Timezone = "UTC"
+	}
+	if w.Matchers == nil {
+		w.Matchers = []string{}
+	}
+	if w.Groups == nil {
+		w.Groups = []string{}
+	}
+	if r.StartsAt != nil {
+		w.StartsAt = *r.StartsAt
+	}
+	return w, nil
+}
+
+func toMaintenanceWindowResponse(ms maintenance.ServiceInterface, w *maintenance.Window) MaintenanceWindowResponse {
+	return MaintenanceWindowResponse{
+		ID:           w.ID,
+		Name:         w.Name,
+		Description:  w.Description,
+		ScheduleType: string(w.ScheduleType),
+		Schedule:     w.Schedule,
+		Duration:     (time.Duration(w.DurationSeconds) * time.Second).String(),
+		Timezone:     w.Timezone,
+		Matchers:     w.Matchers,
+		Groups:       w.Groups,
+		StartsAt:     w.StartsAt,
+		EndsAt:       w.EndsAt,
+		Active:       ms.IsActive(w, time.Now()),
+	}
+}
@@ -0,0 +1,40 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/root-ali/iris/pkg/chatops"
+	"github.com/root-ali/iris/pkg/notifications/mattermost"
+	"go.uber.org/zap"
+)
+
+// MattermostActionHandler receives the Ack, Silence and Resolve button
+// clicks of alert posts. Requests are authenticated by the signed action
+// context.
+func MattermostActionHandler(mi mattermost.Integration, cos chatops.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req model.PostActionIntegrationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logger.Warnw("Invalid mattermost action request", "error", err)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, mi.HandleAction(c.Request.Context(), &req, cos))
+	}
+}
+
+// MattermostSlashCommandHandler answers the /iris slash command. Mattermost
+// posts the command as a form with the token configured for it.
+func MattermostSlashCommandHandler(mi mattermost.Integration, cos chatops.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := mi.HandleSlashCommand(c.PostForm("token"), c.PostForm("user_name"), c.PostForm("text"), cos)
+		if err != nil {
+			logger.Warnw("Rejected mattermost slash command", "user", c.PostForm("user_name"), "error", err)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,137 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/root-ali/iris/pkg/message"
+	"go.uber.org/zap"
+)
+
+type MessageResponse struct {
+	ID          string     `json:"id"`
+	UserID      string     `json:"user_id"`
+	Group       string     `json:"group"`
+	Receptor    string     `json:"receptor"`
+	Provider    string     `json:"provider"`
+	Status      string     `json:"status"`
+	AlertID     string     `json:"alert_id"`
+	Subject     string     `json:"subject"`
+	State       string     `json:"state"`
+	Attempt     int        `json:"attempt"`
+	Response    string     `json:"response"`
+	AlertAt     *time.Time `json:"alert_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	LastAttempt time.Time  `json:"last_attempt"`
+}
+
+// GetMessagesHandler lists the sent notifications, filtered by user_id,
+// group, provider, status and a from/to (RFC3339) range of creation time.
+func GetMessagesHandler(ms message.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		f, err := messageFilterFromQuery(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		msgs, total, err := ms.Search(f)
+		if err != nil {
+			logger.Errorw("Failed to search messages", "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		response := make([]MessageResponse, 0, len(msgs))
+		for _, m := range msgs {
+			response = append(response, toMessageResponse(m))
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "messages": response, "count": len(response), "total": total})
+	}
+}
+
+// GetMessageAnalyticsHandler aggregates the delivery of the messages matched
+// by the same filters as GetMessagesHandler.
+func GetMessageAnalyticsHandler(ms message.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		f, err := messageFilterFromQuery(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		analytics, err := ms.Analytics(f)
+		if err != nil {
+			logger.Errorw("Failed to aggregate messages", "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "analytics": analytics})
+	}
+}
+
+func messageFilterFromQuery(c *gin.Context) (message.Filter, error) {
+	f := message.Filter{
+		UserID:   c.Query("user_id"),
+		Group:    c.Query("group"),
+		Provider: c.Query("provider"),
+		Status:   c.Query("status"),
+	}
+	if f.Status != "" && !validMessageStatus(f.Status) {
+		return f, errors.New("Invalid status in query param")
+	}
+	for name, dst := range map[string]**time.Time{"from": &f.From, "to": &f.To} {
+		v := c.Query(name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return f, fmt.Errorf("Invalid %s in query param", name)
+		}
+		*dst = &t
+	}
+	for name, dst := range map[string]*int{"limit": &f.Limit, "page": &f.Page} {
+		v := c.Query(name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return f, fmt.Errorf("Invalid %s in query param", name)
+		}
+		*dst = n
+	}
+	return f, nil
+}
+
+func validMessageStatus(status string) bool {
+	for _, s := range message.StatusMap {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func toMessageResponse(m *message.Message) MessageResponse {
+	return MessageResponse{
+		ID:          m.Id,
+		UserID:      m.UserId,
+		Group:       m.GroupName,
+		Receptor:    m.Receptor,
+		Provider:    m.Sender,
+		Status:      m.Status,
+		AlertID:     m.AlertID,
+		Subject:     m.Subject,
+		State:       m.State,
+		Attempt:     m.Attempt,
+		Response:    m.Response,
+		AlertAt:     m.AlertAt,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+		LastAttempt: m.LastAttempt,
+	}
+}
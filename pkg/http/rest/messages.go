@@ -9,6 +9,10 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/root-ali/iris/pkg/alerts"
+	"github.com/root-ali/iris/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type AlertManagerRequest struct {
@@ -32,6 +36,22 @@ type LabelsRequest struct {
 	AlertName string `json:"alertName"`
 	Method    string `json:"method"`
 	Receptor  string `json:"receptor"`
+
+	// All keeps every label sent by alertmanager, including the ones above.
+	All map[string]string `json:"-"`
+}
+
+func (lr *LabelsRequest) UnmarshalJSON(b []byte) error {
+	all := make(map[string]string)
+	if err := json.Unmarshal(b, &all); err != nil {
+		return err
+	}
+	lr.All = all
+	lr.Severity = all["severity"]
+	lr.AlertName = all["alertName"]
+	lr.Method = all["method"]
+	lr.Receptor = all["receptor"]
+	return nil
 }
 
 type AnnotationRequest struct {
@@ -58,21 +78,35 @@ func AlertManagerHandler(as alerts.Service) gin.HandlerFunc {
 			})
 
 		}
+		// The scheduler and provider calls continue this trace from the
+		// traceparent stored on the alert
+		ctx, span := tracing.Tracer().Start(tracing.ExtractHeaders(c.Request.Context(), c.Request.Header), "alertmanager.receive",
+			trace.WithAttributes(
+				attribute.String("alertmanager.receiver", amr.Receiver),
+				attribute.Int("alertmanager.alerts", len(amr.Alerts)),
+			))
+		defer span.End()
+		traceParent := tracing.Inject(ctx)
+
 		var als []alerts.Alert
 		for _, a := range amr.Alerts {
 			alert, err := a.convertAlertManagerToAlert(as)
 			if err != nil {
 				continue
 			}
+			alert.TraceParent = traceParent
 			als = append(als, alert)
 		}
 		if len(als) == 0 {
+			span.SetStatus(codes.Error, "no valid alerts")
 			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{})
 			return
 		}
-		n, err := as.AddAlertManagerAlerts(als)
+		n, err := as.AddAlertManagerAlerts(ctx, als)
 		fmt.Println("number of alerts is saved", n)
 		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err})
 		}
 		c.JSON(http.StatusOK, gin.H{"status": "ok", "count": n})
@@ -95,6 +129,7 @@ func (ar AlertRequest) convertAlertManagerToAlert(as alerts.Service) (alerts.Ale
 	if err != nil {
 		return alerts.Alert{}, err
 	}
+	alert.Labels = ar.AlertLabels.All
 	return alert, nil
 
 }
@@ -21,6 +21,7 @@ type ProviderResponse struct {
 	Description string `json:"description"`
 	Priority    int    `json:"priority"`
 	Enabled     bool   `json:"enabled"`
+	Circuit     string `json:"circuit"`
 }
 
 func GetProvidersHandler(ps notifications.ProviderServiceInterface) gin.HandlerFunc {
@@ -54,6 +55,7 @@ func GetProvidersHandler(ps notifications.ProviderServiceInterface) gin.HandlerF
 			pr.Description = provider.Description
 			pr.Priority = provider.Priority
 			pr.Enabled = provider.Status
+			pr.Circuit = ps.BreakerState(provider.Name).String()
 			c.AbortWithStatusJSON(200, gin.H{"provider": pr, "status": "success"})
 			return
 		}
@@ -77,6 +79,7 @@ func GetProvidersHandler(ps notifications.ProviderServiceInterface) gin.HandlerF
 			pr.Description = provider.Description
 			pr.Priority = provider.Priority
 			pr.Enabled = provider.Status
+			pr.Circuit = ps.BreakerState(provider.Name).String()
 			c.AbortWithStatusJSON(200, gin.H{"provider": pr, "status": "success"})
 		}
 
@@ -91,6 +94,7 @@ func GetProvidersHandler(ps notifications.ProviderServiceInterface) gin.HandlerF
 					Description: providers[p].Description,
 					Priority:    providers[p].Priority,
 					Enabled:     providers[p].Status,
+					Circuit:     ps.BreakerState(providers[p].Name).String(),
 				})
 			}
 			if err != nil {
@@ -0,0 +1,84 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/root-ali/iris/pkg/reports"
+	"go.uber.org/zap"
+)
+
+// GetIncidentReportHandler reports MTTA, MTTR, noisy alerts and pages per
+// user of the alerts started between from and to (RFC3339), bucketed by
+// period and grouped by the group_by label. It defaults to the previous
+// and current month. format=csv and format=html export the report.
+func GetIncidentReportHandler(rs reports.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		now := time.Now().UTC()
+		q := reports.Query{
+			From:    time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, time.UTC),
+			To:      now,
+			Period:  reports.Period(c.Query("period")),
+			GroupBy: c.Query("group_by"),
+		}
+		var err error
+		if v := c.Query("from"); v != "" {
+			if q.From, err = time.Parse(time.RFC3339, v); err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid from in query param"})
+				return
+			}
+		}
+		if v := c.Query("to"); v != "" {
+			if q.To, err = time.Parse(time.RFC3339, v); err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid to in query param"})
+				return
+			}
+		}
+		if v := c.Query("top"); v != "" {
+			if q.Top, err = strconv.Atoi(v); err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid top in query param"})
+				return
+			}
+		}
+		format := c.DefaultQuery("format", "json")
+		if format != "json" && format != "csv" && format != "html" {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Invalid format in query param"})
+			return
+		}
+
+		report, err := rs.Report(q)
+		if errors.Is(err, iris_error.ErrInvalidReportQuery) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		if err != nil {
+			logger.Errorw("Failed to build incident report", "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+
+		name := fmt.Sprintf("iris-incidents-%s-%s", report.From.Format("20060102"), report.To.Format("20060102"))
+		switch format {
+		case "csv":
+			c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.csv", name))
+			c.Header("Content-Type", "text/csv; charset=utf-8")
+			c.Status(http.StatusOK)
+			if err := reports.WriteCSV(c.Writer, report); err != nil {
+				logger.Errorw("Failed to write incident report", "format", format, "error", err)
+			}
+		case "html":
+			c.Header("Content-Type", "text/html; charset=utf-8")
+			c.Status(http.StatusOK)
+			if err := reports.WriteHTML(c.Writer, report); err != nil {
+				logger.Errorw("Failed to write incident report", "format", format, "error", err)
+			}
+		default:
+			c.JSON(http.StatusOK, gin.H{"status": "success", "report": report})
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package rest
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/root-ali/iris/pkg/statuspage"
+	"go.uber.org/zap"
+)
+
+type StatusComponentRequestBody struct {
+	Name        string   `json:"name" validate:"required,min=2,max=100"`
+	Description string   `json:"description,omitempty" validate:"omitempty,max=255"`
+	Matchers    []string `json:"matchers" validate:"required,min=1"`
+	Position    int      `json:"position,omitempty"`
+}
+
+func (r *StatusComponentRequestBody) toComponent() *statuspage.Component {
+	return &statuspage.Component{
+		Name:        r.Name,
+		Description: r.Description,
+		Matchers:    r.Matchers,
+		Position:    r.Position,
+	}
+}
+
+func abortStatusComponentError(c *gin.Context, err error, logger *zap.SugaredLogger, msg string) {
+	if errors.Is(err, iris_error.ErrStatusComponentNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+	logger.Errorw(msg, "error", err)
+	c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+}
+
+func CreateStatusComponentHandler(sp statuspage.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req StatusComponentRequestBody
+		if !bindAndValidate(c, &req) {
+			return
+		}
+		comp := req.toComponent()
+		if err := sp.CreateComponent(comp); err != nil {
+			logger.Errorw("Failed to create status component", "error", err)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"status": "created", "component": comp})
+	}
+}
+
+func GetStatusComponentsHandler(sp statuspage.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		comps, err := sp.ListComponents()
+		if err != nil {
+			logger.Errorw("Failed to list status components", "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "components": comps, "count": len(comps)})
+	}
+}
+
+func GetStatusComponentHandler(sp statuspage.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		comp, err := sp.GetComponent(c.Param("component_id"))
+		if err != nil {
+			abortStatusComponentError(c, err, logger, "Failed to get status component")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "success", "component": comp})
+	}
+}
+
+func UpdateStatusComponentHandler(sp statuspage.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req StatusComponentRequestBody
+		if !bindAndValidate(c, &req) {
+			return
+		}
+		comp := req.toComponent()
+		comp.ID = c.Param("component_id")
+		err := sp.UpdateComponent(comp)
+		if errors.Is(err, iris_error.ErrStatusComponentNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		if err != nil {
+			logger.Errorw("Failed to update status component", "error", err)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "updated", "component": comp})
+	}
+}
+
+func DeleteStatusComponentHandler(sp statuspage.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := sp.DeleteComponent(c.Param("component_id")); err != nil {
+			abortStatusComponentError(c, err, logger, "Failed to delete status component")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "deleted"})
+	}
+}
+
+// StatusPageHandler serves the public status page as HTML.
+func StatusPageHandler(sp statuspage.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, err := sp.Page()
+		if err != nil {
+			logger.Errorw("Failed to build status page", "error", err)
+			c.String(http.StatusServiceUnavailable, "Status page is unavailable")
+			return
+		}
+		var buf bytes.Buffer
+		if err := statuspage.WriteHTML(&buf, p); err != nil {
+			logger.Errorw("Failed to render status page", "error", err)
+			c.String(http.StatusInternalServerError, "Status page is unavailable")
+			return
+		}
+		c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+	}
+}
+
+// StatusPageJSONHandler serves the public status page as JSON, it does not
+// follow the API envelope so it can be consumed by status aggregators.
+func StatusPageJSONHandler(sp statuspage.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, err := sp.Page()
+		if err != nil {
+			logger.Errorw("Failed to build status page", "error", err)
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"status": "error", "message": "status page is unavailable"})
+			return
+		}
+		c.JSON(http.StatusOK, p)
+	}
+}
+
+// StatusPageFeedHandler serves the incident history as an RSS feed.
+func StatusPageFeedHandler(sp statuspage.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, err := sp.Page()
+		if err != nil {
+			logger.Errorw("Failed to build status page", "error", err)
+			c.String(http.StatusServiceUnavailable, "Status page is unavailable")
+			return
+		}
+		scheme := "http"
+		if c.Request.TLS != nil {
+			scheme = "https"
+		}
+		var buf bytes.Buffer
+		if err := statuspage.WriteRSS(&buf, p, scheme+"://"+c.Request.Host+"/status"); err != nil {
+			logger.Errorw("Failed to render status feed", "error", err)
+			c.String(http.StatusInternalServerError, "Status feed is unavailable")
+			return
+		}
+		c.Data(http.StatusOK, "application/rss+xml; charset=utf-8", buf.Bytes())
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/root-ali/iris/pkg/auth"
+	"github.com/root-ali/iris/pkg/contactmethods"
 	iris_error "github.com/root-ali/iris/pkg/errors"
 	"github.com/root-ali/iris/pkg/user"
 	"go.uber.org/zap"
@@ -321,7 +322,7 @@ func VerifyUserHandler(us user.UserInterfaceService, logger *zap.SugaredLogger)
 	}
 }
 
-func UpdateUserHandler(us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+func UpdateUserHandler(us user.UserInterfaceService, cms contactmethods.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var processed = false
 		bodyBytes, _ := io.ReadAll(c.Request.Body)
@@ -395,6 +396,15 @@ func UpdateUserHandler(us user.UserInterfaceService, logger *zap.SugaredLogger)
 				c.AbortWithStatusJSON(500, gin.H{"status": "error", "error": err})
 				return
 			}
+			// New contact values are unverified until the user enters the code,
+			// a failed send can be retried through the resend endpoint
+			if cms != nil {
+				if saved, err := us.GetByUserName(updateUser.UserName); err != nil {
+					logger.Errorw("cannot get updated user", "username", updateUser.UserName, "error", err)
+				} else if err := cms.SendPendingCodes(saved.ID); err != nil {
+					logger.Warnw("cannot send verification codes", "user_id", saved.ID, "error", err)
+				}
+			}
 			c.JSON(200, gin.H{"status": "OK", "username": updateUser.UserName})
 		}
 	}
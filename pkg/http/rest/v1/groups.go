@@ -0,0 +1,186 @@
+package v1
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/root-ali/iris/pkg/groups"
+	"github.com/root-ali/iris/pkg/user"
+	"go.uber.org/zap"
+)
+
+// UserID is the path parameter naming a member of a group.
+const UserID = "user_id"
+
+type Group struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type GroupRequest struct {
+	Name        string `json:"name" validate:"required,min=3,max=30"`
+	Description string `json:"description" validate:"max=100"`
+}
+
+type Member struct {
+	ID       string `json:"id"`
+	UserName string `json:"username"`
+}
+
+func ListGroupsHandler(gr groups.GroupServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		gs, err := gr.GetAllGroups()
+		if err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to list groups")
+			return
+		}
+		response := make([]Group, 0, len(gs))
+		for _, g := range gs {
+			response = append(response, toGroup(g))
+		}
+		respondList(c, response)
+	}
+}
+
+func CreateGroupHandler(gr groups.GroupServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req GroupRequest
+		if !decode(c, &req) {
+			return
+		}
+		g := &groups.Group{Name: req.Name, Description: req.Description}
+		if err := gr.CreateGroup(g); err != nil {
+			abort(c, err, http.StatusBadRequest, logger, "Failed to create group")
+			return
+		}
+		respond(c, http.StatusCreated, toGroup(g))
+	}
+}
+
+func GetGroupHandler(gr groups.GroupServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		g, err := gr.GetGroupByID(c.Param(ID))
+		if err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to get group")
+			return
+		}
+		respond(c, http.StatusOK, toGroup(g))
+	}
+}
+
+// UpdateGroupHandler serves PUT and PATCH.
+func UpdateGroupHandler(gr groups.GroupServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cur, err := gr.GetGroupByID(c.Param(ID))
+		if err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to get group")
+			return
+		}
+		var req GroupRequest
+		if isPatch(c) {
+			req = GroupRequest{Name: cur.Name, Description: cur.Description}
+		}
+		if !decode(c, &req) {
+			return
+		}
+		g := &groups.Group{ID: cur.ID, Name: req.Name, Description: req.Description}
+		if err := gr.UpdateGroup(g); err != nil {
+			abort(c, err, http.StatusBadRequest, logger, "Failed to update group")
+			return
+		}
+		respond(c, http.StatusOK, toGroup(g))
+	}
+}
+
+// DeleteGroupHandler deletes the group and its memberships.
+func DeleteGroupHandler(gr groups.GroupServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := gr.DeleteGroup(&groups.Group{ID: c.Param(ID)}); err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to delete group")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func ListGroupMembersHandler(gr groups.GroupServiceInterface, us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		g, ids, ok := groupMembers(c, gr, logger)
+		if !ok {
+			return
+		}
+		members := make([]Member, 0, len(ids))
+		for _, id := range ids {
+			u, err := us.GetByUserId(id)
+			if err != nil {
+				logger.Warnw("Failed to get group member", "group", g.Name, "user_id", id, "error", err)
+				continue
+			}
+			members = append(members, Member{ID: u.ID, UserName: u.UserName})
+		}
+		respondList(c, members)
+	}
+}
+
+// AddGroupMemberHandler adds the user to the group, adding a member twice
+// is not an error.
+func AddGroupMemberHandler(gr groups.GroupServiceInterface, us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		g, ids, ok := groupMembers(c, gr, logger)
+		if !ok {
+			return
+		}
+		u, err := us.GetByUserId(c.Param(UserID))
+		if err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to get user")
+			return
+		}
+		if !slices.Contains(ids, u.ID) {
+			if err := gr.AddUser(g, u.ID); err != nil {
+				abort(c, err, http.StatusInternalServerError, logger, "Failed to add group member")
+				return
+			}
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func RemoveGroupMemberHandler(gr groups.GroupServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		g, ids, ok := groupMembers(c, gr, logger)
+		if !ok {
+			return
+		}
+		userID := c.Param(UserID)
+		if !slices.Contains(ids, userID) {
+			abortMessage(c, http.StatusNotFound, "user is not a member of the group")
+			return
+		}
+		if err := gr.RemoveUser(g, userID); err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to remove group member")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// groupMembers loads the group of the path and the ids of its members.
+func groupMembers(c *gin.Context, gr groups.GroupServiceInterface, logger *zap.SugaredLogger) (*groups.Group, []string, bool) {
+	g, err := gr.GetGroupByID(c.Param(ID))
+	if err != nil {
+		abort(c, err, http.StatusInternalServerError, logger, "Failed to get group")
+		return nil, nil, false
+	}
+	ids, err := gr.ListUsers(g)
+	if err != nil {
+		abort(c, err, http.StatusInternalServerError, logger, "Failed to list group members")
+		return nil, nil, false
+	}
+	return g, ids, true
+}
+
+func toGroup(g *groups.Group) Group {
+	return Group{ID: g.ID, Name: g.Name, Description: g.Description}
+}
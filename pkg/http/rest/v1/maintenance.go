@@ -0,0 +1,192 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/root-ali/iris/pkg/maintenance"
+	"go.uber.org/zap"
+)
+
+type MaintenanceWindow struct {
+	ID           string     `json:"id"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	ScheduleType string     `json:"schedule_type"`
+	Schedule     string     `json:"schedule"`
+	Duration     string     `json:"duration"`
+	Timezone     string     `json:"timezone"`
+	Matchers     []string   `json:"matchers"`
+	Groups       []string   `json:"groups"`
+	StartsAt     time.Time  `json:"starts_at"`
+	EndsAt       *time.Time `json:"ends_at"`
+	Active       bool       `json:"active"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ModifiedAt   time.Time  `json:"modified_at"`
+}
+
+type MaintenanceWindowRequest struct {
+	Name         string     `json:"name" validate:"required,min=3,max=50"`
+	Description  string     `json:"description" validate:"max=255"`
+	ScheduleType string     `json:"schedule_type" validate:"required,oneof=cron rrule"`
+	Schedule     string     `json:"schedule" validate:"required"`
+	Duration     string     `json:"duration" validate:"required" doc:"Go duration of every occurrence, e.g. 2h"`
+	Timezone     string     `json:"timezone" doc:"IANA time zone, UTC when empty"`
+	Matchers     []string   `json:"matchers" doc:"label matchers of the silenced alerts"`
+	Groups       []string   `json:"groups" doc:"groups that are not paged"`
+	StartsAt     *time.Time `json:"starts_at" doc:"now when empty"`
+	EndsAt       *time.Time `json:"ends_at"`
+}
+
+func ListMaintenanceWindowsHandler(ms maintenance.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		windows, err := ms.ListWindows()
+		if err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to list maintenance windows")
+			return
+		}
+		response := make([]MaintenanceWindow, 0, len(windows))
+		for _, w := range windows {
+			response = append(response, toMaintenanceWindow(ms, w))
+		}
+		respondList(c, response)
+	}
+}
+
+func CreateMaintenanceWindowHandler(ms maintenance.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req MaintenanceWindowRequest
+		if !decode(c, &req) {
+			return
+		}
+		w, err := req.window()
+		if err != nil {
+			abortMessage(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := ms.CreateWindow(w); err != nil {
+			abort(c, err, http.StatusBadRequest, logger, "Failed to create maintenance window")
+			return
+		}
+		respond(c, http.StatusCreated, toMaintenanceWindow(ms, w))
+	}
+}
+
+func GetMaintenanceWindowHandler(ms maintenance.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w, err := ms.GetWindow(c.Param(ID))
+		if err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to get maintenance window")
+			return
+		}
+		respond(c, http.StatusOK, toMaintenanceWindow(ms, w))
+	}
+}
+
+// UpdateMaintenanceWindowHandler serves PUT and PATCH.
+func UpdateMaintenanceWindowHandler(ms maintenance.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cur, err := ms.GetWindow(c.Param(ID))
+		if err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to get maintenance window")
+			return
+		}
+		var req MaintenanceWindowRequest
+		if isPatch(c) {
+			req = fromMaintenanceWindow(cur)
+		}
+		if !decode(c, &req) {
+			return
+		}
+		w, err := req.window()
+		if err != nil {
+			abortMessage(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		w.ID = cur.ID
+		w.CreatedAt = cur.CreatedAt
+		if err := ms.UpdateWindow(w); err != nil {
+			abort(c, err, http.StatusBadRequest, logger, "Failed to update maintenance window")
+			return
+		}
+		respond(c, http.StatusOK, toMaintenanceWindow(ms, w))
+	}
+}
+
+func DeleteMaintenanceWindowHandler(ms maintenance.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := ms.DeleteWindow(c.Param(ID)); err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to delete maintenance window")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (r *MaintenanceWindowRequest) window() (*maintenance.Window, error) {
+	d, err := time.ParseDuration(r.Duration)
+	if err != nil {
+		return nil, fmt.Errorf("invalid duration: %w", err)
+	}
+	w := &maintenance.Window{
+		Name:            r.Name,
+		Description:     r.Description,
+		ScheduleType:    maintenance.ScheduleType(r.ScheduleType),
+		Schedule:        r.Schedule,
+		DurationSeconds: int64(d / time.Second),
+		Timezone:        r.Timezone,
+		Matchers:        r.Matchers,
+		Groups:          r.Groups,
+		EndsAt:          r.EndsAt,
+	}
+	if w.Timezone == "" {
+		w.Timezone = "UTC"
+	}
+	if w.Matchers == nil {
+		w.Matchers = []string{}
+	}
+	if w.Groups == nil {
+		w.Groups = []string{}
+	}
+	if r.StartsAt != nil {
+		w.StartsAt = *r.StartsAt
+	}
+	return w, nil
+}
+
+func fromMaintenanceWindow(w *maintenance.Window) MaintenanceWindowRequest {
+	startsAt := w.StartsAt
+	return MaintenanceWindowRequest{
+		Name:         w.Name,
+		Description:  w.Description,
+		ScheduleType: string(w.ScheduleType),
+		Schedule:     w.Schedule,
+		Duration:     (time.Duration(w.DurationSeconds) * time.Second).String(),
+		Timezone:     w.Timezone,
+		Matchers:     w.Matchers,
+		Groups:       w.Groups,
+		StartsAt:     &startsAt,
+		EndsAt:       w.EndsAt,
+	}
+}
+
+func toMaintenanceWindow(ms maintenance.ServiceInterface, w *maintenance.Window) MaintenanceWindow {
+	return MaintenanceWindow{
+		ID:           w.ID,
+		Name:         w.Name,
+		Description:  w.Description,
+		ScheduleType: string(w.ScheduleType),
+		Schedule:     w.Schedule,
+		Duration:     (time.Duration(w.DurationSeconds) * time.Second).String(),
+		Timezone:     w.Timezone,
+		Matchers:     nonNil(w.Matchers),
+		Groups:       nonNil(w.Groups),
+		StartsAt:     w.StartsAt,
+		EndsAt:       w.EndsAt,
+		Active:       ms.IsActive(w, time.Now()),
+		CreatedAt:    w.CreatedAt,
+		ModifiedAt:   w.ModifiedAt,
+	}
+}
@@ -0,0 +1,103 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/root-ali/iris/pkg/notifications"
+	"go.uber.org/zap"
+)
+
+// Provider is a notification provider. Providers come from the
+// configuration, the API only changes their priority and status.
+type Provider struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Priority    int    `json:"priority"`
+	Enabled     bool   `json:"enabled"`
+	Circuit     string `json:"circuit" doc:"state of the circuit breaker"`
+}
+
+type ProviderRequest struct {
+	Priority int  `json:"priority" validate:"required,min=0" doc:"providers are tried in ascending priority"`
+	Enabled  bool `json:"enabled" validate:"required"`
+}
+
+func ListProvidersHandler(ps notifications.ProviderServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		providers, err := ps.GetAllProviders()
+		if err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to list providers")
+			return
+		}
+		response := make([]Provider, 0, len(providers))
+		for i := range providers {
+			response = append(response, toProvider(ps, &providers[i]))
+		}
+		respondList(c, response)
+	}
+}
+
+func GetProviderHandler(ps notifications.ProviderServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, err := findProvider(ps, c.Param(ID))
+		if err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to get provider")
+			return
+		}
+		respond(c, http.StatusOK, toProvider(ps, p))
+	}
+}
+
+// UpdateProviderHandler serves PUT and PATCH.
+func UpdateProviderHandler(ps notifications.ProviderServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, err := findProvider(ps, c.Param(ID))
+		if err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to get provider")
+			return
+		}
+		var req ProviderRequest
+		if isPatch(c) {
+			req = ProviderRequest{Priority: p.Priority, Enabled: p.Status}
+		}
+		if !decode(c, &req) {
+			return
+		}
+		if err := ps.UpdateProvider(p.Name, req.Priority, req.Enabled); err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to update provider")
+			return
+		}
+		p.Priority = req.Priority
+		p.Status = req.Enabled
+		respond(c, http.StatusOK, toProvider(ps, p))
+	}
+}
+
+// findProvider looks the provider up among all of them, ids that are not
+// UUIDs are not found rather than rejected by the database.
+func findProvider(ps notifications.ProviderServiceInterface, id string) (*notifications.Providers, error) {
+	providers, err := ps.GetAllProviders()
+	if err != nil {
+		return nil, err
+	}
+	for i := range providers {
+		if providers[i].ID == id {
+			return &providers[i], nil
+		}
+	}
+	return nil, iris_error.ErrProviderNotFound
+}
+
+func toProvider(ps notifications.ProviderServiceInterface, p *notifications.Providers) Provider {
+	return Provider{
+		ID:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		Priority:    p.Priority,
+		Enabled:     p.Status,
+		Circuit:     ps.BreakerState(p.Name).String(),
+	}
+}
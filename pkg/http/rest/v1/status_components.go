@@ -0,0 +1,126 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/root-ali/iris/pkg/statuspage"
+	"go.uber.org/zap"
+)
+
+type StatusComponent struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Matchers    []string  `json:"matchers"`
+	Position    int       `json:"position"`
+	CreatedAt   time.Time `json:"created_at"`
+	ModifiedAt  time.Time `json:"modified_at"`
+}
+
+type StatusComponentRequest struct {
+	Name        string   `json:"name" validate:"required,min=2,max=100"`
+	Description string   `json:"description" validate:"max=255"`
+	Matchers    []string `json:"matchers" validate:"required,min=1" doc:"label matchers of the alerts that affect the component"`
+	Position    int      `json:"position" doc:"order on the status page"`
+}
+
+func ListStatusComponentsHandler(sp statuspage.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		comps, err := sp.ListComponents()
+		if err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to list status components")
+			return
+		}
+		response := make([]StatusComponent, 0, len(comps))
+		for _, comp := range comps {
+			response = append(response, toStatusComponent(comp))
+		}
+		respondList(c, response)
+	}
+}
+
+func CreateStatusComponentHandler(sp statuspage.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req StatusComponentRequest
+		if !decode(c, &req) {
+			return
+		}
+		comp := req.component()
+		if err := sp.CreateComponent(comp); err != nil {
+			abort(c, err, http.StatusBadRequest, logger, "Failed to create status component")
+			return
+		}
+		respond(c, http.StatusCreated, toStatusComponent(comp))
+	}
+}
+
+func GetStatusComponentHandler(sp statuspage.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		comp, err := sp.GetComponent(c.Param(ID))
+		if err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to get status component")
+			return
+		}
+		respond(c, http.StatusOK, toStatusComponent(comp))
+	}
+}
+
+// UpdateStatusComponentHandler serves PUT and PATCH.
+func UpdateStatusComponentHandler(sp statuspage.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cur, err := sp.GetComponent(c.Param(ID))
+		if err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to get status component")
+			return
+		}
+		var req StatusComponentRequest
+		if isPatch(c) {
+			req = StatusComponentRequest{Name: cur.Name, Description: cur.Description, Matchers: cur.Matchers,
+				Position: cur.Position}
+		}
+		if !decode(c, &req) {
+			return
+		}
+		comp := req.component()
+		comp.ID = cur.ID
+		comp.CreatedAt = cur.CreatedAt
+		if err := sp.UpdateComponent(comp); err != nil {
+			abort(c, err, http.StatusBadRequest, logger, "Failed to update status component")
+			return
+		}
+		respond(c, http.StatusOK, toStatusComponent(comp))
+	}
+}
+
+func DeleteStatusComponentHandler(sp statuspage.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := sp.DeleteComponent(c.Param(ID)); err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to delete status component")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (r *StatusComponentRequest) component() *statuspage.Component {
+	return &statuspage.Component{
+		Name:        r.Name,
+		Description: r.Description,
+		Matchers:    r.Matchers,
+		Position:    r.Position,
+	}
+}
+
+func toStatusComponent(comp *statuspage.Component) StatusComponent {
+	return StatusComponent{
+		ID:          comp.ID,
+		Name:        comp.Name,
+		Description: comp.Description,
+		Matchers:    nonNil(comp.Matchers),
+		Position:    comp.Position,
+		CreatedAt:   comp.CreatedAt,
+		ModifiedAt:  comp.ModifiedAt,
+	}
+}
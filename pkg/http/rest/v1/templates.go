@@ -0,0 +1,130 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/root-ali/iris/pkg/templates"
+	"go.uber.org/zap"
+)
+
+type Template struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Matchers    []string  `json:"matchers"`
+	Subject     string    `json:"subject"`
+	Body        string    `json:"body"`
+	CreatedAt   time.Time `json:"created_at"`
+	ModifiedAt  time.Time `json:"modified_at"`
+}
+
+type TemplateRequest struct {
+	Name        string   `json:"name" validate:"required,max=100"`
+	Description string   `json:"description" validate:"max=255"`
+	Matchers    []string `json:"matchers" doc:"label matchers of the alerts it renders, all alerts when empty"`
+	Subject     string   `json:"subject" doc:"Go template, the alert name when empty"`
+	Body        string   `json:"body" doc:"Go template, the alert description when empty"`
+}
+
+func ListTemplatesHandler(ts templates.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		list, err := ts.ListTemplates()
+		if err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to list notification templates")
+			return
+		}
+		response := make([]Template, 0, len(list))
+		for _, t := range list {
+			response = append(response, toTemplate(t))
+		}
+		respondList(c, response)
+	}
+}
+
+func CreateTemplateHandler(ts templates.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req TemplateRequest
+		if !decode(c, &req) {
+			return
+		}
+		t := req.template()
+		if err := ts.CreateTemplate(t); err != nil {
+			abort(c, err, http.StatusBadRequest, logger, "Failed to create notification template")
+			return
+		}
+		respond(c, http.StatusCreated, toTemplate(t))
+	}
+}
+
+func GetTemplateHandler(ts templates.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t, err := ts.GetTemplate(c.Param(ID))
+		if err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to get notification template")
+			return
+		}
+		respond(c, http.StatusOK, toTemplate(t))
+	}
+}
+
+// UpdateTemplateHandler serves PUT and PATCH.
+func UpdateTemplateHandler(ts templates.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cur, err := ts.GetTemplate(c.Param(ID))
+		if err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to get notification template")
+			return
+		}
+		var req TemplateRequest
+		if isPatch(c) {
+			req = TemplateRequest{Name: cur.Name, Description: cur.Description, Matchers: cur.Matchers,
+				Subject: cur.Subject, Body: cur.Body}
+		}
+		if !decode(c, &req) {
+			return
+		}
+		t := req.template()
+		t.ID = cur.ID
+		t.CreatedAt = cur.CreatedAt
+		if err := ts.UpdateTemplate(t); err != nil {
+			abort(c, err, http.StatusBadRequest, logger, "Failed to update notification template")
+			return
+		}
+		respond(c, http.StatusOK, toTemplate(t))
+	}
+}
+
+func DeleteTemplateHandler(ts templates.ServiceInterface, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := ts.DeleteTemplate(c.Param(ID)); err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to delete notification template")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+func (r *TemplateRequest) template() *templates.Template {
+	return &templates.Template{
+		Name:        r.Name,
+		Description: r.Description,
+		Matchers:    nonNil(r.Matchers),
+		Subject:     r.Subject,
+		Body:        r.Body,
+	}
+}
+
+func toTemplate(t *templates.Template) Template {
+	return Template{
+		ID:          t.ID,
+		Name:        t.Name,
+		Description: t.Description,
+		Matchers:    nonNil(t.Matchers),
+		Subject:     t.Subject,
+		Body:        t.Body,
+		CreatedAt:   t.CreatedAt,
+		ModifiedAt:  t.ModifiedAt,
+	}
+}
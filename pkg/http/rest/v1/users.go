@@ -0,0 +1,190 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/root-ali/iris/pkg/user"
+	"go.uber.org/zap"
+)
+
+type User struct {
+	ID           string    `json:"id"`
+	UserName     string    `json:"username"`
+	FirstName    string    `json:"first_name"`
+	LastName     string    `json:"last_name"`
+	Email        string    `json:"email"`
+	Mobile       string    `json:"mobile"`
+	TelegramID   string    `json:"telegram_id"`
+	MattermostID string    `json:"mattermost_id"`
+	Role         string    `json:"role"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"created_at"`
+	ModifiedAt   time.Time `json:"modified_at"`
+}
+
+// UserRequest replaces a user, the username cannot change.
+type UserRequest struct {
+	UserName     string `json:"username" validate:"required,min=3,max=30"`
+	FirstName    string `json:"first_name" validate:"max=30"`
+	LastName     string `json:"last_name" validate:"max=30"`
+	Email        string `json:"email" validate:"omitempty,email"`
+	Mobile       string `json:"mobile" validate:"omitempty,len=11,numeric"`
+	TelegramID   string `json:"telegram_id" validate:"omitempty,numeric"`
+	MattermostID string `json:"mattermost_id" validate:"max=100"`
+	Role         string `json:"role" validate:"omitempty,oneof=admin viewer" doc:"viewer when empty"`
+}
+
+// NewUserRequest creates a verified user.
+type NewUserRequest struct {
+	UserRequest
+	Password string `json:"password" validate:"required,min=8,max=64"`
+}
+
+func ListUsersHandler(us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		users, err := us.GetAllUsers()
+		if err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to list users")
+			return
+		}
+		roles := make(map[string]string)
+		response := make([]User, 0, len(users))
+		for _, u := range users {
+			role, ok := roles[u.Role]
+			if !ok {
+				if role, err = us.RoleName(u); err != nil {
+					abort(c, err, http.StatusInternalServerError, logger, "Failed to get user role")
+					return
+				}
+				roles[u.Role] = role
+			}
+			response = append(response, toUser(u, role))
+		}
+		respondList(c, response)
+	}
+}
+
+func CreateUserHandler(us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req NewUserRequest
+		if !decode(c, &req) {
+			return
+		}
+		u := req.user()
+		u.Password = req.Password
+		if err := us.CreateUser(u, req.Role); err != nil {
+			abort(c, err, http.StatusBadRequest, logger, "Failed to create user")
+			return
+		}
+		respondUser(c, http.StatusCreated, us, u.ID, logger)
+	}
+}
+
+func GetUserHandler(us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		respondUser(c, http.StatusOK, us, c.Param(ID), logger)
+	}
+}
+
+// UpdateUserHandler serves PUT and PATCH.
+func UpdateUserHandler(us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cur, err := us.GetByUserId(c.Param(ID))
+		if err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to get user")
+			return
+		}
+		var req UserRequest
+		if isPatch(c) {
+			role, err := us.RoleName(cur)
+			if err != nil {
+				abort(c, err, http.StatusInternalServerError, logger, "Failed to get user role")
+				return
+			}
+			req = fromUser(cur, role)
+		}
+		if !decode(c, &req) {
+			return
+		}
+		if req.UserName != cur.UserName {
+			abortMessage(c, http.StatusBadRequest, "username cannot be changed")
+			return
+		}
+		u := req.user()
+		u.ID = cur.ID
+		if err := us.UpdateProfile(u, req.Role); err != nil {
+			abort(c, err, http.StatusBadRequest, logger, "Failed to update user")
+			return
+		}
+		respondUser(c, http.StatusOK, us, cur.ID, logger)
+	}
+}
+
+func DeleteUserHandler(us user.UserInterfaceService, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := us.DeleteUser(c.Param(ID)); err != nil {
+			abort(c, err, http.StatusInternalServerError, logger, "Failed to delete user")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// respondUser reads the user back so the response has what was stored.
+func respondUser(c *gin.Context, status int, us user.UserInterfaceService, id string, logger *zap.SugaredLogger) {
+	u, err := us.GetByUserId(id)
+	if err != nil {
+		abort(c, err, http.StatusInternalServerError, logger, "Failed to get user")
+		return
+	}
+	role, err := us.RoleName(u)
+	if err != nil {
+		abort(c, err, http.StatusInternalServerError, logger, "Failed to get user role")
+		return
+	}
+	respond(c, status, toUser(u, role))
+}
+
+func (r *UserRequest) user() *user.User {
+	return &user.User{
+		UserName:     r.UserName,
+		FirstName:    r.FirstName,
+		LastName:     r.LastName,
+		Email:        r.Email,
+		Mobile:       r.Mobile,
+		TelegramID:   r.TelegramID,
+		MattermostId: r.MattermostID,
+	}
+}
+
+func fromUser(u *user.User, role string) UserRequest {
+	return UserRequest{
+		UserName:     u.UserName,
+		FirstName:    u.FirstName,
+		LastName:     u.LastName,
+		Email:        u.Email,
+		Mobile:       u.Mobile,
+		TelegramID:   u.TelegramID,
+		MattermostID: u.MattermostId,
+		Role:         role,
+	}
+}
+
+func toUser(u *user.User, role string) User {
+	return User{
+		ID:           u.ID,
+		UserName:     u.UserName,
+		FirstName:    u.FirstName,
+		LastName:     u.LastName,
+		Email:        u.Email,
+		Mobile:       u.Mobile,
+		TelegramID:   u.TelegramID,
+		MattermostID: u.MattermostId,
+		Role:         role,
+		Status:       u.Status,
+		CreatedAt:    u.CreatedAt,
+		ModifiedAt:   u.ModifiedAt,
+	}
+}
@@ -0,0 +1,93 @@
+// Package v1 implements the resources of /api/v1. Every resource is
+// listed with GET, created with POST, read, replaced with PUT, changed with
+// PATCH and deleted by its id. Responses wrap the resource in data, errors
+// carry a message. Request bodies are validated against the OpenAPI
+// document before the handlers run.
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// ID is the path parameter naming the resource.
+const ID = "id"
+
+var notFound = []error{
+	iris_error.ErrUserNotFound,
+	iris_error.ErrGroupNotFound,
+	iris_error.ErrProviderNotFound,
+	iris_error.ErrMaintenanceWindowNotFound,
+	iris_error.ErrTemplateNotFound,
+	iris_error.ErrStatusComponentNotFound,
+}
+
+var conflict = []error{
+	iris_error.ErrUserAlreadyExists,
+	iris_error.ErrGroupAlreadyexisted,
+	iris_error.ErrTemplateAlreadyExists,
+	iris_error.ErrDefaultAdminUser,
+}
+
+// abort responds with the status of a known service error or fallback.
+func abort(c *gin.Context, err error, fallback int, logger *zap.SugaredLogger, msg string) {
+	status := fallback
+	for _, target := range notFound {
+		if errors.Is(err, target) {
+			status = http.StatusNotFound
+		}
+	}
+	for _, target := range conflict {
+		if errors.Is(err, target) {
+			status = http.StatusConflict
+		}
+	}
+	if errors.Is(err, iris_error.ErrRoleNotFound) {
+		status = http.StatusBadRequest
+	}
+	if status >= http.StatusInternalServerError {
+		logger.Errorw(msg, "error", err)
+	} else {
+		logger.Infow(msg, "error", err)
+	}
+	c.AbortWithStatusJSON(status, gin.H{"status": "error", "message": err.Error()})
+}
+
+func abortMessage(c *gin.Context, status int, message string) {
+	c.AbortWithStatusJSON(status, gin.H{"status": "error", "message": message})
+}
+
+// decode reads the body into req. For a PATCH req holds the current values
+// and only the fields in the body change.
+func decode(c *gin.Context, req any) bool {
+	if err := json.NewDecoder(c.Request.Body).Decode(req); err != nil {
+		abortMessage(c, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+	return true
+}
+
+func isPatch(c *gin.Context) bool {
+	return c.Request.Method == http.MethodPatch
+}
+
+func respond(c *gin.Context, status int, data any) {
+	c.JSON(status, gin.H{"status": "success", "data": data})
+}
+
+func respondList[T any](c *gin.Context, data []T) {
+	c.JSON(http.StatusOK, gin.H{"status": "success", "data": data, "count": len(data)})
+}
+
+// nonNil keeps empty lists from encoding as null.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -2,23 +2,50 @@ package http
 
 import (
 	"github.com/root-ali/iris/pkg/alerts"
+	"github.com/root-ali/iris/pkg/alertstream"
 	"github.com/root-ali/iris/pkg/auth"
 	"github.com/root-ali/iris/pkg/captcha"
+	"github.com/root-ali/iris/pkg/chatops"
+	"github.com/root-ali/iris/pkg/cluster"
+	"github.com/root-ali/iris/pkg/contactmethods"
+	"github.com/root-ali/iris/pkg/contactrules"
 	"github.com/root-ali/iris/pkg/groups"
 	"github.com/root-ali/iris/pkg/health_check"
+	"github.com/root-ali/iris/pkg/incidents"
+	"github.com/root-ali/iris/pkg/maintenance"
+	"github.com/root-ali/iris/pkg/message"
 	"github.com/root-ali/iris/pkg/notifications"
+	"github.com/root-ali/iris/pkg/notifications/mattermost"
+	"github.com/root-ali/iris/pkg/reports"
+	"github.com/root-ali/iris/pkg/scheduler/message_status"
+	"github.com/root-ali/iris/pkg/statuspage"
+	"github.com/root-ali/iris/pkg/templates"
 	"github.com/root-ali/iris/pkg/user"
 	"go.uber.org/zap"
 )
 
 type HttpHandler struct {
 	AS            alerts.Service
+	AST           alertstream.Interface
 	HS            health_check.HealthService
 	US            user.UserInterfaceService
 	ATHS          auth.AuthServiceInterface
 	GR            groups.GroupServiceInterface
 	CS            captcha.CaptchaServiceInterface
 	PS            notifications.ProviderServiceInterface
+	MS            maintenance.ServiceInterface
+	CRS           contactrules.ServiceInterface
+	CMS           contactmethods.ServiceInterface
+	COS           chatops.ServiceInterface
+	MMI           mattermost.Integration
+	NP            []notifications.NotificationInterface
+	DRH           message_status.DeliveryReportHandler
+	CLS           cluster.ServiceInterface
+	MES           message.ServiceInterface
+	RS            reports.ServiceInterface
+	IS            incidents.ServiceInterface
+	SP            statuspage.ServiceInterface
+	TS            templates.ServiceInterface
 	AdminPassword string
 	GinMode       string
 	SignupEnabled bool
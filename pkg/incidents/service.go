@@ -0,0 +1,311 @@
+package incidents
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/root-ali/iris/pkg/alerts"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const defaultListLimit = 50
+
+func NewService(repo Repository, cfg Config, logger *zap.SugaredLogger) *Service {
+	return &Service{
+		repo:   repo,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+func newEvent(incidentID string, t EventType, author, body string) *Event {
+	return &Event{
+		ID:         uuid.New().String(),
+		IncidentID: incidentID,
+		Type:       t,
+		Author:     author,
+		Body:       body,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// Create opens a triggered incident with the given alerts linked to it.
+func (s *Service) Create(inc *Incident, alertIDs []string, author string) (*Incident, error) {
+	now := time.Now()
+	inc.ID = uuid.New().String()
+	inc.Status = StatusTriggered
+	inc.Severity = strings.ToLower(strings.TrimSpace(inc.Severity))
+	inc.CreatedAt = now
+	inc.UpdatedAt = now
+	events := []*Event{newEvent(inc.ID, EventCreated, author, "Incident created")}
+	if inc.Assignee != "" {
+		events = append(events, newEvent(inc.ID, EventAssigned, author, inc.Assignee))
+	}
+	for _, id := range alertIDs {
+		events = append(events, newEvent(inc.ID, EventAlertLinked, author, id))
+	}
+	if err := s.repo.CreateIncident(inc, alertIDs, events); err != nil {
+		return nil, err
+	}
+	return inc, nil
+}
+
+func (s *Service) Get(id string) (*Details, error) {
+	inc, err := s.repo.GetIncident(id)
+	if err != nil {
+		return nil, err
+	}
+	als, err := s.repo.GetIncidentAlerts(id)
+	if err != nil {
+		return nil, err
+	}
+	timeline, err := s.repo.GetIncidentEvents(id)
+	if err != nil {
+		return nil, err
+	}
+	if als == nil {
+		als = []*alerts.Alert{}
+	}
+	if timeline == nil {
+		timeline = []Event{}
+	}
+	return &Details{Incident: *inc, Alerts: als, Timeline: timeline}, nil
+}
+
+func (s *Service) List(status string, limit, page int) ([]*Incident, error) {
+	if status != "" && !validStatus(Status(status)) {
+		return nil, iris_error.ErrIncidentInvalidStatus
+	}
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if page < 1 {
+		page = 1
+	}
+	return s.repo.GetIncidents(status, limit, page)
+}
+
+// Acknowledge acknowledges the incident and its linked alerts.
+func (s *Service) Acknowledge(id, userID string) (*Incident, error) {
+	inc, err := s.repo.GetIncident(id)
+	if err != nil {
+		return nil, err
+	}
+	switch inc.Status {
+	case StatusResolved:
+		return nil, iris_error.ErrIncidentResolved
+	case StatusAcknowledged:
+		return nil, iris_error.ErrIncidentAcknowledged
+	}
+	now := time.Now()
+	inc.Status = StatusAcknowledged
+	inc.AcknowledgedAt = &now
+	inc.UpdatedAt = now
+	if err := s.repo.UpdateIncident(inc, []*Event{newEvent(id, EventStatus, userID, string(StatusAcknowledged))}); err != nil {
+		return nil, err
+	}
+	if err := s.repo.AcknowledgeIncidentAlerts(id, userID, now); err != nil {
+		return nil, err
+	}
+	return inc, nil
+}
+
+func (s *Service) Resolve(id, userID string) (*Incident, error) {
+	inc, err := s.repo.GetIncident(id)
+	if err != nil {
+		return nil, err
+	}
+	if inc.Status == StatusResolved {
+		return nil, iris_error.ErrIncidentResolved
+	}
+	if err := s.resolve(inc, userID); err != nil {
+		return nil, err
+	}
+	return inc, nil
+}
+
+func (s *Service) resolve(inc *Incident, author string) error {
+	now := time.Now()
+	inc.Status = StatusResolved
+	inc.ResolvedAt = &now
+	inc.UpdatedAt = now
+	return s.repo.UpdateIncident(inc, []*Event{newEvent(inc.ID, EventStatus, author, string(StatusResolved))})
+}
+
+func (s *Service) Assign(id, assignee, author string) (*Incident, error) {
+	inc, err := s.repo.GetIncident(id)
+	if err != nil {
+		return nil, err
+	}
+	inc.Assignee = assignee
+	inc.UpdatedAt = time.Now()
+	if err := s.repo.UpdateIncident(inc, []*Event{newEvent(id, EventAssigned, author, assignee)}); err != nil {
+		return nil, err
+	}
+	return inc, nil
+}
+
+func (s *Service) AddNote(id, author, body string) (*Event, error) {
+	if _, err := s.repo.GetIncident(id); err != nil {
+		return nil, err
+	}
+	ev := newEvent(id, EventNote, author, body)
+	if err := s.repo.AddIncidentEvents([]*Event{ev}); err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+func (s *Service) LinkAlerts(id string, alertIDs []string, author string) (*Incident, error) {
+	inc, err := s.repo.GetIncident(id)
+	if err != nil {
+		return nil, err
+	}
+	if inc.Status == StatusResolved {
+		return nil, iris_error.ErrIncidentResolved
+	}
+	events := make([]*Event, 0, len(alertIDs))
+	for _, a := range alertIDs {
+		events = append(events, newEvent(id, EventAlertLinked, author, a))
+	}
+	if err := s.repo.LinkIncidentAlerts(id, alertIDs, events); err != nil {
+		return nil, err
+	}
+	return inc, nil
+}
+
+// Collapse links a firing alert to the open incident of its group, creating
+// the incident for the first alert, and returns the incident-level update
+// sent instead of the alert notification. A nil update leaves the alert
+// notification as it is.
+func (s *Service) Collapse(al alerts.Alert) (*Update, error) {
+	if al.Internal() {
+		return nil, nil
+	}
+	inc, err := s.repo.GetIncidentByAlertID(al.Id)
+	if err != nil && !errors.Is(err, iris_error.ErrIncidentNotFound) {
+		return nil, err
+	}
+	if inc != nil {
+		return s.linkedAlertUpdate(inc, al)
+	}
+	if al.Status != "firing" || len(s.cfg.GroupBy) == 0 {
+		return nil, nil
+	}
+
+	key, values := s.groupKey(al)
+	inc, err = s.repo.GetOpenIncidentByGroupKey(key)
+	if errors.Is(err, iris_error.ErrIncidentNotFound) {
+		var createErr error
+		inc, createErr = s.createFromAlert(al, key, values)
+		if createErr == nil {
+			return &Update{
+				Incident: inc,
+				Notify:   true,
+				Subject:  "[Incident] " + inc.Title,
+				Message:  fmt.Sprintf("Incident triggered by %s: %s", al.Name, al.Description),
+				State:    al.Status,
+			}, nil
+		}
+		// Another worker opened the incident of this group first
+		inc, err = s.repo.GetOpenIncidentByGroupKey(key)
+		if errors.Is(err, iris_error.ErrIncidentNotFound) {
+			return nil, createErr
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.LinkIncidentAlerts(inc.ID, []string{al.Id},
+		[]*Event{newEvent(inc.ID, EventAlertLinked, "", al.Id)}); err != nil {
+		return nil, err
+	}
+	s.logger.Infow("Alert collapsed into incident", "alertID", al.Id, "incidentID", inc.ID)
+	return &Update{Incident: inc}, nil
+}
+
+// linkedAlertUpdate records the state change of an alert that is already
+// linked. Automatic incidents resolve, and notify, with their last alert.
+func (s *Service) linkedAlertUpdate(inc *Incident, al alerts.Alert) (*Update, error) {
+	if al.Status != "resolved" || inc.Status == StatusResolved {
+		return &Update{Incident: inc}, nil
+	}
+	if err := s.repo.AddIncidentEvents([]*Event{newEvent(inc.ID, EventAlertResolved, "", al.Id)}); err != nil {
+		return nil, err
+	}
+	if !inc.Automatic() {
+		return &Update{Incident: inc}, nil
+	}
+	firing, err := s.repo.CountFiringIncidentAlerts(inc.ID)
+	if err != nil {
+		return nil, err
+	}
+	if firing > 0 {
+		return &Update{Incident: inc}, nil
+	}
+	if err := s.resolve(inc, ""); err != nil {
+		return nil, err
+	}
+	return &Update{
+		Incident: inc,
+		Notify:   true,
+		Subject:  "[Incident] " + inc.Title,
+		Message:  "Incident resolved, all of its alerts are resolved",
+		State:    al.Status,
+	}, nil
+}
+
+func (s *Service) createFromAlert(al alerts.Alert, key string, values []string) (*Incident, error) {
+	title := al.Name
+	if len(values) > 0 {
+		title = fmt.Sprintf("%s (%s)", al.Name, strings.Join(values, ", "))
+	}
+	inc := &Incident{
+		ID:        uuid.New().String(),
+		Title:     title,
+		Status:    StatusTriggered,
+		Severity:  al.Severity,
+		GroupKey:  key,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	events := []*Event{
+		newEvent(inc.ID, EventCreated, "", "Incident created from alert "+al.Name),
+		newEvent(inc.ID, EventAlertLinked, "", al.Id),
+	}
+	if err := s.repo.CreateIncident(inc, []string{al.Id}, events); err != nil {
+		return nil, err
+	}
+	s.logger.Infow("Incident created from alert", "alertID", al.Id, "incidentID", inc.ID)
+	return inc, nil
+}
+
+// groupKey hashes the values of the grouping labels of the alert, the
+// values other than alertname make up the incident title.
+func (s *Service) groupKey(al alerts.Alert) (string, []string) {
+	labels := al.LabelSet()
+	names := append([]string(nil), s.cfg.GroupBy...)
+	sort.Strings(names)
+	var b strings.Builder
+	values := make([]string, 0, len(names))
+	for _, n := range names {
+		v := labels[n]
+		b.WriteString(n + "=" + v + "\n")
+		if n != "alertname" && v != "" {
+			values = append(values, v)
+		}
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:]), values
+}
+
+func validStatus(st Status) bool {
+	return st == StatusTriggered || st == StatusAcknowledged || st == StatusResolved
+}
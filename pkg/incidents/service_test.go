@@ -0,0 +1,216 @@
+package incidents
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/root-ali/iris/pkg/alerts"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// memRepo keeps incidents in memory, alert statuses are set by the tests.
+type memRepo struct {
+	Repository
+	incidents map[string]*Incident
+	links     map[string]string
+	status    map[string]string
+	events    []*Event
+	acked     []string
+}
+
+func newMemRepo() *memRepo {
+	return &memRepo{
+		incidents: map[string]*Incident{},
+		links:     map[string]string{},
+		status:    map[string]string{},
+	}
+}
+
+func (r *memRepo) CreateIncident(inc *Incident, alertIDs []string, events []*Event) error {
+	c := *inc
+	r.incidents[inc.ID] = &c
+	return r.LinkIncidentAlerts(inc.ID, alertIDs, events)
+}
+
+func (r *memRepo) GetIncident(id string) (*Incident, error) {
+	inc, ok := r.incidents[id]
+	if !ok {
+		return nil, iris_error.ErrIncidentNotFound
+	}
+	c := *inc
+	return &c, nil
+}
+
+func (r *memRepo) GetOpenIncidentByGroupKey(key string) (*Incident, error) {
+	for _, inc := range r.incidents {
+		if inc.GroupKey == key && inc.Status != StatusResolved {
+			return r.GetIncident(inc.ID)
+		}
+	}
+	return nil, iris_error.ErrIncidentNotFound
+}
+
+func (r *memRepo) GetIncidentByAlertID(alertID string) (*Incident, error) {
+	id, ok := r.links[alertID]
+	if !ok {
+		return nil, iris_error.ErrIncidentNotFound
+	}
+	return r.GetIncident(id)
+}
+
+func (r *memRepo) UpdateIncident(inc *Incident, events []*Event) error {
+	c := *inc
+	r.incidents[inc.ID] = &c
+	return r.AddIncidentEvents(events)
+}
+
+func (r *memRepo) AddIncidentEvents(events []*Event) error {
+	r.events = append(r.events, events...)
+	return nil
+}
+
+func (r *memRepo) LinkIncidentAlerts(incidentID string, alertIDs []string, events []*Event) error {
+	for _, id := range alertIDs {
+		r.links[id] = incidentID
+	}
+	return r.AddIncidentEvents(events)
+}
+
+func (r *memRepo) CountFiringIncidentAlerts(incidentID string) (int64, error) {
+	var n int64
+	for alertID, id := range r.links {
+		if id == incidentID && r.status[alertID] == "firing" {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *memRepo) AcknowledgeIncidentAlerts(incidentID, _ string, _ time.Time) error {
+	r.acked = append(r.acked, incidentID)
+	return nil
+}
+
+func fire(r *memRepo, id, name, cluster string) alerts.Alert {
+	r.status[id] = "firing"
+	return alerts.Alert{Id: id, Name: name, Severity: "critical", Status: "firing",
+		Labels: alerts.Labels{"cluster": cluster}}
+}
+
+func resolve(r *memRepo, al alerts.Alert) alerts.Alert {
+	r.status[al.Id] = "resolved"
+	al.Status = "resolved"
+	return al
+}
+
+func TestCollapse_GroupsAlertsIntoIncident(t *testing.T) {
+	repo := newMemRepo()
+	s := NewService(repo, Config{GroupBy: []string{"alertname", "cluster"}}, zap.NewNop().Sugar())
+
+	first := fire(repo, "a1", "DiskFull", "prod")
+	u, err := s.Collapse(first)
+	if err != nil {
+		t.Fatalf("collapse: %v", err)
+	}
+	if u == nil || !u.Notify || u.Incident.Title != "DiskFull (prod)" {
+		t.Fatalf("first alert must open and notify the incident: %+v", u)
+	}
+	incidentID := u.Incident.ID
+
+	second := fire(repo, "a2", "DiskFull", "prod")
+	u, err = s.Collapse(second)
+	if err != nil {
+		t.Fatalf("collapse: %v", err)
+	}
+	if u == nil || u.Notify || u.Incident.ID != incidentID {
+		t.Fatalf("second alert must be collapsed: %+v", u)
+	}
+
+	other := fire(repo, "a3", "DiskFull", "staging")
+	u, err = s.Collapse(other)
+	if err != nil || u == nil || u.Incident.ID == incidentID {
+		t.Fatalf("other group must open its own incident: %+v %v", u, err)
+	}
+
+	u, err = s.Collapse(resolve(repo, first))
+	if err != nil || u == nil || u.Notify {
+		t.Fatalf("incident with a firing alert must stay open: %+v %v", u, err)
+	}
+	u, err = s.Collapse(resolve(repo, second))
+	if err != nil {
+		t.Fatalf("collapse: %v", err)
+	}
+	if u == nil || !u.Notify || u.State != "resolved" {
+		t.Fatalf("last resolved alert must resolve the incident: %+v", u)
+	}
+	if inc, _ := repo.GetIncident(incidentID); inc.Status != StatusResolved || inc.ResolvedAt == nil {
+		t.Fatalf("incident not resolved: %+v", inc)
+	}
+
+	// A new firing alert of the group opens a new incident
+	u, err = s.Collapse(fire(repo, "a4", "DiskFull", "prod"))
+	if err != nil || u == nil || !u.Notify || u.Incident.ID == incidentID {
+		t.Fatalf("group must reopen after resolution: %+v %v", u, err)
+	}
+}
+
+func TestCollapse_Disabled(t *testing.T) {
+	repo := newMemRepo()
+	s := NewService(repo, Config{}, zap.NewNop().Sugar())
+
+	u, err := s.Collapse(fire(repo, "a1", "DiskFull", "prod"))
+	if err != nil || u != nil {
+		t.Fatalf("alerts must pass through without grouping: %+v %v", u, err)
+	}
+
+	internal := fire(repo, "a2", "IrisLowProviderBalance", "")
+	internal.Labels[alerts.LabelInternal] = "true"
+	s.cfg.GroupBy = []string{"alertname"}
+	if u, err := s.Collapse(internal); err != nil || u != nil {
+		t.Fatalf("internal alerts must not open incidents: %+v %v", u, err)
+	}
+}
+
+func TestManualIncidentLifecycle(t *testing.T) {
+	repo := newMemRepo()
+	s := NewService(repo, Config{}, zap.NewNop().Sugar())
+
+	inc, err := s.Create(&Incident{Title: "Checkout errors", Severity: " Critical "}, []string{"a1"}, "u1")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if inc.Status != StatusTriggered || inc.Severity != "critical" {
+		t.Fatalf("unexpected incident %+v", inc)
+	}
+
+	// Manual incidents are not resolved by their alerts
+	repo.status["a1"] = "firing"
+	u, err := s.Collapse(resolve(repo, alerts.Alert{Id: "a1"}))
+	if err != nil || u == nil || u.Notify {
+		t.Fatalf("linked alert must be collapsed: %+v %v", u, err)
+	}
+	if got, _ := repo.GetIncident(inc.ID); got.Status != StatusTriggered {
+		t.Fatalf("manual incident resolved by its alert")
+	}
+
+	if _, err := s.Acknowledge(inc.ID, "u2"); err != nil {
+		t.Fatalf("acknowledge: %v", err)
+	}
+	if len(repo.acked) != 1 {
+		t.Fatalf("linked alerts not acknowledged")
+	}
+	if _, err := s.Acknowledge(inc.ID, "u2"); !errors.Is(err, iris_error.ErrIncidentAcknowledged) {
+		t.Fatalf("second acknowledge: %v", err)
+	}
+	if _, err := s.Resolve(inc.ID, "u2"); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if _, err := s.LinkAlerts(inc.ID, []string{"a2"}, "u2"); !errors.Is(err, iris_error.ErrIncidentResolved) {
+		t.Fatalf("link to resolved incident: %v", err)
+	}
+	if _, err := s.List("closed", 0, 0); !errors.Is(err, iris_error.ErrIncidentInvalidStatus) {
+		t.Fatalf("list with invalid status: %v", err)
+	}
+}
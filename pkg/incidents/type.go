@@ -0,0 +1,118 @@
+package incidents
+
+import (
+	"time"
+
+	"github.com/root-ali/iris/pkg/alerts"
+	"go.uber.org/zap"
+)
+
+type Status string
+
+const (
+	StatusTriggered    Status = "triggered"
+	StatusAcknowledged Status = "acknowledged"
+	StatusResolved     Status = "resolved"
+)
+
+type EventType string
+
+const (
+	EventCreated       EventType = "created"
+	EventStatus        EventType = "status"
+	EventAssigned      EventType = "assigned"
+	EventNote          EventType = "note"
+	EventAlertLinked   EventType = "alert_linked"
+	EventAlertResolved EventType = "alert_resolved"
+)
+
+// Incident groups related alerts. Incidents created from alerts carry the
+// GroupKey of their grouping labels and resolve with their last alert.
+type Incident struct {
+	ID             string     `json:"id" gorm:"column:id;primary_key"`
+	Title          string     `json:"title" gorm:"column:title"`
+	Status         Status     `json:"status" gorm:"column:status"`
+	Severity       string     `json:"severity" gorm:"column:severity"`
+	Assignee       string     `json:"assignee" gorm:"column:assignee;default:null"`
+	GroupKey       string     `json:"-" gorm:"column:group_key;default:null"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty" gorm:"column:acknowledged_at"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty" gorm:"column:resolved_at"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func (Incident) TableName() string { return "incidents" }
+
+// Automatic reports whether the incident was created from an alert group.
+func (i *Incident) Automatic() bool {
+	return i.GroupKey != ""
+}
+
+// Event is an entry of the incident timeline.
+type Event struct {
+	ID         string    `json:"id" gorm:"column:id;primary_key"`
+	IncidentID string    `json:"incident_id" gorm:"column:incident_id"`
+	Type       EventType `json:"type" gorm:"column:type"`
+	Author     string    `json:"author" gorm:"column:author;default:null"`
+	Body       string    `json:"body" gorm:"column:body"`
+	CreatedAt  time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+func (Event) TableName() string { return "incident_events" }
+
+// Details is an incident with its linked alerts and timeline.
+type Details struct {
+	Incident
+	Alerts   []*alerts.Alert `json:"alerts"`
+	Timeline []Event         `json:"timeline"`
+}
+
+// Update replaces the notification of an alert linked to an incident.
+// Nothing is sent for the alert when Notify is false.
+type Update struct {
+	Incident *Incident
+	Notify   bool
+	Subject  string
+	Message  string
+	State    string
+}
+
+// Config enables automatic incidents. Firing alerts with the same values
+// of the GroupBy labels are linked to one open incident, no incident is
+// created when GroupBy is empty.
+type Config struct {
+	GroupBy []string
+}
+
+type Repository interface {
+	CreateIncident(inc *Incident, alertIDs []string, events []*Event) error
+	GetIncident(id string) (*Incident, error)
+	GetIncidents(status string, limit, page int) ([]*Incident, error)
+	GetOpenIncidentByGroupKey(key string) (*Incident, error)
+	GetIncidentByAlertID(alertID string) (*Incident, error)
+	UpdateIncident(inc *Incident, events []*Event) error
+	AddIncidentEvents(events []*Event) error
+	LinkIncidentAlerts(incidentID string, alertIDs []string, events []*Event) error
+	GetIncidentAlerts(incidentID string) ([]*alerts.Alert, error)
+	GetIncidentEvents(incidentID string) ([]Event, error)
+	CountFiringIncidentAlerts(incidentID string) (int64, error)
+	AcknowledgeIncidentAlerts(incidentID, userID string, at time.Time) error
+}
+
+type ServiceInterface interface {
+	Create(inc *Incident, alertIDs []string, author string) (*Incident, error)
+	Get(id string) (*Details, error)
+	List(status string, limit, page int) ([]*Incident, error)
+	Acknowledge(id, userID string) (*Incident, error)
+	Resolve(id, userID string) (*Incident, error)
+	Assign(id, assignee, author string) (*Incident, error)
+	AddNote(id, author, body string) (*Event, error)
+	LinkAlerts(id string, alertIDs []string, author string) (*Incident, error)
+	Collapse(al alerts.Alert) (*Update, error)
+}
+
+type Service struct {
+	repo   Repository
+	cfg    Config
+	logger *zap.SugaredLogger
+}
@@ -0,0 +1,132 @@
+package maintenance
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/teambition/rrule-go"
+)
+
+// nextFunc returns the first occurrence strictly after t, or the zero time
+// when there are no more occurrences.
+type nextFunc func(t time.Time) time.Time
+
+var cronParser = cron.NewParser(
+	cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+func (w *Window) duration() time.Duration {
+	return time.Duration(w.DurationSeconds) * time.Second
+}
+
+func (w *Window) location() (*time.Location, error) {
+	if w.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(w.Timezone)
+}
+
+func (w *Window) compile() (nextFunc, error) {
+	loc, err := w.location()
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", w.Timezone, err)
+	}
+	switch w.ScheduleType {
+	case ScheduleCron:
+		sched, err := cronParser.Parse(w.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron schedule: %w", err)
+		}
+		return func(t time.Time) time.Time {
+			return sched.Next(t.In(loc))
+		}, nil
+	case ScheduleRRule:
+		opt, err := rrule.StrToROptionInLocation(strings.TrimPrefix(w.Schedule, "RRULE:"), loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rrule schedule: %w", err)
+		}
+		if opt.Dtstart.IsZero() {
+			opt.Dtstart = w.StartsAt.In(loc)
+		}
+		r, err := rrule.NewRRule(*opt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rrule schedule: %w", err)
+		}
+		return func(t time.Time) time.Time {
+			return r.After(t.In(loc), false)
+		}, nil
+	}
+	return nil, errors.New("unknown schedule type: " + string(w.ScheduleType))
+}
+
+// Validate checks that the schedule, timezone and duration of the window
+// can be evaluated.
+func (w *Window) Validate() error {
+	if w.DurationSeconds <= 0 {
+		return errors.New("duration must be > 0")
+	}
+	_, err := w.compile()
+	return err
+}
+
+// ActiveAt returns the occurrence covering t, if any.
+func (w *Window) ActiveAt(t time.Time) (*Occurrence, bool) {
+	next, err := w.compile()
+	if err != nil {
+		return nil, false
+	}
+	// The only occurrence that can cover t is the first one starting
+	// after t-duration.
+	start := next(t.Add(-w.duration()))
+	if start.IsZero() || start.After(t) || !w.effective(start) {
+		return nil, false
+	}
+	return w.occurrence(start), true
+}
+
+// Occurrences returns up to limit occurrences that overlap [from, until),
+// including one already in progress at from.
+func (w *Window) Occurrences(from, until time.Time, limit int) ([]Occurrence, error) {
+	next, err := w.compile()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Occurrence, 0)
+	start := next(from.Add(-w.duration()))
+	for !start.IsZero() && start.Before(until) {
+		if limit > 0 && len(out) >= limit {
+			break
+		}
+		if w.EndsAt != nil && start.After(*w.EndsAt) {
+			break
+		}
+		if w.effective(start) {
+			out = append(out, *w.occurrence(start))
+		}
+		start = next(start)
+	}
+	return out, nil
+}
+
+func (w *Window) effective(start time.Time) bool {
+	if !w.StartsAt.IsZero() && start.Before(w.StartsAt) {
+		return false
+	}
+	if w.EndsAt != nil && start.After(*w.EndsAt) {
+		return false
+	}
+	return true
+}
+
+func (w *Window) occurrence(start time.Time) *Occurrence {
+	return &Occurrence{
+		WindowID: w.ID,
+		Name:     w.Name,
+		StartsAt: start,
+		EndsAt:   start.Add(w.duration()),
+		Groups:   w.Groups,
+	}
+}
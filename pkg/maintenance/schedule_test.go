@@ -0,0 +1,69 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func tuesdayPatching(scheduleType ScheduleType, schedule string) *Window {
+	return &Window{
+		ID:              "w1",
+		Name:            "db patching",
+		ScheduleType:    scheduleType,
+		Schedule:        schedule,
+		DurationSeconds: int64((2 * time.Hour).Seconds()),
+		Timezone:        "Asia/Tehran",
+		StartsAt:        time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestWindowActiveAt(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Tehran")
+	windows := map[string]*Window{
+		"cron":  tuesdayPatching(ScheduleCron, "0 2 * * TUE"),
+		"rrule": tuesdayPatching(ScheduleRRule, "FREQ=WEEKLY;BYDAY=TU;BYHOUR=2;BYMINUTE=0;BYSECOND=0"),
+	}
+	for name, w := range windows {
+		t.Run(name, func(t *testing.T) {
+			assert.NoError(t, w.Validate())
+
+			// Tuesday 2026-03-03 03:30 Tehran is inside the window
+			occ, ok := w.ActiveAt(time.Date(2026, 3, 3, 3, 30, 0, 0, loc))
+			assert.True(t, ok)
+			assert.Equal(t, time.Date(2026, 3, 3, 2, 0, 0, 0, loc).Unix(), occ.StartsAt.Unix())
+			assert.Equal(t, time.Date(2026, 3, 3, 4, 0, 0, 0, loc).Unix(), occ.EndsAt.Unix())
+
+			_, ok = w.ActiveAt(time.Date(2026, 3, 3, 4, 0, 0, 0, loc))
+			assert.False(t, ok)
+			_, ok = w.ActiveAt(time.Date(2026, 3, 4, 3, 0, 0, 0, loc))
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestWindowOccurrences(t *testing.T) {
+	w := tuesdayPatching(ScheduleCron, "0 2 * * TUE")
+	end := time.Date(2026, 3, 20, 0, 0, 0, 0, time.UTC)
+	w.EndsAt = &end
+
+	occ, err := w.Occurrences(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), 10)
+	assert.NoError(t, err)
+	// 3rd, 10th and 17th of March; the 24th is after EndsAt
+	assert.Len(t, occ, 3)
+}
+
+func TestWindowValidate(t *testing.T) {
+	w := tuesdayPatching(ScheduleCron, "not a cron")
+	assert.Error(t, w.Validate())
+
+	w = tuesdayPatching(ScheduleCron, "0 2 * * TUE")
+	w.Timezone = "Mars/Olympus"
+	assert.Error(t, w.Validate())
+
+	w = tuesdayPatching(ScheduleCron, "0 2 * * TUE")
+	w.DurationSeconds = 0
+	assert.Error(t, w.Validate())
+}
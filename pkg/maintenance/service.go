@@ -0,0 +1,166 @@
+package maintenance
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/root-ali/iris/pkg/cache"
+	"github.com/root-ali/iris/pkg/matchers"
+	"github.com/root-ali/iris/pkg/util"
+	"go.uber.org/zap"
+)
+
+func NewService(repo Repository, c cache.Interface[string, []*Window], logger *zap.SugaredLogger) *Service {
+	return &Service{
+		repo:   repo,
+		cache:  c,
+		logger: logger,
+	}
+}
+
+func (s *Service) CreateWindow(w *Window) error {
+	if w.Name == "" {
+		return errors.New("name is required")
+	}
+	if err := w.Validate(); err != nil {
+		return err
+	}
+	if _, err := matchers.ParseStrings(w.Matchers); err != nil {
+		return err
+	}
+	id, err := util.NewUUIDv7()
+	if err != nil {
+		return err
+	}
+	w.ID = id
+	if w.StartsAt.IsZero() {
+		w.StartsAt = time.Now()
+	}
+	w.CreatedAt = time.Now()
+	w.ModifiedAt = time.Now()
+	if err := s.repo.AddMaintenanceWindow(w); err != nil {
+		s.logger.Errorw("Failed to add maintenance window", "name", w.Name, "error", err)
+		return err
+	}
+	s.cache.Delete(windowsCacheKey)
+	s.logger.Infow("Maintenance window created", "id", w.ID, "name", w.Name, "schedule", w.Schedule)
+	return nil
+}
+
+func (s *Service) GetWindow(id string) (*Window, error) {
+	return s.repo.GetMaintenanceWindow(id)
+}
+
+func (s *Service) ListWindows() ([]*Window, error) {
+	if windows, ok := s.cache.Get(windowsCacheKey); ok {
+		return windows, nil
+	}
+	windows, err := s.repo.GetMaintenanceWindows()
+	if err != nil {
+		s.logger.Errorw("Failed to get maintenance windows", "error", err)
+		return nil, err
+	}
+	if err := s.cache.Set(windowsCacheKey, windows, time.Minute); err != nil {
+		s.logger.Errorw("Failed to cache maintenance windows", "error", err)
+	}
+	return windows, nil
+}
+
+// UpdateWindow replaces the window with the id of w.
+func (s *Service) UpdateWindow(w *Window) error {
+	if w.Name == "" {
+		return errors.New("name is required")
+	}
+	if err := w.Validate(); err != nil {
+		return err
+	}
+	if _, err := matchers.ParseStrings(w.Matchers); err != nil {
+		return err
+	}
+	if w.StartsAt.IsZero() {
+		w.StartsAt = time.Now()
+	}
+	w.ModifiedAt = time.Now()
+	if err := s.repo.UpdateMaintenanceWindow(w); err != nil {
+		return err
+	}
+	s.cache.Delete(windowsCacheKey)
+	s.logger.Infow("Maintenance window updated", "id", w.ID, "name", w.Name, "schedule", w.Schedule)
+	return nil
+}
+
+func (s *Service) DeleteWindow(id string) error {
+	if err := s.repo.DeleteMaintenanceWindow(id); err != nil {
+		return err
+	}
+	s.cache.Delete(windowsCacheKey)
+	return nil
+}
+
+func (s *Service) IsActive(w *Window, at time.Time) bool {
+	_, ok := w.ActiveAt(at)
+	return ok
+}
+
+// Upcoming returns occurrences of every window between from and until,
+// ordered by start time.
+func (s *Service) Upcoming(from, until time.Time, limit int) ([]Occurrence, error) {
+	windows, err := s.ListWindows()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Occurrence, 0)
+	for _, w := range windows {
+		occ, err := w.Occurrences(from, until, limit)
+		if err != nil {
+			s.logger.Warnw("Cannot evaluate maintenance window", "id", w.ID, "error", err)
+			continue
+		}
+		out = append(out, occ...)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].StartsAt.Before(out[j].StartsAt)
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// FilterReceptors removes every receptor group that is under an active
+// maintenance window matching labels. It returns the remaining receptors
+// and the names of the windows that matched.
+func (s *Service) FilterReceptors(labels map[string]string, receptors []string, at time.Time) ([]string, []string, error) {
+	windows, err := s.ListWindows()
+	if err != nil {
+		return receptors, nil, err
+	}
+	remaining := slices.Clone(receptors)
+	matched := make([]string, 0)
+	for _, w := range windows {
+		if _, ok := w.ActiveAt(at); !ok {
+			continue
+		}
+		ms, err := matchers.ParseStrings(w.Matchers)
+		if err != nil {
+			s.logger.Warnw("Invalid matchers in maintenance window", "id", w.ID, "error", err)
+			continue
+		}
+		if !ms.Matches(labels) {
+			continue
+		}
+		if len(w.Groups) == 0 {
+			return []string{}, append(matched, w.Name), nil
+		}
+		before := len(remaining)
+		remaining = slices.DeleteFunc(remaining, func(r string) bool {
+			return slices.Contains(w.Groups, r)
+		})
+		if len(remaining) != before {
+			matched = append(matched, w.Name)
+		}
+	}
+	return remaining, matched, nil
+}
@@ -0,0 +1,75 @@
+package maintenance
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/root-ali/iris/pkg/cache"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type ScheduleType string
+
+const (
+	ScheduleCron  ScheduleType = "cron"
+	ScheduleRRule ScheduleType = "rrule"
+)
+
+// Window is a recurring maintenance window. Every occurrence starts at a
+// time produced by Schedule (a cron expression or an RRULE) evaluated in
+// Timezone, and lasts for DurationSeconds.
+type Window struct {
+	ID              string         `json:"id" gorm:"column:id;primary_key"`
+	Name            string         `json:"name" gorm:"column:name"`
+	Description     string         `json:"description" gorm:"column:description"`
+	ScheduleType    ScheduleType   `json:"schedule_type" gorm:"column:schedule_type"`
+	Schedule        string         `json:"schedule" gorm:"column:schedule"`
+	DurationSeconds int64          `json:"duration_seconds" gorm:"column:duration_seconds"`
+	Timezone        string         `json:"timezone" gorm:"column:timezone"`
+	Matchers        pq.StringArray `json:"matchers" gorm:"column:matchers;type:text[]"`
+	Groups          pq.StringArray `json:"groups" gorm:"column:groups;type:text[]"`
+	StartsAt        time.Time      `json:"starts_at" gorm:"column:starts_at"`
+	EndsAt          *time.Time     `json:"ends_at" gorm:"column:ends_at"`
+	CreatedAt       time.Time      `json:"created_at" gorm:"column:created_at"`
+	ModifiedAt      time.Time      `json:"modified_at" gorm:"column:modified_at"`
+	gorm.DeletedAt  `json:"-"`
+}
+
+func (Window) TableName() string { return "maintenance_windows" }
+
+// Occurrence is a single concrete instance of a window.
+type Occurrence struct {
+	WindowID string    `json:"window_id"`
+	Name     string    `json:"name"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Groups   []string  `json:"groups"`
+}
+
+type Repository interface {
+	AddMaintenanceWindow(w *Window) error
+	GetMaintenanceWindow(id string) (*Window, error)
+	GetMaintenanceWindows() ([]*Window, error)
+	UpdateMaintenanceWindow(w *Window) error
+	DeleteMaintenanceWindow(id string) error
+}
+
+type ServiceInterface interface {
+	CreateWindow(w *Window) error
+	GetWindow(id string) (*Window, error)
+	ListWindows() ([]*Window, error)
+	UpdateWindow(w *Window) error
+	DeleteWindow(id string) error
+	IsActive(w *Window, at time.Time) bool
+	Upcoming(from, until time.Time, limit int) ([]Occurrence, error)
+	FilterReceptors(labels map[string]string, receptors []string, at time.Time) ([]string, []string, error)
+}
+
+type Service struct {
+	repo   Repository
+	cache  cache.Interface[string, []*Window]
+	logger *zap.SugaredLogger
+}
+
+const windowsCacheKey = "maintenance_windows"
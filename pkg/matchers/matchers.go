@@ -0,0 +1,165 @@
+package matchers
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// New builds a matcher and compiles its regular expression when needed.
+func New(name string, t MatchType, value string) (*Matcher, error) {
+	m := &Matcher{Name: name, Type: t, Value: value}
+	switch t {
+	case MatchEqual, MatchNotEqual:
+	case MatchRegexp, MatchNotRegexp:
+		// anchored like Prometheus
+		re, err := regexp.Compile("^(?:" + value + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid regexp for label %s: %w", name, err)
+		}
+		m.re = re
+	default:
+		return nil, fmt.Errorf("unknown match type %q", t)
+	}
+	return m, nil
+}
+
+// Parse parses a single matcher like `severity="critical"`, `team!=db` or `name=~"disk.*"`.
+func Parse(s string) (*Matcher, error) {
+	s = strings.TrimSpace(s)
+	idx := strings.IndexAny(s, "=!")
+	if idx <= 0 {
+		return nil, fmt.Errorf("invalid matcher %q", s)
+	}
+	name := strings.TrimSpace(s[:idx])
+	rest := s[idx:]
+
+	var t MatchType
+	switch {
+	case strings.HasPrefix(rest, "=~"):
+		t = MatchRegexp
+	case strings.HasPrefix(rest, "!~"):
+		t = MatchNotRegexp
+	case strings.HasPrefix(rest, "!="):
+		t = MatchNotEqual
+	case strings.HasPrefix(rest, "="):
+		t = MatchEqual
+	default:
+		return nil, fmt.Errorf("invalid matcher %q", s)
+	}
+	value := strings.TrimSpace(rest[len(t):])
+	if strings.HasPrefix(value, `"`) {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quoted value in matcher %q: %w", s, err)
+		}
+		value = unquoted
+	}
+	return New(name, t, value)
+}
+
+// ParseList parses a comma separated list of matchers, optionally wrapped
+// in braces: {severity="critical",team=~"db|infra"}.
+func ParseList(s string) (Matchers, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "{")
+	s = strings.TrimSuffix(s, "}")
+	if strings.TrimSpace(s) == "" {
+		return Matchers{}, nil
+	}
+	parts := splitOutsideQuotes(s, ',')
+	ms := make(Matchers, 0, len(parts))
+	for _, p := range parts {
+		if strings.TrimSpace(p) == "" {
+			continue
+		}
+		m, err := Parse(p)
+		if err != nil {
+			return nil, err
+		}
+		ms = append(ms, m)
+	}
+	return ms, nil
+}
+
+// ParseStrings parses every entry of ss as a single matcher.
+func ParseStrings(ss []string) (Matchers, error) {
+	ms := make(Matchers, 0, len(ss))
+	for _, s := range ss {
+		m, err := Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		ms = append(ms, m)
+	}
+	return ms, nil
+}
+
+// Matches reports whether the value satisfies the matcher.
+func (m *Matcher) Matches(v string) bool {
+	switch m.Type {
+	case MatchEqual:
+		return v == m.Value
+	case MatchNotEqual:
+		return v != m.Value
+	case MatchRegexp:
+		return m.re.MatchString(v)
+	case MatchNotRegexp:
+		return !m.re.MatchString(v)
+	}
+	return false
+}
+
+// Regexp returns the anchored expression used by regexp matchers.
+func (m *Matcher) Regexp() string {
+	return "^(?:" + m.Value + ")$"
+}
+
+func (m *Matcher) String() string {
+	return m.Name + string(m.Type) + strconv.Quote(m.Value)
+}
+
+// Matches reports whether every matcher matches the label set. Missing
+// labels are treated as empty strings.
+func (ms Matchers) Matches(labels map[string]string) bool {
+	for _, m := range ms {
+		if !m.Matches(labels[m.Name]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Strings returns the canonical string form of every matcher.
+func (ms Matchers) Strings() []string {
+	out := make([]string, 0, len(ms))
+	for _, m := range ms {
+		out = append(out, m.String())
+	}
+	return out
+}
+
+func splitOutsideQuotes(s string, sep rune) []string {
+	var parts []string
+	var cur strings.Builder
+	inQuote := false
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			inQuote = !inQuote
+		case r == sep && !inQuote:
+			parts = append(parts, cur.String())
+			cur.Reset()
+			continue
+		}
+		cur.WriteRune(r)
+	}
+	parts = append(parts, cur.String())
+	return parts
+}
@@ -4,9 +4,18 @@ import (
 	"go.uber.org/zap"
 )
 
-func NewService(repo Repository, logger *zap.SugaredLogger) *Service {
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 500
+	failuresLimit      = 20
+)
+
+// NewService creates the message service, costs is the estimated price of
+// a message per provider name.
+func NewService(repo Repository, costs map[string]float64, logger *zap.SugaredLogger) *Service {
 	return &Service{
 		repo:   repo,
+		costs:  costs,
 		logger: logger,
 	}
 }
@@ -30,3 +39,72 @@ func (s *Service) UpdateMessageStatus(msg *Message, status StatusType, response
 func (s *Service) ListNotFinishedMessages() ([]Message, error) {
 	return s.repo.ListNotFinishedMessages()
 }
+
+func (s *Service) GetMessageBySenderID(sender, senderID string) (*Message, error) {
+	return s.repo.GetMessageBySenderID(sender, senderID)
+}
+
+// Search lists the messages matching the filter, newest first, with the
+// total number of matches.
+func (s *Service) Search(f Filter) ([]*Message, int64, error) {
+	if f.Limit <= 0 {
+		f.Limit = defaultSearchLimit
+	}
+	if f.Limit > maxSearchLimit {
+		f.Limit = maxSearchLimit
+	}
+	if f.Page < 1 {
+		f.Page = 1
+	}
+	return s.repo.SearchMessages(f)
+}
+
+// ByAlerts returns the messages sent for each of the alerts, oldest first.
+func (s *Service) ByAlerts(alertIDs []string) (map[string][]*Message, error) {
+	byAlert := make(map[string][]*Message, len(alertIDs))
+	if len(alertIDs) == 0 {
+		return byAlert, nil
+	}
+	msgs, err := s.repo.GetMessagesByAlertIDs(alertIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range msgs {
+		byAlert[m.AlertID] = append(byAlert[m.AlertID], m)
+	}
+	return byAlert, nil
+}
+
+// Analytics aggregates the delivery of the messages matching the filter
+// per provider, with the most common failures.
+func (s *Service) Analytics(f Filter) (*Analytics, error) {
+	stats, err := s.repo.MessageProviderStats(f)
+	if err != nil {
+		return nil, err
+	}
+	failures, err := s.repo.MessageFailureStats(f, failuresLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Analytics{Providers: []ProviderStats{}, Failures: failures}
+	if a.Failures == nil {
+		a.Failures = []FailureStats{}
+	}
+	var cost float64
+	for _, st := range stats {
+		if left := st.Total - st.Pending; left > 0 {
+			st.DeliveryRate = float64(st.Delivered) / float64(left)
+		}
+		if st.Provider == "" {
+			a.Overall = st
+			continue
+		}
+		// Failed sends are usually not charged by the providers
+		st.EstimatedCost = float64(st.Sent+st.Delivered) * s.costs[st.Provider]
+		cost += st.EstimatedCost
+		a.Providers = append(a.Providers, st)
+	}
+	a.Overall.EstimatedCost = cost
+	return a, nil
+}
@@ -0,0 +1,76 @@
+package message
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+type fakeRepo struct {
+	Repository
+	filter   Filter
+	stats    []ProviderStats
+	failures []FailureStats
+}
+
+func (r *fakeRepo) SearchMessages(f Filter) ([]*Message, int64, error) {
+	r.filter = f
+	return nil, 0, nil
+}
+
+func (r *fakeRepo) MessageProviderStats(Filter) ([]ProviderStats, error) {
+	return r.stats, nil
+}
+
+func (r *fakeRepo) MessageFailureStats(Filter, int) ([]FailureStats, error) {
+	return r.failures, nil
+}
+
+func TestSearch_DefaultsPagination(t *testing.T) {
+	repo := &fakeRepo{}
+	s := NewService(repo, nil, zap.NewNop().Sugar())
+
+	if _, _, err := s.Search(Filter{}); err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if repo.filter.Limit != defaultSearchLimit || repo.filter.Page != 1 {
+		t.Fatalf("got limit %d page %d", repo.filter.Limit, repo.filter.Page)
+	}
+
+	if _, _, err := s.Search(Filter{Limit: 10000, Page: 3}); err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if repo.filter.Limit != maxSearchLimit || repo.filter.Page != 3 {
+		t.Fatalf("got limit %d page %d", repo.filter.Limit, repo.filter.Page)
+	}
+}
+
+func TestAnalytics_RatesAndCosts(t *testing.T) {
+	repo := &fakeRepo{stats: []ProviderStats{
+		{Provider: "", Total: 12, Pending: 2, Sent: 3, Delivered: 6, Failed: 1},
+		{Provider: "Kavenegar", Total: 10, Pending: 2, Sent: 2, Delivered: 5, Failed: 1},
+		{Provider: "Mail", Total: 2, Sent: 1, Delivered: 1},
+	}}
+	s := NewService(repo, map[string]float64{"Kavenegar": 1.5}, zap.NewNop().Sugar())
+
+	a, err := s.Analytics(Filter{})
+	if err != nil {
+		t.Fatalf("analytics: %v", err)
+	}
+	if len(a.Providers) != 2 || a.Failures == nil {
+		t.Fatalf("unexpected analytics %+v", a)
+	}
+	kv := a.Providers[0]
+	if kv.DeliveryRate != 5.0/8 {
+		t.Fatalf("delivery rate = %v", kv.DeliveryRate)
+	}
+	if kv.EstimatedCost != 10.5 {
+		t.Fatalf("cost = %v", kv.EstimatedCost)
+	}
+	if a.Providers[1].EstimatedCost != 0 {
+		t.Fatalf("mail cost = %v", a.Providers[1].EstimatedCost)
+	}
+	if a.Overall.DeliveryRate != 0.6 || a.Overall.EstimatedCost != 10.5 {
+		t.Fatalf("overall = %+v", a.Overall)
+	}
+}
@@ -1,6 +1,9 @@
 package message
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,13 +17,66 @@ type Repository interface {
 	UpdateMessage(msg *Message) error
 	ListMessages() ([]*Message, error)
 	ListNotFinishedMessages() ([]Message, error)
+	GetMessageBySenderID(sender, senderID string) (*Message, error)
+	SearchMessages(f Filter) ([]*Message, int64, error)
+	MessageProviderStats(f Filter) ([]ProviderStats, error)
+	MessageFailureStats(f Filter, limit int) ([]FailureStats, error)
+	GetMessagesByAlertIDs(alertIDs []string) ([]*Message, error)
+}
+
+type ServiceInterface interface {
+	Search(f Filter) ([]*Message, int64, error)
+	Analytics(f Filter) (*Analytics, error)
+	ByAlerts(alertIDs []string) (map[string][]*Message, error)
 }
 
 type Service struct {
 	repo   Repository
+	costs  map[string]float64
 	logger *zap.SugaredLogger
 }
 
+// Filter narrows the searched and aggregated messages, empty fields match
+// every message. From and To bound the creation time.
+type Filter struct {
+	UserID   string
+	Group    string
+	Provider string
+	Status   string
+	From     *time.Time
+	To       *time.Time
+	Limit    int
+	Page     int
+}
+
+// ProviderStats aggregates the messages of a provider. An empty Provider
+// holds the totals of every provider.
+type ProviderStats struct {
+	Provider  string `json:"provider"`
+	Total     int64  `json:"total"`
+	Pending   int64  `json:"pending"`
+	Sent      int64  `json:"sent"`
+	Delivered int64  `json:"delivered"`
+	Failed    int64  `json:"failed"`
+	// DeliveryRate is delivered over the messages that left the queue.
+	DeliveryRate float64 `json:"delivery_rate"`
+	// MedianTimeToDeliver is in seconds from queueing to the delivery report.
+	MedianTimeToDeliver float64 `json:"median_time_to_deliver_seconds"`
+	EstimatedCost       float64 `json:"estimated_cost"`
+}
+
+type FailureStats struct {
+	Provider string `json:"provider"`
+	Error    string `json:"error"`
+	Count    int64  `json:"count"`
+}
+
+type Analytics struct {
+	Overall   ProviderStats   `json:"overall"`
+	Providers []ProviderStats `json:"providers"`
+	Failures  []FailureStats  `json:"failures"`
+}
+
 type Message struct {
 	Id string
 
@@ -33,11 +89,26 @@ type Message struct {
 	Sender   string
 	Status   string
 
+	// Attempt counts the sends of a pending message, and the status
+	// checks once it is sent.
 	Attempt       int
 	LastAttempt   time.Time
+	NextAttemptAt *time.Time
 	LastProviders pq.StringArray `gorm:"type:text[]"`
 	Response      string
 
+	// Outbox fields, the notification is rebuilt from them when the
+	// pending message is dispatched.
+	AlertID        string
+	Subject        string
+	State          string
+	Body           string
+	IdempotencyKey string `gorm:"default:null"`
+	// AlertAt is when the notified alert started or resolved.
+	AlertAt *time.Time
+	// TraceParent continues the trace of the alert in the provider spans.
+	TraceParent string `gorm:"default:null"`
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -46,16 +117,31 @@ type StatusType int
 
 const (
 	TypeMessageStatusSent      StatusType = 1
+	TypeMessageStatusPending   StatusType = 2
 	TypeMessageStatusFailed    StatusType = 6
 	TypeMessageStatusDelivered StatusType = 10
 )
 
 var StatusMap = map[StatusType]string{
 	TypeMessageStatusSent:      "Sent",
+	TypeMessageStatusPending:   "Pending",
 	TypeMessageStatusFailed:    "Failed",
 	TypeMessageStatusDelivered: "Delivered",
 }
 
+// IdempotencyKey identifies the notification of an alert state sent to an
+// address through a provider, so it is queued and sent at most once per
+// notify round. The first round keeps the key of messages queued before
+// alerts could be renotified.
+func IdempotencyKey(alertID, state, provider, address string, round int) string {
+	key := alertID + "|" + state + "|" + provider + "|" + address
+	if round > 0 {
+		key += "|" + strconv.Itoa(round)
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
 func NewMessage(senderId, message, receptor, sender, userId, groupName, response string,
 	providerChain []string,
 	messageStatus StatusType) *Message {
@@ -78,3 +164,11 @@ func NewMessage(senderId, message, receptor, sender, userId, groupName, response
 		UpdatedAt:     time.Now(),
 	}
 }
+
+// AlertTime returns AlertAt, or the zero time for messages without one.
+func (m *Message) AlertTime() time.Time {
+	if m.AlertAt == nil {
+		return time.Time{}
+	}
+	return *m.AlertAt
+}
@@ -2,6 +2,7 @@ package asiatech
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -17,8 +18,8 @@ import (
 	"go.uber.org/zap"
 )
 
-func NewService(username, password, scope, host, sender string, priority int, c cache.Interface[string, string],
-	logger *zap.SugaredLogger) notifications.NotificationInterface {
+func NewService(username, password, scope, host, sender string, priority int, dlrToken string,
+	c cache.Interface[string, string], logger *zap.SugaredLogger) notifications.NotificationInterface {
 	return &Service{
 		host:     host,
 		username: username,
@@ -26,6 +27,7 @@ func NewService(username, password, scope, host, sender string, priority int, c
 		sender:   sender,
 		scope:    scope,
 		priority: priority,
+		dlrToken: dlrToken,
 		c:        c,
 		logger:   logger,
 	}
@@ -49,6 +51,8 @@ func (s *Service) Send(message notifications.Message) ([]string, error) {
 		text = "🚨 Firing \n" + message.Subject + "\n" + message.Message + "\nTime: " + message.Time
 	} else if message.State == "resolved" {
 		text = "✅ Resolved \n" + message.Subject + "\n" + message.Message + "\nTime: " + message.Time
+	} else {
+		text = message.Subject + "\n" + message.Message
 	}
 	// Get asiatech token
 	token, err := s.getAuthenticationToken()
@@ -151,19 +155,54 @@ func (s *Service) Status(messageID string) (notifications.MessageStatusType, err
 		s.logger.Errorw("cannot parse response from asiatech", "error", err)
 		return notifications.TypeMessageStatusFailed, err
 	}
-	msgCode := msgResp.Data[0].DeliveryStatus
-	switch msgCode {
+	status := mapDeliveryStatus(msgResp.Data[0].DeliveryStatus)
+	s.logger.Infow("delivery status of asiatech message", "id", messageID, "status", status)
+	return status, nil
+}
+
+func (s *Service) DeliveryReportsEnabled() bool {
+	return s.dlrToken != ""
+}
+
+// DeliveryReports parses the asiatech delivery callback. The body is either
+// a single report or a list of them.
+func (s *Service) DeliveryReports(r *http.Request) ([]notifications.DeliveryReport, error) {
+	if err := notifications.VerifyCallbackToken(r, s.dlrToken); err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	var bodies []DeliveryReportBody
+	if err := json.Unmarshal(body, &bodies); err != nil {
+		var single DeliveryReportBody
+		if err := json.Unmarshal(body, &single); err != nil {
+			s.logger.Errorw("cannot parse asiatech delivery report", "error", err)
+			return nil, err
+		}
+		bodies = append(bodies, single)
+	}
+	reports := make([]notifications.DeliveryReport, 0, len(bodies))
+	for _, b := range bodies {
+		reports = append(reports, notifications.DeliveryReport{
+			MessageID: b.ID,
+			Status:    mapDeliveryStatus(b.DeliveryStatus),
+		})
+	}
+	return reports, nil
+}
+
+// mapDeliveryStatus maps asiatech delivery codes to Iris message statuses.
+func mapDeliveryStatus(code int) notifications.MessageStatusType {
+	switch code {
 	case 1:
-		s.logger.Infow("delivery status message received", "id", messageID)
-		return notifications.TypeMessageStatusDelivered, nil
+		return notifications.TypeMessageStatusDelivered
 	case 5, 11, 13, 14, 15, 16:
-		s.logger.Infow("delivery status message failed", "id", messageID)
-		return notifications.TypeMessageStatusFailed, nil
+		return notifications.TypeMessageStatusFailed
 	default:
-		s.logger.Infow("message still is in progress...", "id", messageID)
-		return notifications.TypeMessageStatusSent, nil
+		return notifications.TypeMessageStatusSent
 	}
-
 }
 
 func (s *Service) Verify() (string, error) {
@@ -252,3 +291,41 @@ func (t *customTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	}
 	return t.Base.RoundTrip(req)
 }
+
+// HealthCheck logs in to the API, a cached token is reused until it expires.
+func (s *Service) HealthCheck(_ context.Context) error {
+	_, err := s.getAuthenticationToken()
+	return err
+}
+
+// Balance returns the remaining credit of the account.
+func (s *Service) Balance(ctx context.Context) (float64, error) {
+	token, err := s.getAuthenticationToken()
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.host+getCredit, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := s.createApiHandler(token).Do(req)
+	if err != nil {
+		s.logger.Errorw("cannot get credit from asiatech", "error", err)
+		return 0, err
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("cannot get credit from asiatech: http status code %d", resp.StatusCode)
+	}
+	creditResp := CreditResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&creditResp); err != nil {
+		s.logger.Errorw("cannot parse credit response from asiatech", "error", err)
+		return 0, err
+	}
+	if creditResp.ResultCode != 100 {
+		return 0, fmt.Errorf("cannot get credit from asiatech: %d", creditResp.ResultCode)
+	}
+	return creditResp.Data, nil
+}
@@ -24,6 +24,7 @@ func TestNewService(t *testing.T) {
 		"http://asiatech.sms",
 		"testsender",
 		1,
+		"",
 		mockCache,
 		logger)
 
@@ -482,6 +483,7 @@ func TestGetStatus_FromAPI_GetRejectedStatus(t *testing.T) {
 		"98900090",
 		"scope",
 		1,
+		"",
 		mockCache,
 		logger,
 	}
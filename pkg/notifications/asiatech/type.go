@@ -14,6 +14,7 @@ type Service struct {
 	sender   string
 	scope    string
 	priority int
+	dlrToken string
 
 	c cache.Interface[string, string]
 
@@ -46,6 +47,13 @@ type TokenResponse struct {
 
 type DeliveryMessageRequest []string
 
+// DeliveryReportBody is a single report of the asiatech delivery callback.
+type DeliveryReportBody struct {
+	ID             string `json:"id"`
+	DeliveryStatus int    `json:"deliveryStatus"`
+	DeliveryTime   string `json:"deliveryDate"`
+}
+
 type DeliveryMessageResponse struct {
 	Message    string `json:"message"`
 	ResultCode int    `json:"resultCode"`
@@ -56,8 +64,16 @@ type DeliveryMessageResponse struct {
 	}
 }
 
+// CreditResponse is the account credit in rials.
+type CreditResponse struct {
+	Message    string  `json:"message"`
+	ResultCode int     `json:"resultCode"`
+	Data       float64 `json:"data"`
+}
+
 var (
 	getTokenPath   = "/connect/token"
 	sendSms        = "/api/1/message/send"
 	deliveryStatus = "/api/message/getdlr"
+	getCredit      = "/api/1/account/credit"
 )
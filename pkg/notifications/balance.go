@@ -0,0 +1,26 @@
+package notifications
+
+import (
+	"context"
+)
+
+// BalanceInterface is implemented by prepaid providers that can report the
+// credit left on the account.
+type BalanceInterface interface {
+	Balance(ctx context.Context) (float64, error)
+}
+
+// BalanceOf returns the balance capability of ni, guarded providers are
+// looked through.
+func BalanceOf(ni NotificationInterface) (BalanceInterface, bool) {
+	if g, ok := ni.(GuardedInterface); ok {
+		ni = g.Unwrap()
+	}
+	b, ok := ni.(BalanceInterface)
+	return b, ok
+}
+
+// SetBalance exports the last polled balance of provider.
+func SetBalance(provider string, balance float64) {
+	providerBalance.WithLabelValues(provider).Set(balance)
+}
@@ -0,0 +1,35 @@
+package notifications
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	iris_error "github.com/root-ali/iris/pkg/errors"
+)
+
+// CallbackTokenHeader carries the callback token for providers that can set
+// request headers, the others pass it as the token query parameter.
+const CallbackTokenHeader = "X-Iris-Token"
+
+// DeliveryReportsOf returns the delivery report capability of ni, guarded
+// providers are looked through.
+func DeliveryReportsOf(ni NotificationInterface) (DeliveryReportInterface, bool) {
+	if g, ok := ni.(GuardedInterface); ok {
+		ni = g.Unwrap()
+	}
+	dr, ok := ni.(DeliveryReportInterface)
+	return dr, ok
+}
+
+// VerifyCallbackToken checks the token of a provider callback request. An
+// empty token disables the callback.
+func VerifyCallbackToken(r *http.Request, token string) error {
+	got := r.Header.Get(CallbackTokenHeader)
+	if got == "" {
+		got = r.URL.Query().Get("token")
+	}
+	if token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+		return iris_error.ErrCallbackTokenInvalid
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package notifications
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyCallbackToken(t *testing.T) {
+	r := httptest.NewRequest("POST", "/v1/providers/kavenegar/dlr?token=secret", nil)
+	assert.NoError(t, VerifyCallbackToken(r, "secret"))
+	assert.Error(t, VerifyCallbackToken(r, "other"))
+	assert.Error(t, VerifyCallbackToken(r, ""))
+
+	r = httptest.NewRequest("POST", "/v1/providers/smsir/dlr", nil)
+	assert.Error(t, VerifyCallbackToken(r, "secret"))
+	r.Header.Set(CallbackTokenHeader, "secret")
+	assert.NoError(t, VerifyCallbackToken(r, "secret"))
+}
@@ -0,0 +1,242 @@
+package notifications
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// BreakerState is the state of a provider circuit breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets every send through.
+	BreakerClosed BreakerState = iota
+	// BreakerHalfOpen lets a single trial send through after OpenTimeout.
+	BreakerHalfOpen
+	// BreakerOpen rejects sends until OpenTimeout has passed.
+	BreakerOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerHalfOpen:
+		return "half-open"
+	case BreakerOpen:
+		return "open"
+	default:
+		return "closed"
+	}
+}
+
+// GuardConfig configures the rate limit and circuit breaker of a provider.
+type GuardConfig struct {
+	// RateLimit is the sustained sends per second, 0 disables the limit.
+	RateLimit float64
+	// Burst is how many sends may go out at once, defaults to 1.
+	Burst int
+	// FailureThreshold is the number of consecutive failed sends that
+	// opens the breaker, defaults to 5.
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before a trial send,
+	// defaults to 30s.
+	OpenTimeout time.Duration
+	// Retry is the backoff of failed sends, unset fields use
+	// DefaultRetryPolicy.
+	Retry RetryPolicy
+}
+
+// GuardedInterface is implemented by providers wrapped with Guard.
+type GuardedInterface interface {
+	BreakerState() BreakerState
+	RetryPolicy() RetryPolicy
+	Unwrap() NotificationInterface
+}
+
+// guarded wraps a provider with a token bucket rate limit and a circuit
+// breaker so an unhealthy provider is not called by every worker.
+type guarded struct {
+	NotificationInterface
+	limiter *tokenBucket
+	breaker *circuitBreaker
+	retry   RetryPolicy
+	logger  *zap.SugaredLogger
+}
+
+// Guard wraps ni with the rate limit and circuit breaker in cfg.
+func Guard(ni NotificationInterface, cfg GuardConfig, logger *zap.SugaredLogger) NotificationInterface {
+	if cfg.Burst < 1 {
+		cfg.Burst = 1
+	}
+	if cfg.FailureThreshold < 1 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = 30 * time.Second
+	}
+	g := &guarded{
+		NotificationInterface: ni,
+		breaker: &circuitBreaker{
+			name:      ni.GetName(),
+			threshold: cfg.FailureThreshold,
+			timeout:   cfg.OpenTimeout,
+			logger:    logger,
+		},
+		retry:  cfg.Retry.withDefaults(),
+		logger: logger,
+	}
+	if cfg.RateLimit > 0 {
+		g.limiter = &tokenBucket{
+			rate:   cfg.RateLimit,
+			burst:  float64(cfg.Burst),
+			tokens: float64(cfg.Burst),
+			last:   time.Now(),
+		}
+	}
+	providerCircuitState.WithLabelValues(ni.GetName()).Set(float64(BreakerClosed))
+	return g
+}
+
+func (g *guarded) Send(message Message) ([]string, error) {
+	if !g.breaker.allow(time.Now()) {
+		providerCircuitRejected.WithLabelValues(g.GetName()).Inc()
+		return nil, iris_error.ErrCircuitOpen
+	}
+	if g.limiter != nil {
+		if d := g.limiter.reserve(time.Now()); d > 0 {
+			providerRateLimited.WithLabelValues(g.GetName()).Inc()
+			time.Sleep(d)
+		}
+	}
+	ids, err := g.NotificationInterface.Send(message)
+	// Permanent errors are about the message, not the provider
+	g.breaker.record(err == nil || IsPermanent(err), time.Now())
+	return ids, err
+}
+
+func (g *guarded) BreakerState() BreakerState {
+	return g.breaker.state(time.Now())
+}
+
+func (g *guarded) RetryPolicy() RetryPolicy {
+	return g.retry
+}
+
+func (g *guarded) Unwrap() NotificationInterface {
+	return g.NotificationInterface
+}
+
+// Available reports whether ni may be picked to send, that is unless its
+// circuit breaker is open.
+func Available(ni NotificationInterface) bool {
+	return BreakerStateOf(ni) != BreakerOpen
+}
+
+// BreakerStateOf returns the breaker state of ni, providers without a
+// breaker are always closed.
+func BreakerStateOf(ni NotificationInterface) BreakerState {
+	if g, ok := ni.(GuardedInterface); ok {
+		return g.BreakerState()
+	}
+	return BreakerClosed
+}
+
+type circuitBreaker struct {
+	name      string
+	threshold int
+	timeout   time.Duration
+	logger    *zap.SugaredLogger
+
+	mu       sync.Mutex
+	current  BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// state is the effective state at now, an open breaker whose timeout has
+// passed is reported half-open so it can be picked for the trial send.
+func (b *circuitBreaker) state(now time.Time) BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.current == BreakerOpen && now.Sub(b.openedAt) >= b.timeout {
+		return BreakerHalfOpen
+	}
+	return b.current
+}
+
+func (b *circuitBreaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.current {
+	case BreakerOpen:
+		if now.Sub(b.openedAt) < b.timeout {
+			return false
+		}
+		b.transition(BreakerHalfOpen)
+		b.probing = true
+		return true
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *circuitBreaker) record(ok bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if ok {
+		b.failures = 0
+		if b.current != BreakerClosed {
+			b.transition(BreakerClosed)
+		}
+		return
+	}
+	b.failures++
+	if b.current == BreakerHalfOpen || b.failures >= b.threshold {
+		b.openedAt = now
+		if b.current != BreakerOpen {
+			b.transition(BreakerOpen)
+		}
+	}
+}
+
+func (b *circuitBreaker) transition(to BreakerState) {
+	b.logger.Warnw("Provider circuit breaker changed state", "provider", b.name,
+		"from", b.current.String(), "to", to.String(), "failures", b.failures)
+	b.current = to
+	providerCircuitState.WithLabelValues(b.name).Set(float64(to))
+	providerCircuitTransitions.WithLabelValues(b.name, to.String()).Inc()
+}
+
+// tokenBucket hands out tokens at rate per second up to burst. Callers
+// that find it empty take a token in advance and wait for it, which keeps
+// them in arrival order.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
@@ -0,0 +1,101 @@
+package notifications
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeProvider struct {
+	err   error
+	calls int
+}
+
+func (f *fakeProvider) Send(message Message) ([]string, error) {
+	f.calls++
+	return []string{"1"}, f.err
+}
+func (f *fakeProvider) Status(messageID string) (MessageStatusType, error) {
+	return TypeMessageStatusDelivered, nil
+}
+func (f *fakeProvider) Verify() (string, error) { return "", nil }
+func (f *fakeProvider) GetName() string         { return "Fake" }
+func (f *fakeProvider) GetFlag() string         { return "sms" }
+func (f *fakeProvider) GetPriority() int        { return 1 }
+
+func TestGuardCircuitBreaker(t *testing.T) {
+	fp := &fakeProvider{err: errors.New("503 service unavailable")}
+	g := Guard(fp, GuardConfig{FailureThreshold: 2, OpenTimeout: time.Minute}, zap.NewNop().Sugar())
+	b := g.(*guarded).breaker
+
+	g.Send(Message{})
+	assert.True(t, Available(g))
+	g.Send(Message{})
+	assert.Equal(t, BreakerOpen, BreakerStateOf(g))
+	assert.False(t, Available(g))
+
+	_, err := g.Send(Message{})
+	assert.ErrorIs(t, err, iris_error.ErrCircuitOpen)
+	assert.Equal(t, 2, fp.calls)
+
+	// After the timeout a single trial is let through
+	b.openedAt = time.Now().Add(-2 * time.Minute)
+	assert.Equal(t, BreakerHalfOpen, BreakerStateOf(g))
+	assert.True(t, b.allow(time.Now()))
+	assert.False(t, b.allow(time.Now()))
+	b.record(false, time.Now())
+	assert.Equal(t, BreakerOpen, BreakerStateOf(g))
+
+	b.openedAt = time.Now().Add(-2 * time.Minute)
+	fp.err = nil
+	_, err = g.Send(Message{})
+	assert.NoError(t, err)
+	assert.Equal(t, BreakerClosed, BreakerStateOf(g))
+	assert.Equal(t, BreakerClosed, BreakerStateOf(fp))
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	b := &tokenBucket{rate: 2, burst: 2, tokens: 2, last: now}
+	assert.Zero(t, b.reserve(now))
+	assert.Zero(t, b.reserve(now))
+	assert.Equal(t, 500*time.Millisecond, b.reserve(now))
+	assert.Equal(t, time.Second, b.reserve(now))
+	// Tokens refill at rate, never above burst
+	assert.Equal(t, 500*time.Millisecond, b.reserve(now.Add(time.Second)))
+	assert.Zero(t, b.reserve(now.Add(time.Hour)))
+}
+
+func TestRetryPolicy(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, Factor: 2, MaxAttempts: 3}.withDefaults()
+	assert.Equal(t, time.Second, p.Backoff(1))
+	assert.Equal(t, 4*time.Second, p.Backoff(3))
+	assert.Equal(t, time.Hour, p.Backoff(100))
+
+	p.Jitter = 0.5
+	for i := 0; i < 20; i++ {
+		d := p.Backoff(2)
+		assert.GreaterOrEqual(t, d, time.Second)
+		assert.LessOrEqual(t, d, 3*time.Second)
+	}
+	assert.Equal(t, DefaultRetryPolicy, RetryPolicyOf(&fakeProvider{}))
+}
+
+func TestPermanentError(t *testing.T) {
+	err := Permanent(errors.New("invalid number"))
+	assert.True(t, IsPermanent(err))
+	assert.True(t, IsPermanent(fmt.Errorf("send: %w", err)))
+	assert.False(t, IsPermanent(errors.New("timeout")))
+	assert.Nil(t, Permanent(nil))
+
+	// Permanent errors do not open the breaker
+	fp := &fakeProvider{err: err}
+	g := Guard(fp, GuardConfig{FailureThreshold: 1}, zap.NewNop().Sugar())
+	g.Send(Message{})
+	assert.Equal(t, BreakerClosed, BreakerStateOf(g))
+}
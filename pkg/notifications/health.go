@@ -0,0 +1,30 @@
+package notifications
+
+import (
+	"context"
+)
+
+// HealthCheckInterface is implemented by providers with a better probe than
+// Verify, such as asking the bot API who the bot is.
+type HealthCheckInterface interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// Probe checks that ni can send. Providers without HealthCheck are probed
+// with Verify, which cannot be cancelled and is left running on timeout.
+func Probe(ctx context.Context, ni NotificationInterface) error {
+	if h, ok := ni.(HealthCheckInterface); ok {
+		return h.HealthCheck(ctx)
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := ni.Verify()
+		errCh <- err
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
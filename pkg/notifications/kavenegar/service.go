@@ -1,6 +1,11 @@
 package kavenegar
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
 	"strconv"
 
 	kn "github.com/kavenegar/kavenegar-go"
@@ -8,12 +13,13 @@ import (
 	"go.uber.org/zap"
 )
 
-func NewKavenegarService(apiToken string, p int, sender string, logger *zap.SugaredLogger) *KavenegarService {
+func NewKavenegarService(apiToken string, p int, sender, dlrToken string, logger *zap.SugaredLogger) *KavenegarService {
 	api := kn.New(apiToken)
 	return &KavenegarService{
 		API:      api,
 		Sender:   sender,
 		Priority: p,
+		DlrToken: dlrToken,
 		Logger:   logger,
 	}
 }
@@ -32,14 +38,8 @@ func (k *KavenegarService) Status(messageID string) (notifications.MessageStatus
 	}
 	k.Logger.Infow("Kavenegar message status response", "status", status, "messageID", messageID)
 	for _, s := range status {
-		if s.Status == 10 {
-			messageStatus = notifications.TypeMessageStatusDelivered
-		} else if s.Status == 11 {
-			messageStatus = notifications.TypeMessageStatusUndelivered
-		} else if s.Status == 6 {
-			messageStatus = notifications.TypeMessageStatusFailed
-		} else if s.Status == 4 || s.Status == 5 {
-			messageStatus = notifications.TypeMessageStatusSent
+		if st, ok := mapDeliveryStatus(s.Status); ok {
+			messageStatus = st
 		}
 	}
 	k.Logger.Infow("Kavenegar mapped message status",
@@ -54,9 +54,20 @@ func (k *KavenegarService) kavenegarSend(_ string, messages notifications.Messag
 		text = "🚨 Firing \n" + messages.Subject + "\n" + messages.Message + "\nTime: " + messages.Time
 	} else if messages.State == "resolved" {
 		text = "✅ Resolved \n" + messages.Subject + "\n" + messages.Message + "\nTime: " + messages.Time
+	} else {
+		text = messages.Subject + "\n" + messages.Message
 	}
-	resp, err := k.API.Message.Send("", messages.Receptors, text, nil)
+	var params *kn.MessageSendParam
+	if messages.IdempotencyKey != "" {
+		// Kavenegar returns the first message again for a repeated localid
+		params = &kn.MessageSendParam{LocalID: []string{localID(messages.IdempotencyKey)}}
+	}
+	resp, err := k.API.Message.Send("", messages.Receptors, text, params)
 	if err != nil {
+		var apiErr *kn.APIError
+		if errors.As(err, &apiErr) && permanentStatus[apiErr.Status] {
+			return nil, notifications.Permanent(err)
+		}
 		return nil, err
 	}
 	var messageIDs []string
@@ -76,6 +87,16 @@ func (k *KavenegarService) Verify() (string, error) {
 	return strconv.Itoa(accInfo.Remaincredit), nil
 }
 
+// Balance returns the remaining credit of the account in rials.
+func (k *KavenegarService) Balance(_ context.Context) (float64, error) {
+	accInfo, err := k.API.Account.Info()
+	if err != nil {
+		k.Logger.Errorw("Failed to get account info", "error", err)
+		return 0, err
+	}
+	return float64(accInfo.Remaincredit), nil
+}
+
 func (k *KavenegarService) GetName() string {
 	return "Kavenegar"
 }
@@ -87,3 +108,50 @@ func (k *KavenegarService) GetFlag() string {
 func (k *KavenegarService) GetPriority() int {
 	return k.Priority
 }
+
+func (k *KavenegarService) DeliveryReportsEnabled() bool {
+	return k.DlrToken != ""
+}
+
+// DeliveryReports parses a kavenegar delivery callback, which carries the
+// messageid and status form values.
+func (k *KavenegarService) DeliveryReports(r *http.Request) ([]notifications.DeliveryReport, error) {
+	if err := notifications.VerifyCallbackToken(r, k.DlrToken); err != nil {
+		return nil, err
+	}
+	messageID := r.FormValue("messageid")
+	code, err := strconv.Atoi(r.FormValue("status"))
+	if messageID == "" || err != nil {
+		return nil, fmt.Errorf("invalid kavenegar delivery report: messageid=%q status=%q",
+			messageID, r.FormValue("status"))
+	}
+	status, ok := mapDeliveryStatus(code)
+	if !ok {
+		k.Logger.Infow("Ignoring kavenegar delivery report", "messageID", messageID, "status", code)
+		return nil, nil
+	}
+	return []notifications.DeliveryReport{{MessageID: messageID, Status: status}}, nil
+}
+
+// localID turns an idempotency key into the numeric localid kavenegar expects.
+func localID(key string) string {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return strconv.FormatUint(h.Sum64()>>1, 10)
+}
+
+// mapDeliveryStatus maps kavenegar message status codes to Iris message statuses.
+func mapDeliveryStatus(code int) (notifications.MessageStatusType, bool) {
+	switch code {
+	case 10:
+		return notifications.TypeMessageStatusDelivered, true
+	case 11:
+		return notifications.TypeMessageStatusUndelivered, true
+	case 6:
+		return notifications.TypeMessageStatusFailed, true
+	case 4, 5:
+		return notifications.TypeMessageStatusSent, true
+	default:
+		return 0, false
+	}
+}
@@ -9,5 +9,16 @@ type KavenegarService struct {
 	API      *kn.Kavenegar
 	Sender   string
 	Priority int
+	DlrToken string
 	Logger   *zap.SugaredLogger
 }
+
+// permanentStatus are the Kavenegar API codes of messages that cannot be
+// sent whatever the number of attempts: invalid receptor, empty or too long
+// message, too many receptors and invalid characters.
+var permanentStatus = map[int]bool{
+	411: true,
+	413: true,
+	414: true,
+	422: true,
+}
@@ -2,6 +2,8 @@ package mail
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"html/template"
 	"time"
 
@@ -31,7 +33,8 @@ func (s *service) Send(message notifications.Message) ([]string, error) {
 	}
 	if err := msg.To(message.Receptors...); err != nil {
 		s.logger.Errorf("failed to set subject: %v", err)
-		return nil, err
+		// An invalid address fails the same way every time
+		return nil, notifications.Permanent(err)
 	}
 
 	msg.Subject(messageSubject)
@@ -39,6 +42,10 @@ func (s *service) Send(message notifications.Message) ([]string, error) {
 
 	if err := s.client.DialAndSend(msg); err != nil {
 		s.logger.Errorf("failed to send email: %v", err)
+		var se *mail.SendError
+		if errors.As(err, &se) && permanentSMTPCodes[se.ErrorCode()] {
+			return nil, notifications.Permanent(err)
+		}
 		return nil, err
 	}
 	s.logger.Infof("email sent successfully to: %v", message.Receptors)
@@ -79,3 +86,15 @@ func generateEmail(als AlertNotification) (string, error) {
 
 	return buf.String(), nil
 }
+
+// HealthCheck opens an SMTP session of its own and sends NOOP.
+func (s *service) HealthCheck(ctx context.Context) error {
+	sc, err := s.client.DialToSMTPClientWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = s.client.CloseWithSMTPClient(sc)
+	}()
+	return sc.Noop()
+}
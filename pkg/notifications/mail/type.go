@@ -127,3 +127,11 @@ func NewService(config Config, name string, priority int,
 		logger:   logger,
 	}
 }
+
+// permanentSMTPCodes are replies about the recipient mailbox, the same
+// message is rejected again on every attempt.
+var permanentSMTPCodes = map[int]bool{
+	550: true,
+	551: true,
+	553: true,
+}
@@ -0,0 +1,147 @@
+package mattermost
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/root-ali/iris/pkg/alerts"
+	"github.com/root-ali/iris/pkg/chatops"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+)
+
+// ActionsPath is the Iris endpoint interactive buttons call back to.
+const ActionsPath = "/v0/integrations/mattermost/actions"
+
+var actionButtons = []struct {
+	action string
+	name   string
+	style  string
+}{
+	{chatops.ActionAck, "Ack", "primary"},
+	{chatops.ActionSilence, "Silence 1h", "default"},
+	{chatops.ActionResolve, "Resolve", "good"},
+}
+
+// actions returns the buttons for a firing alert, skipping the ones that no
+// longer apply.
+func (s service) actions(alertID string, skip ...string) []*model.PostAction {
+	if s.callbackUrl == "" || alertID == "" {
+		return nil
+	}
+	actions := make([]*model.PostAction, 0, len(actionButtons))
+	for _, b := range actionButtons {
+		if slices.Contains(skip, b.action) {
+			continue
+		}
+		actions = append(actions, &model.PostAction{
+			Id:    b.action,
+			Type:  model.PostActionTypeButton,
+			Name:  b.name,
+			Style: b.style,
+			Integration: &model.PostActionIntegration{
+				URL: s.callbackUrl + ActionsPath,
+				Context: map[string]any{
+					"alert_id": alertID,
+					"action":   b.action,
+					"token":    s.sign(alertID, b.action),
+				},
+			},
+		})
+	}
+	return actions
+}
+
+func (s service) sign(alertID, action string) string {
+	mac := hmac.New(sha256.New, []byte(s.actionSecret))
+	mac.Write([]byte(alertID + ":" + action))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// HandleAction runs a button click and updates the original post with the
+// new alert state and the name of the person who acted.
+func (s service) HandleAction(ctx context.Context, req *model.PostActionIntegrationRequest, h CommandHandler) *model.PostActionIntegrationResponse {
+	alertID, _ := req.Context["alert_id"].(string)
+	action, _ := req.Context["action"].(string)
+	token, _ := req.Context["token"].(string)
+	if !hmac.Equal([]byte(token), []byte(s.sign(alertID, action))) {
+		s.logger.Warnw("Invalid mattermost action token", "alertID", alertID, "action", action, "user", req.UserName)
+		return &model.PostActionIntegrationResponse{EphemeralText: "Invalid action."}
+	}
+
+	actor := "@" + req.UserName
+	al, err := h.HandleAction(alertID, action, actor)
+	if err != nil {
+		s.logger.Errorw("Mattermost action failed", "alertID", alertID, "action", action, "error", err)
+		return &model.PostActionIntegrationResponse{EphemeralText: fmt.Sprintf("Cannot %s alert: %s", action, err)}
+	}
+
+	post, _, err := s.client.GetPost(ctx, req.PostId, "")
+	if err != nil {
+		s.logger.Errorw("Cannot get mattermost post", "postID", req.PostId, "error", err)
+		return &model.PostActionIntegrationResponse{EphemeralText: "Alert updated by " + actor}
+	}
+	attachments := post.Attachments()
+	if len(attachments) == 0 {
+		attachments = []*model.SlackAttachment{{}}
+	}
+	a := attachments[0]
+	a.Fields = append(a.Fields, actionField(al, action, actor))
+	a.Actions = s.actions(al.Id, doneActions(al)...)
+	if al.Status == "resolved" {
+		a.Color = "#008000"
+	} else if al.AcknowledgedAt != nil {
+		a.Color = "#FFA500"
+	}
+	post.AddProp("attachments", attachments)
+	return &model.PostActionIntegrationResponse{Update: post}
+}
+
+// HandleSlashCommand answers the /iris slash command. The reply is only
+// visible to the user that ran it.
+func (s service) HandleSlashCommand(token, userName, text string, h CommandHandler) (*model.CommandResponse, error) {
+	if s.slashToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.slashToken)) != 1 {
+		return nil, iris_error.ErrSlashTokenInvalid
+	}
+	return &model.CommandResponse{
+		ResponseType: model.CommandResponseTypeEphemeral,
+		Text:         "```\n" + h.Execute("@"+userName, text) + "\n```",
+	}, nil
+}
+
+func actionField(al *alerts.Alert, action, actor string) *model.SlackAttachmentField {
+	at := time.Now().Format(time.RFC1123)
+	switch action {
+	case chatops.ActionAck:
+		return &model.SlackAttachmentField{Title: "Acknowledged", Value: actor + " at " + at, Short: true}
+	case chatops.ActionSilence:
+		until := ""
+		if al.SilencedUntil != nil {
+			until = " until " + al.SilencedUntil.Format(time.RFC1123)
+		}
+		return &model.SlackAttachmentField{Title: "Silenced", Value: "by " + actor + until, Short: true}
+	default:
+		return &model.SlackAttachmentField{Title: "Resolved", Value: actor + " at " + at, Short: true}
+	}
+}
+
+// doneActions lists the buttons that no longer apply to al.
+func doneActions(al *alerts.Alert) []string {
+	if al.Status != "firing" {
+		return []string{chatops.ActionAck, chatops.ActionSilence, chatops.ActionResolve}
+	}
+	done := make([]string, 0, 2)
+	if al.AcknowledgedAt != nil {
+		done = append(done, chatops.ActionAck)
+	}
+	if al.SilencedUntil != nil && al.SilencedUntil.After(time.Now()) {
+		done = append(done, chatops.ActionSilence)
+	}
+	return done
+}
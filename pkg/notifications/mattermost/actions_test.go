@@ -0,0 +1,62 @@
+package mattermost
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/root-ali/iris/pkg/alerts"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeHandler struct {
+	actions int
+}
+
+func (f *fakeHandler) Execute(actor, text string) string {
+	return actor + " " + text
+}
+
+func (f *fakeHandler) HandleAction(alertID, action, actor string) (*alerts.Alert, error) {
+	f.actions++
+	return &alerts.Alert{Id: alertID, Status: "firing"}, nil
+}
+
+func newTestService() service {
+	s := NewService(Config{Url: "http://mattermost.local", BotToken: "bot", CallbackUrl: "https://iris.local/", SlashToken: "slash"}, zap.NewNop().Sugar())
+	return *s.(*service)
+}
+
+func TestActions(t *testing.T) {
+	s := newTestService()
+	actions := s.actions("a1", "ack")
+	assert.Len(t, actions, 2)
+	assert.Equal(t, "https://iris.local"+ActionsPath, actions[0].Integration.URL)
+	assert.Equal(t, s.sign("a1", "silence"), actions[0].Integration.Context["token"])
+
+	s.callbackUrl = ""
+	assert.Nil(t, s.actions("a1"))
+}
+
+func TestHandleActionRejectsForgedToken(t *testing.T) {
+	s := newTestService()
+	h := &fakeHandler{}
+	resp := s.HandleAction(context.Background(), &model.PostActionIntegrationRequest{
+		UserName: "alice",
+		Context:  map[string]any{"alert_id": "a1", "action": "resolve", "token": s.sign("a2", "resolve")},
+	}, h)
+	assert.Equal(t, "Invalid action.", resp.EphemeralText)
+	assert.Zero(t, h.actions)
+}
+
+func TestHandleSlashCommand(t *testing.T) {
+	s := newTestService()
+	_, err := s.HandleSlashCommand("wrong", "alice", "", &fakeHandler{})
+	assert.Error(t, err)
+
+	resp, err := s.HandleSlashCommand("slash", "alice", "ack a1", &fakeHandler{})
+	assert.NoError(t, err)
+	assert.Equal(t, model.CommandResponseTypeEphemeral, resp.ResponseType)
+	assert.Contains(t, resp.Text, "@alice ack a1")
+}
@@ -16,6 +16,8 @@ func (s service) Send(message notifications.Message) ([]string, error) {
 	for _, recipient := range message.Receptors {
 		post := &model.Post{
 			ChannelId: recipient,
+			// Mattermost drops posts with a pending post id it has already seen
+			PendingPostId: message.IdempotencyKey,
 		}
 		post.AddProp("from", "iris")
 
@@ -23,10 +25,11 @@ func (s service) Send(message notifications.Message) ([]string, error) {
 			post.Message = "🚨 " + message.Subject + " 🚨"
 			post.AddProp("attachments", []*model.SlackAttachment{
 				{
-					Title:  message.State,
-					Text:   message.Message,
-					Color:  "#FF6B6B",
-					Footer: message.Time,
+					Title:   message.State,
+					Text:    message.Message,
+					Color:   "#FF6B6B",
+					Footer:  message.Time,
+					Actions: s.actions(message.AlertID),
 				},
 			})
 			post.AddProp("emoji", ":red_circle:")
@@ -47,6 +50,9 @@ func (s service) Send(message notifications.Message) ([]string, error) {
 			// Optional: Also override the username
 			post.AddProp("override_username", "IRIS BOT")
 			post.AddProp("emoji", ":green_check_mark:")
+		} else {
+			post.Message = "**" + message.Subject + "**\n" + message.Message
+			post.AddProp("override_username", "IRIS BOT")
 		}
 
 		_, r, err := s.client.CreatePost(ctx, post)
@@ -83,3 +89,9 @@ func (s service) GetFlag() string {
 func (s service) GetPriority() int {
 	return s.priority
 }
+
+// HealthCheck fetches the bot user, which fails for a bad URL or token.
+func (s service) HealthCheck(ctx context.Context) error {
+	_, _, err := s.client.GetMe(ctx, "")
+	return err
+}
@@ -1,7 +1,11 @@
 package mattermost
 
 import (
+	"context"
+	"strings"
+
 	"github.com/mattermost/mattermost/server/public/model"
+	"github.com/root-ali/iris/pkg/alerts"
 	"github.com/root-ali/iris/pkg/notifications"
 
 	"go.uber.org/zap"
@@ -12,13 +16,36 @@ type service struct {
 
 	priority int
 
+	// callbackUrl is the public Iris URL Mattermost posts button clicks to,
+	// buttons are left out of alerts when it is empty.
+	callbackUrl  string
+	actionSecret string
+	slashToken   string
+
 	logger *zap.SugaredLogger
 }
 
 type Config struct {
-	Url      string
-	BotToken string
-	Priority int
+	Url          string
+	BotToken     string
+	Priority     int
+	CallbackUrl  string
+	ActionSecret string
+	SlashToken   string
+}
+
+// CommandHandler runs the alert actions and slash commands received from
+// Mattermost.
+type CommandHandler interface {
+	Execute(actor, text string) string
+	HandleAction(alertID, action, actor string) (*alerts.Alert, error)
+}
+
+// Integration is implemented by the mattermost provider to answer
+// interactive message buttons and the /iris slash command.
+type Integration interface {
+	HandleAction(ctx context.Context, req *model.PostActionIntegrationRequest, h CommandHandler) *model.PostActionIntegrationResponse
+	HandleSlashCommand(token, userName, text string, h CommandHandler) (*model.CommandResponse, error)
 }
 
 type errorStack []error
@@ -43,9 +70,17 @@ func NewService(cfg Config, logger *zap.SugaredLogger) notifications.Notificatio
 	client := model.NewAPIv4Client(cfg.Url)
 	client.SetToken(cfg.BotToken)
 
+	secret := cfg.ActionSecret
+	if secret == "" {
+		secret = cfg.BotToken
+	}
+
 	return &service{
-		client:   client,
-		priority: cfg.Priority,
-		logger:   logger,
+		client:       client,
+		priority:     cfg.Priority,
+		callbackUrl:  strings.TrimSuffix(cfg.CallbackUrl, "/"),
+		actionSecret: secret,
+		slashToken:   cfg.SlashToken,
+		logger:       logger,
 	}
 }
@@ -0,0 +1,82 @@
+package notifications
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	providerCircuitState = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "iris_provider_circuit_state",
+		Help: "Circuit breaker state of a provider: 0 closed, 1 half-open, 2 open.",
+	}, []string{"provider"})
+	providerCircuitTransitions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "iris_provider_circuit_transitions_total",
+		Help: "Circuit breaker state changes of a provider by new state.",
+	}, []string{"provider", "state"})
+	providerCircuitRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "iris_provider_circuit_rejected_total",
+		Help: "Sends rejected because the provider circuit breaker was open.",
+	}, []string{"provider"})
+	providerRateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "iris_provider_rate_limited_total",
+		Help: "Sends delayed by the provider rate limit.",
+	}, []string{"provider"})
+	providerBalance = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "iris_provider_balance",
+		Help: "Account credit left on a provider, in the provider's currency.",
+	}, []string{"provider"})
+	notificationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "iris_notifications_total",
+		Help: "Notifications by provider and result: sent, failed or delivered.",
+	}, []string{"provider", "result"})
+	notificationAcceptLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "iris_notification_accept_latency_seconds",
+		Help:    "Time from the alert starting or resolving to the provider accepting its notification.",
+		Buckets: latencyBuckets,
+	}, []string{"provider"})
+	notificationDeliveryLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "iris_notification_delivery_latency_seconds",
+		Help:    "Time from the alert starting or resolving to its notification being delivered.",
+		Buckets: latencyBuckets,
+	}, []string{"provider"})
+	notificationFallbacks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "iris_notification_fallbacks_total",
+		Help: "Notifications resent through an alternative provider.",
+	}, []string{"from", "to"})
+)
+
+// latencyBuckets spans a chat message sent right away to an SMS delivered
+// after an hour of retries.
+var latencyBuckets = []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600}
+
+// ObserveSent counts a notification accepted by provider. alertAt is when
+// the notified alert state began, zero for messages queued before it was
+// recorded.
+func ObserveSent(provider string, alertAt time.Time) {
+	notificationsTotal.WithLabelValues(provider, "sent").Inc()
+	if !alertAt.IsZero() {
+		notificationAcceptLatency.WithLabelValues(provider).Observe(time.Since(alertAt).Seconds())
+	}
+}
+
+// ObserveFailed counts a notification given up on by provider.
+func ObserveFailed(provider string) {
+	notificationsTotal.WithLabelValues(provider, "failed").Inc()
+}
+
+// ObserveDelivered counts a notification delivered by provider.
+func ObserveDelivered(provider string, alertAt time.Time) {
+	notificationsTotal.WithLabelValues(provider, "delivered").Inc()
+	if !alertAt.IsZero() {
+		notificationDeliveryLatency.WithLabelValues(provider).Observe(time.Since(alertAt).Seconds())
+	}
+}
+
+// ObserveFallback counts a notification resent through to after from
+// failed to deliver it.
+func ObserveFallback(from, to string) {
+	notificationFallbacks.WithLabelValues(from, to).Inc()
+}
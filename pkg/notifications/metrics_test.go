@@ -0,0 +1,29 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserveNotifications(t *testing.T) {
+	sent := notificationsTotal.WithLabelValues("metrics-test", "sent")
+	delivered := notificationsTotal.WithLabelValues("metrics-test", "delivered")
+
+	ObserveSent("metrics-test", time.Now().Add(-time.Minute))
+	ObserveSent("metrics-test", time.Time{})
+	ObserveDelivered("metrics-test", time.Now().Add(-2*time.Minute))
+	ObserveFailed("metrics-test")
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(sent))
+	assert.Equal(t, 1.0, testutil.ToFloat64(delivered))
+	assert.Equal(t, 1.0, testutil.ToFloat64(notificationsTotal.WithLabelValues("metrics-test", "failed")))
+	// Messages without an alert time are counted but not timed
+	m := &dto.Metric{}
+	assert.NoError(t, notificationAcceptLatency.WithLabelValues("metrics-test").(prometheus.Histogram).Write(m))
+	assert.Equal(t, uint64(1), m.GetHistogram().GetSampleCount())
+}
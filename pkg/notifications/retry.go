@@ -0,0 +1,98 @@
+package notifications
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+const maxBackoff = time.Hour
+
+// DefaultRetryPolicy is used for providers without their own policy and
+// fills the unset fields of the ones that have it.
+var DefaultRetryPolicy = RetryPolicy{
+	BaseDelay:   30 * time.Second,
+	Factor:      2,
+	Jitter:      0.2,
+	MaxAttempts: 5,
+}
+
+// RetryPolicy is the exponential backoff of failed sends.
+type RetryPolicy struct {
+	// BaseDelay is the wait after the first failed attempt.
+	BaseDelay time.Duration
+	// Factor multiplies the wait after every further attempt.
+	Factor float64
+	// Jitter spreads the wait by up to this fraction in both directions.
+	Jitter float64
+	// MaxAttempts is the number of sends before the message fails.
+	MaxAttempts int
+}
+
+// RetryPolicyInterface is implemented by providers with their own policy.
+type RetryPolicyInterface interface {
+	RetryPolicy() RetryPolicy
+}
+
+// RetryPolicyOf returns the retry policy of ni.
+func RetryPolicyOf(ni NotificationInterface) RetryPolicy {
+	if r, ok := ni.(RetryPolicyInterface); ok {
+		return r.RetryPolicy()
+	}
+	return DefaultRetryPolicy
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = DefaultRetryPolicy.BaseDelay
+	}
+	if p.Factor < 1 {
+		p.Factor = DefaultRetryPolicy.Factor
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		p.Jitter = DefaultRetryPolicy.Jitter
+	}
+	if p.MaxAttempts < 1 {
+		p.MaxAttempts = DefaultRetryPolicy.MaxAttempts
+	}
+	return p
+}
+
+// Backoff returns how long to wait after the given failed attempt,
+// counting from 1, capped at an hour.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	d := float64(p.BaseDelay) * math.Pow(p.Factor, float64(attempt-1))
+	if p.Jitter > 0 {
+		d *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(math.Min(d, float64(maxBackoff)))
+}
+
+// PermanentError is a send error that fails the same way on every
+// attempt, such as an invalid number or a user who blocked the bot.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
+
+func (e *PermanentError) Unwrap() error { return e.Err }
+
+// Permanent marks err as not worth retrying.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent, any other
+// error is assumed to be transient.
+func IsPermanent(err error) bool {
+	var pe *PermanentError
+	return errors.As(err, &pe)
+}
@@ -1,6 +1,7 @@
 package notifications
 
 import (
+	"context"
 	"time"
 
 	"github.com/root-ali/iris/pkg/cache"
@@ -59,6 +60,48 @@ func (p *ProviderService) ModifyProviderPriority(name string, priority int) erro
 	return nil
 }
 
+// UpdateProvider sets the priority and the status of the named provider
+// at once and drops the cached provider lists.
+func (p *ProviderService) UpdateProvider(name string, priority int, enabled bool) error {
+	provider := &Providers{Name: name, Priority: priority, Status: enabled}
+	p.Logger.Infow("Updating provider", "name", name, "priority", priority, "enabled", enabled)
+	if err := p.repo.UpdateProviderSettings(provider); err != nil {
+		p.Logger.Errorw("Error updating provider", "name", name, "error", err)
+		return err
+	}
+	p.dropCache()
+	return nil
+}
+
+func (p *ProviderService) dropCache() {
+	p.cache.Delete("active_providers")
+	p.cache.Delete("providers_priority")
+}
+
+// Watch drops the cached providers whenever the settings of a provider are
+// changed, by another replica or `iris apply`, until ctx is done.
+func (p *ProviderService) Watch(ctx context.Context, l ChangeListener) {
+	go func() {
+		for {
+			err := l.Listen(ctx, ProvidersChannel, func(name string) {
+				p.Logger.Infow("Provider settings changed, dropping cached providers", "provider", name)
+				p.dropCache()
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			p.Logger.Errorw("Provider change listener stopped, listening again", "error", err, "delay", relistenDelay)
+			// changes made while not listening are missed
+			p.dropCache()
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(relistenDelay):
+			}
+		}
+	}()
+}
+
 func (p *ProviderService) GetProviderByName(name string) (*Providers, error) {
 	provider := &Providers{Name: name}
 	if err := p.repo.GetProvider(provider); err != nil {
@@ -86,6 +129,16 @@ func (p *ProviderService) GetAllProviders() ([]Providers, error) {
 	return providers, nil
 }
 
+// BreakerState returns the circuit breaker state of the named provider.
+func (p *ProviderService) BreakerState(name string) BreakerState {
+	for _, np := range p.ns {
+		if np.GetName() == name {
+			return BreakerStateOf(np)
+		}
+	}
+	return BreakerClosed
+}
+
 func (p *ProviderService) GetActiveProviders() ([]Providers, error) {
 	if cachedProviders, ok := p.cache.Get("active_providers"); ok {
 		p.Logger.Info("Active providers fetched from cache")
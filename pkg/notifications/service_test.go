@@ -0,0 +1,61 @@
+package notifications
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/root-ali/iris/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type countingProviders struct {
+	RepositoryInterface
+	reads int
+}
+
+func (r *countingProviders) GetProviders() ([]Providers, error) {
+	r.reads++
+	return []Providers{{Name: "Kavenegar", Status: true}}, nil
+}
+
+// changeFeed hands the notifications of a channel to the listener.
+type changeFeed struct {
+	listening chan func(string)
+}
+
+func (f *changeFeed) Listen(ctx context.Context, _ string, fn func(payload string)) error {
+	f.listening <- fn
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestWatchDropsCachedProvidersOnChange(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	repo := &countingProviders{}
+	ps := NewProvidersService(repo, nil, cache.New[string, *[]Providers](logger), logger)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	feed := &changeFeed{listening: make(chan func(string))}
+	ps.Watch(ctx, feed)
+
+	var notify func(string)
+	select {
+	case notify = <-feed.listening:
+	case <-time.After(time.Second):
+		t.Fatal("Watch does not listen for provider changes")
+	}
+	for i := 0; i < 2; i++ {
+		_, err := ps.GetActiveProviders()
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, repo.reads, "the second read comes from the cache")
+
+	// another process applied provider settings
+	notify("Kavenegar")
+	_, err := ps.GetActiveProviders()
+	require.NoError(t, err)
+	assert.Equal(t, 2, repo.reads, "providers are read again after the change")
+}
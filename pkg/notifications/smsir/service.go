@@ -2,6 +2,7 @@ package smsir
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -18,9 +19,9 @@ var (
 	Host = "https://api.sms.ir"
 )
 
-func NewSmsirService(apikey string, lineNumber string, p int, logger *zap.SugaredLogger) *Service {
+func NewSmsirService(apikey string, lineNumber string, p int, dlrToken string, logger *zap.SugaredLogger) *Service {
 	client := createAPIHandler(apikey)
-	return &Service{Client: client, Priority: p, LineNumber: lineNumber, Logger: logger}
+	return &Service{Client: client, Priority: p, LineNumber: lineNumber, DlrToken: dlrToken, Logger: logger}
 }
 
 func (s *Service) Send(message notifications.Message) ([]string, error) {
@@ -30,6 +31,8 @@ func (s *Service) Send(message notifications.Message) ([]string, error) {
 		text = "🚨 Firing \n" + message.Subject + "\n" + message.Message + "\nTime: " + message.Time
 	} else if message.State == "resolved" {
 		text = "✅ Resolved \n" + message.Subject + "\n" + message.Message + "\nTime: " + message.Time
+	} else {
+		text = message.Subject + "\n" + message.Message
 	}
 	requestBody := SendSMSRequestBody{
 		Mobiles:     message.Receptors,
@@ -61,7 +64,12 @@ func (s *Service) Send(message notifications.Message) ([]string, error) {
 			"status", resp.StatusCode,
 			"error", fmt.Errorf("http status code %d", resp.StatusCode),
 		)
-		return nil, fmt.Errorf("http status code %d", resp.StatusCode)
+		err = fmt.Errorf("http status code %d", resp.StatusCode)
+		// Validation errors such as an invalid mobile number fail again
+		if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnprocessableEntity {
+			return nil, notifications.Permanent(err)
+		}
+		return nil, err
 	}
 
 	defer func(Body io.ReadCloser) {
@@ -182,48 +190,47 @@ func (s *Service) GetPriority() int {
 }
 
 func (s *Service) Verify() (string, error) {
-	req, _ := http.NewRequest("GET", Host+"/v1/credit", nil)
+	credit, err := s.Balance(context.Background())
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatFloat(credit, 'f', -1, 64), nil
+}
+
+// Balance returns the remaining credit of the account in SMS units.
+func (s *Service) Balance(ctx context.Context) (float64, error) {
+	req, _ := http.NewRequestWithContext(ctx, "GET", Host+"/v1/credit", nil)
 	resp, err := s.Client.Do(req)
 	if err != nil {
 		s.Logger.Errorw("Cannot send request", "error", err)
-		return "", err
+		return 0, err
 	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+	respBody, _ := io.ReadAll(resp.Body)
 	if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		defer func(Body io.ReadCloser) {
-			err := Body.Close()
-			if err != nil {
-
-			}
-		}(resp.Body)
 		s.Logger.Errorw("Smsir returned non-200 status code",
 			"body", string(respBody),
 			"status", resp.StatusCode,
 			"error", fmt.Errorf("http status code %d", resp.StatusCode),
 		)
-		return "", fmt.Errorf("http status code %d", resp.StatusCode)
+		return 0, fmt.Errorf("http status code %d", resp.StatusCode)
 	}
-	respBody, _ := io.ReadAll(resp.Body)
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-
-		}
-	}(resp.Body)
 	var verifyResponse VerifyResponseBody
 	if err := json.Unmarshal(respBody, &verifyResponse); err != nil {
 		s.Logger.Errorw("Cannot unmarshal response body", "error", err)
-		return "", err
+		return 0, err
 	}
 	if verifyResponse.Status != 1 {
 		s.Logger.Errorw("Smsir returned non-200 status code",
 			"status", verifyResponse.Status,
 			"message", verifyResponse.Message,
 		)
-		return "", fmt.Errorf("smsir returned non-200 status code: %d, message: %s", verifyResponse.Status, verifyResponse.Message)
+		return 0, fmt.Errorf("smsir returned non-200 status code: %d, message: %s", verifyResponse.Status, verifyResponse.Message)
 	}
 	s.Logger.Infow("Smsir verify response", "status", verifyResponse.Status, "message", verifyResponse.Message)
-	return strconv.FormatFloat(verifyResponse.Data, 'f', -1, 64), nil
+	return verifyResponse.Data, nil
 }
 
 func (t *customTransport) RoundTrip(req *http.Request) (*http.Response, error) {
@@ -234,3 +241,54 @@ func (t *customTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	}
 	return t.Base.RoundTrip(req)
 }
+
+func (s *Service) DeliveryReportsEnabled() bool {
+	return s.DlrToken != ""
+}
+
+// DeliveryReports parses the sms.ir delivery webhook. The body is either a
+// single report or a list of them.
+func (s *Service) DeliveryReports(r *http.Request) ([]notifications.DeliveryReport, error) {
+	if err := notifications.VerifyCallbackToken(r, s.DlrToken); err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	var bodies []DeliveryReportBody
+	if err := json.Unmarshal(body, &bodies); err != nil {
+		var single DeliveryReportBody
+		if err := json.Unmarshal(body, &single); err != nil {
+			s.Logger.Errorw("Cannot unmarshal smsir delivery report", "error", err)
+			return nil, err
+		}
+		bodies = append(bodies, single)
+	}
+	reports := make([]notifications.DeliveryReport, 0, len(bodies))
+	for _, b := range bodies {
+		status, ok := mapDeliveryStatus(b.DeliveryState)
+		if !ok {
+			continue
+		}
+		reports = append(reports, notifications.DeliveryReport{
+			MessageID: strconv.FormatInt(b.MessageId, 10),
+			Status:    status,
+		})
+	}
+	return reports, nil
+}
+
+// mapDeliveryStatus maps sms.ir delivery states to Iris message statuses.
+func mapDeliveryStatus(state int) (notifications.MessageStatusType, bool) {
+	switch state {
+	case 1:
+		return notifications.TypeMessageStatusDelivered, true
+	case 3, 5:
+		return notifications.TypeMessageStatusSent, true
+	case 2, 4, 6, 7:
+		return notifications.TypeMessageStatusFailed, true
+	default:
+		return 0, false
+	}
+}
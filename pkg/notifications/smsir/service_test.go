@@ -0,0 +1,34 @@
+package smsir
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/root-ali/iris/pkg/notifications"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestDeliveryReports(t *testing.T) {
+	s := NewSmsirService("key", "3000", 1, "secret", zap.NewNop().Sugar())
+
+	r := httptest.NewRequest("POST", "/v1/providers/smsir/dlr?token=secret",
+		strings.NewReader(`[{"messageId": 42, "deliveryState": 1}, {"messageId": 43, "deliveryState": 6}, {"messageId": 44, "deliveryState": 99}]`))
+	reports, err := s.DeliveryReports(r)
+	assert.NoError(t, err)
+	assert.Equal(t, []notifications.DeliveryReport{
+		{MessageID: "42", Status: notifications.TypeMessageStatusDelivered},
+		{MessageID: "43", Status: notifications.TypeMessageStatusFailed},
+	}, reports)
+
+	r = httptest.NewRequest("POST", "/v1/providers/smsir/dlr?token=secret",
+		strings.NewReader(`{"messageId": 45, "deliveryState": 3}`))
+	reports, err = s.DeliveryReports(r)
+	assert.NoError(t, err)
+	assert.Equal(t, []notifications.DeliveryReport{{MessageID: "45", Status: notifications.TypeMessageStatusSent}}, reports)
+
+	r = httptest.NewRequest("POST", "/v1/providers/smsir/dlr?token=wrong", strings.NewReader(`{}`))
+	_, err = s.DeliveryReports(r)
+	assert.Error(t, err)
+}
@@ -10,6 +10,7 @@ type Service struct {
 	Client     *http.Client
 	LineNumber string
 	Priority   int
+	DlrToken   string
 	Logger     *zap.SugaredLogger
 }
 
@@ -48,3 +49,9 @@ type GetStatusResponse struct {
 		DeliveryStatus int `json:"deliveryState"`
 	} `json:"data"`
 }
+
+// DeliveryReportBody is a single report of the sms.ir delivery webhook.
+type DeliveryReportBody struct {
+	MessageId     int64 `json:"messageId"`
+	DeliveryState int   `json:"deliveryState"`
+}
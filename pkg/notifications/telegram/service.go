@@ -2,13 +2,16 @@ package telegram
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-telegram/bot"
 	"github.com/go-telegram/bot/models"
+	"github.com/root-ali/iris/pkg/cluster"
 	"github.com/root-ali/iris/pkg/notifications"
 	"go.uber.org/zap"
 )
@@ -28,7 +31,8 @@ func NewTelegramService(token string, proxy string, logger *zap.SugaredLogger) (
 			},
 		}))
 	}
-	bopts = append(bopts, bot.WithDefaultHandler(nil))
+	// Updates without a matching handler are ignored
+	bopts = append(bopts, bot.WithDefaultHandler(func(context.Context, *bot.Bot, *models.Update) {}))
 	b, err := bot.New(token, bopts...)
 	if err != nil {
 		return nil, err
@@ -61,13 +65,18 @@ func (s *service) Send(message notifications.Message) ([]string, error) {
 		text = `🚨<b> Firing </b>🚨` + "\n\n<b>" + message.Subject + "</b>\n\n" + message.Message + "\n\n" + message.Time
 	} else if message.State == "resolved" {
 		text = `✅<b> Resolved </b>✅` + "\n\n<b>" + message.Subject + "</b>\n\n" + message.Message + "\n\n" + message.Time
+	} else {
+		text = "<b>" + message.Subject + "</b>\n\n" + message.Message
+	}
+	if message.AlertID != "" && message.State == "firing" {
+		text += "\n\nID: <code>" + message.AlertID + "</code>"
 	}
 
 	for _, receptor := range message.Receptors {
 		chatID, err := strconv.ParseInt(receptor, 10, 64)
 		if err != nil {
 			s.logger.Errorw("Cannot parse chat id", "receptor", receptor, "error", err)
-			errStack.Append(err)
+			errStack.Append(notifications.Permanent(err))
 			continue
 		}
 
@@ -79,6 +88,10 @@ func (s *service) Send(message notifications.Message) ([]string, error) {
 
 		if err != nil {
 			s.logger.Errorw("Error sending telegram message", "chatID", chatID, "error", err)
+			// Blocked bots and unknown chats are not fixed by retrying
+			if errors.Is(err, bot.ErrorForbidden) || errors.Is(err, bot.ErrorBadRequest) {
+				err = notifications.Permanent(err)
+			}
 			errStack.Append(err)
 			responses = append(responses, receptor)
 			continue
@@ -88,12 +101,98 @@ func (s *service) Send(message notifications.Message) ([]string, error) {
 		errStack = append(errStack, nil)
 		responses = append(responses, strconv.Itoa(resp.ID))
 	}
-	if len(errStack) > 0 {
-		return responses, errStack
-	}
-	return responses, nil
+	return responses, errStack.orNil()
 }
 
 func (s *service) Status(_ string) (notifications.MessageStatusType, error) {
 	return notifications.TypeMessageStatusFailed, nil
 }
+
+// Listen starts polling for updates and answers every message starting with
+// a slash using h. Telegram answers a second getUpdates poller of the same
+// bot with 409 Conflict, so with a leader only the lock holder polls.
+func (s *service) Listen(ctx context.Context, h CommandHandler, leader cluster.LeaderInterface) error {
+	me, err := s.bot.GetMe(ctx)
+	if err != nil {
+		s.logger.Errorw("Cannot get telegram bot info", "error", err)
+		return err
+	}
+	s.username = me.Username
+
+	s.bot.RegisterHandlerMatchFunc(func(update *models.Update) bool {
+		return update.Message != nil && strings.HasPrefix(update.Message.Text, "/")
+	}, func(ctx context.Context, b *bot.Bot, update *models.Update) {
+		chatID := update.Message.Chat.ID
+		reply := h.HandleCommand(s.GetFlag(), strconv.FormatInt(chatID, 10), update.Message.Text)
+		_, err := b.SendMessage(ctx, &bot.SendMessageParams{
+			ChatID: chatID,
+			Text:   reply,
+		})
+		if err != nil {
+			s.logger.Errorw("Cannot reply to telegram command", "chatID", chatID, "error", err)
+		}
+	})
+
+	if leader == nil {
+		go s.bot.Start(ctx)
+		s.logger.Infow("Telegram bot listening for commands", "bot", s.username)
+		return nil
+	}
+	go s.pollWhileLeader(ctx, leader)
+	return nil
+}
+
+// leaderCheckInterval is how often a replica checks whether it took over or
+// lost the polling lock.
+const leaderCheckInterval = 5 * time.Second
+
+// pollWhileLeader polls for updates while this replica holds the lock and
+// stops as soon as it loses it.
+func (s *service) pollWhileLeader(ctx context.Context, leader cluster.LeaderInterface) {
+	ticker := time.NewTicker(leaderCheckInterval)
+	defer ticker.Stop()
+	var stop context.CancelFunc
+	var stopped chan struct{}
+	for {
+		isLeader := leader.IsLeader(cluster.LockTelegramUpdates)
+		switch {
+		case isLeader && stop == nil:
+			var pollCtx context.Context
+			pollCtx, stop = context.WithCancel(ctx)
+			stopped = make(chan struct{})
+			go func() {
+				s.bot.Start(pollCtx)
+				close(stopped)
+			}()
+			s.logger.Infow("Telegram bot listening for commands", "bot", s.username)
+		case !isLeader && stop != nil:
+			stop()
+			<-stopped
+			stop = nil
+			s.logger.Infow("Telegram bot stopped listening, another replica holds the lock", "bot", s.username)
+		}
+		select {
+		case <-ctx.Done():
+			if stop != nil {
+				stop()
+				<-stopped
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeepLink returns the t.me link that opens the bot and sends /start token.
+func (s *service) DeepLink(token string) string {
+	if s.username == "" {
+		return ""
+	}
+	return "https://t.me/" + s.username + "?start=" + token
+}
+
+// HealthCheck asks the bot API for the bot itself.
+func (s *service) HealthCheck(ctx context.Context) error {
+	_, err := s.bot.GetMe(ctx)
+	return err
+}
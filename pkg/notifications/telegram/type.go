@@ -1,13 +1,31 @@
 package telegram
 
 import (
+	"context"
+
+	"github.com/root-ali/iris/pkg/cluster"
+
 	"github.com/go-telegram/bot"
 	"go.uber.org/zap"
 )
 
 type service struct {
-	bot    *bot.Bot
-	logger *zap.SugaredLogger
+	bot      *bot.Bot
+	username string
+	logger   *zap.SugaredLogger
+}
+
+// CommandHandler answers chat commands such as /start, /ack and /silence.
+type CommandHandler interface {
+	HandleCommand(provider, address, text string) string
+}
+
+// Listener is implemented by the telegram provider to receive chat commands.
+// Only the holder of cluster.LockTelegramUpdates polls for them, a nil
+// leader always polls.
+type Listener interface {
+	Listen(ctx context.Context, h CommandHandler, leader cluster.LeaderInterface) error
+	DeepLink(token string) string
 }
 
 type errorStack []error
@@ -16,6 +34,22 @@ func (e *errorStack) Append(err error) {
 	*e = append(*e, err)
 }
 
+// Unwrap lets errors.Is and errors.As see the error of every receptor.
+func (e errorStack) Unwrap() []error {
+	return e
+}
+
+// orNil is the stack as the error of a send, nil when every receptor was
+// sent. The nil entries keep the receptors of a partial failure in order.
+func (e errorStack) orNil() error {
+	for _, err := range e {
+		if err != nil {
+			return e
+		}
+	}
+	return nil
+}
+
 func (e errorStack) Error() string {
 	errMsg := ""
 	for _, err := range e {
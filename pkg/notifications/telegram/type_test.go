@@ -0,0 +1,30 @@
+package telegram
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrorStackOrNil(t *testing.T) {
+	var sent errorStack
+	sent.Append(nil)
+	sent.Append(nil)
+	if err := sent.orNil(); err != nil {
+		t.Errorf("all receptors sent, got error %v", err)
+	}
+
+	var partial errorStack
+	partial.Append(nil)
+	partial.Append(errors.New("chat not found"))
+	err := partial.orNil()
+	if err == nil {
+		t.Fatal("partial failure returned no error")
+	}
+	if err.Error() != "nil;chat not found;" {
+		t.Errorf("error = %q", err.Error())
+	}
+
+	if err := (errorStack{}).orNil(); err != nil {
+		t.Errorf("no receptors, got error %v", err)
+	}
+}
@@ -1,6 +1,8 @@
 package notifications
 
 import (
+	"context"
+	"net/http"
 	"time"
 
 	"github.com/root-ali/iris/pkg/cache"
@@ -17,12 +19,29 @@ type NotificationInterface interface {
 	GetPriority() int
 }
 
+// DeliveryReportInterface is implemented by providers that can push
+// delivery reports to Iris instead of being polled with Status.
+type DeliveryReportInterface interface {
+	// DeliveryReportsEnabled reports whether a callback token is configured.
+	DeliveryReportsEnabled() bool
+	// DeliveryReports authenticates a delivery report callback and returns
+	// the reports it carries.
+	DeliveryReports(r *http.Request) ([]DeliveryReport, error)
+}
+
+// DeliveryReport is the status of a sent message pushed by a provider.
+type DeliveryReport struct {
+	MessageID string
+	Status    MessageStatusType
+}
+
 type RepositoryInterface interface {
 	AddProvider(providers *Providers) error
 	ModifyProvider(providers *Providers) error
 	GetProvider(providers *Providers) error
 	SetStatusFalse(providers *Providers) error
 	GetProviders() ([]Providers, error)
+	UpdateProviderSettings(providers *Providers) error
 }
 
 // ProviderServiceInterface is an interface for managing notification providers.
@@ -31,9 +50,25 @@ type ProviderServiceInterface interface {
 	EnableProvider(name string) error
 	DisableProvider(name string) error
 	ModifyProviderPriority(name string, priority int) error
+	UpdateProvider(name string, priority int, enabled bool) error
 	GetProviderByName(name string) (*Providers, error)
 	GetProviderByID(id string) (*Providers, error)
 	GetAllProviders() ([]Providers, error)
+	BreakerState(name string) BreakerState
+}
+
+// ProvidersChannel is the Postgres NOTIFY channel provider setting changes
+// go through, so every replica and `iris apply` drop the cached providers.
+const ProvidersChannel = "iris_provider_changes"
+
+// relistenDelay is the wait before listening for provider changes again
+// after the connection was lost.
+const relistenDelay = 5 * time.Second
+
+// ChangeListener calls fn with every payload sent to channel until ctx is
+// done or the connection fails.
+type ChangeListener interface {
+	Listen(ctx context.Context, channel string, fn func(payload string)) error
 }
 
 type ProviderStatusInterface interface {
@@ -54,11 +89,14 @@ type Providers struct {
 	gorm.DeletedAt `gorm:"index;default:null" json:"-"`
 }
 type Message struct {
+	AlertID   string
 	Subject   string
 	Message   string
 	State     string
 	Time      string
 	Receptors []string
+	// IdempotencyKey is passed to providers that can drop repeated sends
+	IdempotencyKey string
 }
 
 type MessageStatusType int
@@ -0,0 +1,86 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+func NewDocument(info Info) *Document {
+	return &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			Schemas:         make(map[string]*Schema),
+			SecuritySchemes: make(map[string]*SecurityScheme),
+		},
+		types: make(map[reflect.Type]string),
+	}
+}
+
+// Add documents op at method and path, path uses the {param} syntax.
+func (d *Document) Add(method, path string, op *Operation) {
+	item, ok := d.Paths[path]
+	if !ok {
+		item = make(PathItem)
+		d.Paths[path] = item
+	}
+	item[strings.ToLower(method)] = op
+}
+
+// Operation returns the operation documented at method and path.
+func (d *Document) Operation(method, path string) *Operation {
+	return d.Paths[path][strings.ToLower(method)]
+}
+
+// JSONBody is a required JSON request body with schema s.
+func JSONBody(s *Schema) *RequestBody {
+	return &RequestBody{Required: true, Content: map[string]MediaType{contentJSON: {Schema: s}}}
+}
+
+// JSONResponse is a JSON response with schema s, or without a body when
+// s is nil.
+func JSONResponse(description string, s *Schema) *Response {
+	r := &Response{Description: description}
+	if s != nil {
+		r.Content = map[string]MediaType{contentJSON: {Schema: s}}
+	}
+	return r
+}
+
+// Schema returns the schema of the body, nil when there is none.
+func (b *RequestBody) Schema() *Schema {
+	if b == nil {
+		return nil
+	}
+	return b.Content[contentJSON].Schema
+}
+
+// PathParameter is a required string parameter of the path.
+func PathParameter(name, description string) *Parameter {
+	return &Parameter{Name: name, In: InPath, Description: description, Required: true,
+		Schema: &Schema{Type: "string", MinLength: intPtr(1)}}
+}
+
+// Decode converts the raw value of the parameter to the type of its
+// schema, values that do not convert are returned as is and fail
+// validation.
+func (p *Parameter) Decode(raw string) any {
+	switch p.Schema.Type {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(raw, 64); err == nil {
+			return json.Number(raw)
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(raw); err == nil {
+			return b
+		}
+	}
+	return raw
+}
+
+func intPtr(n int) *int { return &n }
+
+func floatPtr(f float64) *float64 { return &f }
@@ -0,0 +1,122 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type widget struct {
+	Name    string     `json:"name" validate:"required,min=3,max=10"`
+	Kind    string     `json:"kind" validate:"omitempty,oneof=small large"`
+	Email   string     `json:"email" validate:"omitempty,email"`
+	Size    int        `json:"size" validate:"gte=1,lte=5" doc:"size in units"`
+	Tags    []string   `json:"tags" validate:"dive,max=4"`
+	Until   *time.Time `json:"until"`
+	Part    *part      `json:"part"`
+	private string
+}
+
+type part struct {
+	ID string `json:"id" validate:"required,uuid"`
+}
+
+func decode(t *testing.T, body string) any {
+	t.Helper()
+	var v any
+	dec := json.NewDecoder(bytes.NewBufferString(body))
+	dec.UseNumber()
+	require.NoError(t, dec.Decode(&v))
+	return v
+}
+
+func validationErrors(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var ve *ValidationError
+	require.True(t, errors.As(err, &ve))
+	return ve.Errors
+}
+
+func TestSchemaOf(t *testing.T) {
+	d := NewDocument(Info{Title: "test", Version: "1"})
+	s := d.SchemaOf(widget{})
+	assert.Equal(t, "#/components/schemas/widget", s.Ref)
+
+	w := d.Components.Schemas["widget"]
+	require.NotNil(t, w)
+	assert.Equal(t, []string{"name"}, w.Required)
+	assert.Equal(t, false, w.AdditionalProperties)
+	assert.NotContains(t, w.Properties, "private")
+	assert.Equal(t, 3, *w.Properties["name"].MinLength)
+	assert.Equal(t, 10, *w.Properties["name"].MaxLength)
+	assert.Equal(t, []any{"", "small", "large"}, w.Properties["kind"].Enum)
+	assert.Equal(t, "email", w.Properties["email"].Format)
+	assert.Equal(t, "size in units", w.Properties["size"].Description)
+	assert.Equal(t, 1.0, *w.Properties["size"].Minimum)
+	assert.Equal(t, 4, *w.Properties["tags"].Items.MaxLength)
+	assert.Equal(t, "date-time", w.Properties["until"].Format)
+	assert.True(t, w.Properties["until"].Nullable)
+	assert.Equal(t, "#/components/schemas/part", w.Properties["part"].Ref)
+	assert.Equal(t, "uuid", d.Components.Schemas["part"].Properties["id"].Format)
+
+	p := d.PartialOf(widget{})
+	assert.Equal(t, "#/components/schemas/widgetPatch", p.Ref)
+	assert.Empty(t, d.Components.Schemas["widgetPatch"].Required)
+	assert.Equal(t, []string{"name"}, w.Required)
+
+	_, err := json.Marshal(d)
+	assert.NoError(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	d := NewDocument(Info{Title: "test", Version: "1"})
+	s := d.SchemaOf(widget{})
+
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{"valid", `{"name":"gear","kind":"","size":2,"tags":["a"],"until":null,"part":{"id":"0190b6b8-4e2a-7cc5-9f4b-0c1f3a6a8d11"}}`, nil},
+		{"missing required", `{"size":2}`, []string{"body.name is required"}},
+		{"unknown field", `{"name":"gear","size":2,"color":"red"}`, []string{"body.color is not a known field"}},
+		{"enum", `{"name":"gear","size":2,"kind":"huge"}`, []string{`body.kind must be one of "", "small", "large"`}},
+		{"bounds", `{"name":"ge","size":9}`, []string{"body.name must be at least 3 characters", "body.size must be at most 5"}},
+		{"types", `{"name":1,"size":1.5,"tags":"a"}`, []string{"body.name must be a string", "body.size must be an integer", "body.tags must be an array"}},
+		{"items", `{"name":"gear","size":1,"tags":["ok","toolong"]}`, []string{"body.tags[1] must be at most 4 characters"}},
+		{"formats", `{"name":"gear","size":1,"email":"nope","until":"tomorrow","part":{"id":"x"}}`,
+			[]string{"body.email must be an email address", "body.part.id must be a UUID", "body.until must be an RFC 3339 date-time"}},
+		{"null", `{"name":null,"size":1}`, []string{"body.name must not be null"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validationErrors(t, d.Validate("body", s, decode(t, tt.body)))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	// PATCH bodies may leave out required fields but not break the rules
+	p := d.PartialOf(widget{})
+	assert.NoError(t, d.Validate("body", p, decode(t, `{"size":3}`)))
+	assert.Equal(t, []string{"body.size must be at least 1"},
+		validationErrors(t, d.Validate("body", p, decode(t, `{"size":0}`))))
+}
+
+func TestParameterDecode(t *testing.T) {
+	d := NewDocument(Info{Title: "test", Version: "1"})
+	limit := &Parameter{Name: "limit", In: InQuery, Schema: &Schema{Type: "integer", Minimum: floatPtr(1)}}
+	assert.NoError(t, d.Validate("limit", limit.Schema, limit.Decode("10")))
+	assert.Equal(t, []string{"limit must be a string"}, validationErrors(t, d.Validate("limit", &Schema{Type: "string"}, limit.Decode("10"))))
+	assert.Equal(t, []string{"limit must be a number"}, validationErrors(t, d.Validate("limit", limit.Schema, limit.Decode("ten"))))
+
+	id := PathParameter("id", "id of the resource")
+	assert.Equal(t, []string{"id must be at least 1 characters"}, validationErrors(t, d.Validate("id", id.Schema, id.Decode(""))))
+}
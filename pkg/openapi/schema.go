@@ -0,0 +1,227 @@
+package openapi
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// SchemaOf returns the schema of the type of v. Named structs are added to
+// the components and referenced, their properties follow the json tags and
+// the validate tags add the constraints: required, min, max, len, gte,
+// lte, oneof, email, url, uuid, numeric and dive for the items of slices.
+// Objects do not allow unknown properties.
+func (d *Document) SchemaOf(v any) *Schema {
+	return d.schema(reflect.TypeOf(v))
+}
+
+// PartialOf is the schema of the struct v without required properties,
+// the body of a PATCH that only sets some of them.
+func (d *Document) PartialOf(v any) *Schema {
+	t := reflect.TypeOf(v)
+	full := d.resolve(d.schema(t))
+	name := d.types[t] + "Patch"
+	if _, ok := d.Components.Schemas[name]; !ok {
+		partial := *full
+		partial.Required = nil
+		d.Components.Schemas[name] = &partial
+	}
+	return &Schema{Ref: schemaRef + name}
+}
+
+func (d *Document) schema(t reflect.Type) *Schema {
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := d.schema(t.Elem())
+		// siblings of $ref are ignored, a referenced object is not nullable
+		if s.Ref == "" {
+			s.Nullable = true
+		}
+		return s
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: d.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.schema(t.Elem())}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return d.object(t)
+		}
+		name, ok := d.types[t]
+		if !ok {
+			name = d.name(t)
+			d.types[t] = name
+			// registered first so recursive types end up as a reference
+			s := &Schema{}
+			d.Components.Schemas[name] = s
+			*s = *d.object(t)
+		}
+		return &Schema{Ref: schemaRef + name}
+	}
+	panic(fmt.Sprintf("openapi: unsupported type %s", t))
+}
+
+// name is the name of the type, prefixed with its package when another
+// type already uses it.
+func (d *Document) name(t reflect.Type) string {
+	name := t.Name()
+	if _, taken := d.Components.Schemas[name]; taken {
+		pkg := path.Base(t.PkgPath())
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	return name
+}
+
+func (d *Document) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema), AdditionalProperties: false}
+	d.fields(t, s)
+	return s
+}
+
+func (d *Document) fields(t reflect.Type, s *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct && ft != timeType {
+			d.fields(ft, s)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fs := d.schema(f.Type)
+		if doc := f.Tag.Get("doc"); doc != "" && fs.Ref == "" {
+			fs.Description = doc
+		}
+		if constrain(fs, f.Tag.Get("validate")) {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = fs
+	}
+}
+
+// constrain adds the rules of a validate tag to s and reports whether the
+// property is required. An empty string passes the rules of an omitempty
+// property.
+func constrain(s *Schema, tag string) bool {
+	if tag == "" || s.Ref != "" {
+		return false
+	}
+	rules := strings.Split(tag, ",")
+	optional := false
+	required := false
+	for i, rule := range rules {
+		key, arg, _ := strings.Cut(rule, "=")
+		switch key {
+		case "omitempty":
+			optional = true
+		case "required":
+			required = true
+			if s.Type == "string" && s.MinLength == nil {
+				s.MinLength = intPtr(1)
+			}
+		case "min", "gte":
+			bound(s, arg, true, optional)
+		case "max", "lte":
+			bound(s, arg, false, optional)
+		case "len":
+			bound(s, arg, true, optional)
+			bound(s, arg, false, optional)
+		case "oneof":
+			if optional && s.Type == "string" {
+				s.Enum = append(s.Enum, "")
+			}
+			for _, v := range strings.Fields(arg) {
+				if s.Type == "integer" {
+					n, err := strconv.Atoi(v)
+					if err != nil {
+						panic(fmt.Sprintf("openapi: invalid oneof %q", arg))
+					}
+					s.Enum = append(s.Enum, n)
+				} else {
+					s.Enum = append(s.Enum, v)
+				}
+			}
+		case "email":
+			s.Format = "email"
+		case "url":
+			s.Format = "uri"
+		case "uuid":
+			s.Format = "uuid"
+		case "numeric":
+			s.Pattern = "^[0-9]+$"
+			if optional {
+				s.Pattern = "^[0-9]*$"
+			}
+		case "dive":
+			if s.Items != nil {
+				constrain(s.Items, strings.Join(rules[i+1:], ","))
+			}
+			return required
+		}
+	}
+	return required
+}
+
+func bound(s *Schema, arg string, lower, optional bool) {
+	n, err := strconv.Atoi(arg)
+	if err != nil {
+		panic(fmt.Sprintf("openapi: invalid bound %q", arg))
+	}
+	switch s.Type {
+	case "string":
+		if lower && !optional {
+			s.MinLength = intPtr(n)
+		} else if !lower {
+			s.MaxLength = intPtr(n)
+		}
+	case "array":
+		if lower {
+			s.MinItems = intPtr(n)
+		} else {
+			s.MaxItems = intPtr(n)
+		}
+	case "integer", "number":
+		if lower {
+			s.Minimum = floatPtr(float64(n))
+		} else {
+			s.Maximum = floatPtr(float64(n))
+		}
+	}
+}
+
+func (d *Document) resolve(s *Schema) *Schema {
+	for s.Ref != "" {
+		s = d.Components.Schemas[strings.TrimPrefix(s.Ref, schemaRef)]
+	}
+	return s
+}
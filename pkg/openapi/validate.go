@@ -0,0 +1,200 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidationError lists the values that do not match a schema.
+type ValidationError struct {
+	Errors []string
+}
+
+func (e *ValidationError) Error() string {
+	return "request does not match the schema: " + strings.Join(e.Errors, "; ")
+}
+
+var patterns sync.Map
+
+// Validate checks v, decoded with json.Decoder.UseNumber, against s and
+// returns a *ValidationError naming every mismatch under name. Formats and
+// patterns are not checked on empty strings, the validate tags leave those
+// to omitempty and required.
+func (d *Document) Validate(name string, s *Schema, v any) error {
+	var errs []string
+	d.validate(s, v, name, &errs)
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+func (d *Document) validate(s *Schema, v any, at string, errs *[]string) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, at+" "+fmt.Sprintf(format, args...))
+	}
+	s = d.resolve(s)
+	if v == nil {
+		if s.Type != "" && !s.Nullable {
+			fail("must not be null")
+		}
+		return
+	}
+	if len(s.Enum) > 0 && !inEnum(s.Enum, v) {
+		fail("must be one of %s", enumString(s.Enum))
+		return
+	}
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			fail("must be an object")
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				*errs = append(*errs, at+"."+name+" is required")
+			}
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if ps, ok := s.Properties[k]; ok {
+				d.validate(ps, obj[k], at+"."+k, errs)
+				continue
+			}
+			switch ap := s.AdditionalProperties.(type) {
+			case bool:
+				if !ap {
+					*errs = append(*errs, at+"."+k+" is not a known field")
+				}
+			case *Schema:
+				d.validate(ap, obj[k], at+"."+k, errs)
+			}
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			fail("must be an array")
+			return
+		}
+		if s.MinItems != nil && len(arr) < *s.MinItems {
+			fail("must have at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(arr) > *s.MaxItems {
+			fail("must have at most %d items", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range arr {
+				d.validate(s.Items, item, fmt.Sprintf("%s[%d]", at, i), errs)
+			}
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			fail("must be a string")
+			return
+		}
+		n := utf8.RuneCountInString(str)
+		if s.MinLength != nil && n < *s.MinLength {
+			fail("must be at least %d characters", *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			fail("must be at most %d characters", *s.MaxLength)
+		}
+		if str == "" {
+			return
+		}
+		if s.Pattern != "" && !pattern(s.Pattern).MatchString(str) {
+			fail("must match %s", s.Pattern)
+		}
+		if err := checkFormat(s.Format, str); err != nil {
+			fail("must be %s", err)
+		}
+	case "integer", "number":
+		num, ok := v.(json.Number)
+		if !ok {
+			fail("must be a number")
+			return
+		}
+		f, err := num.Float64()
+		if err != nil {
+			fail("must be a number")
+			return
+		}
+		if _, err := num.Int64(); s.Type == "integer" && err != nil {
+			fail("must be an integer")
+			return
+		}
+		if s.Minimum != nil && f < *s.Minimum {
+			fail("must be at least %v", *s.Minimum)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			fail("must be at most %v", *s.Maximum)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			fail("must be a boolean")
+		}
+	}
+}
+
+func pattern(expr string) *regexp.Regexp {
+	if re, ok := patterns.Load(expr); ok {
+		return re.(*regexp.Regexp)
+	}
+	re := regexp.MustCompile(expr)
+	patterns.Store(expr, re)
+	return re
+}
+
+func checkFormat(format, s string) error {
+	switch format {
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, s); err != nil {
+			return errors.New("an RFC 3339 date-time")
+		}
+	case "email":
+		if a, err := mail.ParseAddress(s); err != nil || a.Address != s {
+			return errors.New("an email address")
+		}
+	case "uuid":
+		if !pattern(`^[0-9a-fA-F]{8}-([0-9a-fA-F]{4}-){3}[0-9a-fA-F]{12}$`).MatchString(s) {
+			return errors.New("a UUID")
+		}
+	}
+	return nil
+}
+
+func inEnum(enum []any, v any) bool {
+	if n, ok := v.(json.Number); ok {
+		i, err := n.Int64()
+		return err == nil && slices.Contains(enum, any(int(i)))
+	}
+	return slices.Contains(enum, v)
+}
+
+func enumString(enum []any) string {
+	values := make([]string, 0, len(enum))
+	for _, v := range enum {
+		if str, ok := v.(string); ok {
+			values = append(values, strconv.Quote(str))
+			continue
+		}
+		values = append(values, fmt.Sprint(v))
+	}
+	return strings.Join(values, ", ")
+}
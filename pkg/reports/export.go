@@ -0,0 +1,153 @@
+package reports
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"time"
+)
+
+const periodLayout = "2006-01-02"
+
+// WriteCSV writes the report as consecutive CSV tables, one per section,
+// separated by an empty line. Times are in seconds.
+func WriteCSV(w io.Writer, r *Report) error {
+	cw := csv.NewWriter(w)
+	groupBy := r.GroupBy
+	if groupBy == "" {
+		groupBy = "group"
+	}
+	rows := [][]string{{"period", groupBy, "alerts", "acknowledged", "resolved", "mtta_seconds", "mttr_seconds"}}
+	for _, g := range r.Groups {
+		rows = append(rows, []string{
+			g.Period.Format(periodLayout), g.Group,
+			strconv.FormatInt(g.Alerts, 10),
+			strconv.FormatInt(g.Acknowledged, 10),
+			strconv.FormatInt(g.Resolved, 10),
+			strconv.FormatFloat(g.MTTA, 'f', 0, 64),
+			strconv.FormatFloat(g.MTTR, 'f', 0, 64),
+		})
+	}
+	rows = append(rows, nil, []string{"period", "alertname", groupBy, "count"})
+	for _, n := range r.NoisyAlerts {
+		rows = append(rows, []string{n.Period.Format(periodLayout), n.Name, n.Group, strconv.FormatInt(n.Count, 10)})
+	}
+	rows = append(rows, nil, []string{"period", "user_id", "username", "pages"})
+	for _, p := range r.Pages {
+		rows = append(rows, []string{p.Period.Format(periodLayout), p.UserID, p.Username, strconv.FormatInt(p.Pages, 10)})
+	}
+	for _, row := range rows {
+		if row == nil {
+			// csv.Writer quotes a single empty field, the separator is written raw
+			cw.Flush()
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteHTML renders the report as a standalone page laid out for printing
+// to PDF from the browser.
+func WriteHTML(w io.Writer, r *Report) error {
+	tmpl, err := template.New("report").Funcs(template.FuncMap{
+		"period":   func(t time.Time) string { return t.Format(periodLayout) },
+		"date":     func(t time.Time) string { return t.Format(time.RFC1123) },
+		"duration": formatSeconds,
+	}).Parse(reportTemplate)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, r)
+}
+
+func formatSeconds(s float64) string {
+	if s <= 0 {
+		return "-"
+	}
+	d := time.Duration(s) * time.Second
+	if d < time.Hour {
+		return fmt.Sprintf("%dm %ds", int(d.Minutes()), int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+var reportTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8" />
+    <title>Iris incident report</title>
+    <style>
+        body { font-family: Arial, sans-serif; color: #2c3e50; margin: 2rem; }
+        h1 { margin-bottom: 0.25rem; }
+        h2 { margin-top: 2rem; border-bottom: 2px solid #3498db; padding-bottom: 0.25rem; }
+        .meta { color: #7f8c8d; }
+        table { width: 100%; border-collapse: collapse; font-size: 0.9rem; }
+        th, td { text-align: left; padding: 0.4rem 0.6rem; border-bottom: 1px solid #ecf0f1; }
+        th { background: #f8f9fa; }
+        td.num { text-align: right; }
+        .empty { color: #7f8c8d; font-style: italic; }
+        @page { size: A4; margin: 1.5cm; }
+        @media print {
+            body { margin: 0; }
+            section { page-break-inside: avoid; }
+            tr { page-break-inside: avoid; }
+        }
+    </style>
+</head>
+<body>
+    <h1>Incident report</h1>
+    <p class="meta">{{date .From}} to {{date .To}}, per {{.Period}}{{if .GroupBy}}, grouped by {{.GroupBy}}{{end}}</p>
+
+    <section>
+        <h2>Response times</h2>
+        {{if .Groups}}
+        <table>
+            <thead><tr><th>Period</th><th>{{if .GroupBy}}{{.GroupBy}}{{else}}Group{{end}}</th><th>Alerts</th><th>Acknowledged</th><th>Resolved</th><th>MTTA</th><th>MTTR</th></tr></thead>
+            <tbody>
+            {{range .Groups}}
+                <tr><td>{{period .Period}}</td><td>{{.Group}}</td><td class="num">{{.Alerts}}</td><td class="num">{{.Acknowledged}}</td><td class="num">{{.Resolved}}</td><td class="num">{{duration .MTTA}}</td><td class="num">{{duration .MTTR}}</td></tr>
+            {{end}}
+            </tbody>
+        </table>
+        {{else}}<p class="empty">No alerts in this range</p>{{end}}
+    </section>
+
+    <section>
+        <h2>Noisiest alerts</h2>
+        {{if .NoisyAlerts}}
+        <table>
+            <thead><tr><th>Period</th><th>Alert</th><th>{{if .GroupBy}}{{.GroupBy}}{{else}}Group{{end}}</th><th>Count</th></tr></thead>
+            <tbody>
+            {{range .NoisyAlerts}}
+                <tr><td>{{period .Period}}</td><td>{{.Name}}</td><td>{{.Group}}</td><td class="num">{{.Count}}</td></tr>
+            {{end}}
+            </tbody>
+        </table>
+        {{else}}<p class="empty">No alerts in this range</p>{{end}}
+    </section>
+
+    <section>
+        <h2>Pages per on-call person</h2>
+        {{if .Pages}}
+        <table>
+            <thead><tr><th>Period</th><th>User</th><th>Pages</th></tr></thead>
+            <tbody>
+            {{range .Pages}}
+                <tr><td>{{period .Period}}</td><td>{{if .Username}}{{.Username}}{{else}}{{.UserID}}{{end}}</td><td class="num">{{.Pages}}</td></tr>
+            {{end}}
+            </tbody>
+        </table>
+        {{else}}<p class="empty">Nobody was paged in this range</p>{{end}}
+    </section>
+</body>
+</html>
+`
@@ -0,0 +1,87 @@
+package reports
+
+import (
+	"fmt"
+	"regexp"
+
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTop = 10
+	maxTop     = 100
+)
+
+var labelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+func NewService(repo Repository, logger *zap.SugaredLogger) *Service {
+	return &Service{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Report computes the response times, noisy alerts and pages per user of
+// the alerts that started in the queried range.
+func (s *Service) Report(q Query) (*Report, error) {
+	if err := normalize(&q); err != nil {
+		return nil, err
+	}
+	groups, err := s.repo.AlertResponseStats(q)
+	if err != nil {
+		return nil, err
+	}
+	noisy, err := s.repo.NoisyAlerts(q)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := s.repo.PagesPerUser(q)
+	if err != nil {
+		return nil, err
+	}
+	r := &Report{
+		From:        q.From,
+		To:          q.To,
+		Period:      q.Period,
+		GroupBy:     q.GroupBy,
+		Groups:      groups,
+		NoisyAlerts: noisy,
+		Pages:       pages,
+	}
+	if r.Groups == nil {
+		r.Groups = []GroupStats{}
+	}
+	if r.NoisyAlerts == nil {
+		r.NoisyAlerts = []NoisyAlert{}
+	}
+	if r.Pages == nil {
+		r.Pages = []Pages{}
+	}
+	return r, nil
+}
+
+func normalize(q *Query) error {
+	if q.From.IsZero() || q.To.IsZero() || !q.From.Before(q.To) {
+		return fmt.Errorf("%w: from must be before to", iris_error.ErrInvalidReportQuery)
+	}
+	switch q.Period {
+	case "":
+		q.Period = PeriodMonth
+	case PeriodDay, PeriodWeek, PeriodMonth:
+	default:
+		return fmt.Errorf("%w: period must be day, week or month", iris_error.ErrInvalidReportQuery)
+	}
+	// The label is interpolated into the query as a jsonb key parameter,
+	// but keeping it to label syntax keeps the reports readable.
+	if q.GroupBy != "" && !labelName.MatchString(q.GroupBy) {
+		return fmt.Errorf("%w: group_by must be a label name", iris_error.ErrInvalidReportQuery)
+	}
+	if q.Top <= 0 {
+		q.Top = defaultTop
+	}
+	if q.Top > maxTop {
+		q.Top = maxTop
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package reports
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"go.uber.org/zap"
+)
+
+type fakeRepo struct {
+	query  Query
+	groups []GroupStats
+}
+
+func (r *fakeRepo) AlertResponseStats(q Query) ([]GroupStats, error) {
+	r.query = q
+	return r.groups, nil
+}
+
+func (r *fakeRepo) NoisyAlerts(Query) ([]NoisyAlert, error) { return nil, nil }
+
+func (r *fakeRepo) PagesPerUser(Query) ([]Pages, error) { return nil, nil }
+
+func TestReport_Validation(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 2, 0)
+	cases := map[string]Query{
+		"reversed range": {From: to, To: from},
+		"bad period":     {From: from, To: to, Period: "year"},
+		"bad label":      {From: from, To: to, GroupBy: "team'); --"},
+	}
+	s := NewService(&fakeRepo{}, zap.NewNop().Sugar())
+	for name, q := range cases {
+		if _, err := s.Report(q); !errors.Is(err, iris_error.ErrInvalidReportQuery) {
+			t.Errorf("%s: got %v", name, err)
+		}
+	}
+}
+
+func TestReport_Defaults(t *testing.T) {
+	repo := &fakeRepo{}
+	s := NewService(repo, zap.NewNop().Sugar())
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	r, err := s.Report(Query{From: from, To: from.AddDate(0, 1, 0), GroupBy: "team", Top: 1000})
+	if err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	if repo.query.Period != PeriodMonth || repo.query.Top != maxTop {
+		t.Fatalf("query not normalized: %+v", repo.query)
+	}
+	if r.Groups == nil || r.NoisyAlerts == nil || r.Pages == nil {
+		t.Fatalf("empty sections must not be nil: %+v", r)
+	}
+}
+
+func TestExport(t *testing.T) {
+	month := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	r := &Report{
+		From:    month,
+		To:      month.AddDate(0, 1, 0),
+		Period:  PeriodMonth,
+		GroupBy: "team",
+		Groups: []GroupStats{
+			{Period: month, Group: "db", Alerts: 4, Acknowledged: 3, Resolved: 4, MTTA: 90, MTTR: 5400},
+		},
+		NoisyAlerts: []NoisyAlert{{Period: month, Name: "DiskFull", Group: "db", Count: 3}},
+		Pages:       []Pages{{Period: month, UserID: "u1", Username: "alice", Pages: 7}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, r); err != nil {
+		t.Fatalf("csv: %v", err)
+	}
+	want := "period,team,alerts,acknowledged,resolved,mtta_seconds,mttr_seconds\n" +
+		"2026-03-01,db,4,3,4,90,5400\n" +
+		"\n" +
+		"period,alertname,team,count\n" +
+		"2026-03-01,DiskFull,db,3\n" +
+		"\n" +
+		"period,user_id,username,pages\n" +
+		"2026-03-01,u1,alice,7\n"
+	if buf.String() != want {
+		t.Fatalf("csv mismatch:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteHTML(&buf, r); err != nil {
+		t.Fatalf("html: %v", err)
+	}
+	for _, s := range []string{"DiskFull", "alice", "1m 30s", "1h 30m", "@media print"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("html is missing %q", s)
+		}
+	}
+}
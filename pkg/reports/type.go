@@ -0,0 +1,78 @@
+package reports
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Period string
+
+const (
+	PeriodDay   Period = "day"
+	PeriodWeek  Period = "week"
+	PeriodMonth Period = "month"
+)
+
+// Query selects the alerts that started in [From, To), bucketed by Period
+// and grouped by the value of the GroupBy label. alertname and severity
+// group by the alert's own columns.
+type Query struct {
+	From    time.Time
+	To      time.Time
+	Period  Period
+	GroupBy string
+	// Top is the number of noisy alerts listed per period.
+	Top int
+}
+
+// GroupStats holds the response times of a group in a period, in seconds.
+type GroupStats struct {
+	Period       time.Time `json:"period"`
+	Group        string    `json:"group"`
+	Alerts       int64     `json:"alerts"`
+	Acknowledged int64     `json:"acknowledged"`
+	Resolved     int64     `json:"resolved"`
+	MTTA         float64   `json:"mtta_seconds"`
+	MTTR         float64   `json:"mttr_seconds"`
+}
+
+type NoisyAlert struct {
+	Period time.Time `json:"period"`
+	Name   string    `json:"name"`
+	Group  string    `json:"group"`
+	Count  int64     `json:"count"`
+}
+
+// Pages counts the alert notifications sent to a user in a period.
+type Pages struct {
+	Period   time.Time `json:"period"`
+	UserID   string    `json:"user_id"`
+	Username string    `json:"username"`
+	Pages    int64     `json:"pages"`
+}
+
+type Report struct {
+	From        time.Time    `json:"from"`
+	To          time.Time    `json:"to"`
+	Period      Period       `json:"period"`
+	GroupBy     string       `json:"group_by"`
+	Groups      []GroupStats `json:"groups"`
+	NoisyAlerts []NoisyAlert `json:"noisy_alerts"`
+	Pages       []Pages      `json:"pages"`
+}
+
+type Repository interface {
+	AlertResponseStats(q Query) ([]GroupStats, error)
+	NoisyAlerts(q Query) ([]NoisyAlert, error)
+	PagesPerUser(q Query) ([]Pages, error)
+}
+
+type ServiceInterface interface {
+	Report(q Query) (*Report, error)
+}
+
+type Service struct {
+	repo   Repository
+	logger *zap.SugaredLogger
+}
@@ -0,0 +1,150 @@
+package alert
+
+import (
+	"errors"
+	"time"
+
+	"github.com/root-ali/iris/pkg/message"
+	"github.com/root-ali/iris/pkg/notifications"
+	"github.com/root-ali/iris/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// newOutboxMessage builds the pending message of a recipient for a notify
+// round of the alert.
+func newOutboxMessage(msg notifications.Message, round int, provider string, rc recipient) *message.Message {
+	m := message.NewMessage("",
+		msg.State+":"+msg.Subject+":"+msg.Message,
+		rc.address,
+		provider,
+		rc.userID,
+		rc.group,
+		"",
+		[]string{provider},
+		message.TypeMessageStatusPending)
+	m.AlertID = msg.AlertID
+	m.Subject = msg.Subject
+	m.State = msg.State
+	m.Body = msg.Message
+	m.IdempotencyKey = message.IdempotencyKey(msg.AlertID, msg.State, provider, rc.address, round)
+	return m
+}
+
+func (s *Scheduler) wakeDispatchers() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// dispatcher sends pending outbox messages until the scheduler stops.
+func (s *Scheduler) dispatcher(id int) {
+	defer s.wgLoop.Done()
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		for s.ctx.Err() == nil {
+			found, err := s.outbox.DispatchPendingMessage(s.send)
+			if err != nil {
+				s.logger.Errorw("Failed to dispatch outbox message", "dispatcher", id, "error", err)
+				break
+			}
+			if !found {
+				break
+			}
+		}
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-s.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// send delivers a claimed outbox message and records the result on it.
+// Failed sends are put back in the outbox after the provider's backoff.
+func (s *Scheduler) send(m *message.Message) {
+	m.Attempt++
+	m.LastAttempt = time.Now()
+	p, err := s.providerByName(m.Sender)
+	if err != nil {
+		s.logger.Errorw("Cannot send outbox message", "message", m.Id, "provider", m.Sender, "error", err)
+		s.retryOrFail(m, notifications.DefaultRetryPolicy, err)
+		return
+	}
+
+	span := tracing.StartProviderCall(m.TraceParent, "send", m.Sender,
+		attribute.String("message.id", m.Id),
+		attribute.Int("message.attempt", m.Attempt))
+	ids, err := p.Send(notifications.Message{
+		AlertID:        m.AlertID,
+		Subject:        m.Subject,
+		Message:        m.Body,
+		State:          m.State,
+		Time:           m.CreatedAt.Format(time.DateTime),
+		Receptors:      []string{m.Receptor},
+		IdempotencyKey: m.IdempotencyKey,
+	})
+	if err != nil {
+		tracing.End(span, err)
+		s.retryOrFail(m, notifications.RetryPolicyOf(p), err)
+		return
+	}
+	tracing.End(span, nil)
+
+	// From here on Attempt counts status checks
+	m.Attempt = 0
+	m.NextAttemptAt = nil
+	m.SenderId = messageID(ids, 0)
+	notifications.ObserveSent(m.Sender, m.AlertTime())
+	if p.GetFlag() == "telegram" {
+		m.Status = message.StatusMap[message.TypeMessageStatusDelivered]
+		m.Response = "Delivered"
+		notifications.ObserveDelivered(m.Sender, m.AlertTime())
+	} else {
+		m.Status = message.StatusMap[message.TypeMessageStatusSent]
+		m.Response = "Sent"
+	}
+	s.logger.Infow("Outbox message sent", "message", m.Id, "alertID", m.AlertID, "provider", m.Sender)
+}
+
+// retryOrFail schedules the next attempt of a failed message, or fails it
+// when the error is permanent or it ran out of attempts.
+func (s *Scheduler) retryOrFail(m *message.Message, policy notifications.RetryPolicy, err error) {
+	m.Response = err.Error()
+	if notifications.IsPermanent(err) || m.Attempt >= policy.MaxAttempts {
+		s.logger.Errorw("Failed to send notification",
+			"message", m.Id,
+			"provider", m.Sender,
+			"attempt", m.Attempt,
+			"permanent", notifications.IsPermanent(err),
+			"error", err)
+		m.Status = message.StatusMap[message.TypeMessageStatusFailed]
+		m.NextAttemptAt = nil
+		notifications.ObserveFailed(m.Sender)
+		return
+	}
+	next := m.LastAttempt.Add(policy.Backoff(m.Attempt))
+	s.logger.Warnw("Failed to send notification, retrying",
+		"message", m.Id,
+		"provider", m.Sender,
+		"attempt", m.Attempt,
+		"nextAttemptAt", next,
+		"error", err)
+	m.Status = message.StatusMap[message.TypeMessageStatusPending]
+	m.NextAttemptAt = &next
+}
+
+func (s *Scheduler) providerByName(name string) (notifications.NotificationInterface, error) {
+	providers, err := s.provider.GetActiveProviders()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range providers {
+		if p.Provider != nil && p.Provider.GetName() == name {
+			return p.Provider, nil
+		}
+	}
+	return nil, errors.New("provider " + name + " is not active")
+}
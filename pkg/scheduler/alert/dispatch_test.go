@@ -0,0 +1,163 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/root-ali/iris/pkg/alerts"
+	"github.com/root-ali/iris/pkg/message"
+	"github.com/root-ali/iris/pkg/notifications"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeProvider struct {
+	name string
+	flag string
+	err  error
+	sent []notifications.Message
+}
+
+func (f *fakeProvider) Send(m notifications.Message) ([]string, error) {
+	f.sent = append(f.sent, m)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []string{"provider-id"}, nil
+}
+func (f *fakeProvider) Status(string) (notifications.MessageStatusType, error) { return 0, nil }
+func (f *fakeProvider) Verify() (string, error)                                { return "", nil }
+func (f *fakeProvider) GetName() string                                        { return f.name }
+func (f *fakeProvider) GetFlag() string                                        { return f.flag }
+func (f *fakeProvider) GetPriority() int                                       { return 1 }
+
+type fakeProviders struct {
+	providers []notifications.Providers
+}
+
+func (f *fakeProviders) GetActiveProviders() ([]notifications.Providers, error) {
+	return f.providers, nil
+}
+func (f *fakeProviders) GetProvidersPriority() ([]notifications.Providers, error) {
+	return f.providers, nil
+}
+
+func TestNewOutboxMessage(t *testing.T) {
+	msg := notifications.Message{AlertID: "a1", Subject: "DiskFull", Message: "disk is full", State: "firing"}
+	m1 := newOutboxMessage(msg, 0, "Kavenegar", recipient{userID: "u1", address: "0912", group: "oncall"})
+	m2 := newOutboxMessage(msg, 0, "Kavenegar", recipient{userID: "u1", address: "0912", group: "oncall"})
+
+	assert.Equal(t, "Pending", m1.Status)
+	assert.Equal(t, "oncall", m1.GroupName, "messages are filtered by the group they were sent through")
+	assert.Empty(t, m1.Response)
+	assert.Equal(t, m1.IdempotencyKey, m2.IdempotencyKey)
+	assert.NotEqual(t, m1.Id, m2.Id)
+
+	msg.State = "resolved"
+	m3 := newOutboxMessage(msg, 0, "Kavenegar", recipient{userID: "u1", address: "0912"})
+	assert.NotEqual(t, m1.IdempotencyKey, m3.IdempotencyKey)
+}
+
+// keyedOutbox drops messages with a known idempotency key like the unique
+// index of the message table.
+type keyedOutbox struct {
+	keys   map[string]bool
+	queued []*message.Message
+}
+
+func (o *keyedOutbox) EnqueueAlertMessages(_ context.Context, _ string, msgs []*message.Message) (int64, error) {
+	var n int64
+	for _, m := range msgs {
+		if o.keys[m.IdempotencyKey] {
+			continue
+		}
+		o.keys[m.IdempotencyKey] = true
+		o.queued = append(o.queued, m)
+		n++
+	}
+	return n, nil
+}
+
+func (o *keyedOutbox) DispatchPendingMessage(func(*message.Message)) (bool, error) {
+	return false, nil
+}
+
+type fixedReceptors map[string][]string
+
+func (f fixedReceptors) GetNumbers(string) (map[string][]string, error) { return f, nil }
+func (f fixedReceptors) Get(string, string) (map[string][]string, bool) { return f, true }
+
+func TestHandleAlertQueuesEachNotifyRound(t *testing.T) {
+	sms := &fakeProvider{name: "Kavenegar", flag: "sms"}
+	outbox := &keyedOutbox{keys: map[string]bool{}}
+	s := &Scheduler{
+		provider: &fakeProviders{providers: []notifications.Providers{
+			{Name: "Kavenegar", Flag: "sms", Status: true, Provider: sms},
+		}},
+		receptorRepo: fixedReceptors{"u1": {"0912"}},
+		outbox:       outbox,
+		logger:       zap.NewNop().Sugar(),
+	}
+	al := alerts.Alert{Id: "a1", Name: "DiskFull", Status: "firing",
+		Method: []string{"sms"}, Receptor: []string{"oncall"}}
+
+	require.NoError(t, s.handleAlert(al))
+	require.Len(t, outbox.queued, 1)
+	assert.Equal(t, "oncall", outbox.queued[0].GroupName)
+
+	// the alert is queued again, e.g. by another replica, in the same round
+	require.NoError(t, s.handleAlert(al))
+	assert.Len(t, outbox.queued, 1)
+
+	// renotify starts a new round
+	al.NotifyRound++
+	require.NoError(t, s.handleAlert(al))
+	require.Len(t, outbox.queued, 2)
+	assert.NotEqual(t, outbox.queued[0].IdempotencyKey, outbox.queued[1].IdempotencyKey)
+}
+
+func TestSend(t *testing.T) {
+	sms := &fakeProvider{name: "Kavenegar", flag: "sms"}
+	broken := &fakeProvider{name: "Smsir", flag: "sms", err: errors.New("timeout")}
+	s := &Scheduler{
+		provider: &fakeProviders{providers: []notifications.Providers{
+			{Name: "Kavenegar", Provider: sms},
+			{Name: "Smsir", Provider: broken},
+		}},
+		logger: zap.NewNop().Sugar(),
+	}
+	msg := notifications.Message{AlertID: "a1", Subject: "DiskFull", Message: "disk is full", State: "firing"}
+
+	m := newOutboxMessage(msg, 0, "Kavenegar", recipient{userID: "u1", address: "0912"})
+	s.send(m)
+	assert.Equal(t, message.StatusMap[message.TypeMessageStatusSent], m.Status)
+	assert.Equal(t, "provider-id", m.SenderId)
+	assert.Equal(t, m.IdempotencyKey, sms.sent[0].IdempotencyKey)
+	assert.Equal(t, []string{"0912"}, sms.sent[0].Receptors)
+
+	// Transient errors go back to the outbox with a backoff
+	m = newOutboxMessage(msg, 0, "Smsir", recipient{userID: "u1", address: "0912"})
+	s.send(m)
+	assert.Equal(t, message.StatusMap[message.TypeMessageStatusPending], m.Status)
+	assert.Equal(t, "timeout", m.Response)
+	assert.Equal(t, 1, m.Attempt)
+	assert.True(t, m.NextAttemptAt.After(m.LastAttempt))
+
+	m.Attempt = notifications.DefaultRetryPolicy.MaxAttempts - 1
+	s.send(m)
+	assert.Equal(t, message.StatusMap[message.TypeMessageStatusFailed], m.Status)
+	assert.Nil(t, m.NextAttemptAt)
+
+	// Permanent errors are not retried
+	broken.err = notifications.Permanent(errors.New("invalid receptor"))
+	m = newOutboxMessage(msg, 0, "Smsir", recipient{userID: "u1", address: "0912"})
+	s.send(m)
+	assert.Equal(t, message.StatusMap[message.TypeMessageStatusFailed], m.Status)
+	assert.Equal(t, 1, m.Attempt)
+
+	m = newOutboxMessage(msg, 0, "Asiatech", recipient{userID: "u1", address: "0912"})
+	s.send(m)
+	assert.Equal(t, message.StatusMap[message.TypeMessageStatusPending], m.Status)
+}
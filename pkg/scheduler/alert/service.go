@@ -1,14 +1,21 @@
 package alert
 
 import (
+	"context"
 	"errors"
 	"slices"
-	"strings"
 	"time"
 
 	"github.com/root-ali/iris/pkg/alerts"
+	"github.com/root-ali/iris/pkg/cluster"
 	"github.com/root-ali/iris/pkg/message"
 	"github.com/root-ali/iris/pkg/notifications"
+	"github.com/root-ali/iris/pkg/scheduler"
+	"github.com/root-ali/iris/pkg/templates"
+	"github.com/root-ali/iris/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func (s *Scheduler) Start() error {
@@ -29,6 +36,12 @@ func (s *Scheduler) Start() error {
 	s.wgLoop.Add(1)
 	go s.loop()
 
+	// start outbox dispatchers
+	for i := 0; i < s.cfg.Workers; i++ {
+		s.wgLoop.Add(1)
+		go s.dispatcher(i)
+	}
+
 	return nil
 }
 
@@ -58,6 +71,12 @@ func (s *Scheduler) loop() {
 }
 
 func (s *Scheduler) fetchAndEnqueue() {
+	// Only one replica reads unsent alerts, dispatchers run everywhere since
+	// they claim outbox rows one by one.
+	if s.leader != nil && !s.leader.IsLeader(cluster.LockAlertScheduler) {
+		s.logger.Debug("Not the alert scheduler leader, skipping")
+		return
+	}
 	s.logger.Debug("Fetching unsent alerts...")
 	unsent, err := s.repo.GetUnsentAlerts()
 	if err != nil {
@@ -67,6 +86,7 @@ func (s *Scheduler) fetchAndEnqueue() {
 	for _, al := range unsent {
 		select {
 		case s.queue <- al:
+			scheduler.QueueDepth.WithLabelValues("alert").Set(float64(len(s.queue)))
 		case <-s.ctx.Done():
 			return
 		}
@@ -76,6 +96,7 @@ func (s *Scheduler) fetchAndEnqueue() {
 func (s *Scheduler) worker(id int) {
 	defer s.wgWorkers.Done()
 	for al := range s.queue {
+		scheduler.QueueDepth.WithLabelValues("alert").Set(float64(len(s.queue)))
 		if err := s.handleAlert(al); err != nil {
 			s.logger.Errorw("Failed processing alert", "worker", id, "alert", al, "error", err)
 		}
@@ -83,6 +104,16 @@ func (s *Scheduler) worker(id int) {
 }
 
 func (s *Scheduler) handleAlert(al alerts.Alert) error {
+	// Continue the trace of the request that received the alert. Workers
+	// drain the queue after Stop, so the context is not the scheduler's.
+	ctx, span := tracing.Tracer().Start(tracing.Extract(context.Background(), al.TraceParent), "alert.schedule",
+		trace.WithAttributes(
+			attribute.String("alert.id", al.Id),
+			attribute.String("alert.name", al.Name),
+			attribute.String("alert.status", al.Status),
+		))
+	defer span.End()
+
 	s.logger.Infow("Processing alert",
 		"alertID", al.Id, "name", al.Name, "methods", al.Method, "receptors", al.Receptor)
 
@@ -96,22 +127,106 @@ func (s *Scheduler) handleAlert(al alerts.Alert) error {
 		return nil
 	}
 
-	// Prepare receptors list and mapping for receptor users
-	var receptors []string
+	// Alert was silenced from chat or the API
+	if al.SilencedUntil != nil && time.Now().Before(*al.SilencedUntil) {
+		s.logger.Infow("Alert is silenced, skipping notification",
+			"alertID", al.Id, "until", al.SilencedUntil)
+		return s.repo.MarkAlertAsSent(al.Id)
+	}
+
+	// The firing notification was held back by a maintenance window, nobody
+	// is told the alert resolved
+	if al.Status == "resolved" && al.Silenced {
+		s.logger.Infow("Alert resolved under maintenance, skipping notification", "alertID", al.Id)
+		return s.repo.MarkAlertAsSent(al.Id)
+	}
+
+	// Drop receptors that are under an active maintenance window. Alerts
+	// suppressed by a window stay unsent and are checked again on every pass
+	// until it ends.
+	if s.maintenance != nil {
+		remaining, windows, err := s.maintenance.FilterReceptors(al.LabelSet(), al.Receptor, time.Now())
+		if err != nil {
+			s.logger.Errorw("Failed to check maintenance windows", "alertID", al.Id, "error", err)
+		} else if len(windows) > 0 {
+			if len(remaining) == 0 {
+				if al.Silenced {
+					return nil
+				}
+				s.logger.Infow("Alert suppressed by maintenance window",
+					"alertID", al.Id, "windows", windows)
+				return s.repo.MarkAlertAsSilenced(al.Id)
+			}
+			s.logger.Infow("Some receptors are under maintenance",
+				"alertID", al.Id, "windows", windows, "remaining", remaining)
+			al.Receptor = remaining
+		}
+	}
 
+	// Users already notified through a higher priority provider
 	userMessage := make(map[string]bool)
 
 	// Prepare Message
 	msg := notifications.Message{
+		AlertID: al.Id,
 		Subject: al.Name,
 		Message: al.Description,
 		State:   al.Status,
-		Time:    time.Now().Format(time.DateTime),
+	}
+	if s.templates != nil {
+		if subject, body, ok := s.templates.Render(templates.Data{
+			Name:        al.Name,
+			Description: al.Description,
+			Severity:    al.Severity,
+			Status:      al.Status,
+			Fingerprint: al.FingerPrint,
+			Labels:      al.LabelSet(),
+			StartsAt:    al.StartsAt,
+			EndsAt:      al.EndsAt,
+		}); ok {
+			msg.Subject = subject
+			msg.Message = body
+		}
+	}
+
+	// Alerts linked to an incident are notified through incident-level
+	// updates, the others of its alerts are collapsed
+	if s.incidents != nil {
+		update, err := s.incidents.Collapse(al)
+		if err != nil {
+			s.logger.Errorw("Failed to collapse alert into incident", "alertID", al.Id, "error", err)
+		} else if update != nil {
+			span.SetAttributes(attribute.String("incident.id", update.Incident.ID))
+			if !update.Notify {
+				s.logger.Infow("Alert notification collapsed into incident",
+					"alertID", al.Id, "incidentID", update.Incident.ID)
+				return s.repo.MarkAlertAsSent(al.Id)
+			}
+			msg.Subject = update.Subject
+			msg.Message = update.Message
+			msg.State = update.State
+		}
 	}
 
-	saveTextMsg := msg.State + ":" + msg.Subject + ":" + msg.Message
+	// Latency of the notifications is measured from the state change
+	alertAt := al.StartsAt
+	if al.Status == "resolved" && !al.EndsAt.IsZero() {
+		alertAt = al.EndsAt
+	}
 
-	provider, err := s.getProvider(al.Method, 0)
+	// Users may ask for channels the alert did not list in its method label,
+	// internal alerts keep to theirs so low SMS credit is not sent by SMS
+	now := time.Now()
+	methods := slices.Clone(al.Method)
+	if s.contactRules != nil && !al.Internal() {
+		for _, m := range s.contactRules.Methods(al.Severity) {
+			if !slices.Contains(methods, m) {
+				methods = append(methods, m)
+			}
+		}
+	}
+
+	provider, err := s.getProvider(methods, 0)
 	if err != nil {
 		s.logger.Errorw("Failed to get provider", "error", err)
 		err = s.repo.MarkAlertAsSent(al.Id)
@@ -121,8 +236,10 @@ func (s *Scheduler) handleAlert(al alerts.Alert) error {
 		return err
 	}
 
+	outbox := make([]*message.Message, 0)
 	for _, p := range provider {
-		receptorIds := make(map[string]string)
+		var receptors []string
+		var recipients []recipient
 		s.logger.Infow("Using provider for alert",
 			"alertID", al.Id,
 			"provider", p.GetName())
@@ -142,23 +259,32 @@ func (s *Scheduler) handleAlert(al alerts.Alert) error {
 				}
 				return errors.New("failed to get receptors from group: " + r)
 			}
-			for k, v := range cacheReceptors {
-				if ok := userMessage[k]; ok {
-					s.logger.Debugw("User already has message prepared, skipping receptor",
-						"method", al.Method,
+			for k, addresses := range cacheReceptors {
+				allowed, explicit := s.allowedChannel(k, al, p.GetFlag(), now)
+				if !allowed {
+					s.logger.Debugw("User does not want alerts on this channel, skipping",
+						"method", p.GetFlag(),
 						"user", k,
-						"receptor", v)
+						"severity", al.Severity)
 					continue
 				}
-				if slices.Contains(receptors, v) {
-					s.logger.Debugw("Receptor already added, skipping",
+				if ok := userMessage[k]; ok && !explicit {
+					s.logger.Debugw("User already has message prepared, skipping receptor",
 						"method", al.Method,
-						"receptor", v)
+						"user", k,
+						"receptor", addresses)
 					continue
 				}
-				receptorIds[k] = v
-				userMessage[k] = false
-				receptors = append(receptors, v)
+				for _, v := range addresses {
+					if slices.Contains(receptors, v) {
+						s.logger.Debugw("Receptor already added, skipping",
+							"method", al.Method,
+							"receptor", v)
+						continue
+					}
+					recipients = append(recipients, recipient{userID: k, address: v, group: r})
+					receptors = append(receptors, v)
+				}
 			}
 			s.logger.Debugw("Prepared receptors for alert",
 				"method", al.Method,
@@ -170,100 +296,49 @@ func (s *Scheduler) handleAlert(al alerts.Alert) error {
 				"method", al.Method)
 			continue
 		}
-		msg.Receptors = receptors
-
-		msgIds, err := p.Send(msg)
-		if p.GetName() == "Telegram" {
-			s.logger.Infow("Telegram notification details", "receptors", receptors,
-				"errors", err.Error())
-			telegramErrors := strings.Split(err.Error(), ";")
-			i := 0
-			for userId, receptor := range receptorIds {
-				if telegramErrors[i] != "nil" {
-					// Save Message
-					failedMsg := message.NewMessage("",
-						saveTextMsg,
-						receptor, p.GetName(),
-						userId,
-						"",
-						telegramErrors[i],
-						[]string{p.GetName()},
-						message.TypeMessageStatusSent)
-					err := s.messageRepo.Add(failedMsg)
-					if err != nil {
-						s.logger.Errorw("Failed to save failed message", "receptor", receptor, "error", err)
-						continue
-					}
-				} else {
-					// Save Message
-					sentMsg := message.NewMessage(msgIds[i],
-						saveTextMsg,
-						receptor,
-						p.GetName(),
-						userId,
-						"",
-						"Delivered",
-						[]string{p.GetName()},
-						message.TypeMessageStatusDelivered)
-					err := s.messageRepo.Add(sentMsg)
-					if err != nil {
-						s.logger.Errorw("Failed to save sent message", "receptor", receptor, "error", err)
-						continue
-					}
-				}
-				i++
-			}
-
-			continue
-		} else if err != nil {
-			s.logger.Errorw("Failed to send notification", "error", err)
-			for r, v := range receptorIds {
-
-				// Save Message
-				failedMsg := message.NewMessage("",
-					saveTextMsg,
-					v, p.GetName(),
-					r,
-					r,
-					err.Error(),
-					[]string{p.GetName()},
-					message.TypeMessageStatusFailed)
-				err := s.messageRepo.Add(failedMsg)
-				if err != nil {
-					s.logger.Errorw("Failed to save failed message", "receptor", v, "error", err)
-					return err
-				}
-
-			}
-			continue
+		for _, rc := range recipients {
+			userMessage[rc.userID] = true
+			m := newOutboxMessage(msg, al.NotifyRound, p.GetName(), rc)
+			m.AlertAt = &alertAt
+			m.TraceParent = tracing.Inject(ctx)
+			outbox = append(outbox, m)
 		}
-		var i = 0
-		for r, v := range receptorIds {
+	}
 
-			// Save Message
-			userMessage[r] = true
-			sentMsg := message.NewMessage(msgIds[i],
-				saveTextMsg,
-				v,
-				p.GetName(),
-				r,
-				"",
-				"Sent",
-				[]string{p.GetName()},
-				message.TypeMessageStatusSent)
-			err := s.messageRepo.Add(sentMsg)
-			if err != nil {
-				s.logger.Errorw("Failed to save sent message", "receptor", v, "error", err)
-				continue
-			}
-			i++
-		}
+	// Queue the messages and mark the alert as sent in one transaction, the
+	// dispatchers send them afterwards
+	queued, err := s.outbox.EnqueueAlertMessages(ctx, al.Id, outbox)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	span.SetAttributes(attribute.Int64("alert.messages", queued))
+	s.logger.Infow("Alert messages queued", "alertID", al.Id, "messages", queued)
+	s.wakeDispatchers()
+	return nil
+}
 
-		continue
+// messageID returns the provider message id of the i-th receptor, providers
+// may return fewer ids than receptors.
+func messageID(ids []string, i int) string {
+	if i < len(ids) {
+		return ids[i]
 	}
+	return ""
+}
 
-	// Mark alert as sent
-	return s.repo.MarkAlertAsSent(al.Id)
+// allowedChannel reports whether userID should be notified through flag.
+// Users with a matching contact rule get exactly the channels it lists;
+// everyone else, and every receptor of internal alerts, follows the alert's
+// method label.
+func (s *Scheduler) allowedChannel(userID string, al alerts.Alert, flag string, at time.Time) (bool, bool) {
+	if s.contactRules != nil && !al.Internal() {
+		if channels, ok := s.contactRules.Channels(userID, al.Severity, at); ok {
+			return slices.Contains(channels, flag), true
+		}
+	}
+	return slices.Contains(al.Method, flag), false
 }
 
 func (s *Scheduler) getProvider(flags []string, _ int) ([]notifications.NotificationInterface, error) {
@@ -279,7 +354,9 @@ func (s *Scheduler) getProvider(flags []string, _ int) ([]notifications.Notifica
 			if slices.Contains(returnProviders, p.Flag) {
 				continue
 			}
-			if p.Status == true && p.Flag == flag {
+			// Skip providers whose circuit breaker is open, the next
+			// provider with the same flag is used instead
+			if p.Status == true && p.Flag == flag && notifications.Available(p.Provider) {
 				s.logger.Debugw("Found active provider", "name", p.Name, "flag", flag)
 				ni = append(ni, p.Provider)
 				returnProviders = append(returnProviders, p.Flag)
@@ -0,0 +1,87 @@
+package alert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/root-ali/iris/pkg/alerts"
+	"github.com/root-ali/iris/pkg/notifications"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// windowUntil suppresses every receptor until end.
+type windowUntil struct {
+	end time.Time
+}
+
+func (w *windowUntil) FilterReceptors(_ map[string]string, receptors []string, at time.Time) ([]string, []string, error) {
+	if at.Before(w.end) {
+		return []string{}, []string{"db upgrade"}, nil
+	}
+	return receptors, nil, nil
+}
+
+// alertFlags records the flags the scheduler sets on an alert.
+type alertFlags struct {
+	alerts.AlertRepository
+	sent     bool
+	silenced bool
+}
+
+func (r *alertFlags) MarkAlertAsSent(string) error {
+	r.sent = true
+	return nil
+}
+
+func (r *alertFlags) MarkAlertAsSilenced(string) error {
+	r.silenced = true
+	return nil
+}
+
+func newMaintenanceScheduler(window *windowUntil, repo *alertFlags, outbox *keyedOutbox) *Scheduler {
+	return &Scheduler{
+		provider: &fakeProviders{providers: []notifications.Providers{
+			{Name: "Kavenegar", Flag: "sms", Status: true, Provider: &fakeProvider{name: "Kavenegar", flag: "sms"}},
+		}},
+		receptorRepo: fixedReceptors{"u1": {"0912"}},
+		maintenance:  window,
+		outbox:       outbox,
+		repo:         repo,
+		logger:       zap.NewNop().Sugar(),
+	}
+}
+
+func TestHandleAlertPagesWhenWindowEndsWhileFiring(t *testing.T) {
+	window := &windowUntil{end: time.Now().Add(time.Hour)}
+	repo := &alertFlags{}
+	outbox := &keyedOutbox{keys: map[string]bool{}}
+	s := newMaintenanceScheduler(window, repo, outbox)
+	al := alerts.Alert{Id: "a1", Name: "DiskFull", Status: "firing",
+		Method: []string{"sms"}, Receptor: []string{"oncall"}}
+
+	require.NoError(t, s.handleAlert(al))
+	assert.True(t, repo.silenced)
+	assert.False(t, repo.sent, "suppressed alerts are checked again after the window")
+	assert.Empty(t, outbox.queued)
+
+	// the window ends and the alert still fires
+	window.end = time.Now()
+	al.Silenced = true
+	require.NoError(t, s.handleAlert(al))
+	assert.Len(t, outbox.queued, 1)
+}
+
+func TestHandleAlertSkipsResolveOfSuppressedAlert(t *testing.T) {
+	window := &windowUntil{end: time.Now()}
+	repo := &alertFlags{}
+	outbox := &keyedOutbox{keys: map[string]bool{}}
+	s := newMaintenanceScheduler(window, repo, outbox)
+	al := alerts.Alert{Id: "a1", Name: "DiskFull", Status: "resolved", Silenced: true,
+		Method: []string{"sms"}, Receptor: []string{"oncall"}}
+
+	require.NoError(t, s.handleAlert(al))
+	assert.True(t, repo.sent)
+	assert.Empty(t, outbox.queued, "nobody saw the alert fire")
+}
@@ -7,8 +7,11 @@ import (
 
 	"github.com/root-ali/iris/pkg/alerts"
 	"github.com/root-ali/iris/pkg/cache"
+	"github.com/root-ali/iris/pkg/cluster"
+	"github.com/root-ali/iris/pkg/incidents"
 	"github.com/root-ali/iris/pkg/message"
 	"github.com/root-ali/iris/pkg/notifications"
+	"github.com/root-ali/iris/pkg/templates"
 	"go.uber.org/zap"
 )
 
@@ -19,19 +22,56 @@ type SchedulerConfig struct {
 }
 
 type ReceptorInterface interface {
-	GetNumbers(group string) (map[string]string, error)
-	Get(model string, groupName string) (map[string]string, bool)
+	GetNumbers(group string) (map[string][]string, error)
+	Get(model string, groupName string) (map[string][]string, bool)
 }
 
-type MessageInterface interface {
-	Add(msg *message.Message) error
+// recipient is a single address of a user a message is sent to, and the
+// receptor group the user was paged through.
+type recipient struct {
+	userID  string
+	address string
+	group   string
+}
+
+// OutboxInterface stores the messages of alerts before they are sent and
+// hands them to dispatchers one at a time.
+type OutboxInterface interface {
+	EnqueueAlertMessages(ctx context.Context, alertID string, msgs []*message.Message) (int64, error)
+	DispatchPendingMessage(send func(msg *message.Message)) (bool, error)
+}
+
+type MaintenanceInterface interface {
+	FilterReceptors(labels map[string]string, receptors []string, at time.Time) ([]string, []string, error)
+}
+
+// ContactRulesInterface resolves per-user notification preferences.
+type ContactRulesInterface interface {
+	Channels(userID, severity string, at time.Time) ([]string, bool)
+	Methods(severity string) []string
+}
+
+// IncidentInterface collapses the notifications of alerts linked to an
+// incident into incident-level updates.
+type IncidentInterface interface {
+	Collapse(al alerts.Alert) (*incidents.Update, error)
+}
+
+// TemplateInterface renders the subject and body of alert notifications.
+type TemplateInterface interface {
+	Render(d templates.Data) (subject, body string, ok bool)
 }
 
 type Scheduler struct {
 	// dependencies
 	cache        cache.Interface[string, []string]
 	receptorRepo ReceptorInterface
-	messageRepo  MessageInterface
+	outbox       OutboxInterface
+	maintenance  MaintenanceInterface
+	contactRules ContactRulesInterface
+	incidents    IncidentInterface
+	templates    TemplateInterface
+	leader       cluster.LeaderInterface
 	provider     notifications.ProviderStatusInterface
 	repo         alerts.AlertRepository
 	logger       *zap.SugaredLogger
@@ -43,6 +83,7 @@ type Scheduler struct {
 	ctx       context.Context
 	cancel    context.CancelFunc
 	queue     chan alerts.Alert
+	wake      chan struct{}
 	wgWorkers sync.WaitGroup
 	wgLoop    sync.WaitGroup
 	ticker    *time.Ticker
@@ -53,7 +94,12 @@ func NewScheduler(
 	receptorRepo ReceptorInterface,
 	repo alerts.AlertRepository,
 	provider notifications.ProviderStatusInterface,
-	messageRepo MessageInterface,
+	outbox OutboxInterface,
+	maintenance MaintenanceInterface,
+	contactRules ContactRulesInterface,
+	incidents IncidentInterface,
+	templates TemplateInterface,
+	leader cluster.LeaderInterface,
 	logger *zap.SugaredLogger,
 	cfg SchedulerConfig,
 ) *Scheduler {
@@ -73,11 +119,17 @@ func NewScheduler(
 		receptorRepo: receptorRepo,
 		repo:         repo,
 		provider:     provider,
-		messageRepo:  messageRepo,
+		outbox:       outbox,
+		maintenance:  maintenance,
+		contactRules: contactRules,
+		incidents:    incidents,
+		templates:    templates,
+		leader:       leader,
 		logger:       logger,
 		cfg:          cfg,
 		ctx:          ctx,
 		cancel:       cancel,
 		queue:        make(chan alerts.Alert, cfg.QueueSize),
+		wake:         make(chan struct{}, 1),
 	}
 }
@@ -0,0 +1,168 @@
+package balance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/root-ali/iris/pkg/alerts"
+	"github.com/root-ali/iris/pkg/cluster"
+	"github.com/root-ali/iris/pkg/notifications"
+	"github.com/root-ali/iris/pkg/scheduler"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func NewService(
+	providers []notifications.NotificationInterface,
+	as AlertService,
+	repo AlertRepository,
+	leader cluster.LeaderInterface,
+	cfg Config,
+	logger *zap.SugaredLogger,
+) (scheduler.ServiceInterface, error) {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultInterval
+	}
+	if len(cfg.Methods) == 0 {
+		cfg.Methods = defaultMethods
+	}
+	for name, threshold := range cfg.Thresholds {
+		if threshold > 0 && cfg.Group == "" {
+			return nil, fmt.Errorf("balance threshold of %s needs an alert group", name)
+		}
+	}
+	return &Service{
+		providers: providers,
+		alerts:    as,
+		repo:      repo,
+		leader:    leader,
+		cfg:       cfg,
+		low:       make(map[string]bool),
+		logger:    logger,
+	}, nil
+}
+
+func (s *Service) Start() error {
+	s.logger.Infow("Starting provider balance scheduler",
+		"interval", s.cfg.Interval, "thresholds", s.cfg.Thresholds, "group", s.cfg.Group)
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.wg.Add(1)
+	go s.run()
+	return nil
+}
+
+func (s *Service) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+	return nil
+}
+
+func (s *Service) run() {
+	defer s.wg.Done()
+	s.checkAll()
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkAll()
+		}
+	}
+}
+
+func (s *Service) checkAll() {
+	// Every replica exports the gauge, only the leader raises alerts
+	leader := s.leader == nil || s.leader.IsLeader(cluster.LockBalance)
+	for _, p := range s.providers {
+		b, ok := notifications.BalanceOf(p)
+		if !ok {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(s.ctx, checkTimeout)
+		balance, err := b.Balance(ctx)
+		cancel()
+		if err != nil {
+			s.logger.Errorw("Failed to get provider balance", "provider", p.GetName(), "error", err)
+			continue
+		}
+		notifications.SetBalance(p.GetName(), balance)
+		s.logger.Debugw("Provider balance", "provider", p.GetName(), "balance", balance)
+		if leader {
+			s.check(p.GetName(), balance)
+		}
+	}
+}
+
+// check raises or resolves the low balance alert of provider when its
+// balance crossed the threshold.
+func (s *Service) check(provider string, balance float64) {
+	threshold := s.cfg.Thresholds[provider]
+	if threshold <= 0 {
+		return
+	}
+	low := balance < threshold
+	was, known := s.low[provider]
+	if known && was == low {
+		return
+	}
+	if !low && !known {
+		// Nothing to resolve unless an alert was left firing before a
+		// restart or by another leader
+		_, err := s.repo.GetAlertByFingerPrintAndStatus(fingerprint(provider), "firing")
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.low[provider] = false
+			return
+		} else if err != nil {
+			s.logger.Errorw("Failed to get low balance alert", "provider", provider, "error", err)
+			return
+		}
+	}
+
+	status := "resolved"
+	if low {
+		status = "firing"
+		s.logger.Warnw("Provider balance is below threshold",
+			"provider", provider, "balance", balance, "threshold", threshold)
+	}
+	if err := s.raise(provider, status, balance, threshold); err != nil {
+		s.logger.Errorw("Failed to save low balance alert", "provider", provider, "status", status, "error", err)
+		return
+	}
+	s.low[provider] = low
+}
+
+func (s *Service) raise(provider, status string, balance, threshold float64) error {
+	now := time.Now()
+	al, err := s.alerts.NewAlert(
+		fingerprint(provider),
+		alertName,
+		"critical",
+		fmt.Sprintf("%s balance is %.0f, below the %.0f threshold. SMS notifications stop when it runs out.",
+			provider, balance, threshold),
+		status,
+		s.cfg.Methods,
+		now,
+		now,
+		[]string{s.cfg.Group},
+	)
+	if err != nil {
+		return err
+	}
+	al.Labels = alerts.Labels{
+		alerts.LabelInternal: "true",
+		"provider":           provider,
+	}
+	_, err = s.alerts.AddAlertManagerAlerts(context.Background(), []alerts.Alert{al})
+	return err
+}
+
+func fingerprint(provider string) string {
+	return "iris-low-balance-" + strings.ToLower(provider)
+}
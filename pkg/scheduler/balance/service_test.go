@@ -0,0 +1,97 @@
+package balance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/root-ali/iris/pkg/alerts"
+	"github.com/root-ali/iris/pkg/notifications"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type fakeProvider struct {
+	notifications.NotificationInterface
+	balance float64
+}
+
+func (f *fakeProvider) GetName() string { return "Kavenegar" }
+
+func (f *fakeProvider) Balance(_ context.Context) (float64, error) {
+	return f.balance, nil
+}
+
+type fakeAlerts struct {
+	saved  []alerts.Alert
+	firing map[string]bool
+}
+
+func (f *fakeAlerts) NewAlert(fingerprint, name, severity, description, status string, method []string,
+	startsAt, endsAt time.Time, receptor []string) (alerts.Alert, error) {
+	return alerts.Alert{FingerPrint: fingerprint, Name: name, Status: status, Method: method, Receptor: receptor}, nil
+}
+
+func (f *fakeAlerts) AddAlertManagerAlerts(_ context.Context, als []alerts.Alert) (int64, error) {
+	for _, al := range als {
+		f.firing[al.FingerPrint] = al.Status == "firing"
+	}
+	f.saved = append(f.saved, als...)
+	return int64(len(als)), nil
+}
+
+func (f *fakeAlerts) GetAlertByFingerPrintAndStatus(fingerPrint, status string) (*alerts.Alert, error) {
+	if f.firing[fingerPrint] {
+		return &alerts.Alert{FingerPrint: fingerPrint, Status: "firing"}, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestCheck(t *testing.T) {
+	fa := &fakeAlerts{firing: map[string]bool{}}
+	p := &fakeProvider{balance: 500}
+	svc, err := NewService([]notifications.NotificationInterface{p}, fa, fa, nil, Config{
+		Thresholds: map[string]float64{"Kavenegar": 1000},
+		Group:      "admins",
+	}, zap.NewNop().Sugar())
+	assert.NoError(t, err)
+	s := svc.(*Service)
+	s.ctx = context.Background()
+
+	s.checkAll()
+	assert.Len(t, fa.saved, 1)
+	al := fa.saved[0]
+	assert.Equal(t, "firing", al.Status)
+	assert.Equal(t, []string{"admins"}, []string(al.Receptor))
+	assert.NotContains(t, al.Method, "sms")
+	assert.True(t, al.Internal())
+
+	// Still low, nothing new is saved
+	s.checkAll()
+	assert.Len(t, fa.saved, 1)
+
+	p.balance = 5000
+	s.checkAll()
+	assert.Len(t, fa.saved, 2)
+	assert.Equal(t, "resolved", fa.saved[1].Status)
+}
+
+func TestCheckResolvesAfterRestart(t *testing.T) {
+	fa := &fakeAlerts{firing: map[string]bool{fingerprint("Kavenegar"): true}}
+	cfg := Config{Thresholds: map[string]float64{"Kavenegar": 1000}, Group: "admins"}
+	svc, err := NewService(nil, fa, fa, nil, cfg, zap.NewNop().Sugar())
+	assert.NoError(t, err)
+	s := svc.(*Service)
+
+	s.check("Kavenegar", 5000)
+	assert.Len(t, fa.saved, 1)
+	assert.Equal(t, "resolved", fa.saved[0].Status)
+
+	// Providers without a threshold only export the gauge
+	s.check("Smsir", 0)
+	assert.Len(t, fa.saved, 1)
+
+	_, err = NewService(nil, fa, fa, nil, Config{Thresholds: cfg.Thresholds}, zap.NewNop().Sugar())
+	assert.Error(t, err)
+}
@@ -0,0 +1,62 @@
+package balance
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/root-ali/iris/pkg/alerts"
+	"github.com/root-ali/iris/pkg/cluster"
+	"github.com/root-ali/iris/pkg/notifications"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultInterval = 10 * time.Minute
+	checkTimeout    = 30 * time.Second
+	alertName       = "IrisLowProviderBalance"
+)
+
+// defaultMethods are the channels low balance alerts are sent on, SMS is
+// left out since it is the one running out.
+var defaultMethods = []string{"mail", "telegram", "mattermost"}
+
+type AlertService interface {
+	NewAlert(id, name, severity, description, status string, method []string,
+		startsAt, endsAt time.Time,
+		receptor []string) (alerts.Alert, error)
+	AddAlertManagerAlerts(ctx context.Context, alerts []alerts.Alert) (int64, error)
+}
+
+type AlertRepository interface {
+	GetAlertByFingerPrintAndStatus(fingerPrint, status string) (*alerts.Alert, error)
+}
+
+type Config struct {
+	Interval time.Duration
+	// Thresholds holds the balance under which a provider raises an alert,
+	// providers without one are only exported as a metric.
+	Thresholds map[string]float64
+	// Group receives the alerts through Methods.
+	Group   string
+	Methods []string
+}
+
+// Service polls the balance of prepaid providers and raises an internal
+// alert while one is below its threshold.
+type Service struct {
+	providers []notifications.NotificationInterface
+	alerts    AlertService
+	repo      AlertRepository
+	leader    cluster.LeaderInterface
+	cfg       Config
+
+	// low is the last known state of every provider with a threshold
+	low map[string]bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	logger *zap.SugaredLogger
+}
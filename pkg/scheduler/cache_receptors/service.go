@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
@@ -13,7 +14,7 @@ import (
 
 func NewCacheReceptorsScheduler(
 	repository Repository,
-	cacheService cache.Interface[string, map[string]string],
+	cacheService cache.Interface[string, map[string][]string],
 	logger *zap.SugaredLogger,
 	config Config,
 ) (*CacheReceptor, error) {
@@ -152,35 +153,27 @@ func (s *CacheReceptor) setOnCache() {
 	}
 	s.Logger.Infow("length group numbers", "length", len(results))
 
-	mobileCached := make(map[string]map[string]string)
-	emailCached := make(map[string]map[string]string)
-	telegramCached := make(map[string]map[string]string)
-	mattermostCached := make(map[string]map[string]string)
+	mobileCached := make(map[string]map[string][]string)
+	emailCached := make(map[string]map[string][]string)
+	telegramCached := make(map[string]map[string][]string)
+	mattermostCached := make(map[string]map[string][]string)
 	for _, group := range results {
 		if mobileCached[group.GroupName] == nil {
-			mobileCached[group.GroupName] = make(map[string]string)
+			mobileCached[group.GroupName] = make(map[string][]string)
 		}
 		if emailCached[group.GroupName] == nil {
-			emailCached[group.GroupName] = make(map[string]string)
+			emailCached[group.GroupName] = make(map[string][]string)
 		}
 		if telegramCached[group.GroupName] == nil {
-			telegramCached[group.GroupName] = make(map[string]string)
+			telegramCached[group.GroupName] = make(map[string][]string)
 		}
 		if mattermostCached[group.GroupName] == nil {
-			mattermostCached[group.GroupName] = make(map[string]string)
-		}
-		if group.Mobile != "" {
-			mobileCached[group.GroupName][group.UserId] = group.Mobile
-		}
-		if group.Email != "" {
-			emailCached[group.GroupName][group.UserId] = group.Email
-		}
-		if group.TelegramID != "" {
-			telegramCached[group.GroupName][group.UserId] = group.TelegramID
-		}
-		if group.MattermostID != "" {
-			mattermostCached[group.GroupName][group.UserId] = group.MattermostID
+			mattermostCached[group.GroupName] = make(map[string][]string)
 		}
+		addReceptor(mobileCached[group.GroupName], group.UserId, group.Mobile)
+		addReceptor(emailCached[group.GroupName], group.UserId, group.Email)
+		addReceptor(telegramCached[group.GroupName], group.UserId, group.TelegramID)
+		addReceptor(mattermostCached[group.GroupName], group.UserId, group.MattermostID)
 	}
 
 	for groupName, _ := range mobileCached {
@@ -208,11 +201,29 @@ func (s *CacheReceptor) setOnCache() {
 		}
 	}
 
+	s.loaded.Store(true)
 	s.Logger.Info("Finished Cache Receptors Job at %v", time.Now())
 
 }
 
-func (s *CacheReceptor) GetNumbers(name string) (map[string]string, error) {
+// addReceptor appends value to the addresses of userID, skipping empty
+// and duplicate values.
+func addReceptor(m map[string][]string, userID, value string) {
+	if value == "" || slices.Contains(m[userID], value) {
+		return
+	}
+	m[userID] = append(m[userID], value)
+}
+
+// Ready fails until receptors have been cached once.
+func (s *CacheReceptor) Ready() error {
+	if !s.loaded.Load() {
+		return errors.New("receptors are not cached yet")
+	}
+	return nil
+}
+
+func (s *CacheReceptor) GetNumbers(name string) (map[string][]string, error) {
 	mobiles, ok := s.Cache.Get("mobiles_" + name)
 	if !ok {
 		s.setOnCache()
@@ -225,7 +236,7 @@ func (s *CacheReceptor) GetNumbers(name string) (map[string]string, error) {
 	return mobiles, nil
 }
 
-func (s *CacheReceptor) Get(model string, groupName string) (map[string]string, bool) {
+func (s *CacheReceptor) Get(model string, groupName string) (map[string][]string, bool) {
 	query := ""
 	switch model {
 	case "sms":
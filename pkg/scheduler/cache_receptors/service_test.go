@@ -57,7 +57,7 @@ func mockLogger() *zap.SugaredLogger {
 func newMockService() (*CacheReceptor, error) {
 	repo := &mockRepo{}
 	logger := mockLogger()
-	c := cache.New[string, map[string]string](logger, cache.WithCapacity(3))
+	c := cache.New[string, map[string][]string](logger, cache.WithCapacity(3))
 	config := Config{
 		Interval:  1 * time.Minute,
 		Workers:   2,
@@ -81,7 +81,7 @@ func TestNewCacheReceptorsScheduler(t *testing.T) {
 	if err != nil {
 		t.Fatalf("expected no error getting cached numbers, got %v", err)
 	}
-	if len(cached) != 1 || cached["user2"] != "5555555555" {
+	if len(cached) != 1 || len(cached["user2"]) != 1 || cached["user2"][0] != "5555555555" {
 		t.Fatalf("expected cached numbers to contain '5555555555', got %v", cached)
 	}
 
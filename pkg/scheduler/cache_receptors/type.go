@@ -3,6 +3,7 @@ package cache_receptors
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
@@ -18,13 +19,13 @@ type Repository interface {
 }
 
 type CacheService interface {
-	GetNumbers(group string) (map[string]string, error)
-	Get(model string, groupName string) (map[string]string, bool)
+	GetNumbers(group string) (map[string][]string, error)
+	Get(model string, groupName string) (map[string][]string, bool)
 }
 
 type CacheReceptor struct {
 	Repository Repository
-	Cache      cache.Interface[string, map[string]string]
+	Cache      cache.Interface[string, map[string][]string]
 
 	conf   Config
 	ctx    context.Context
@@ -35,10 +36,13 @@ type CacheReceptor struct {
 
 	taskCh chan struct{}
 	wg     sync.WaitGroup
+	loaded atomic.Bool
 
 	Logger *zap.SugaredLogger
 }
 
+// GroupWithMobiles is one verified contact method of a group member. Only
+// the field matching the contact method type is set.
 type GroupWithMobiles struct {
 	GroupID      string `gorm:"column:group_id"`
 	GroupName    string `gorm:"column:group_name"`
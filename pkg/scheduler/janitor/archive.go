@@ -0,0 +1,65 @@
+package janitor
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// archiveKey names the archive of a batch, archives of a table are grouped
+// by day of the run: alerts/2026/10/19/alerts-20261019T030000Z-0.jsonl.gz.
+func archiveKey(table string, now time.Time, batch int) string {
+	now = now.UTC()
+	return fmt.Sprintf("%s/%s/%s-%s-%d.jsonl.gz",
+		table, now.Format("2006/01/02"), table, now.Format("20060102T150405Z"), batch)
+}
+
+// encodeRows returns the rows as gzip compressed JSON lines.
+func encodeRows(rows []Row) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	for _, r := range rows {
+		if _, err := zw.Write([]byte(r.Doc)); err != nil {
+			return nil, err
+		}
+		if _, err := zw.Write([]byte{'\n'}); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func NewLocalArchive(dir string) (*LocalArchive, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("archive directory is required")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &LocalArchive{Dir: dir}, nil
+}
+
+// Put writes the archive to a temporary file first so a partial archive
+// never has the final name.
+func (a *LocalArchive) Put(_ context.Context, key string, body []byte) error {
+	path := filepath.Join(a.Dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, body, 0o640); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package janitor
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	rowsArchived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "iris_janitor_rows_archived_total",
+		Help: "Expired rows written to the archive, by table.",
+	}, []string{"table"})
+	rowsDeleted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "iris_janitor_rows_deleted_total",
+		Help: "Expired rows deleted, by table.",
+	}, []string{"table"})
+)
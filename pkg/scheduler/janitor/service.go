@@ -0,0 +1,170 @@
+package janitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/root-ali/iris/pkg/cluster"
+	"github.com/root-ali/iris/pkg/scheduler"
+	"go.uber.org/zap"
+)
+
+// NewService returns the janitor, rows are deleted without being archived
+// when archive is nil.
+func NewService(repo Repository, archive Archive, leader cluster.LeaderInterface, cfg Config,
+	logger *zap.SugaredLogger) (scheduler.ServiceInterface, error) {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.PartitionsAhead <= 0 {
+		cfg.PartitionsAhead = defaultPartitionsAhead
+	}
+	return &Service{
+		repo:    repo,
+		archive: archive,
+		leader:  leader,
+		cfg:     cfg,
+		logger:  logger,
+	}, nil
+}
+
+func (s *Service) Start() error {
+	s.logger.Infow("Starting retention janitor",
+		"interval", s.cfg.Interval,
+		"alerts", s.cfg.Alerts,
+		"messages", s.cfg.Messages,
+		"softDeleted", s.cfg.SoftDeleted,
+		"archive", s.archive != nil)
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.wg.Add(1)
+	go s.run()
+	return nil
+}
+
+func (s *Service) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+	return nil
+}
+
+func (s *Service) run() {
+	defer s.wg.Done()
+	s.runOnce(time.Now())
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.runOnce(now)
+		}
+	}
+}
+
+func (s *Service) runOnce(now time.Time) {
+	if s.leader != nil && !s.leader.IsLeader(cluster.LockJanitor) {
+		s.logger.Debug("Not the janitor leader, skipping")
+		return
+	}
+
+	// New alerts go to the partition of the current month, it has to
+	// exist before the month starts
+	if err := s.repo.EnsureAlertPartitions(s.ctx, now, s.cfg.PartitionsAhead); err != nil {
+		s.logger.Errorw("Failed to create alert partitions", "error", err)
+	}
+
+	var resolvedBefore, deletedBefore time.Time
+	if s.cfg.Alerts > 0 {
+		resolvedBefore = now.Add(-s.cfg.Alerts)
+	}
+	if s.cfg.SoftDeleted > 0 {
+		deletedBefore = now.Add(-s.cfg.SoftDeleted)
+	}
+	if !resolvedBefore.IsZero() || !deletedBefore.IsZero() {
+		s.purge(TableAlerts, now, func(ctx context.Context, limit int) ([]Row, error) {
+			return s.repo.ExpiredAlerts(ctx, resolvedBefore, deletedBefore, limit)
+		})
+	}
+	if s.cfg.Messages > 0 {
+		before := now.Add(-s.cfg.Messages)
+		s.purge(TableMessages, now, func(ctx context.Context, limit int) ([]Row, error) {
+			return s.repo.ExpiredMessages(ctx, before, limit)
+		})
+	}
+
+	dropped, err := s.repo.DropEmptyAlertPartitions(s.ctx, monthStart(now))
+	if err != nil {
+		s.logger.Errorw("Failed to drop empty alert partitions", "error", err)
+	} else if len(dropped) > 0 {
+		s.logger.Infow("Dropped empty alert partitions", "partitions", dropped)
+	}
+}
+
+// purge archives and deletes the expired rows of table a batch at a time.
+// A batch is only deleted once its archive is stored, it stops at the
+// first error and the rest is left to the next run.
+func (s *Service) purge(table string, now time.Time, fetch func(ctx context.Context, limit int) ([]Row, error)) {
+	var total int64
+	for batch := 0; batch < maxBatches; batch++ {
+		n, err := s.purgeBatch(table, now, batch, fetch)
+		if err != nil {
+			s.logger.Errorw("Failed to purge expired rows", "table", table, "batch", batch, "error", err)
+			break
+		}
+		total += n
+		if n < int64(s.cfg.BatchSize) {
+			break
+		}
+	}
+	if total > 0 {
+		s.logger.Infow("Purged expired rows", "table", table, "rows", total)
+	}
+}
+
+func (s *Service) purgeBatch(table string, now time.Time, batch int,
+	fetch func(ctx context.Context, limit int) ([]Row, error)) (int64, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, batchTimeout)
+	defer cancel()
+
+	rows, err := fetch(ctx, s.cfg.BatchSize)
+	if err != nil || len(rows) == 0 {
+		return 0, err
+	}
+	if s.archive != nil {
+		body, err := encodeRows(rows)
+		if err != nil {
+			return 0, err
+		}
+		if err := s.archive.Put(ctx, archiveKey(table, now, batch), body); err != nil {
+			return 0, err
+		}
+		rowsArchived.WithLabelValues(table).Add(float64(len(rows)))
+	}
+	ids := make([]string, 0, len(rows))
+	for _, r := range rows {
+		ids = append(ids, r.ID)
+	}
+	n, err := s.repo.DeleteRows(ctx, table, ids)
+	if err != nil {
+		return 0, err
+	}
+	rowsDeleted.WithLabelValues(table).Add(float64(n))
+	if n == 0 {
+		// the next batch would select the same rows again
+		return 0, fmt.Errorf("none of the %d expired rows were deleted", len(rows))
+	}
+	// Rows deleted by someone else in the meantime still count for the
+	// batch size
+	return int64(len(rows)), nil
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
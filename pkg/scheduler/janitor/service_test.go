@@ -0,0 +1,173 @@
+package janitor
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fakeRepo struct {
+	alerts     []Row
+	deleted    []string
+	partitions []time.Time
+	// events records archive and delete calls in order
+	events *[]string
+}
+
+func (f *fakeRepo) ExpiredAlerts(_ context.Context, resolvedBefore, deletedBefore time.Time, limit int) ([]Row, error) {
+	if limit > len(f.alerts) {
+		limit = len(f.alerts)
+	}
+	return f.alerts[:limit], nil
+}
+
+func (f *fakeRepo) ExpiredMessages(context.Context, time.Time, int) ([]Row, error) {
+	return nil, nil
+}
+
+func (f *fakeRepo) DeleteRows(_ context.Context, table string, ids []string) (int64, error) {
+	*f.events = append(*f.events, "delete "+table)
+	f.deleted = append(f.deleted, ids...)
+	f.alerts = f.alerts[len(ids):]
+	return int64(len(ids)), nil
+}
+
+func (f *fakeRepo) EnsureAlertPartitions(_ context.Context, from time.Time, months int) error {
+	f.partitions = append(f.partitions, from)
+	return nil
+}
+
+func (f *fakeRepo) DropEmptyAlertPartitions(context.Context, time.Time) ([]string, error) {
+	return nil, nil
+}
+
+type fakeArchive struct {
+	keys   []string
+	fail   bool
+	events *[]string
+}
+
+func (f *fakeArchive) Put(_ context.Context, key string, body []byte) error {
+	if f.fail {
+		return errors.New("store unavailable")
+	}
+	*f.events = append(*f.events, "archive")
+	f.keys = append(f.keys, key)
+	return nil
+}
+
+type notLeader struct{}
+
+func (notLeader) IsLeader(string) bool { return false }
+
+func newTestService(t *testing.T, rows int, archive *fakeArchive) (*Service, *fakeRepo) {
+	t.Helper()
+	events := []string{}
+	repo := &fakeRepo{events: &events}
+	for i := 0; i < rows; i++ {
+		repo.alerts = append(repo.alerts, Row{ID: fmt.Sprintf("a%d", i), Doc: fmt.Sprintf(`{"id":"a%d"}`, i)})
+	}
+	var a Archive
+	if archive != nil {
+		archive.events = &events
+		a = archive
+	}
+	svc, err := NewService(repo, a, nil, Config{BatchSize: 2, Alerts: 24 * time.Hour}, zap.NewNop().Sugar())
+	assert.NoError(t, err)
+	s := svc.(*Service)
+	s.ctx = context.Background()
+	return s, repo
+}
+
+func TestRunOnceArchivesBeforeDeleting(t *testing.T) {
+	archive := &fakeArchive{}
+	s, repo := newTestService(t, 5, archive)
+	now := time.Date(2026, 10, 19, 3, 0, 0, 0, time.UTC)
+	s.runOnce(now)
+
+	assert.Equal(t, []string{"a0", "a1", "a2", "a3", "a4"}, repo.deleted)
+	assert.Equal(t, []string{
+		"archive", "delete alerts",
+		"archive", "delete alerts",
+		"archive", "delete alerts",
+	}, *repo.events)
+	assert.Equal(t, "alerts/2026/10/19/alerts-20261019T030000Z-0.jsonl.gz", archive.keys[0])
+	assert.Len(t, repo.partitions, 1)
+}
+
+func TestRunOnceKeepsRowsWhenArchiveFails(t *testing.T) {
+	s, repo := newTestService(t, 3, &fakeArchive{fail: true})
+	s.runOnce(time.Now())
+	assert.Empty(t, repo.deleted)
+	assert.Len(t, repo.alerts, 3)
+}
+
+func TestRunOnceOnlyOnLeader(t *testing.T) {
+	s, repo := newTestService(t, 3, &fakeArchive{})
+	s.leader = notLeader{}
+	s.runOnce(time.Now())
+	assert.Empty(t, repo.deleted)
+	assert.Empty(t, repo.partitions)
+}
+
+func TestLocalArchiveWritesGzipLines(t *testing.T) {
+	dir := t.TempDir()
+	a, err := NewLocalArchive(dir)
+	assert.NoError(t, err)
+	body, err := encodeRows([]Row{{ID: "a1", Doc: `{"id":"a1"}`}, {ID: "a2", Doc: `{"id":"a2"}`}})
+	assert.NoError(t, err)
+	assert.NoError(t, a.Put(context.Background(), "alerts/2026/10/19/x.jsonl.gz", body))
+
+	f, err := os.Open(filepath.Join(dir, "alerts", "2026", "10", "19", "x.jsonl.gz"))
+	assert.NoError(t, err)
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	assert.NoError(t, err)
+	lines, err := io.ReadAll(zr)
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"id\":\"a1\"}\n{\"id\":\"a2\"}\n", string(lines))
+}
+
+// The example of the AWS Signature Version 4 documentation.
+func TestSigningKey(t *testing.T) {
+	key := signingKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	assert.Equal(t, "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d", hex.EncodeToString(key))
+}
+
+func TestS3ArchivePut(t *testing.T) {
+	var gotPath, gotAuth string
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotAuth = r.URL.Path, r.Header.Get("Authorization")
+		gotBody, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	a, err := NewS3Archive(S3Config{
+		Endpoint:  strings.TrimPrefix(srv.URL, "http://"),
+		Bucket:    "backups",
+		Prefix:    "iris",
+		AccessKey: "AKID",
+		SecretKey: "secret",
+		Insecure:  true,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, a.Put(context.Background(), "alerts/x.jsonl.gz", []byte("body")))
+	assert.Equal(t, "/backups/iris/alerts/x.jsonl.gz", gotPath)
+	assert.True(t, strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 Credential=AKID/"), gotAuth)
+	assert.True(t, bytes.Equal([]byte("body"), gotBody))
+}
@@ -0,0 +1,106 @@
+package janitor
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/root-ali/iris/pkg/cluster"
+	"go.uber.org/zap"
+)
+
+// Tables the janitor purges.
+const (
+	TableAlerts   = "alerts"
+	TableMessages = "message"
+)
+
+const (
+	defaultInterval        = time.Hour
+	defaultBatchSize       = 1000
+	defaultPartitionsAhead = 2
+	// A run stops after this many batches per table, the rest is left to
+	// the next run.
+	maxBatches   = 100
+	batchTimeout = 2 * time.Minute
+)
+
+// Row is an expired row as the JSON document written to the archive.
+type Row struct {
+	ID  string `gorm:"column:id"`
+	Doc string `gorm:"column:doc"`
+}
+
+type Repository interface {
+	// ExpiredAlerts returns the oldest alerts resolved before
+	// resolvedBefore or soft deleted before deletedBefore, a zero time
+	// leaves the condition out.
+	ExpiredAlerts(ctx context.Context, resolvedBefore, deletedBefore time.Time, limit int) ([]Row, error)
+	// ExpiredMessages returns the oldest messages created before before
+	// that are not waiting in the outbox.
+	ExpiredMessages(ctx context.Context, before time.Time, limit int) ([]Row, error)
+	DeleteRows(ctx context.Context, table string, ids []string) (int64, error)
+	// EnsureAlertPartitions creates the monthly partitions of alerts from
+	// the month of from to months after it.
+	EnsureAlertPartitions(ctx context.Context, from time.Time, months int) error
+	// DropEmptyAlertPartitions drops the empty monthly partitions ending
+	// before before and returns their names.
+	DropEmptyAlertPartitions(ctx context.Context, before time.Time) ([]string, error)
+}
+
+// Archive stores the expired rows before they are deleted.
+type Archive interface {
+	Put(ctx context.Context, key string, body []byte) error
+}
+
+// Config holds the retention of every kind of row, a zero retention keeps
+// the rows forever. Alert partitions are managed either way.
+type Config struct {
+	Interval  time.Duration
+	BatchSize int
+	// Alerts is the retention of resolved alerts.
+	Alerts   time.Duration
+	Messages time.Duration
+	// SoftDeleted is how long deleted alerts are kept before they are purged.
+	SoftDeleted     time.Duration
+	PartitionsAhead int
+}
+
+// Service archives and deletes expired rows and keeps the monthly
+// partitions of alerts ahead of time. It only runs on the leader.
+type Service struct {
+	repo    Repository
+	archive Archive
+	leader  cluster.LeaderInterface
+	cfg     Config
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	logger *zap.SugaredLogger
+}
+
+// LocalArchive writes archives below Dir.
+type LocalArchive struct {
+	Dir string
+}
+
+// S3Config is an S3-compatible bucket, objects are addressed path-style so
+// MinIO and other stores work as well as AWS.
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	Prefix    string
+	AccessKey string
+	SecretKey string
+	// Insecure uses plain HTTP.
+	Insecure bool
+}
+
+type S3Archive struct {
+	cfg    S3Config
+	client *http.Client
+}
@@ -8,21 +8,26 @@ import (
 	"sync"
 	"time"
 
+	"github.com/root-ali/iris/pkg/cluster"
 	"github.com/root-ali/iris/pkg/message"
 	"github.com/root-ali/iris/pkg/notifications"
 	"github.com/root-ali/iris/pkg/scheduler"
+	"github.com/root-ali/iris/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
 func NewMessageStatusMessageService(
 	messageRepo MessageListRepository,
 	providerRepo ProviderRepository,
+	leader cluster.LeaderInterface,
 	config Config,
 	logger *zap.SugaredLogger,
 ) (scheduler.ServiceInterface, error) {
 	s := &Service{}
 	s.providerRepo = providerRepo
 	s.messageRepo = messageRepo
+	s.leader = leader
 
 	s.wg = sync.WaitGroup{}
 	s.ctx = context.Background()
@@ -116,6 +121,10 @@ func (s *Service) run() {
 }
 
 func (s *Service) enqueueCache() {
+	if s.leader != nil && !s.leader.IsLeader(cluster.LockMessageStatus) {
+		s.logger.Debug("Not the message status leader, skipping")
+		return
+	}
 	messages, err := s.messageRepo.ListNotFinishedMessages()
 	if err != nil {
 		s.logger.Errorf("failed to get not finished messages: %v", err)
@@ -127,6 +136,7 @@ func (s *Service) enqueueCache() {
 	for _, msg := range messages {
 		select {
 		case s.taskCh <- msg:
+			scheduler.QueueDepth.WithLabelValues("message_status").Set(float64(len(s.taskCh)))
 		case <-s.ctx.Done():
 			return
 		}
@@ -143,6 +153,7 @@ func (s *Service) worker(id int) {
 			if !ok {
 				return
 			}
+			scheduler.QueueDepth.WithLabelValues("message_status").Set(float64(len(s.taskCh)))
 			s.logger.Info("message status scheduler worker #" + strconv.Itoa(id) +
 				" started for checking message " + msg.Id)
 			s.safeRunJob(msg)
@@ -174,15 +185,25 @@ func (s *Service) checkMessageStatus(msg message.Message) {
 			"message", msg.Id, "error", err)
 		return
 	}
+	if dr, ok := notifications.DeliveryReportsOf(provider); ok && dr.DeliveryReportsEnabled() &&
+		time.Since(msg.CreatedAt) < DeliveryReportTimeout {
+		s.logger.Debugw("waiting for delivery report of message",
+			"message", msg.Id,
+			"provider", provider.GetName())
+		return
+	}
 	s.logger.Infow("checking message status provider successfully get for message",
 		"message", msg.Id,
 		"provider", provider.GetName())
+	span := tracing.StartProviderCall(msg.TraceParent, "status", provider.GetName(),
+		attribute.String("message.id", msg.Id))
 	messageStatus, err := provider.Status(msg.SenderId)
+	tracing.End(span, err)
 	if err != nil {
 		s.logger.Errorw("failed to get message status from provider",
 			"message", msg.Id, "error", err)
 		err = s.messageRepo.UpdateMessageStatus(&msg,
-			0,
+			message.TypeMessageStatusSent,
 			"Failed to get status from provider: "+err.Error())
 		if err != nil {
 			s.logger.Errorw("failed to update message status to Failed for message",
@@ -198,17 +219,21 @@ func (s *Service) checkMessageStatus(msg message.Message) {
 		if err != nil {
 			s.logger.Errorw("failed to update message status to Delivered for message",
 				"message", msg.Id, "error", err.Error())
+			return
 		}
+		notifications.ObserveDelivered(msg.Sender, msg.AlertTime())
 		return
 	} else if messageStatus == 6 {
 		err := s.messageRepo.UpdateMessageStatus(&msg, 6, "Failed")
 		if err != nil {
 			s.logger.Errorw("failed to update message status to Failed for message",
 				"message", msg.Id, "error", err.Error())
+			return
 		}
+		notifications.ObserveFailed(msg.Sender)
 		return
 	} else {
-		err := s.messageRepo.UpdateMessageStatus(&msg, 0, "Sent")
+		err := s.messageRepo.UpdateMessageStatus(&msg, message.TypeMessageStatusSent, "Sent")
 		if err != nil {
 			s.logger.Errorw("failed to update message status to Sent for message",
 				"message", msg.Id, "error", err.Error())
@@ -217,6 +242,38 @@ func (s *Service) checkMessageStatus(msg message.Message) {
 	}
 }
 
+// HandleDeliveryReport updates the message of a delivery report and falls
+// back to an alternative provider right away when the message failed.
+func (s *Service) HandleDeliveryReport(provider notifications.NotificationInterface,
+	report notifications.DeliveryReport) error {
+	msg, err := s.messageRepo.GetMessageBySenderID(provider.GetName(), report.MessageID)
+	if err != nil {
+		return err
+	}
+	s.logger.Infow("delivery report received",
+		"message", msg.Id,
+		"provider", provider.GetName(),
+		"status", report.Status)
+	if msg.Status != message.StatusMap[message.TypeMessageStatusSent] {
+		// Already delivered, failed or handled by the poller
+		return nil
+	}
+
+	switch report.Status {
+	case notifications.TypeMessageStatusDelivered:
+		if err := s.messageRepo.UpdateMessageStatus(msg, message.TypeMessageStatusDelivered, "Delivered"); err != nil {
+			return err
+		}
+		notifications.ObserveDelivered(msg.Sender, msg.AlertTime())
+		return nil
+	case notifications.TypeMessageStatusFailed, notifications.TypeMessageStatusUndelivered:
+		s.sendAlternativeNotification(*msg)
+		return nil
+	default:
+		return nil
+	}
+}
+
 func (s *Service) sendAlternativeNotification(msg message.Message) {
 	provider, err := s.getNotificationProvider(msg.Sender)
 	if err != nil {
@@ -231,6 +288,7 @@ func (s *Service) sendAlternativeNotification(msg message.Message) {
 
 		s.logger.Errorw("failed to get alternative provider for message",
 			"message", msg.Id, "error", err)
+		notifications.ObserveFailed(msg.Sender)
 		err := s.messageRepo.UpdateMessageStatus(&msg, 6, "failed to get alternative provider")
 		if err != nil {
 			return
@@ -242,8 +300,10 @@ func (s *Service) sendAlternativeNotification(msg message.Message) {
 	s.logger.Infow("resending message with alternative provider",
 		"message", msg.Id,
 		"provider", alternativeProvider.GetName())
+	notifications.ObserveFallback(provider.GetName(), alternativeProvider.GetName())
 	msgId, err := s.sendNotification(msg, alternativeProvider)
 	if err != nil {
+		notifications.ObserveFailed(alternativeProvider.GetName())
 
 		s.logger.Errorw("failed to resend message with alternative provider",
 			"message", msg.Id, "error", err)
@@ -264,6 +324,8 @@ func (s *Service) sendAlternativeNotification(msg message.Message) {
 			[]string{provider.GetName(), alternativeProvider.GetName()},
 			message.TypeMessageStatusSent)
 		newMessage.Status = message.StatusMap[message.TypeMessageStatusFailed]
+		newMessage.AlertAt = msg.AlertAt
+		newMessage.TraceParent = msg.TraceParent
 		err = s.messageRepo.Add(newMessage)
 		s.logger.Errorw("failed to save resent message to repository after failed resend attempt",
 			"message", msg.Id, "error", err)
@@ -285,6 +347,9 @@ func (s *Service) sendAlternativeNotification(msg message.Message) {
 		msg.GroupName,
 		"Resent with alternative provider: "+alternativeProvider.GetName(),
 		[]string{provider.GetName(), alternativeProvider.GetName()}, message.TypeMessageStatusSent)
+	newMessage.AlertAt = msg.AlertAt
+	newMessage.TraceParent = msg.TraceParent
+	notifications.ObserveSent(alternativeProvider.GetName(), msg.AlertTime())
 	err = s.messageRepo.Add(newMessage)
 	if err != nil {
 		s.logger.Errorw("failed to save resent message to repository",
@@ -332,7 +397,11 @@ func (s *Service) sendNotification(msg message.Message, provider notifications.N
 		Receptors: []string{msg.Receptor},
 	}
 
+	span := tracing.StartProviderCall(msg.TraceParent, "send", provider.GetName(),
+		attribute.String("message.id", msg.Id),
+		attribute.Bool("message.fallback", true))
 	msgIds, err := provider.Send(notificationMessage)
+	tracing.End(span, err)
 	if err != nil {
 		return "", fmt.Errorf("failed to send message: %w", err)
 	}
@@ -353,6 +422,9 @@ func (s *Service) getAlternativeProvider(flag string, providerName []string) (no
 	}
 
 	for _, p := range providers {
+		if !notifications.Available(p.Provider) {
+			continue
+		}
 		providerMap[p.Provider.GetName()] = p.Flag
 	}
 
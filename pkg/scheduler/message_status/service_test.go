@@ -0,0 +1,73 @@
+package message_status
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/root-ali/iris/pkg/message"
+	"github.com/root-ali/iris/pkg/notifications"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// dlrProvider pushes delivery reports and counts the status polls.
+type dlrProvider struct {
+	polls int
+}
+
+func (p *dlrProvider) Send(notifications.Message) ([]string, error) { return nil, nil }
+func (p *dlrProvider) Status(string) (notifications.MessageStatusType, error) {
+	p.polls++
+	return notifications.TypeMessageStatusDelivered, nil
+}
+func (p *dlrProvider) Verify() (string, error) { return "", nil }
+func (p *dlrProvider) GetName() string         { return "kavenegar" }
+func (p *dlrProvider) GetFlag() string         { return "sms" }
+func (p *dlrProvider) GetPriority() int        { return 1 }
+func (p *dlrProvider) DeliveryReportsEnabled() bool {
+	return true
+}
+func (p *dlrProvider) DeliveryReports(*http.Request) ([]notifications.DeliveryReport, error) {
+	return nil, nil
+}
+
+type fixedProviders []notifications.Providers
+
+func (f fixedProviders) GetActiveProviders() ([]notifications.Providers, error)   { return f, nil }
+func (f fixedProviders) GetProvidersPriority() ([]notifications.Providers, error) { return f, nil }
+
+type recordingMessages struct {
+	MessageListRepository
+	updates []message.StatusType
+}
+
+func (r *recordingMessages) UpdateMessageStatus(_ *message.Message, status message.StatusType, _ string) error {
+	r.updates = append(r.updates, status)
+	return nil
+}
+
+// Active providers are guarded by rate limits and circuit breakers, the
+// delivery report capability is found through the guard.
+func TestCheckMessageStatusWaitsForDeliveryReportOfGuardedProvider(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	p := &dlrProvider{}
+	guarded := notifications.Guard(p, notifications.GuardConfig{}, logger)
+	msgs := &recordingMessages{}
+	svc, err := NewMessageStatusMessageService(msgs,
+		fixedProviders{{Name: p.GetName(), Provider: guarded}},
+		nil,
+		Config{Interval: time.Second, Workers: 1},
+		logger)
+	require.NoError(t, err)
+	s := svc.(*Service)
+
+	s.checkMessageStatus(message.Message{Id: "m1", Sender: p.GetName(), SenderId: "42", CreatedAt: time.Now()})
+	assert.Zero(t, p.polls, "recent messages are left to the delivery report")
+	assert.Empty(t, msgs.updates)
+
+	s.checkMessageStatus(message.Message{Id: "m2", Sender: p.GetName(), SenderId: "43",
+		CreatedAt: time.Now().Add(-DeliveryReportTimeout - time.Minute)})
+	assert.Equal(t, 1, p.polls, "messages without a report are polled after the timeout")
+}
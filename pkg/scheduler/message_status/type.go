@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/root-ali/iris/pkg/cluster"
 	"github.com/root-ali/iris/pkg/message"
 	"github.com/root-ali/iris/pkg/notifications"
 	"go.uber.org/zap"
@@ -14,6 +15,12 @@ type MessageListRepository interface {
 	Add(msg *message.Message) error
 	UpdateMessageStatus(msg *message.Message, status message.StatusType, response string) error
 	ListNotFinishedMessages() ([]message.Message, error)
+	GetMessageBySenderID(sender, senderID string) (*message.Message, error)
+}
+
+// DeliveryReportHandler applies delivery reports pushed by providers.
+type DeliveryReportHandler interface {
+	HandleDeliveryReport(provider notifications.NotificationInterface, report notifications.DeliveryReport) error
 }
 
 type ProviderRepository interface {
@@ -25,6 +32,7 @@ type Service struct {
 	// scheduler dependencies
 	messageRepo  MessageListRepository
 	providerRepo ProviderRepository
+	leader       cluster.LeaderInterface
 
 	//Config
 	config Config
@@ -53,4 +61,7 @@ type Config struct {
 
 var (
 	MaxAttempt = 3
+	// DeliveryReportTimeout is how long messages of providers with delivery
+	// report callbacks are left to the callback before they are polled.
+	DeliveryReportTimeout = 10 * time.Minute
 )
@@ -0,0 +1,12 @@
+package scheduler
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// QueueDepth is the number of jobs waiting for a worker of a scheduler.
+var QueueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "iris_scheduler_queue_depth",
+	Help: "Jobs waiting for a worker, by scheduler.",
+}, []string{"scheduler"})
@@ -0,0 +1,147 @@
+package statuspage
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+)
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description"`
+	PubDate     string  `xml:"pubDate"`
+	GUID        rssGUID `xml:"guid"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// WriteRSS writes the incident history as an RSS 2.0 feed, an incident is
+// published again with a new guid whenever it changes.
+func WriteRSS(w io.Writer, p *Page, link string) error {
+	feed := rss{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         p.Title,
+			Link:          link,
+			Description:   fmt.Sprintf("%s incident history", p.Title),
+			LastBuildDate: p.UpdatedAt.Format(time.RFC1123Z),
+			Items:         make([]rssItem, 0, len(p.Incidents)),
+		},
+	}
+	for _, inc := range p.Incidents {
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title: fmt.Sprintf("[%s] %s", strings.ToUpper(string(inc.Status)), inc.Title),
+			Link:  link,
+			Description: fmt.Sprintf("%s affecting %s since %s", inc.Title,
+				strings.Join(inc.Components, ", "), inc.CreatedAt.Format(time.RFC1123Z)),
+			PubDate: inc.UpdatedAt.Format(time.RFC1123Z),
+			GUID: rssGUID{
+				Value: fmt.Sprintf("%s-%d", inc.ID, inc.UpdatedAt.Unix()),
+			},
+		})
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(feed)
+}
+
+// WriteHTML renders the public status page.
+func WriteHTML(w io.Writer, p *Page) error {
+	tmpl, err := template.New("status").Funcs(template.FuncMap{
+		"date": func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04 MST") },
+		"join": strings.Join,
+	}).Parse(pageTemplate)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, p)
+}
+
+var pageTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8" />
+    <meta name="viewport" content="width=device-width, initial-scale=1.0" />
+    <meta http-equiv="refresh" content="60" />
+    <title>{{.Title}}</title>
+    <link rel="alternate" type="application/rss+xml" title="{{.Title}}" href="status/feed.rss" />
+    <style>
+        body { font-family: Arial, sans-serif; color: #2c3e50; background: #f5f5f7; margin: 0; }
+        main { max-width: 800px; margin: 0 auto; padding: 2rem 1rem; }
+        .banner { border-radius: 8px; padding: 1rem 1.5rem; color: white; font-size: 1.2rem; margin-bottom: 2rem; }
+        .banner.operational { background: #27ae60; }
+        .banner.degraded { background: #f39c12; }
+        .banner.outage { background: #e74c3c; }
+        .card { background: white; border-radius: 8px; box-shadow: 0 2px 4px rgba(0, 0, 0, 0.1); margin-bottom: 2rem; }
+        .component { display: flex; justify-content: space-between; padding: 1rem 1.5rem; border-bottom: 1px solid #ecf0f1; }
+        .component:last-child { border-bottom: none; }
+        .component small { display: block; color: #7f8c8d; }
+        .state { font-weight: 600; text-transform: capitalize; }
+        .state.operational { color: #27ae60; }
+        .state.degraded { color: #f39c12; }
+        .state.outage { color: #e74c3c; }
+        .incident { padding: 1rem 1.5rem; border-bottom: 1px solid #ecf0f1; }
+        .incident:last-child { border-bottom: none; }
+        .incident h3 { margin: 0 0 0.25rem; }
+        .meta { color: #7f8c8d; font-size: 0.9rem; }
+        footer { color: #7f8c8d; font-size: 0.8rem; text-align: center; }
+    </style>
+</head>
+<body>
+<main>
+    <h1>{{.Title}}</h1>
+    <div class="banner {{.State}}">
+        {{if eq .State "operational"}}All systems operational{{else if eq .State "degraded"}}Some systems are degraded{{else}}Some systems are down{{end}}
+    </div>
+
+    <div class="card">
+        {{range .Components}}
+        <div class="component">
+            <div>{{.Name}}{{if .Description}}<small>{{.Description}}</small>{{end}}</div>
+            <span class="state {{.State}}">{{.State}}</span>
+        </div>
+        {{end}}
+    </div>
+
+    <h2>Incident history</h2>
+    <div class="card">
+        {{range .Incidents}}
+        <div class="incident">
+            <h3>{{.Title}}</h3>
+            <div class="meta">
+                <span class="state">{{.Status}}</span> · {{join .Components ", "}} · {{date .CreatedAt}}{{with .ResolvedAt}} to {{date .}}{{end}}
+            </div>
+        </div>
+        {{else}}
+        <div class="incident meta">No incidents reported</div>
+        {{end}}
+    </div>
+    <footer>Updated {{date .UpdatedAt}} · <a href="status/index.json">JSON</a> · <a href="status/feed.rss">RSS</a></footer>
+</main>
+</body>
+</html>
+`
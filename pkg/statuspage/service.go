@@ -0,0 +1,203 @@
+package statuspage
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/root-ali/iris/pkg/alerts"
+	"github.com/root-ali/iris/pkg/cache"
+	"github.com/root-ali/iris/pkg/matchers"
+	"github.com/root-ali/iris/pkg/util"
+	"go.uber.org/zap"
+)
+
+func NewService(repo Repository, c cache.Interface[string, *Page], cfg Config, logger *zap.SugaredLogger) *Service {
+	if cfg.Title == "" {
+		cfg.Title = "Status"
+	}
+	if cfg.HistoryDays <= 0 {
+		cfg.HistoryDays = 14
+	}
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = 30 * time.Second
+	}
+	return &Service{
+		repo:   repo,
+		cache:  c,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+func validateComponent(c *Component) error {
+	if c.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(c.Matchers) == 0 {
+		return errors.New("at least one matcher is required")
+	}
+	_, err := matchers.ParseStrings(c.Matchers)
+	return err
+}
+
+func (s *Service) CreateComponent(c *Component) error {
+	if err := validateComponent(c); err != nil {
+		return err
+	}
+	id, err := util.NewUUIDv7()
+	if err != nil {
+		return err
+	}
+	c.ID = id
+	c.CreatedAt = time.Now()
+	c.ModifiedAt = time.Now()
+	if err := s.repo.AddStatusComponent(c); err != nil {
+		s.logger.Errorw("Failed to add status component", "name", c.Name, "error", err)
+		return err
+	}
+	s.cache.Delete(pageCacheKey)
+	return nil
+}
+
+func (s *Service) UpdateComponent(c *Component) error {
+	if err := validateComponent(c); err != nil {
+		return err
+	}
+	c.ModifiedAt = time.Now()
+	if err := s.repo.UpdateStatusComponent(c); err != nil {
+		return err
+	}
+	s.cache.Delete(pageCacheKey)
+	return nil
+}
+
+func (s *Service) GetComponent(id string) (*Component, error) {
+	return s.repo.GetStatusComponent(id)
+}
+
+func (s *Service) ListComponents() ([]*Component, error) {
+	return s.repo.GetStatusComponents()
+}
+
+func (s *Service) DeleteComponent(id string) error {
+	if err := s.repo.DeleteStatusComponent(id); err != nil {
+		return err
+	}
+	s.cache.Delete(pageCacheKey)
+	return nil
+}
+
+// Page returns the public status page. It is cached for CacheTTL since
+// the public routes are not authenticated.
+func (s *Service) Page() (*Page, error) {
+	if p, ok := s.cache.Get(pageCacheKey); ok {
+		return p, nil
+	}
+	p, err := s.buildPage(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.Set(pageCacheKey, p, s.cfg.CacheTTL); err != nil {
+		s.logger.Errorw("Failed to cache status page", "error", err)
+	}
+	return p, nil
+}
+
+type compiledComponent struct {
+	*Component
+	matchers matchers.Matchers
+}
+
+func (s *Service) buildPage(now time.Time) (*Page, error) {
+	components, err := s.repo.GetStatusComponents()
+	if err != nil {
+		return nil, err
+	}
+	compiled := make([]compiledComponent, 0, len(components))
+	for _, c := range components {
+		ms, err := matchers.ParseStrings(c.Matchers)
+		if err != nil {
+			s.logger.Warnw("Invalid matchers in status component", "id", c.ID, "error", err)
+			continue
+		}
+		compiled = append(compiled, compiledComponent{Component: c, matchers: ms})
+	}
+
+	firing, err := s.repo.GetFiringAlerts()
+	if err != nil {
+		return nil, err
+	}
+	page := &Page{
+		Title:      s.cfg.Title,
+		State:      StateOperational,
+		Components: make([]ComponentStatus, 0, len(compiled)),
+		Incidents:  []IncidentSummary{},
+		UpdatedAt:  now,
+	}
+	for _, c := range compiled {
+		st := ComponentStatus{Name: c.Name, Description: c.Description, State: StateOperational}
+		for _, al := range firing {
+			if al.Internal() || !c.matchers.Matches(al.LabelSet()) {
+				continue
+			}
+			if state := alertState(al); state.rank() > st.State.rank() {
+				st.State = state
+			}
+		}
+		if st.State.rank() > page.State.rank() {
+			page.State = st.State
+		}
+		page.Components = append(page.Components, st)
+	}
+
+	incs, err := s.repo.GetIncidentsSince(now.AddDate(0, 0, -s.cfg.HistoryDays))
+	if err != nil {
+		return nil, err
+	}
+	for _, inc := range incs {
+		als, err := s.repo.GetIncidentAlerts(inc.ID)
+		if err != nil {
+			return nil, err
+		}
+		affected := affectedComponents(compiled, als)
+		if len(affected) == 0 {
+			continue
+		}
+		page.Incidents = append(page.Incidents, IncidentSummary{
+			ID:         inc.ID,
+			Title:      inc.Title,
+			Status:     inc.Status,
+			Components: affected,
+			CreatedAt:  inc.CreatedAt,
+			UpdatedAt:  inc.UpdatedAt,
+			ResolvedAt: inc.ResolvedAt,
+		})
+	}
+	sort.SliceStable(page.Incidents, func(i, j int) bool {
+		return page.Incidents[i].CreatedAt.After(page.Incidents[j].CreatedAt)
+	})
+	return page, nil
+}
+
+func alertState(al *alerts.Alert) State {
+	if al.Severity == "critical" {
+		return StateOutage
+	}
+	return StateDegraded
+}
+
+// affectedComponents returns the names of the components matching any of
+// the alerts.
+func affectedComponents(components []compiledComponent, als []*alerts.Alert) []string {
+	names := make([]string, 0)
+	for _, c := range components {
+		for _, al := range als {
+			if !al.Internal() && c.matchers.Matches(al.LabelSet()) {
+				names = append(names, c.Name)
+				break
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,166 @@
+package statuspage
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/root-ali/iris/pkg/alerts"
+	"github.com/root-ali/iris/pkg/cache"
+	"github.com/root-ali/iris/pkg/incidents"
+	"go.uber.org/zap"
+)
+
+type fakeRepo struct {
+	Repository
+	components []*Component
+	firing     []*alerts.Alert
+	incidents  []*incidents.Incident
+	links      map[string][]*alerts.Alert
+	queries    int
+}
+
+func (r *fakeRepo) GetStatusComponents() ([]*Component, error) {
+	r.queries++
+	return r.components, nil
+}
+
+func (r *fakeRepo) AddStatusComponent(c *Component) error {
+	r.components = append(r.components, c)
+	return nil
+}
+
+func (r *fakeRepo) GetFiringAlerts() ([]*alerts.Alert, error) { return r.firing, nil }
+
+func (r *fakeRepo) GetIncidentsSince(time.Time) ([]*incidents.Incident, error) {
+	return r.incidents, nil
+}
+
+func (r *fakeRepo) GetIncidentAlerts(id string) ([]*alerts.Alert, error) {
+	return r.links[id], nil
+}
+
+func newTestService(repo *fakeRepo) *Service {
+	logger := zap.NewNop().Sugar()
+	return NewService(repo, cache.New[string, *Page](logger), Config{Title: "Acme"}, logger)
+}
+
+func TestPageComponentStates(t *testing.T) {
+	repo := &fakeRepo{
+		components: []*Component{
+			{ID: "1", Name: "API", Matchers: []string{`service="api"`}},
+			{ID: "2", Name: "Web", Matchers: []string{`service="web"`}},
+			{ID: "3", Name: "Billing", Matchers: []string{`service="billing"`}},
+		},
+		firing: []*alerts.Alert{
+			{Name: "HighLatency", Severity: "warning", Labels: alerts.Labels{"service": "api"}},
+			{Name: "Down", Severity: "critical", Labels: alerts.Labels{"service": "web"}},
+			{Name: "Credit", Severity: "critical", Labels: alerts.Labels{"service": "billing", alerts.LabelInternal: "true"}},
+		},
+	}
+	p, err := newTestService(repo).Page()
+	if err != nil {
+		t.Fatalf("Page: %v", err)
+	}
+	want := []State{StateDegraded, StateOutage, StateOperational}
+	for i, c := range p.Components {
+		if c.State != want[i] {
+			t.Errorf("%s: state = %s, want %s", c.Name, c.State, want[i])
+		}
+	}
+	if p.State != StateOutage {
+		t.Errorf("page state = %s, want outage", p.State)
+	}
+	if p.Title != "Acme" {
+		t.Errorf("title = %q", p.Title)
+	}
+}
+
+func TestPageIncidentsOnlyForComponents(t *testing.T) {
+	now := time.Now()
+	repo := &fakeRepo{
+		components: []*Component{{ID: "1", Name: "API", Matchers: []string{`service="api"`}}},
+		incidents: []*incidents.Incident{
+			{ID: "a", Title: "API errors", Status: incidents.StatusResolved, CreatedAt: now.Add(-2 * time.Hour)},
+			{ID: "b", Title: "Internal database", Status: incidents.StatusTriggered, CreatedAt: now.Add(-time.Hour)},
+		},
+		links: map[string][]*alerts.Alert{
+			"a": {{Name: "Errors", Labels: alerts.Labels{"service": "api"}}},
+			"b": {{Name: "Disk", Labels: alerts.Labels{"service": "db"}}},
+		},
+	}
+	p, err := newTestService(repo).Page()
+	if err != nil {
+		t.Fatalf("Page: %v", err)
+	}
+	if len(p.Incidents) != 1 || p.Incidents[0].ID != "a" {
+		t.Fatalf("incidents = %+v, want only a", p.Incidents)
+	}
+	if got := p.Incidents[0].Components; len(got) != 1 || got[0] != "API" {
+		t.Errorf("components = %v", got)
+	}
+}
+
+func TestPageIsCachedUntilComponentsChange(t *testing.T) {
+	repo := &fakeRepo{}
+	s := newTestService(repo)
+	for i := 0; i < 3; i++ {
+		if _, err := s.Page(); err != nil {
+			t.Fatalf("Page: %v", err)
+		}
+	}
+	if repo.queries != 1 {
+		t.Fatalf("queries = %d, want 1", repo.queries)
+	}
+	if err := s.CreateComponent(&Component{Name: "API", Matchers: []string{`service="api"`}}); err != nil {
+		t.Fatalf("CreateComponent: %v", err)
+	}
+	p, _ := s.Page()
+	if repo.queries != 2 || len(p.Components) != 1 {
+		t.Errorf("queries = %d, components = %d; want the page rebuilt", repo.queries, len(p.Components))
+	}
+}
+
+func TestCreateComponentValidatesMatchers(t *testing.T) {
+	s := newTestService(&fakeRepo{})
+	for _, c := range []*Component{
+		{Name: "", Matchers: []string{`service="api"`}},
+		{Name: "API"},
+		{Name: "API", Matchers: []string{`service=~"(`}},
+	} {
+		if err := s.CreateComponent(c); err == nil {
+			t.Errorf("CreateComponent(%+v) succeeded, want error", c)
+		}
+	}
+}
+
+func TestWriteRSS(t *testing.T) {
+	p := &Page{
+		Title: "Acme",
+		Incidents: []IncidentSummary{
+			{ID: "a", Title: "API <errors>", Status: incidents.StatusTriggered, Components: []string{"API"}},
+		},
+	}
+	var buf bytes.Buffer
+	if err := WriteRSS(&buf, p, "https://status.example.com/status"); err != nil {
+		t.Fatalf("WriteRSS: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "<title>[TRIGGERED] API &lt;errors&gt;</title>") {
+		t.Errorf("item title not escaped or missing:\n%s", out)
+	}
+}
+
+// Components are served by the component API, a promoted MarshalJSON
+// would encode every component as null.
+func TestComponentMarshalsFields(t *testing.T) {
+	b, err := json.Marshal(&Component{ID: "c1", Name: "API", Matchers: []string{`service="api"`}})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if !strings.Contains(string(b), `"id":"c1"`) || !strings.Contains(string(b), `"name":"API"`) {
+		t.Errorf("component json = %s", b)
+	}
+}
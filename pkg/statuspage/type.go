@@ -0,0 +1,112 @@
+package statuspage
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/root-ali/iris/pkg/alerts"
+	"github.com/root-ali/iris/pkg/cache"
+	"github.com/root-ali/iris/pkg/incidents"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type State string
+
+const (
+	StateOperational State = "operational"
+	StateDegraded    State = "degraded"
+	StateOutage      State = "outage"
+)
+
+// rank orders the states from the best to the worst.
+func (s State) rank() int {
+	switch s {
+	case StateOutage:
+		return 2
+	case StateDegraded:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Component is a public part of the service. It is in outage while a
+// critical alert matching its matchers fires, and degraded for any other
+// severity.
+type Component struct {
+	ID          string         `json:"id" gorm:"column:id;primary_key"`
+	Name        string         `json:"name" gorm:"column:name"`
+	Description string         `json:"description" gorm:"column:description"`
+	Matchers    pq.StringArray `json:"matchers" gorm:"column:matchers;type:text[]"`
+	Position    int            `json:"position" gorm:"column:position"`
+	CreatedAt   time.Time      `json:"created_at" gorm:"column:created_at"`
+	ModifiedAt  time.Time      `json:"modified_at" gorm:"column:modified_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"column:deleted_at"`
+}
+
+func (Component) TableName() string { return "status_components" }
+
+// ComponentStatus is the public view of a component.
+type ComponentStatus struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	State       State  `json:"state"`
+}
+
+// IncidentSummary is the public view of an incident, only incidents with
+// alerts of a component are published.
+type IncidentSummary struct {
+	ID         string           `json:"id"`
+	Title      string           `json:"title"`
+	Status     incidents.Status `json:"status"`
+	Components []string         `json:"components"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+	ResolvedAt *time.Time       `json:"resolved_at,omitempty"`
+}
+
+type Page struct {
+	Title      string            `json:"title"`
+	State      State             `json:"state"`
+	Components []ComponentStatus `json:"components"`
+	Incidents  []IncidentSummary `json:"incidents"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+// Config of the public page, incidents are listed for HistoryDays.
+type Config struct {
+	Title       string
+	HistoryDays int
+	// CacheTTL bounds how often the public routes query the database.
+	CacheTTL time.Duration
+}
+
+type Repository interface {
+	AddStatusComponent(c *Component) error
+	GetStatusComponent(id string) (*Component, error)
+	GetStatusComponents() ([]*Component, error)
+	UpdateStatusComponent(c *Component) error
+	DeleteStatusComponent(id string) error
+	GetFiringAlerts() ([]*alerts.Alert, error)
+	GetIncidentsSince(since time.Time) ([]*incidents.Incident, error)
+	GetIncidentAlerts(incidentID string) ([]*alerts.Alert, error)
+}
+
+type ServiceInterface interface {
+	CreateComponent(c *Component) error
+	UpdateComponent(c *Component) error
+	GetComponent(id string) (*Component, error)
+	ListComponents() ([]*Component, error)
+	DeleteComponent(id string) error
+	Page() (*Page, error)
+}
+
+type Service struct {
+	repo   Repository
+	cache  cache.Interface[string, *Page]
+	cfg    Config
+	logger *zap.SugaredLogger
+}
+
+const pageCacheKey = "status_page"
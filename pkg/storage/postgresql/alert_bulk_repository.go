@@ -0,0 +1,115 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/root-ali/iris/pkg/alerts"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// selectBulkAlerts adds the selector of req to q.
+func selectBulkAlerts(q *gorm.DB, req alerts.BulkRequest) (*gorm.DB, error) {
+	if len(req.IDs) > 0 {
+		q = q.Where("id IN ?", req.IDs)
+	}
+	return filterAlerts(q, alerts.Query{Matchers: req.Matchers})
+}
+
+// bulkAlertChanges returns the condition an alert must meet for the action
+// of req to change it and the columns it sets.
+func bulkAlertChanges(req alerts.BulkRequest, entry *alerts.AuditEntry) (string, map[string]interface{}, error) {
+	at := entry.CreatedAt
+	switch req.Action {
+	case alerts.BulkResolve:
+		return "status = 'firing'", map[string]interface{}{
+			"status": "resolved", "ends_at": at, "send_notif": false, "updated_at": at,
+		}, nil
+	case alerts.BulkAcknowledge:
+		return "acknowledged_at IS NULL", map[string]interface{}{
+			"acknowledged_at": at, "acknowledged_by": req.Actor, "updated_at": at,
+		}, nil
+	case alerts.BulkSilence:
+		return "", map[string]interface{}{"silenced_until": *entry.SilencedUntil, "updated_at": at}, nil
+	case alerts.BulkDelete:
+		return "", map[string]interface{}{"deleted_at": at}, nil
+	case alerts.BulkRenotify:
+		// the alert scheduler sends alerts that are not marked as sent, the
+		// next round gets new idempotency keys so the messages are queued again
+		return "", map[string]interface{}{
+			"send_notif": false, "notify_round": gorm.Expr("notify_round + 1"), "updated_at": at,
+		}, nil
+	}
+	return "", nil, fmt.Errorf("unknown bulk action %q", req.Action)
+}
+
+func (s *Storage) CountBulkAlerts(req alerts.BulkRequest) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	q, err := selectBulkAlerts(s.db.WithContext(ctx).Model(&alerts.Alert{}), req)
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	if err := q.Count(&n).Error; err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (s *Storage) ApplyBulkAlerts(req alerts.BulkRequest, entry *alerts.AuditEntry) ([]*alerts.Alert, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cond, values, err := bulkAlertChanges(req, entry)
+	if err != nil {
+		return nil, err
+	}
+	var changed []*alerts.Alert
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		count, err := selectBulkAlerts(tx.Model(&alerts.Alert{}), req)
+		if err != nil {
+			return err
+		}
+		if err := count.Count(&entry.Matched).Error; err != nil {
+			return err
+		}
+
+		update, err := selectBulkAlerts(tx.Model(&changed).Clauses(clause.Returning{}), req)
+		if err != nil {
+			return err
+		}
+		if cond != "" {
+			update = update.Where(cond)
+		}
+		if err := update.Updates(values).Error; err != nil {
+			return err
+		}
+
+		entry.Affected = int64(len(changed))
+		entry.AlertIDs = make([]string, 0, len(changed))
+		for _, al := range changed {
+			entry.AlertIDs = append(entry.AlertIDs, al.Id)
+		}
+		return tx.Create(entry).Error
+	})
+	if err != nil {
+		s.logger.Errorw("Failed to apply bulk alert action", "action", req.Action, "error", err)
+		return nil, err
+	}
+	return changed, nil
+}
+
+func (s *Storage) GetAlertAuditLog(limit, page int) ([]*alerts.AuditEntry, error) {
+	var entries []*alerts.AuditEntry
+	err := s.db.Order("created_at DESC, id DESC").
+		Limit(limit).Offset((page - 1) * limit).
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -10,8 +10,8 @@ import (
 	"gorm.io/gorm/clause"
 )
 
-func (s *Storage) AddAlert(alert *alerts.Alert) (int64, error) {
-	result := s.db.Save(alert)
+func (s *Storage) AddAlert(ctx context.Context, alert *alerts.Alert) (int64, error) {
+	result := s.db.WithContext(ctx).Save(alert)
 	if result.Error != nil {
 		s.logger.Error("Database Error is: ", result.Error)
 		return 0, result.Error
@@ -65,9 +65,10 @@ func (s *Storage) GetAlerts(status string, severity string, l int, p int) ([]*al
 	} else {
 		severityQuery = "%" + severity + "%"
 	}
+	// page is 1-based, the first page starts at offset 0
 	var offset int
 	if l > 0 && p > 1 {
-		offset = p * l
+		offset = (p - 1) * l
 	} else {
 		offset = -1
 	}
@@ -75,7 +76,9 @@ func (s *Storage) GetAlerts(status string, severity string, l int, p int) ([]*al
 		l = -1
 	}
 	s.logger.Info("query", statusQuery, "limit", l, "offset", offset)
-	err := s.db.Where("status LIKE ?", statusQuery).Where("severity LIKE ?", severityQuery).Limit(l).Offset(offset).Find(&as).Error
+	err := s.db.Where("status LIKE ?", statusQuery).Where("severity LIKE ?", severityQuery).
+		Order("starts_at DESC, id DESC").
+		Limit(l).Offset(offset).Find(&as).Error
 	if err != nil {
 		s.logger.Error("Error getting as from database")
 		return as, err
@@ -144,6 +147,53 @@ func (s *Storage) MarkAlertAsSent(alertID string) error {
 	return nil
 }
 
+// MarkAlertAsSilenced flags an alert whose notification was held back by a
+// maintenance window. It stays unsent so the scheduler checks it again after
+// the window ends.
+func (s *Storage) MarkAlertAsSilenced(alertID string) error {
+	result := s.db.Model(&alerts.Alert{}).
+		Where("id = ?", alertID).
+		Update("silenced", true)
+	if result.Error != nil {
+		s.logger.Errorf("failed to silence alert %s: %v", alertID, result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (s *Storage) AcknowledgeAlert(alertID, userID string, at time.Time) error {
+	result := s.db.Model(&alerts.Alert{}).
+		Where("id = ?", alertID).
+		Updates(map[string]interface{}{"acknowledged_at": at, "acknowledged_by": userID})
+	if result.Error != nil {
+		s.logger.Errorf("failed to acknowledge alert %s: %v", alertID, result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (s *Storage) SilenceAlert(alertID string, until time.Time) error {
+	result := s.db.Model(&alerts.Alert{}).
+		Where("id = ?", alertID).
+		Update("silenced_until", until)
+	if result.Error != nil {
+		s.logger.Errorf("failed to silence alert %s: %v", alertID, result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (s *Storage) ResolveAlert(alertID string, at time.Time) error {
+	result := s.db.Model(&alerts.Alert{}).
+		Where("id = ? AND status = ?", alertID, "firing").
+		Updates(map[string]interface{}{"status": "resolved", "ends_at": at, "send_notif": false, "updated_at": at})
+	if result.Error != nil {
+		s.logger.Errorf("failed to resolve alert %s: %v", alertID, result.Error)
+		return result.Error
+	}
+	return nil
+}
+
 func (s *Storage) GetUnsentAlertID(alert alerts.Alert) (string, error) {
 	// Start a new transaction
 	tx := s.db.Begin()
@@ -209,3 +259,18 @@ func (s *Storage) GetAlertByFingerPrintAndStatus(fingerPrint, status string) (*a
 	s.logger.Infof("Fetched alert %s for status %s", fingerPrint, status)
 	return alert, nil
 }
+
+// GetAlertByFingerPrintAndStartsAt returns the alert of fingerPrint that
+// started at startsAt, imports use it to skip alerts stored already.
+func (s *Storage) GetAlertByFingerPrintAndStartsAt(fingerPrint string, startsAt time.Time) (*alerts.Alert, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var alert alerts.Alert
+	result := s.db.WithContext(ctx).
+		Where("fingerprint = ? AND starts_at = ?", fingerPrint, startsAt).
+		First(&alert)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &alert, nil
+}
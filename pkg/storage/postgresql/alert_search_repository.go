@@ -0,0 +1,123 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/root-ali/iris/pkg/alerts"
+	"github.com/root-ali/iris/pkg/matchers"
+	"gorm.io/gorm"
+)
+
+// alertSearchDocument is the text searched by Query.Text, it matches the
+// expression of idx_alerts_search.
+const alertSearchDocument = "to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(description, ''))"
+
+// alertLabelColumns maps the labels LabelSet adds from the alert fields to
+// their columns, every other label is read from the labels jsonb.
+var alertLabelColumns = map[string]string{
+	"alertname": "name",
+	"severity":  "severity",
+	"status":    "status",
+}
+
+// matchAlertLabel adds the condition of m to q. A missing label matches
+// the empty string, as in Prometheus.
+func matchAlertLabel(q *gorm.DB, m *matchers.Matcher) (*gorm.DB, error) {
+	if col, ok := alertLabelColumns[m.Name]; ok {
+		switch m.Type {
+		case matchers.MatchEqual:
+			return q.Where(col+" = ?", m.Value), nil
+		case matchers.MatchNotEqual:
+			return q.Where(col+" <> ?", m.Value), nil
+		case matchers.MatchRegexp:
+			return q.Where(col+" ~ ?", "^(?:"+m.Value+")$"), nil
+		case matchers.MatchNotRegexp:
+			return q.Where(col+" !~ ?", "^(?:"+m.Value+")$"), nil
+		}
+		return nil, fmt.Errorf("unknown match type %q", m.Type)
+	}
+	switch m.Type {
+	case matchers.MatchEqual:
+		if m.Value == "" {
+			return q.Where("coalesce(labels->>?, '') = ''", m.Name), nil
+		}
+		// containment is served by idx_alerts_labels
+		b, err := json.Marshal(map[string]string{m.Name: m.Value})
+		if err != nil {
+			return nil, err
+		}
+		return q.Where("labels @> ?::jsonb", string(b)), nil
+	case matchers.MatchNotEqual:
+		return q.Where("coalesce(labels->>?, '') <> ?", m.Name, m.Value), nil
+	case matchers.MatchRegexp:
+		return q.Where("coalesce(labels->>?, '') ~ ?", m.Name, "^(?:"+m.Value+")$"), nil
+	case matchers.MatchNotRegexp:
+		return q.Where("coalesce(labels->>?, '') !~ ?", m.Name, "^(?:"+m.Value+")$"), nil
+	}
+	return nil, fmt.Errorf("unknown match type %q", m.Type)
+}
+
+func filterAlerts(q *gorm.DB, aq alerts.Query) (*gorm.DB, error) {
+	for _, m := range aq.Matchers {
+		var err error
+		if q, err = matchAlertLabel(q, m); err != nil {
+			return nil, err
+		}
+	}
+	if aq.Text != "" {
+		q = q.Where(alertSearchDocument+" @@ websearch_to_tsquery('simple', ?)", aq.Text)
+	}
+	if aq.From != nil {
+		q = q.Where("starts_at >= ?", *aq.From)
+	}
+	if aq.To != nil {
+		q = q.Where("starts_at < ?", *aq.To)
+	}
+	return q, nil
+}
+
+// SearchAlerts returns the page of alerts after q.After and the number of
+// alerts matching q regardless of the page.
+func (s *Storage) SearchAlerts(q alerts.Query) ([]*alerts.Alert, int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var total int64
+	countQuery, err := filterAlerts(s.db.WithContext(ctx).Model(&alerts.Alert{}), q)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := countQuery.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	pageQuery, err := filterAlerts(s.db.WithContext(ctx), q)
+	if err != nil {
+		return nil, 0, err
+	}
+	col := string(q.Sort)
+	dir, cmp := "ASC", ">"
+	if q.Desc {
+		dir, cmp = "DESC", "<"
+	}
+	if q.After != nil {
+		var value any = q.After.Value
+		if q.Sort.Time() {
+			// validated when the cursor was decoded
+			value, _ = time.Parse(time.RFC3339Nano, q.After.Value)
+		}
+		pageQuery = pageQuery.Where(fmt.Sprintf("(%s, id) %s (?, ?)", col, cmp), value, q.After.ID)
+	}
+	var als []*alerts.Alert
+	err = pageQuery.
+		Order(fmt.Sprintf("%s %s, id %s", col, dir, dir)).
+		Limit(q.Limit).
+		Find(&als).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return als, total, nil
+}
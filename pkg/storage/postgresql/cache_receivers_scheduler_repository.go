@@ -3,10 +3,13 @@ package postgresql
 import (
 	"database/sql"
 
+	"github.com/root-ali/iris/pkg/contactmethods"
 	"github.com/root-ali/iris/pkg/scheduler/cache_receptors"
 )
 import _ "github.com/lib/pq"
 
+// GetPerGroupIds returns one row per verified contact method of every group
+// member. Groups without members still return a row so they are cached.
 func (s *Storage) GetPerGroupIds(group ...string) ([]cache_receptors.GroupWithMobiles, error) {
 	var gms []cache_receptors.GroupWithMobiles
 	var err error
@@ -15,11 +18,9 @@ func (s *Storage) GetPerGroupIds(group ...string) ([]cache_receptors.GroupWithMo
     SELECT
         g.id   AS group_id,
         g.name AS group_name,
-        u.id AS user_id ,
-        u.mobile AS mobiles,
-    	u.email AS email,
-        u.telegram_id AS telegram_id,
-        u.mattermost_id AS mattermost_id
+        u.id AS user_id,
+        cm.type AS type,
+        cm.value AS value
     FROM groups g
              LEFT JOIN user_groups ug
                        ON ug.group_id = g.id
@@ -27,6 +28,10 @@ func (s *Storage) GetPerGroupIds(group ...string) ([]cache_receptors.GroupWithMo
              LEFT JOIN users u
                        ON u.id = ug.user_id
                            AND u.deleted_at IS NULL
+             LEFT JOIN contact_methods cm
+                       ON cm.user_id = u.id
+                           AND cm.deleted_at IS NULL
+                           AND cm.verified_at IS NOT NULL
     WHERE g.deleted_at IS NULL
     `
 
@@ -55,41 +60,30 @@ func (s *Storage) GetPerGroupIds(group ...string) ([]cache_receptors.GroupWithMo
 		var g cache_receptors.GroupWithMobiles
 
 		// Use NullString for nullable text columns to avoid NULL->string scan error.
-		var nsMobile, nsEmail, nsTelegram, nsMattermost sql.NullString
+		var nsUser, nsType, nsValue sql.NullString
 
 		err := rows.Scan(
 			&g.GroupID,
 			&g.GroupName,
-			&g.UserId,
-			&nsMobile,
-			&nsEmail,
-			&nsTelegram,
-			&nsMattermost,
+			&nsUser,
+			&nsType,
+			&nsValue,
 		)
 		if err != nil {
 			s.logger.Errorw("Failed to scan row", "error", err)
 			return nil, err
 		}
 
-		if nsMobile.Valid {
-			g.Mobile = nsMobile.String
-		} else {
-			g.Mobile = ""
-		}
-		if nsEmail.Valid {
-			g.Email = nsEmail.String
-		} else {
-			g.Email = ""
-		}
-		if nsTelegram.Valid {
-			g.TelegramID = nsTelegram.String
-		} else {
-			g.TelegramID = ""
-		}
-		if nsMattermost.Valid {
-			g.MattermostID = nsMattermost.String
-		} else {
-			g.MattermostID = ""
+		g.UserId = nsUser.String
+		switch contactmethods.Type(nsType.String) {
+		case contactmethods.TypeSMS:
+			g.Mobile = nsValue.String
+		case contactmethods.TypeMail:
+			g.Email = nsValue.String
+		case contactmethods.TypeTelegram:
+			g.TelegramID = nsValue.String
+		case contactmethods.TypeMattermost:
+			g.MattermostID = nsValue.String
 		}
 
 		gms = append(gms, g)
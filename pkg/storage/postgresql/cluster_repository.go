@@ -0,0 +1,69 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/root-ali/iris/pkg/cluster"
+	"gorm.io/gorm/clause"
+)
+
+// advisoryLocker keeps Postgres session advisory locks on a connection
+// taken out of the pool for as long as the locks are held.
+type advisoryLocker struct {
+	conn *sql.Conn
+}
+
+func (s *Storage) NewLocker(ctx context.Context) (cluster.Locker, error) {
+	db, err := s.db.DB()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &advisoryLocker{conn: conn}, nil
+}
+
+func (l *advisoryLocker) TryLock(ctx context.Context, key int64) (bool, error) {
+	var ok bool
+	err := l.conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&ok)
+	return ok, err
+}
+
+func (l *advisoryLocker) Unlock(ctx context.Context, key int64) error {
+	_, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", key)
+	return err
+}
+
+func (l *advisoryLocker) Ping(ctx context.Context) error {
+	return l.conn.PingContext(ctx)
+}
+
+func (l *advisoryLocker) Close() error {
+	return l.conn.Close()
+}
+
+func (s *Storage) SaveMember(m *cluster.Member) error {
+	result := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "instance_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"hostname", "locks", "heartbeat_at"}),
+	}).Create(m)
+	return result.Error
+}
+
+func (s *Storage) GetMembers(since time.Time) ([]*cluster.Member, error) {
+	var members []*cluster.Member
+	result := s.db.Where("heartbeat_at >= ?", since).Order("started_at").Find(&members)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return members, nil
+}
+
+func (s *Storage) DeleteMembers(before time.Time) error {
+	result := s.db.Where("heartbeat_at < ?", before).Delete(&cluster.Member{})
+	return result.Error
+}
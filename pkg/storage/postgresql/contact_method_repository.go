@@ -0,0 +1,77 @@
+package postgresql
+
+import (
+	"errors"
+
+	"github.com/root-ali/iris/pkg/contactmethods"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"gorm.io/gorm"
+)
+
+func (s *Storage) AddContactMethod(cm *contactmethods.ContactMethod) error {
+	result := s.db.Create(cm)
+	if isUniqueViolation(result.Error) {
+		return iris_error.ErrContactMethodAlreadyExists
+	}
+	if result.Error != nil {
+		s.logger.Errorw("Failed to save contact method", "userID", cm.UserID, "error", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (s *Storage) GetContactMethod(userID, id string) (*contactmethods.ContactMethod, error) {
+	var cm contactmethods.ContactMethod
+	result := s.db.First(&cm, "id = ? AND user_id = ?", id, userID)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, iris_error.ErrContactMethodNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &cm, nil
+}
+
+func (s *Storage) GetUserContactMethods(userID string) ([]*contactmethods.ContactMethod, error) {
+	var cms []*contactmethods.ContactMethod
+	result := s.db.Where("user_id = ?", userID).Order("type, created_at").Find(&cms)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return cms, nil
+}
+
+func (s *Storage) GetContactMethodByValue(t contactmethods.Type, value string) (*contactmethods.ContactMethod, error) {
+	var cm contactmethods.ContactMethod
+	result := s.db.Order("verified_at IS NULL, created_at").First(&cm, "type = ? AND value = ?", t, value)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, iris_error.ErrContactMethodNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &cm, nil
+}
+
+func (s *Storage) UpdateContactMethod(cm *contactmethods.ContactMethod) error {
+	result := s.db.Model(cm).Select(
+		"verified_at", "verification_code_hash", "verification_expires_at",
+		"verification_attempts", "modified_at",
+	).Updates(cm)
+	if result.Error != nil {
+		s.logger.Errorw("Failed to update contact method", "id", cm.ID, "error", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (s *Storage) DeleteContactMethod(userID, id string) error {
+	result := s.db.Delete(&contactmethods.ContactMethod{}, "id = ? AND user_id = ?", id, userID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return iris_error.ErrContactMethodNotFound
+	}
+	return nil
+}
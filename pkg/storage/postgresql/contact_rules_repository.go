@@ -0,0 +1,84 @@
+package postgresql
+
+import (
+	"errors"
+
+	"github.com/root-ali/iris/pkg/contactrules"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (s *Storage) GetContactRules() ([]*contactrules.Rule, error) {
+	var rules []*contactrules.Rule
+	result := s.db.Order("user_id, created_at").Find(&rules)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return rules, nil
+}
+
+func (s *Storage) GetUserContactRules(userID string) ([]*contactrules.Rule, error) {
+	var rules []*contactrules.Rule
+	result := s.db.Where("user_id = ?", userID).Order("created_at").Find(&rules)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return rules, nil
+}
+
+func (s *Storage) ReplaceUserContactRules(userID string, rules []*contactrules.Rule) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&contactrules.Rule{}).Error; err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		return tx.Create(&rules).Error
+	})
+}
+
+func (s *Storage) GetAllQuietHours() ([]*contactrules.QuietHours, error) {
+	var qs []*contactrules.QuietHours
+	result := s.db.Find(&qs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return qs, nil
+}
+
+func (s *Storage) GetUserQuietHours(userID string) (*contactrules.QuietHours, error) {
+	var q contactrules.QuietHours
+	result := s.db.First(&q, "user_id = ?", userID)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, iris_error.ErrQuietHoursNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &q, nil
+}
+
+func (s *Storage) SaveQuietHours(q *contactrules.QuietHours) error {
+	result := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"start_time", "end_time", "timezone", "bypass_severities", "modified_at"}),
+	}).Create(q)
+	if result.Error != nil {
+		s.logger.Errorw("Failed to save quiet hours", "userID", q.UserID, "error", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (s *Storage) DeleteQuietHours(userID string) error {
+	result := s.db.Delete(&contactrules.QuietHours{}, "user_id = ?", userID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return iris_error.ErrQuietHoursNotFound
+	}
+	return nil
+}
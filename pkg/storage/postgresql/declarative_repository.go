@@ -0,0 +1,21 @@
+package postgresql
+
+import (
+	"hash/fnv"
+
+	"github.com/root-ali/iris/pkg/declarative"
+	"gorm.io/gorm"
+)
+
+// DeclarativeTransaction holds a transaction advisory lock, keyed like the
+// cluster locks, until fn returns.
+func (s *Storage) DeclarativeTransaction(fn func(tx declarative.Repository) error) error {
+	h := fnv.New64a()
+	h.Write([]byte("iris:declarative"))
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", int64(h.Sum64())).Error; err != nil {
+			return err
+		}
+		return fn(&Storage{db: tx, logger: s.logger})
+	})
+}
@@ -47,10 +47,34 @@ func (s *Storage) GetAllGroups() ([]*groups.Group, error) {
 	return gs, nil
 }
 
+// DeleteGroup soft deletes the group and removes its members.
 func (s *Storage) DeleteGroup(g *groups.Group) error {
-	result := s.db.Delete(g)
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Delete(&groups.UserGroup{}, "group_id = ?", g.ID).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(g)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return iris_error.ErrGroupNotFound
+		}
+		return nil
+	})
+}
+
+func (s *Storage) UpdateGroup(g *groups.Group) error {
+	result := s.db.Model(&groups.Group{}).Where("id = ?", g.ID).Updates(map[string]any{
+		"name":        g.Name,
+		"description": g.Description,
+		"modified_at": g.ModifiedAt,
+	})
 	if result.Error != nil {
 		return result.Error
 	}
+	if result.RowsAffected == 0 {
+		return iris_error.ErrGroupNotFound
+	}
 	return nil
 }
@@ -0,0 +1,184 @@
+package postgresql
+
+import (
+	"errors"
+	"time"
+
+	"github.com/root-ali/iris/pkg/alerts"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/root-ali/iris/pkg/incidents"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type incidentAlert struct {
+	IncidentID string    `gorm:"column:incident_id"`
+	AlertID    string    `gorm:"column:alert_id"`
+	LinkedAt   time.Time `gorm:"column:linked_at"`
+}
+
+func (incidentAlert) TableName() string { return "incident_alerts" }
+
+// linkAlerts links the alerts in tx and keeps the events of the alerts
+// that were not linked yet.
+func linkAlerts(tx *gorm.DB, incidentID string, alertIDs []string, events []*incidents.Event) error {
+	linked := make(map[string]bool, len(alertIDs))
+	for _, id := range alertIDs {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&incidentAlert{IncidentID: incidentID, AlertID: id, LinkedAt: time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		linked[id] = result.RowsAffected > 0
+	}
+	for _, ev := range events {
+		if ev.Type == incidents.EventAlertLinked && !linked[ev.Body] {
+			continue
+		}
+		if err := tx.Create(ev).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Storage) CreateIncident(inc *incidents.Incident, alertIDs []string, events []*incidents.Event) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(inc).Error; err != nil {
+			return err
+		}
+		return linkAlerts(tx, inc.ID, alertIDs, events)
+	})
+}
+
+func (s *Storage) GetIncident(id string) (*incidents.Incident, error) {
+	var inc incidents.Incident
+	result := s.db.First(&inc, "id = ?", id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, iris_error.ErrIncidentNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &inc, nil
+}
+
+func (s *Storage) GetIncidents(status string, limit, page int) ([]*incidents.Incident, error) {
+	var incs []*incidents.Incident
+	q := s.db.Order("created_at DESC").Limit(limit).Offset((page - 1) * limit)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if err := q.Find(&incs).Error; err != nil {
+		return nil, err
+	}
+	return incs, nil
+}
+
+func (s *Storage) GetOpenIncidentByGroupKey(key string) (*incidents.Incident, error) {
+	var inc incidents.Incident
+	result := s.db.Where("group_key = ? AND status <> ?", key, incidents.StatusResolved).First(&inc)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, iris_error.ErrIncidentNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &inc, nil
+}
+
+// GetIncidentByAlertID returns the latest incident the alert is linked to.
+func (s *Storage) GetIncidentByAlertID(alertID string) (*incidents.Incident, error) {
+	var inc incidents.Incident
+	result := s.db.
+		Joins("JOIN incident_alerts ON incident_alerts.incident_id = incidents.id").
+		Where("incident_alerts.alert_id = ?", alertID).
+		Order("incidents.created_at DESC").
+		First(&inc)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, iris_error.ErrIncidentNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &inc, nil
+}
+
+func (s *Storage) UpdateIncident(inc *incidents.Incident, events []*incidents.Event) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&incidents.Incident{}).
+			Where("id = ?", inc.ID).
+			Updates(map[string]interface{}{
+				"status":          inc.Status,
+				"assignee":        gorm.Expr("NULLIF(?, '')", inc.Assignee),
+				"acknowledged_at": inc.AcknowledgedAt,
+				"resolved_at":     inc.ResolvedAt,
+				"updated_at":      inc.UpdatedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return iris_error.ErrIncidentNotFound
+		}
+		for _, ev := range events {
+			if err := tx.Create(ev).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Storage) AddIncidentEvents(events []*incidents.Event) error {
+	return s.db.Create(events).Error
+}
+
+func (s *Storage) LinkIncidentAlerts(incidentID string, alertIDs []string, events []*incidents.Event) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return linkAlerts(tx, incidentID, alertIDs, events)
+	})
+}
+
+func (s *Storage) GetIncidentAlerts(incidentID string) ([]*alerts.Alert, error) {
+	var als []*alerts.Alert
+	result := s.db.
+		Joins("JOIN incident_alerts ON incident_alerts.alert_id = alerts.id").
+		Where("incident_alerts.incident_id = ?", incidentID).
+		Order("alerts.starts_at").
+		Find(&als)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return als, nil
+}
+
+func (s *Storage) GetIncidentEvents(incidentID string) ([]incidents.Event, error) {
+	var events []incidents.Event
+	if err := s.db.Where("incident_id = ?", incidentID).Order("created_at").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (s *Storage) CountFiringIncidentAlerts(incidentID string) (int64, error) {
+	var count int64
+	result := s.db.Model(&alerts.Alert{}).
+		Joins("JOIN incident_alerts ON incident_alerts.alert_id = alerts.id").
+		Where("incident_alerts.incident_id = ? AND alerts.status = ?", incidentID, "firing").
+		Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+// AcknowledgeIncidentAlerts acknowledges the linked alerts that are not
+// acknowledged yet.
+func (s *Storage) AcknowledgeIncidentAlerts(incidentID, userID string, at time.Time) error {
+	result := s.db.Model(&alerts.Alert{}).
+		Where("id IN (?)", s.db.Model(&incidentAlert{}).Select("alert_id").Where("incident_id = ?", incidentID)).
+		Where("acknowledged_at IS NULL").
+		Updates(map[string]interface{}{"acknowledged_at": at, "acknowledged_by": userID})
+	return result.Error
+}
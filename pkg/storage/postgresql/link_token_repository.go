@@ -0,0 +1,33 @@
+package postgresql
+
+import (
+	"time"
+
+	"github.com/root-ali/iris/pkg/chatops"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"gorm.io/gorm/clause"
+)
+
+func (s *Storage) AddLinkToken(t *chatops.LinkToken) error {
+	result := s.db.Create(t)
+	if result.Error != nil {
+		return result.Error
+	}
+	return nil
+}
+
+// ConsumeLinkToken marks an unused, unexpired token as used and returns it.
+func (s *Storage) ConsumeLinkToken(hash, provider string, at time.Time) (*chatops.LinkToken, error) {
+	var tokens []*chatops.LinkToken
+	result := s.db.Model(&tokens).
+		Clauses(clause.Returning{}).
+		Where("token_hash = ? AND provider = ? AND used_at IS NULL AND expires_at > ?", hash, provider, at).
+		Update("used_at", at)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(tokens) == 0 {
+		return nil, iris_error.ErrLinkTokenInvalid
+	}
+	return tokens[0], nil
+}
@@ -0,0 +1,74 @@
+package postgresql
+
+import (
+	"errors"
+
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/root-ali/iris/pkg/maintenance"
+	"gorm.io/gorm"
+)
+
+func (s *Storage) AddMaintenanceWindow(w *maintenance.Window) error {
+	result := s.db.Create(w)
+	if result.Error != nil {
+		s.logger.Errorw("Failed to save maintenance window", "name", w.Name, "error", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (s *Storage) GetMaintenanceWindow(id string) (*maintenance.Window, error) {
+	var w maintenance.Window
+	result := s.db.First(&w, "id = ?", id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, iris_error.ErrMaintenanceWindowNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &w, nil
+}
+
+func (s *Storage) GetMaintenanceWindows() ([]*maintenance.Window, error) {
+	var ws []*maintenance.Window
+	result := s.db.Order("starts_at asc").Find(&ws)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return ws, nil
+}
+
+func (s *Storage) UpdateMaintenanceWindow(w *maintenance.Window) error {
+	result := s.db.Model(&maintenance.Window{}).Where("id = ?", w.ID).Updates(map[string]any{
+		"name":             w.Name,
+		"description":      w.Description,
+		"schedule_type":    w.ScheduleType,
+		"schedule":         w.Schedule,
+		"duration_seconds": w.DurationSeconds,
+		"timezone":         w.Timezone,
+		"matchers":         w.Matchers,
+		"groups":           w.Groups,
+		"starts_at":        w.StartsAt,
+		"ends_at":          w.EndsAt,
+		"modified_at":      w.ModifiedAt,
+	})
+	if result.Error != nil {
+		s.logger.Errorw("Failed to update maintenance window", "name", w.Name, "error", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return iris_error.ErrMaintenanceWindowNotFound
+	}
+	return nil
+}
+
+func (s *Storage) DeleteMaintenanceWindow(id string) error {
+	result := s.db.Delete(&maintenance.Window{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return iris_error.ErrMaintenanceWindowNotFound
+	}
+	return nil
+}
@@ -2,9 +2,12 @@ package postgresql
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	iris_error "github.com/root-ali/iris/pkg/errors"
 	"github.com/root-ali/iris/pkg/message"
+	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
@@ -89,3 +92,129 @@ func (s *Storage) ListNotFinishedMessages() ([]message.Message, error) {
 
 	return msgs, nil
 }
+
+// GetMessageBySenderID returns the latest message sent by sender with the
+// provider's own message ID.
+func (s *Storage) GetMessageBySenderID(sender, senderID string) (*message.Message, error) {
+	var m message.Message
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result := s.db.Table("message").
+		WithContext(ctx).
+		Where("sender = ? AND sender_id = ?", sender, senderID).
+		Order("created_at DESC").
+		First(&m)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, iris_error.ErrMessageNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &m, nil
+}
+
+func filterMessages(q *gorm.DB, f message.Filter) *gorm.DB {
+	if f.UserID != "" {
+		q = q.Where("user_id = ?", f.UserID)
+	}
+	if f.Group != "" {
+		q = q.Where("group_name = ?", f.Group)
+	}
+	if f.Provider != "" {
+		q = q.Where("sender = ?", f.Provider)
+	}
+	if f.Status != "" {
+		q = q.Where("status = ?", f.Status)
+	}
+	if f.From != nil {
+		q = q.Where("created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		q = q.Where("created_at < ?", *f.To)
+	}
+	return q
+}
+
+// SearchMessages returns a page of the filtered messages, newest first, and
+// the number of messages matching the filter.
+func (s *Storage) SearchMessages(f message.Filter) ([]*message.Message, int64, error) {
+	var msgs []*message.Message
+	var total int64
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := filterMessages(s.db.WithContext(ctx).Table("message"), f).
+		Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := 0
+	if f.Page > 1 {
+		offset = (f.Page - 1) * f.Limit
+	}
+	result := filterMessages(s.db.WithContext(ctx).Table("message"), f).
+		Order("created_at DESC").
+		Limit(f.Limit).
+		Offset(offset).
+		Find(&msgs)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return msgs, total, nil
+}
+
+func (s *Storage) GetMessagesByAlertIDs(alertIDs []string) ([]*message.Message, error) {
+	var msgs []*message.Message
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	result := s.db.WithContext(ctx).Table("message").
+		Where("alert_id IN ?", alertIDs).
+		Order("created_at ASC").
+		Find(&msgs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return msgs, nil
+}
+
+// MessageProviderStats counts the filtered messages by status per provider,
+// the row with an empty provider holds the totals.
+func (s *Storage) MessageProviderStats(f message.Filter) ([]message.ProviderStats, error) {
+	var stats []message.ProviderStats
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	result := filterMessages(s.db.WithContext(ctx).Table("message"), f).
+		Select(`CASE WHEN GROUPING(sender) = 1 THEN '' ELSE sender END AS provider,
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE status = 'Pending') AS pending,
+			COUNT(*) FILTER (WHERE status = 'Sent') AS sent,
+			COUNT(*) FILTER (WHERE status = 'Delivered') AS delivered,
+			COUNT(*) FILTER (WHERE status = 'Failed') AS failed,
+			COALESCE(PERCENTILE_CONT(0.5) WITHIN GROUP (
+				ORDER BY EXTRACT(EPOCH FROM updated_at - created_at)
+			) FILTER (WHERE status = 'Delivered'), 0) AS median_time_to_deliver`).
+		Group("GROUPING SETS ((sender), ())").
+		Order("provider").
+		Scan(&stats)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return stats, nil
+}
+
+// MessageFailureStats returns the most common provider responses of the
+// filtered failed messages.
+func (s *Storage) MessageFailureStats(f message.Filter, limit int) ([]message.FailureStats, error) {
+	var failures []message.FailureStats
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	result := filterMessages(s.db.WithContext(ctx).Table("message"), f).
+		Select("sender AS provider, COALESCE(response, '') AS error, COUNT(*) AS count").
+		Where("status = ?", "Failed").
+		Group("sender, response").
+		Order("count DESC").
+		Limit(limit).
+		Scan(&failures)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return failures, nil
+}
@@ -0,0 +1,57 @@
+package postgresql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+func (s *Storage) Notify(channel, payload string) error {
+	return s.db.Exec("SELECT pg_notify(?, ?)", channel, payload).Error
+}
+
+// Listen takes a connection out of the pool and waits for notifications
+// on channel. The connection is closed when ctx is done, so it never goes
+// back to the pool while listening.
+func (s *Storage) Listen(ctx context.Context, channel string, fn func(payload string)) error {
+	db, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return errors.New("listen needs a pgx connection")
+		}
+		pc := c.Conn()
+		if _, err := pc.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			return err
+		}
+		defer func() {
+			if pc.IsClosed() {
+				return
+			}
+			uctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if _, err := pc.Exec(uctx, "UNLISTEN *"); err != nil {
+				s.logger.Errorw("Failed to unlisten", "channel", channel, "error", err)
+			}
+		}()
+		s.logger.Infow("Listening for notifications", "channel", channel)
+		for {
+			n, err := pc.WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+			fn(n.Payload)
+		}
+	})
+}
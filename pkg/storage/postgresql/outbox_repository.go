@@ -0,0 +1,112 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"github.com/root-ali/iris/pkg/alerts"
+	"github.com/root-ali/iris/pkg/message"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EnqueueAlertMessages stores the pending messages of an alert and marks the
+// alert as sent in one transaction. Alerts another replica is queueing or
+// has already queued are skipped, and messages with a known idempotency key
+// are not stored twice.
+func (s *Storage) EnqueueAlertMessages(ctx context.Context, alertID string, msgs []*message.Message) (int64, error) {
+	var queued int64
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var als []alerts.Alert
+		if err := tx.Where("id = ? AND send_notif = ?", alertID, false).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Find(&als).Error; err != nil {
+			return err
+		}
+		if len(als) == 0 {
+			return nil
+		}
+		if len(msgs) > 0 {
+			result := tx.Table("message").
+				Clauses(clause.OnConflict{DoNothing: true}).
+				Create(&msgs)
+			if result.Error != nil {
+				return result.Error
+			}
+			queued = result.RowsAffected
+		}
+		return tx.Model(&alerts.Alert{}).
+			Where("id = ?", alertID).
+			Updates(map[string]interface{}{"send_notif": true, "silenced": false}).Error
+	})
+	if err != nil {
+		s.logger.Errorw("Failed to enqueue alert messages", "alertID", alertID, "error", err)
+		return 0, err
+	}
+	return queued, nil
+}
+
+// dispatchLease hides a claimed message from other dispatchers while it is
+// sent. A dispatcher that dies mid-send leaves the message pending and it is
+// picked up again once the lease runs out.
+const dispatchLease = 5 * time.Minute
+
+// DispatchPendingMessage claims the oldest pending message that is due with
+// FOR UPDATE SKIP LOCKED and leases it by moving next_attempt_at past the
+// send. The claim commits before send is called, so no connection is held
+// while the provider is slow or rate limited. The result is stored in a
+// second transaction, unless the lease ran out and another dispatcher has
+// claimed the message since.
+func (s *Storage) DispatchPendingMessage(send func(msg *message.Message)) (bool, error) {
+	var m *message.Message
+	lease := time.Now().Add(dispatchLease).Truncate(time.Microsecond)
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var msgs []*message.Message
+		if err := tx.Table("message").
+			Where("status = ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?)",
+				message.StatusMap[message.TypeMessageStatusPending], time.Now()).
+			Order("created_at").
+			Limit(1).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Find(&msgs).Error; err != nil {
+			return err
+		}
+		if len(msgs) == 0 {
+			return nil
+		}
+		m = msgs[0]
+		return tx.Table("message").
+			Where("id = ?", m.Id).
+			Update("next_attempt_at", lease).Error
+	})
+	if err != nil {
+		s.logger.Errorw("Failed to claim pending message", "error", err)
+		return false, err
+	}
+	if m == nil {
+		return false, nil
+	}
+
+	send(m)
+
+	result := s.db.Table("message").
+		Where("id = ? AND next_attempt_at = ?", m.Id, lease).
+		Updates(map[string]interface{}{
+			"sender_id":       m.SenderId,
+			"status":          m.Status,
+			"response":        m.Response,
+			"attempt":         m.Attempt,
+			"last_attempt":    m.LastAttempt,
+			"next_attempt_at": m.NextAttemptAt,
+			"updated_at":      time.Now(),
+		})
+	if result.Error != nil {
+		s.logger.Errorw("Failed to store the result of a sent message", "message", m.Id, "error", result.Error)
+		return true, result.Error
+	}
+	if result.RowsAffected == 0 {
+		s.logger.Warnw("Message lease ran out before its send finished, the result is dropped",
+			"message", m.Id, "provider", m.Sender)
+	}
+	return true, nil
+}
@@ -1,9 +1,12 @@
 package postgresql
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/root-ali/iris/pkg/tracing"
 	"go.uber.org/zap"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -42,6 +45,10 @@ func New(l *zap.SugaredLogger, p *Postgres) *Storage {
 	if err != nil {
 		l.Panic("Failed to connect to the Database")
 	}
+	// Queries made with the context of a traced operation get a span
+	if err := DB.Use(tracing.GormPlugin{}); err != nil {
+		l.Errorw("Failed to register tracing plugin", "error", err)
+	}
 	sqlDB, err := DB.DB()
 	if err != nil {
 		return nil
@@ -76,3 +83,28 @@ func (s *Storage) Health() error {
 	}
 	return nil
 }
+
+// Migrated fails until golang-migrate has applied a migration and while
+// one is half applied, for example by another replica starting up.
+func (s *Storage) Migrated() error {
+	var m struct {
+		Version int64
+		Dirty   bool
+	}
+	if err := s.db.Raw("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&m).Error; err != nil {
+		return err
+	}
+	if m.Version == 0 {
+		return errors.New("no migration applied")
+	}
+	if m.Dirty {
+		return fmt.Errorf("migration %d is dirty", m.Version)
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether err is a postgres unique_violation.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
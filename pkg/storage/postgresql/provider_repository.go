@@ -4,6 +4,7 @@ import (
 	"github.com/root-ali/iris/pkg/errors"
 	"github.com/root-ali/iris/pkg/notifications"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 func (s *Storage) AddProvider(p *notifications.Providers) error {
@@ -60,3 +61,23 @@ func (s *Storage) GetProviders() ([]notifications.Providers, error) {
 	}
 	return providers, nil
 }
+
+// UpdateProviderSettings sets the priority and the status of the provider
+// named p.Name, unlike ModifyProvider it can disable it. Servers watching
+// notifications.ProvidersChannel are told when the change commits.
+func (s *Storage) UpdateProviderSettings(p *notifications.Providers) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&notifications.Providers{}).Where("name = ?", p.Name).Updates(map[string]any{
+			"priority":  p.Priority,
+			"is_active": p.Status,
+		})
+		if result.Error != nil {
+			s.logger.Errorw("UpdateProviderSettings error", "error", result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.ErrProviderNotFound
+		}
+		return tx.Exec("SELECT pg_notify(?, ?)", notifications.ProvidersChannel, p.Name).Error
+	})
+}
@@ -0,0 +1,105 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+
+	"github.com/root-ali/iris/pkg/reports"
+)
+
+// reportGroup is the SQL expression of the label alerts are grouped by.
+func reportGroup(groupBy string) string {
+	switch groupBy {
+	case "":
+		return "''"
+	case "alertname":
+		return "name"
+	case "severity":
+		return "severity"
+	default:
+		return "COALESCE(labels->>@label, '')"
+	}
+}
+
+func reportArgs(q reports.Query) map[string]any {
+	return map[string]any{
+		"period": string(q.Period),
+		"label":  q.GroupBy,
+		"from":   q.From,
+		"to":     q.To,
+		"top":    q.Top,
+	}
+}
+
+// AlertResponseStats averages the time to acknowledge and to resolve the
+// alerts per period and group.
+func (s *Storage) AlertResponseStats(q reports.Query) ([]reports.GroupStats, error) {
+	var stats []reports.GroupStats
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	query := `
+		SELECT date_trunc(@period, starts_at) AS period,
+			` + reportGroup(q.GroupBy) + ` AS "group",
+			COUNT(*) AS alerts,
+			COUNT(acknowledged_at) AS acknowledged,
+			COUNT(*) FILTER (WHERE status = 'resolved') AS resolved,
+			COALESCE(AVG(EXTRACT(EPOCH FROM acknowledged_at - starts_at))
+				FILTER (WHERE acknowledged_at >= starts_at), 0) AS mtta,
+			COALESCE(AVG(EXTRACT(EPOCH FROM ends_at - starts_at))
+				FILTER (WHERE status = 'resolved' AND ends_at >= starts_at), 0) AS mttr
+		FROM alerts
+		WHERE deleted_at IS NULL AND starts_at >= @from AND starts_at < @to
+		GROUP BY 1, 2
+		ORDER BY 1, 2`
+	if err := s.db.WithContext(ctx).Raw(query, reportArgs(q)).Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// NoisyAlerts returns the alert names that fired the most in each period.
+func (s *Storage) NoisyAlerts(q reports.Query) ([]reports.NoisyAlert, error) {
+	var noisy []reports.NoisyAlert
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	query := `
+		SELECT period, name, "group", count FROM (
+			SELECT period, name, "group", count,
+				ROW_NUMBER() OVER (PARTITION BY period ORDER BY count DESC, name) AS rank
+			FROM (
+				SELECT date_trunc(@period, starts_at) AS period, name,
+					` + reportGroup(q.GroupBy) + ` AS "group",
+					COUNT(*) AS count
+				FROM alerts
+				WHERE deleted_at IS NULL AND starts_at >= @from AND starts_at < @to
+				GROUP BY 1, 2, 3
+			) counted
+		) ranked
+		WHERE rank <= @top
+		ORDER BY period, count DESC, name`
+	if err := s.db.WithContext(ctx).Raw(query, reportArgs(q)).Scan(&noisy).Error; err != nil {
+		return nil, err
+	}
+	return noisy, nil
+}
+
+// PagesPerUser counts the alert notifications that went out to each user,
+// failed sends are left out.
+func (s *Storage) PagesPerUser(q reports.Query) ([]reports.Pages, error) {
+	var pages []reports.Pages
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	query := `
+		SELECT date_trunc(@period, m.created_at) AS period,
+			m.user_id, COALESCE(u.user_name, '') AS username, COUNT(*) AS pages
+		FROM message m
+		LEFT JOIN users u ON u.id = m.user_id
+		WHERE COALESCE(m.alert_id, '') <> '' AND m.status <> 'Failed'
+			AND m.created_at >= @from AND m.created_at < @to
+		GROUP BY 1, 2, 3
+		ORDER BY 1, 4 DESC, 3`
+	if err := s.db.WithContext(ctx).Raw(query, reportArgs(q)).Scan(&pages).Error; err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
@@ -0,0 +1,130 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/root-ali/iris/pkg/scheduler/janitor"
+)
+
+// alertPartitionPrefix names the monthly partitions of alerts, such as
+// alerts_p2026_10.
+const alertPartitionPrefix = "alerts_p"
+
+func alertPartition(month time.Time) string {
+	return alertPartitionPrefix + month.Format("2006_01")
+}
+
+func (s *Storage) ExpiredAlerts(ctx context.Context, resolvedBefore, deletedBefore time.Time, limit int) ([]janitor.Row, error) {
+	var conds []string
+	var args []interface{}
+	if !resolvedBefore.IsZero() {
+		// an alert resolved before the cutoff was created before it too,
+		// the created_at condition prunes the recent partitions
+		conds = append(conds, "(a.status = 'resolved' AND a.created_at < ? AND a.updated_at < ?)")
+		args = append(args, resolvedBefore, resolvedBefore)
+	}
+	if !deletedBefore.IsZero() {
+		conds = append(conds, "a.deleted_at < ?")
+		args = append(args, deletedBefore)
+	}
+	if len(conds) == 0 {
+		return nil, nil
+	}
+	var rows []janitor.Row
+	err := s.db.WithContext(ctx).
+		Table("alerts AS a").
+		Select("a.id, row_to_json(a)::text AS doc").
+		Where(strings.Join(conds, " OR "), args...).
+		Order("a.created_at").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (s *Storage) ExpiredMessages(ctx context.Context, before time.Time, limit int) ([]janitor.Row, error) {
+	var rows []janitor.Row
+	err := s.db.WithContext(ctx).
+		Table("message AS m").
+		Select("m.id, row_to_json(m)::text AS doc").
+		Where("m.created_at < ? AND m.status <> ?", before, "Pending").
+		Order("m.created_at").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// DeleteRows hard deletes the rows, soft deleted alerts included.
+func (s *Storage) DeleteRows(ctx context.Context, table string, ids []string) (int64, error) {
+	if table != janitor.TableAlerts && table != janitor.TableMessages {
+		return 0, fmt.Errorf("unknown table %q", table)
+	}
+	result := s.db.WithContext(ctx).Exec("DELETE FROM "+table+" WHERE id IN ?", ids)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+func (s *Storage) EnsureAlertPartitions(ctx context.Context, from time.Time, months int) error {
+	// created_at and the partition bounds are wall clock times
+	start := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i <= months; i++ {
+		m := start.AddDate(0, i, 0)
+		err := s.db.WithContext(ctx).Exec(fmt.Sprintf(
+			"CREATE TABLE IF NOT EXISTS %s PARTITION OF alerts FOR VALUES FROM ('%s') TO ('%s')",
+			pgx.Identifier{alertPartition(m)}.Sanitize(),
+			m.Format("2006-01-02"), m.AddDate(0, 1, 0).Format("2006-01-02"))).Error
+		if err != nil {
+			return fmt.Errorf("partition %s: %w", alertPartition(m), err)
+		}
+	}
+	return nil
+}
+
+func (s *Storage) DropEmptyAlertPartitions(ctx context.Context, before time.Time) ([]string, error) {
+	var names []string
+	err := s.db.WithContext(ctx).Raw(`SELECT c.relname FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		JOIN pg_class p ON p.oid = i.inhparent
+		WHERE p.relname = 'alerts'`).Scan(&names).Error
+	if err != nil {
+		return nil, err
+	}
+	// created_at and the partition bounds are wall clock times
+	before = time.Date(before.Year(), before.Month(), before.Day(), before.Hour(), before.Minute(), 0, 0, time.UTC)
+	var dropped []string
+	for _, name := range names {
+		suffix, ok := strings.CutPrefix(name, alertPartitionPrefix)
+		if !ok {
+			// the default partition
+			continue
+		}
+		m, err := time.Parse("2006_01", suffix)
+		if err != nil || m.AddDate(0, 1, 0).After(before) {
+			continue
+		}
+		table := pgx.Identifier{name}.Sanitize()
+		var exists bool
+		if err := s.db.WithContext(ctx).Raw("SELECT EXISTS (SELECT 1 FROM " + table + ")").Scan(&exists).Error; err != nil {
+			return dropped, err
+		}
+		if exists {
+			continue
+		}
+		if err := s.db.WithContext(ctx).Exec("DROP TABLE " + table).Error; err != nil {
+			return dropped, err
+		}
+		dropped = append(dropped, name)
+	}
+	return dropped, nil
+}
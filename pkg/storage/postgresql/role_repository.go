@@ -44,3 +44,13 @@ func (s *Storage) GetRoleName(role *roles.Role) error {
 		"rolename", role.Name)
 	return nil
 }
+
+func (s *Storage) GetRoles() ([]*roles.Role, error) {
+	var rs []*roles.Role
+	result := s.db.Find(&rs)
+	if result.Error != nil {
+		s.logger.Errorw("cannot get roles from database", "error", result.Error)
+		return nil, result.Error
+	}
+	return rs, nil
+}
@@ -0,0 +1,95 @@
+package postgresql
+
+import (
+	"errors"
+	"time"
+
+	"github.com/root-ali/iris/pkg/alerts"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/root-ali/iris/pkg/incidents"
+	"github.com/root-ali/iris/pkg/statuspage"
+	"gorm.io/gorm"
+)
+
+// statusPageIncidentLimit bounds the incidents listed on the public page.
+const statusPageIncidentLimit = 50
+
+func (s *Storage) AddStatusComponent(c *statuspage.Component) error {
+	result := s.db.Create(c)
+	if result.Error != nil {
+		s.logger.Errorw("Failed to save status component", "name", c.Name, "error", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (s *Storage) GetStatusComponent(id string) (*statuspage.Component, error) {
+	var c statuspage.Component
+	result := s.db.First(&c, "id = ?", id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, iris_error.ErrStatusComponentNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &c, nil
+}
+
+func (s *Storage) GetStatusComponents() ([]*statuspage.Component, error) {
+	var cs []*statuspage.Component
+	result := s.db.Order("position asc, name asc").Find(&cs)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return cs, nil
+}
+
+func (s *Storage) UpdateStatusComponent(c *statuspage.Component) error {
+	result := s.db.Model(&statuspage.Component{}).Where("id = ?", c.ID).Updates(map[string]interface{}{
+		"name":        c.Name,
+		"description": c.Description,
+		"matchers":    c.Matchers,
+		"position":    c.Position,
+		"modified_at": c.ModifiedAt,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return iris_error.ErrStatusComponentNotFound
+	}
+	return nil
+}
+
+func (s *Storage) DeleteStatusComponent(id string) error {
+	result := s.db.Delete(&statuspage.Component{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return iris_error.ErrStatusComponentNotFound
+	}
+	return nil
+}
+
+func (s *Storage) GetFiringAlerts() ([]*alerts.Alert, error) {
+	var as []*alerts.Alert
+	if err := s.db.Where("status = ?", "firing").Find(&as).Error; err != nil {
+		return nil, err
+	}
+	return as, nil
+}
+
+// GetIncidentsSince returns the incidents opened since the given time and
+// the ones still open, newest first.
+func (s *Storage) GetIncidentsSince(since time.Time) ([]*incidents.Incident, error) {
+	var incs []*incidents.Incident
+	err := s.db.Where("created_at >= ? OR status <> ?", since, incidents.StatusResolved).
+		Order("created_at DESC").
+		Limit(statusPageIncidentLimit).
+		Find(&incs).Error
+	if err != nil {
+		return nil, err
+	}
+	return incs, nil
+}
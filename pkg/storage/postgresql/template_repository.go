@@ -0,0 +1,69 @@
+package postgresql
+
+import (
+	"errors"
+
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/root-ali/iris/pkg/templates"
+	"gorm.io/gorm"
+)
+
+func (s *Storage) GetTemplates() ([]*templates.Template, error) {
+	var ts []*templates.Template
+	result := s.db.Order("name asc").Find(&ts)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return ts, nil
+}
+
+func (s *Storage) GetTemplate(id string) (*templates.Template, error) {
+	var t templates.Template
+	result := s.db.First(&t, "id = ?", id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, iris_error.ErrTemplateNotFound
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &t, nil
+}
+
+func (s *Storage) AddTemplate(t *templates.Template) error {
+	result := s.db.Create(t)
+	if result.Error != nil {
+		s.logger.Errorw("Failed to save notification template", "name", t.Name, "error", result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+func (s *Storage) UpdateTemplate(t *templates.Template) error {
+	result := s.db.Model(&templates.Template{}).Where("id = ?", t.ID).Updates(map[string]any{
+		"name":        t.Name,
+		"description": t.Description,
+		"matchers":    t.Matchers,
+		"subject":     t.Subject,
+		"body":        t.Body,
+		"modified_at": t.ModifiedAt,
+	})
+	if result.Error != nil {
+		s.logger.Errorw("Failed to update notification template", "name", t.Name, "error", result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return iris_error.ErrTemplateNotFound
+	}
+	return nil
+}
+
+func (s *Storage) DeleteTemplate(id string) error {
+	result := s.db.Delete(&templates.Template{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return iris_error.ErrTemplateNotFound
+	}
+	return nil
+}
@@ -16,7 +16,8 @@ func (s *Storage) AddUserToGroup(userID, groupID string) error {
 }
 
 func (s *Storage) RemoveUserFromGroup(userID, groupID string) error {
-	result := s.db.Delete(&groups.UserGroup{}, "uid = ? AND gid = ?", userID, groupID)
+	// memberships are removed for good, the pair is the primary key
+	result := s.db.Unscoped().Delete(&groups.UserGroup{}, "user_id = ? AND group_id = ?", userID, groupID)
 	if result.Error != nil {
 		s.logger.Errorf("Failed to remove user group from user_group table: %v", result.Error)
 		return result.Error
@@ -44,3 +45,13 @@ func (s *Storage) FindUsersById(groupID string) ([]string, error) {
 	}
 	return userIDs, nil
 }
+
+func (s *Storage) GetGroupMembers() ([]*groups.UserGroup, error) {
+	var members []*groups.UserGroup
+	result := s.db.Order("group_id, created_at").Find(&members)
+	if result.Error != nil {
+		s.logger.Errorf("Failed to get user groups from user_group table: %v", result.Error)
+		return nil, result.Error
+	}
+	return members, nil
+}
@@ -1,17 +1,24 @@
 package postgresql
 
 import (
+	"time"
+
+	"github.com/root-ali/iris/pkg/contactmethods"
+	"github.com/root-ali/iris/pkg/groups"
 	"github.com/root-ali/iris/pkg/user"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
-func (s *Storage) AddUser(user *user.User) error {
-	result := s.db.Save(user)
-	if result.Error != nil {
-		s.logger.Error("Error saving user", zap.Error(result.Error))
-		return result.Error
-	}
-	return nil
+func (s *Storage) AddUser(u *user.User) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Save(u)
+		if result.Error != nil {
+			s.logger.Error("Error saving user", zap.Error(result.Error))
+			return result.Error
+		}
+		return s.syncPrimaryContactMethods(tx, u.ID, true)
+	})
 }
 
 func (s *Storage) GetUserByID(id string) (*user.User, error) {
@@ -57,8 +64,22 @@ func (s *Storage) UpdateUserPassword(_ string, _ string) error {
 	return nil
 }
 
-func (s *Storage) DeleteUser(_ string) error {
-	return nil
+// DeleteUser soft deletes the user and removes it from its groups.
+func (s *Storage) DeleteUser(id string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Delete(&groups.UserGroup{}, "user_id = ?", id).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&user.User{}, "id = ?", id)
+		if result.Error != nil {
+			s.logger.Error("Error deleting user", zap.Error(result.Error))
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
 }
 
 func (s *Storage) GetRole(u *user.User) error {
@@ -83,10 +104,87 @@ func (s *Storage) VerifyUser(u *user.User) error {
 
 func (s *Storage) UpdateUserData(u *user.User) error {
 	s.logger.Debugw("updating user data", "user_id", u.ID, "username", u.UserName)
-	result := s.db.Model(&user.User{}).Where("user_name = ?", u.UserName).Updates(u)
-	if result.Error != nil {
-		s.logger.Error("Error updating user data", zap.Error(result.Error))
-		return result.Error
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&user.User{}).Where("user_name = ?", u.UserName).Updates(u)
+		if result.Error != nil {
+			s.logger.Error("Error updating user data", zap.Error(result.Error))
+			return result.Error
+		}
+		var cur user.User
+		if err := tx.Select("id").First(&cur, "user_name = ?", u.UserName).Error; err != nil {
+			return err
+		}
+		return s.syncPrimaryContactMethods(tx, cur.ID, false)
+	})
+}
+
+// UpdateUserProfile sets the profile fields and role of the user with the
+// id of u, empty values clear them.
+func (s *Storage) UpdateUserProfile(u *user.User) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&user.User{}).Where("id = ?", u.ID).Updates(map[string]any{
+			"first_name":    u.FirstName,
+			"last_name":     u.LastName,
+			"email":         u.Email,
+			"mobile":        u.Mobile,
+			"telegram_id":   u.TelegramID,
+			"mattermost_id": u.MattermostId,
+			"role_id":       u.Role,
+			"modified_at":   u.ModifiedAt,
+		})
+		if result.Error != nil {
+			s.logger.Error("Error updating user profile", zap.Error(result.Error))
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return s.syncPrimaryContactMethods(tx, u.ID, true)
+	})
+}
+
+// syncPrimaryContactMethods makes the contact methods the user is paged on
+// follow its contact columns, which every user write sets. Only admin writes
+// are verified, values users set themselves wait for a verification code.
+func (s *Storage) syncPrimaryContactMethods(tx *gorm.DB, userID string, verified bool) error {
+	var u user.User
+	if err := tx.First(&u, "id = ?", userID).Error; err != nil {
+		return err
+	}
+	var current []*contactmethods.ContactMethod
+	if err := tx.Where("user_id = ?", userID).Find(&current).Error; err != nil {
+		return err
+	}
+	sync, err := contactmethods.SyncPrimary(userID, map[contactmethods.Type]string{
+		contactmethods.TypeSMS:        u.Mobile,
+		contactmethods.TypeMail:       u.Email,
+		contactmethods.TypeTelegram:   u.TelegramID,
+		contactmethods.TypeMattermost: u.MattermostId,
+	}, current, verified, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, cm := range sync.Remove {
+		if err := tx.Delete(cm).Error; err != nil {
+			return err
+		}
+	}
+	for _, cm := range sync.Verify {
+		if err := tx.Model(cm).Select(
+			"verified_at", "verification_code_hash", "verification_expires_at",
+			"verification_attempts", "modified_at",
+		).Updates(cm).Error; err != nil {
+			return err
+		}
+	}
+	for _, cm := range sync.Add {
+		if err := tx.Create(cm).Error; err != nil {
+			return err
+		}
+	}
+	if n := len(sync.Remove) + len(sync.Verify) + len(sync.Add); n > 0 {
+		s.logger.Infow("Synced primary contact methods", "user_id", userID,
+			"removed", len(sync.Remove), "verified", len(sync.Verify), "added", len(sync.Add))
 	}
 	return nil
 }
@@ -0,0 +1,166 @@
+package templates
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/root-ali/iris/pkg/cache"
+	iris_error "github.com/root-ali/iris/pkg/errors"
+	"github.com/root-ali/iris/pkg/matchers"
+	"github.com/root-ali/iris/pkg/util"
+	"go.uber.org/zap"
+)
+
+func NewService(repo Repository, c cache.Interface[string, []*Template], logger *zap.SugaredLogger) *Service {
+	return &Service{
+		repo:   repo,
+		cache:  c,
+		logger: logger,
+	}
+}
+
+// Validate checks the name, matchers and both templates.
+func (t *Template) Validate() error {
+	if t.Name == "" {
+		return errors.New("name is required")
+	}
+	if _, err := matchers.ParseStrings(t.Matchers); err != nil {
+		return err
+	}
+	if _, err := template.New("subject").Parse(t.Subject); err != nil {
+		return fmt.Errorf("invalid subject: %w", err)
+	}
+	if _, err := template.New("body").Parse(t.Body); err != nil {
+		return fmt.Errorf("invalid body: %w", err)
+	}
+	return nil
+}
+
+// ListTemplates returns the templates ordered by name.
+func (s *Service) ListTemplates() ([]*Template, error) {
+	if ts, ok := s.cache.Get(templatesCacheKey); ok {
+		return ts, nil
+	}
+	ts, err := s.repo.GetTemplates()
+	if err != nil {
+		s.logger.Errorw("Failed to get notification templates", "error", err)
+		return nil, err
+	}
+	if err := s.cache.Set(templatesCacheKey, ts, time.Minute); err != nil {
+		s.logger.Errorw("Failed to cache notification templates", "error", err)
+	}
+	return ts, nil
+}
+
+func (s *Service) GetTemplate(id string) (*Template, error) {
+	return s.repo.GetTemplate(id)
+}
+
+func (s *Service) CreateTemplate(t *Template) error {
+	if err := s.validateName(t); err != nil {
+		return err
+	}
+	id, err := util.NewUUIDv7()
+	if err != nil {
+		return err
+	}
+	t.ID = id
+	t.CreatedAt = time.Now()
+	t.ModifiedAt = t.CreatedAt
+	if err := s.repo.AddTemplate(t); err != nil {
+		return err
+	}
+	s.cache.Delete(templatesCacheKey)
+	s.logger.Infow("Notification template created", "id", t.ID, "name", t.Name)
+	return nil
+}
+
+// UpdateTemplate replaces the template with the id of t.
+func (s *Service) UpdateTemplate(t *Template) error {
+	if err := s.validateName(t); err != nil {
+		return err
+	}
+	t.ModifiedAt = time.Now()
+	if err := s.repo.UpdateTemplate(t); err != nil {
+		return err
+	}
+	s.cache.Delete(templatesCacheKey)
+	s.logger.Infow("Notification template updated", "id", t.ID, "name", t.Name)
+	return nil
+}
+
+func (s *Service) DeleteTemplate(id string) error {
+	if err := s.repo.DeleteTemplate(id); err != nil {
+		return err
+	}
+	s.cache.Delete(templatesCacheKey)
+	s.logger.Infow("Notification template deleted", "id", id)
+	return nil
+}
+
+// validateName validates t and checks that no other template has its name.
+func (s *Service) validateName(t *Template) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	ts, err := s.repo.GetTemplates()
+	if err != nil {
+		return err
+	}
+	for _, other := range ts {
+		if other.Name == t.Name && other.ID != t.ID {
+			return fmt.Errorf("%w: %s", iris_error.ErrTemplateAlreadyExists, t.Name)
+		}
+	}
+	return nil
+}
+
+// Render executes the first template whose matchers match the labels of
+// d. It returns false when no template matches or the template fails, the
+// notification then keeps the alert name and description.
+func (s *Service) Render(d Data) (string, string, bool) {
+	ts, err := s.ListTemplates()
+	if err != nil {
+		return "", "", false
+	}
+	for _, t := range ts {
+		ms, err := matchers.ParseStrings(t.Matchers)
+		if err != nil {
+			s.logger.Warnw("Invalid matchers in notification template", "name", t.Name, "error", err)
+			continue
+		}
+		if !ms.Matches(d.Labels) {
+			continue
+		}
+		subject, err := execute(t.Subject, d.Name, d)
+		if err != nil {
+			s.logger.Errorw("Failed to render notification subject", "template", t.Name, "error", err)
+			return "", "", false
+		}
+		body, err := execute(t.Body, d.Description, d)
+		if err != nil {
+			s.logger.Errorw("Failed to render notification body", "template", t.Name, "error", err)
+			return "", "", false
+		}
+		return subject, body, true
+	}
+	return "", "", false
+}
+
+func execute(text, fallback string, d Data) (string, error) {
+	if text == "" {
+		return fallback, nil
+	}
+	tmpl, err := template.New("iris").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, d); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,43 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/root-ali/iris/pkg/cache"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type fixedRepo []*Template
+
+func (r fixedRepo) GetTemplates() ([]*Template, error)    { return r, nil }
+func (r fixedRepo) GetTemplate(string) (*Template, error) { return nil, nil }
+func (r fixedRepo) AddTemplate(*Template) error           { return nil }
+func (r fixedRepo) UpdateTemplate(*Template) error        { return nil }
+func (r fixedRepo) DeleteTemplate(string) error           { return nil }
+
+func TestRender(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	repo := fixedRepo{
+		{Name: "a-db", Matchers: []string{`team="db"`}, Subject: "[{{.Severity}}] {{.Labels.instance}}"},
+		{Name: "b-all", Body: "{{.Name}} is {{.Status}}{{.Labels.missing}}"},
+	}
+	s := NewService(repo, cache.New[string, []*Template](logger), logger)
+
+	d := Data{Name: "DiskFull", Description: "disk is full", Severity: "critical", Status: "firing",
+		Labels: map[string]string{"team": "db", "instance": "db-1"}}
+	subject, body, ok := s.Render(d)
+	assert.True(t, ok)
+	assert.Equal(t, "[critical] db-1", subject)
+	assert.Equal(t, "disk is full", body)
+
+	d.Labels = map[string]string{"team": "web"}
+	subject, body, ok = s.Render(d)
+	assert.True(t, ok)
+	assert.Equal(t, "DiskFull", subject)
+	assert.Equal(t, "DiskFull is firing", body)
+
+	s = NewService(fixedRepo{{Name: "broken", Subject: "{{.Name.Missing}}"}}, cache.New[string, []*Template](logger), logger)
+	_, _, ok = s.Render(d)
+	assert.False(t, ok)
+}
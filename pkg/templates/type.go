@@ -0,0 +1,62 @@
+package templates
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/root-ali/iris/pkg/cache"
+	"go.uber.org/zap"
+)
+
+// Template renders the subject and body of the notifications of alerts
+// matching Matchers. Subject and Body are Go text/template executed with
+// Data, an empty one keeps the alert name or description.
+type Template struct {
+	ID          string         `json:"id" gorm:"column:id;primary_key"`
+	Name        string         `json:"name" gorm:"column:name"`
+	Description string         `json:"description" gorm:"column:description"`
+	Matchers    pq.StringArray `json:"matchers" gorm:"column:matchers;type:text[]"`
+	Subject     string         `json:"subject" gorm:"column:subject"`
+	Body        string         `json:"body" gorm:"column:body"`
+	CreatedAt   time.Time      `json:"created_at" gorm:"column:created_at"`
+	ModifiedAt  time.Time      `json:"modified_at" gorm:"column:modified_at"`
+}
+
+func (Template) TableName() string { return "notification_templates" }
+
+// Data is what templates are executed with.
+type Data struct {
+	Name        string
+	Description string
+	Severity    string
+	Status      string
+	Fingerprint string
+	Labels      map[string]string
+	StartsAt    time.Time
+	EndsAt      time.Time
+}
+
+type Repository interface {
+	GetTemplates() ([]*Template, error)
+	GetTemplate(id string) (*Template, error)
+	AddTemplate(t *Template) error
+	UpdateTemplate(t *Template) error
+	DeleteTemplate(id string) error
+}
+
+type ServiceInterface interface {
+	ListTemplates() ([]*Template, error)
+	GetTemplate(id string) (*Template, error)
+	CreateTemplate(t *Template) error
+	UpdateTemplate(t *Template) error
+	DeleteTemplate(id string) error
+	Render(d Data) (subject, body string, ok bool)
+}
+
+type Service struct {
+	repo   Repository
+	cache  cache.Interface[string, []*Template]
+	logger *zap.SugaredLogger
+}
+
+const templatesCacheKey = "notification_templates"
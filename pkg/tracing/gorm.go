@@ -0,0 +1,78 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "iris:tracing:span"
+
+// GormPlugin records a span for every query run with the context of a
+// traced operation. Queries outside a trace, such as the scheduler polls,
+// are not recorded.
+type GormPlugin struct{}
+
+// gormSpan is the span of a running query and the context it replaced.
+type gormSpan struct {
+	span   trace.Span
+	parent context.Context
+}
+
+func (GormPlugin) Name() string {
+	return "iris:tracing"
+}
+
+func (p GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("iris:tracing:before_create", before("create")),
+		cb.Create().After("gorm:create").Register("iris:tracing:after_create", after),
+		cb.Query().Before("gorm:query").Register("iris:tracing:before_query", before("query")),
+		cb.Query().After("gorm:query").Register("iris:tracing:after_query", after),
+		cb.Update().Before("gorm:update").Register("iris:tracing:before_update", before("update")),
+		cb.Update().After("gorm:update").Register("iris:tracing:after_update", after),
+		cb.Delete().Before("gorm:delete").Register("iris:tracing:before_delete", before("delete")),
+		cb.Delete().After("gorm:delete").Register("iris:tracing:after_delete", after),
+		cb.Row().Before("gorm:row").Register("iris:tracing:before_row", before("row")),
+		cb.Row().After("gorm:row").Register("iris:tracing:after_row", after),
+		cb.Raw().Before("gorm:raw").Register("iris:tracing:before_raw", before("raw")),
+		cb.Raw().After("gorm:raw").Register("iris:tracing:after_raw", after),
+	)
+}
+
+func before(op string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		parent := db.Statement.Context
+		if parent == nil || !trace.SpanContextFromContext(parent).IsValid() {
+			return
+		}
+		ctx, span := Tracer().Start(parent, "db."+op, trace.WithSpanKind(trace.SpanKindClient))
+		db.Statement.Context = ctx
+		db.InstanceSet(gormSpanKey, gormSpan{span: span, parent: parent})
+	}
+}
+
+func after(db *gorm.DB) {
+	v, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	s := v.(gormSpan)
+	db.Statement.Context = s.parent
+	s.span.SetAttributes(
+		attribute.String("db.system.name", "postgresql"),
+		attribute.String("db.collection.name", db.Statement.Table),
+		attribute.String("db.query.text", db.Statement.SQL.String()),
+		attribute.Int64("db.response.returned_rows", db.RowsAffected),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		s.span.RecordError(db.Error)
+		s.span.SetStatus(codes.Error, db.Error.Error())
+	}
+	s.span.End()
+}
@@ -0,0 +1,129 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	instrumentationName = "github.com/root-ali/iris"
+	defaultServiceName  = "iris"
+)
+
+// Config selects the OTLP/HTTP collector spans are exported to.
+type Config struct {
+	Enabled bool
+	// Endpoint is host:port of the collector, the OTEL_EXPORTER_OTLP_*
+	// variables apply when it is empty.
+	Endpoint    string
+	Insecure    bool
+	ServiceName string
+	// SampleRatio of new traces, 0 samples every trace.
+	SampleRatio float64
+}
+
+var propagator = propagation.TraceContext{}
+
+// Init installs the global tracer provider and returns a function flushing
+// it on shutdown. When tracing is disabled the global no-op provider stays
+// in place and every span is dropped.
+func Init(ctx context.Context, cfg Config, logger *zap.SugaredLogger) (func(context.Context) error, error) {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+	opts := make([]otlptracehttp.Option, 0, 2)
+	if cfg.Endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	name := cfg.ServiceName
+	if name == "" {
+		name = defaultServiceName
+	}
+	hostname, _ := os.Hostname()
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(name),
+		semconv.HostName(hostname),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	sampler := sdktrace.AlwaysSample()
+	if cfg.SampleRatio > 0 && cfg.SampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(cfg.SampleRatio)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagator)
+	logger.Infow("Tracing enabled", "endpoint", cfg.Endpoint, "service", name, "sampleRatio", cfg.SampleRatio)
+	return tp.Shutdown, nil
+}
+
+// Tracer returns the tracer of Iris spans.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Inject returns the W3C traceparent of the span in ctx, empty when there
+// is none, to be stored with rows that continue the trace later.
+func Inject(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+// Extract returns ctx continuing the trace of a stored traceparent.
+func Extract(ctx context.Context, traceParent string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+	return propagator.Extract(ctx, propagation.MapCarrier{"traceparent": traceParent})
+}
+
+// ExtractHeaders returns ctx continuing the trace of an incoming request
+// that carries a traceparent header.
+func ExtractHeaders(ctx context.Context, h http.Header) context.Context {
+	return propagator.Extract(ctx, propagation.HeaderCarrier(h))
+}
+
+// StartProviderCall starts the span of a Send or Status call to provider,
+// continuing the trace of a stored traceparent.
+func StartProviderCall(traceParent, op, provider string, attrs ...attribute.KeyValue) trace.Span {
+	_, span := Tracer().Start(Extract(context.Background(), traceParent), "provider."+op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attrs, attribute.String("provider.name", provider))...))
+	return span
+}
+
+// End records err, if any, on span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
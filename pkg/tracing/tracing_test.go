@@ -0,0 +1,50 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+)
+
+func TestInitDisabled(t *testing.T) {
+	shutdown, err := Init(context.Background(), Config{}, zap.NewNop().Sugar())
+	assert.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+	// Without a provider nothing is propagated
+	_, span := Tracer().Start(context.Background(), "noop")
+	defer span.End()
+	assert.Empty(t, Inject(context.Background()))
+}
+
+func TestProviderCallContinuesTrace(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	defer otel.SetTracerProvider(previous)
+
+	ctx, root := Tracer().Start(context.Background(), "alertmanager.receive")
+	traceParent := Inject(ctx)
+	root.End()
+	assert.NotEmpty(t, traceParent)
+
+	// The stored traceparent is all a later call needs
+	span := StartProviderCall(traceParent, "send", "Kavenegar")
+	End(span, errors.New("boom"))
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+	send := spans[1]
+	assert.Equal(t, "provider.send", send.Name)
+	assert.Equal(t, root.SpanContext().TraceID(), send.SpanContext.TraceID())
+	assert.Equal(t, root.SpanContext().SpanID(), send.Parent.SpanID())
+	assert.Equal(t, codes.Error, send.Status.Code)
+
+	assert.Equal(t, ctx, Extract(ctx, ""))
+}
@@ -33,6 +33,7 @@ type userServiceImpl struct {
 
 type UserRoleRepository interface {
 	GetRoleIDByName(name string) (string, error)
+	GetRoleByID(id string) (string, string, error)
 }
 
 type UserInterfaceRepository interface {
@@ -46,6 +47,7 @@ type UserInterfaceRepository interface {
 	GetRole(u *User) error
 	VerifyUser(u *User) error
 	UpdateUserData(u *User) error
+	UpdateUserProfile(u *User) error
 }
 
 type UserInterfaceService interface {
@@ -59,4 +61,8 @@ type UserInterfaceService interface {
 	GetAllUsers() ([]*User, error)
 	GetByUserName(string) (*User, error)
 	GetByUserId(string) (*User, error)
+	CreateUser(u *User, role string) error
+	UpdateProfile(u *User, role string) error
+	DeleteUser(id string) error
+	RoleName(u *User) (string, error)
 }
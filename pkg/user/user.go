@@ -2,6 +2,7 @@ package user
 
 import (
 	"errors"
+	"fmt"
 	"math/rand"
 	"regexp"
 	"time"
@@ -215,8 +216,100 @@ func (us *userServiceImpl) GetByUserId(id string) (*User, error) {
 		return nil, errors.New("user id is required")
 	}
 	user, err := us.repo.GetUserByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, iris_error.ErrUserNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 	return user, nil
 }
+
+// CreateUser adds a verified user with the named role, viewer when empty.
+// u.Password is the plain text password.
+func (us *userServiceImpl) CreateUser(u *User, role string) error {
+	err := us.repo.GetUserByUsername(&User{UserName: u.UserName})
+	if err == nil {
+		return iris_error.ErrUserAlreadyExists
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	roleID, err := us.roleID(role)
+	if err != nil {
+		return err
+	}
+	t := time.Now()
+	entropy := rand.New(rand.NewSource(t.UnixNano()))
+	u.ID = ulid.MustNew(ulid.Timestamp(t), entropy).String()
+	u.Role = roleID
+	u.Status = "Verified"
+	u.CreatedAt = t
+	u.ModifiedAt = t
+	if err := u.SetPassword(u.Password); err != nil {
+		return err
+	}
+	if err := us.repo.AddUser(u); err != nil {
+		us.logger.Errorw("Failed to add user", "user", u.UserName, "error", err)
+		return err
+	}
+	us.logger.Infow("User created", "user", u.UserName, "role", role)
+	return nil
+}
+
+// UpdateProfile replaces the profile of the user with the id of u and sets
+// the named role, viewer when empty. The default admin stays an admin.
+func (us *userServiceImpl) UpdateProfile(u *User, role string) error {
+	if u.UserName == "admin" && role != "admin" {
+		return iris_error.ErrDefaultAdminUser
+	}
+	roleID, err := us.roleID(role)
+	if err != nil {
+		return err
+	}
+	u.Role = roleID
+	u.ModifiedAt = time.Now()
+	err = us.repo.UpdateUserProfile(u)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return iris_error.ErrUserNotFound
+	}
+	return err
+}
+
+// DeleteUser deletes the user and its group memberships, the default admin
+// is kept.
+func (us *userServiceImpl) DeleteUser(id string) error {
+	u, err := us.GetByUserId(id)
+	if err != nil {
+		return err
+	}
+	if u.UserName == "admin" {
+		return iris_error.ErrDefaultAdminUser
+	}
+	if err := us.repo.DeleteUser(id); err != nil {
+		us.logger.Errorw("Failed to delete user", "user", u.UserName, "error", err)
+		return err
+	}
+	us.logger.Infow("User deleted", "user", u.UserName)
+	return nil
+}
+
+// RoleName returns the name of the role of u.
+func (us *userServiceImpl) RoleName(u *User) (string, error) {
+	name, _, err := us.role.GetRoleByID(u.Role)
+	return name, err
+}
+
+func (us *userServiceImpl) roleID(name string) (string, error) {
+	if name == "" {
+		name = "viewer"
+	}
+	id, err := us.role.GetRoleIDByName(name)
+	if err != nil {
+		return "", err
+	}
+	if id == "" {
+		return "", fmt.Errorf("%w: %s", iris_error.ErrRoleNotFound, name)
+	}
+	return id, nil
+}
@@ -42,3 +42,24 @@ func generateRandomPassword(length int) (string, error) {
 
 	return string(password), nil
 }
+
+// SetPassword salts and hashes password into u.
+func (u *User) SetPassword(password string) error {
+	salt, err := generateSalt()
+	if err != nil {
+		return err
+	}
+	hashed, err := hashPassword(password, salt)
+	if err != nil {
+		return err
+	}
+	u.Salt = salt
+	u.Password = hashed
+	return nil
+}
+
+// GeneratePassword returns a random password for accounts created without
+// one.
+func GeneratePassword() (string, error) {
+	return generateRandomPassword(20)
+}
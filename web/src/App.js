@@ -7,6 +7,10 @@ import UserManagement from './pages/UserManagement';
 import GroupManagement from './pages/GroupManagement';
 import AlertsPage from './pages/AlertsPage';
 import ProvidersPage from './pages/ProvidersPage';
+import MaintenancePage from './pages/MaintenancePage';
+import DeliveryAnalytics from './pages/DeliveryAnalytics';
+import IncidentsPage from './pages/IncidentsPage';
+import StatusComponentsPage from './pages/StatusComponentsPage';
 import Profile from './pages/Profile';
 import ProtectedRoute from './components/ProtectedRoute';
 
@@ -70,6 +74,42 @@ function App() {
           }
         />
 
+        <Route
+          path="/maintenance"
+          element={
+            <ProtectedRoute>
+              <MaintenancePage />
+            </ProtectedRoute>
+          }
+        />
+
+        <Route
+          path="/incidents"
+          element={
+            <ProtectedRoute>
+              <IncidentsPage />
+            </ProtectedRoute>
+          }
+        />
+
+        <Route
+          path="/delivery"
+          element={
+            <ProtectedRoute>
+              <DeliveryAnalytics />
+            </ProtectedRoute>
+          }
+        />
+
+        <Route
+          path="/status-components"
+          element={
+            <ProtectedRoute requireAdmin={true}>
+              <StatusComponentsPage />
+            </ProtectedRoute>
+          }
+        />
+
         {/* Redirect any unknown routes to login */}
         <Route path="*" element={<Navigate to="/" replace />} />
       </Routes>
@@ -0,0 +1,152 @@
+import React, { useState, useEffect } from 'react';
+import apiService from '../utils/apiService';
+import './NotificationPreferences.css';
+
+const TYPES = [
+    { value: 'sms', label: 'Phone (SMS)' },
+    { value: 'mail', label: 'Email' },
+    { value: 'telegram', label: 'Telegram chat ID' },
+    { value: 'mattermost', label: 'Mattermost channel ID' },
+];
+
+const emptyMethod = { type: 'sms', label: '', value: '' };
+
+const ContactMethods = ({ userId }) => {
+    const [methods, setMethods] = useState([]);
+    const [newMethod, setNewMethod] = useState(emptyMethod);
+    const [codes, setCodes] = useState({});
+    const [loading, setLoading] = useState(true);
+    const [message, setMessage] = useState(null);
+
+    useEffect(() => {
+        if (userId) {
+            fetchMethods();
+        }
+    }, [userId]);
+
+    const fetchMethods = async () => {
+        setLoading(true);
+        try {
+            const data = await apiService.getContactMethods(userId);
+            setMethods(data.contact_methods || []);
+        } catch (e) {
+            setMessage('Error loading contact methods: ' + e.message);
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    const handleAdd = async (e) => {
+        e.preventDefault();
+        try {
+            const data = await apiService.addContactMethod(userId, newMethod);
+            setNewMethod(emptyMethod);
+            setMessage(data.message || 'Verification code sent');
+            fetchMethods();
+        } catch (e) {
+            setMessage('Error adding contact method: ' + e.message);
+        }
+    };
+
+    const handleVerify = async (method) => {
+        try {
+            await apiService.verifyContactMethod(userId, method.id, codes[method.id] || '');
+            setMessage(`${method.value} verified`);
+            fetchMethods();
+        } catch (e) {
+            setMessage('Error verifying contact method: ' + e.message);
+        }
+    };
+
+    const handleResend = async (method) => {
+        try {
+            await apiService.resendContactMethodCode(userId, method.id);
+            setMessage(`Verification code sent to ${method.value}`);
+        } catch (e) {
+            setMessage('Error sending verification code: ' + e.message);
+        }
+    };
+
+    const handleLinkTelegram = async () => {
+        try {
+            const data = await apiService.createTelegramLink(userId);
+            if (data.url) {
+                window.open(data.url, '_blank', 'noopener');
+                setMessage('Press Start in Telegram to link the chat, then refresh this page');
+            } else {
+                setMessage(`Send "/start ${data.token}" to the Iris Telegram bot within 15 minutes`);
+            }
+        } catch (e) {
+            setMessage('Error creating Telegram link: ' + e.message);
+        }
+    };
+
+    const handleDelete = async (method) => {
+        if (!window.confirm(`Are you sure you want to remove ${method.value}?`)) return;
+        try {
+            await apiService.deleteContactMethod(userId, method.id);
+            fetchMethods();
+        } catch (e) {
+            setMessage('Error removing contact method: ' + e.message);
+        }
+    };
+
+    if (loading) {
+        return <div className="loading">Loading contact methods...</div>;
+    }
+
+    return (
+        <div className="notification-preferences">
+            <p className="preferences-hint">Only verified contact methods are used for paging.</p>
+            {methods.map((m) => (
+                <div className="rule-row" key={m.id}>
+                    <span className="role-badge">{m.type}</span>
+                    <span>{m.value}</span>
+                    {m.label && <span className="preferences-hint">({m.label})</span>}
+                    {m.verified_at ? (
+                        <span className="status-badge status-active">Verified</span>
+                    ) : (
+                        <>
+                            <input
+                                type="text"
+                                placeholder="Code"
+                                maxLength="6"
+                                value={codes[m.id] || ''}
+                                onChange={(e) => setCodes({ ...codes, [m.id]: e.target.value })}
+                            />
+                            <button className="btn-primary" onClick={() => handleVerify(m)}>Verify</button>
+                            <button className="btn-secondary" onClick={() => handleResend(m)}>Resend</button>
+                        </>
+                    )}
+                    <button className="btn-delete" onClick={() => handleDelete(m)}>Remove</button>
+                </div>
+            ))}
+            <form className="rule-row" onSubmit={handleAdd}>
+                <select
+                    value={newMethod.type}
+                    onChange={(e) => setNewMethod({ ...newMethod, type: e.target.value })}
+                >
+                    {TYPES.map((t) => <option key={t.value} value={t.value}>{t.label}</option>)}
+                </select>
+                <input
+                    type="text"
+                    placeholder="Address"
+                    value={newMethod.value}
+                    onChange={(e) => setNewMethod({ ...newMethod, value: e.target.value })}
+                    required
+                />
+                <input
+                    type="text"
+                    placeholder="Label (work, personal)"
+                    value={newMethod.label}
+                    onChange={(e) => setNewMethod({ ...newMethod, label: e.target.value })}
+                />
+                <button type="submit" className="btn-primary">+ Add</button>
+                <button type="button" className="btn-secondary" onClick={handleLinkTelegram}>Link Telegram</button>
+            </form>
+            {message && <div className="preferences-message">{message}</div>}
+        </div>
+    );
+};
+
+export default ContactMethods;
@@ -32,6 +32,15 @@ const Layout = ({ children }) => {
                     <li className={location.pathname === '/alerts' ? 'active' : ''}>
                         <Link to="/alerts">Alerts</Link>
                     </li>
+                    <li className={location.pathname === '/incidents' ? 'active' : ''}>
+                        <Link to="/incidents">Incidents</Link>
+                    </li>
+                    <li className={location.pathname === '/maintenance' ? 'active' : ''}>
+                        <Link to="/maintenance">Maintenance</Link>
+                    </li>
+                    <li className={location.pathname === '/delivery' ? 'active' : ''}>
+                        <Link to="/delivery">Delivery</Link>
+                    </li>
                     {userIsAdmin && (
                         <>
                             <li className={location.pathname === '/users' ? 'active' : ''}>
@@ -43,6 +52,9 @@ const Layout = ({ children }) => {
                             <li className={location.pathname === '/providers' ? 'active' : ''}>
                                 <Link to="/providers">Providers</Link>
                             </li>
+                            <li className={location.pathname === '/status-components' ? 'active' : ''}>
+                                <Link to="/status-components">Status Page</Link>
+                            </li>
                         </>
                     )}
                 </ul>
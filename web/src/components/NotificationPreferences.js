@@ -0,0 +1,160 @@
+import React, { useState, useEffect } from 'react';
+import apiService from '../utils/apiService';
+import './NotificationPreferences.css';
+
+const METHODS = ['sms', 'telegram', 'mail', 'mattermost'];
+
+const NotificationPreferences = ({ userId }) => {
+    const [rules, setRules] = useState([]);
+    const [quietHours, setQuietHours] = useState({ start: '', end: '', timezone: 'UTC', bypass: 'critical' });
+    const [loading, setLoading] = useState(true);
+    const [message, setMessage] = useState(null);
+
+    useEffect(() => {
+        if (userId) {
+            fetchPreferences();
+        }
+    }, [userId]);
+
+    const fetchPreferences = async () => {
+        setLoading(true);
+        try {
+            const data = await apiService.getContactPreferences(userId);
+            const prefs = data.preferences || {};
+            setRules((prefs.rules || []).map((r) => ({ severity: r.severity, methods: r.methods || [] })));
+            if (prefs.quiet_hours) {
+                setQuietHours({
+                    start: prefs.quiet_hours.start,
+                    end: prefs.quiet_hours.end,
+                    timezone: prefs.quiet_hours.timezone,
+                    bypass: (prefs.quiet_hours.bypass_severities || []).join(', '),
+                });
+            }
+        } catch (e) {
+            setMessage('Error loading preferences: ' + e.message);
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    const updateRule = (index, changes) => {
+        setRules(rules.map((r, i) => (i === index ? { ...r, ...changes } : r)));
+    };
+
+    const toggleMethod = (index, method) => {
+        const methods = rules[index].methods.includes(method)
+            ? rules[index].methods.filter((m) => m !== method)
+            : [...rules[index].methods, method];
+        updateRule(index, { methods });
+    };
+
+    const saveRules = async () => {
+        try {
+            await apiService.setContactRules(userId, rules);
+            setMessage('Contact rules saved');
+        } catch (e) {
+            setMessage('Error saving contact rules: ' + e.message);
+        }
+    };
+
+    const saveQuietHours = async () => {
+        try {
+            await apiService.setQuietHours(userId, {
+                start: quietHours.start,
+                end: quietHours.end,
+                timezone: quietHours.timezone,
+                bypass_severities: quietHours.bypass.split(',').map((s) => s.trim()).filter((s) => s !== ''),
+            });
+            setMessage('Quiet hours saved');
+        } catch (e) {
+            setMessage('Error saving quiet hours: ' + e.message);
+        }
+    };
+
+    const clearQuietHours = async () => {
+        try {
+            await apiService.deleteQuietHours(userId);
+            setQuietHours({ start: '', end: '', timezone: 'UTC', bypass: 'critical' });
+            setMessage('Quiet hours cleared');
+        } catch (e) {
+            setMessage('Error clearing quiet hours: ' + e.message);
+        }
+    };
+
+    if (loading) {
+        return <div className="loading">Loading preferences...</div>;
+    }
+
+    return (
+        <div className="notification-preferences">
+            <p className="preferences-hint">
+                Without a matching rule you are notified on every method the alert asks for.
+                Use <code>*</code> as severity to match everything.
+            </p>
+            {rules.map((rule, index) => (
+                <div className="rule-row" key={index}>
+                    <input
+                        type="text"
+                        placeholder="critical"
+                        value={rule.severity}
+                        onChange={(e) => updateRule(index, { severity: e.target.value })}
+                    />
+                    {METHODS.map((m) => (
+                        <label key={m} className="method-checkbox">
+                            <input
+                                type="checkbox"
+                                checked={rule.methods.includes(m)}
+                                onChange={() => toggleMethod(index, m)}
+                            />
+                            {m}
+                        </label>
+                    ))}
+                    <button className="btn-delete" onClick={() => setRules(rules.filter((_, i) => i !== index))}>
+                        Remove
+                    </button>
+                </div>
+            ))}
+            <div className="preferences-actions">
+                <button className="btn-secondary" onClick={() => setRules([...rules, { severity: '', methods: [] }])}>
+                    + Add Rule
+                </button>
+                <button className="btn-primary" onClick={saveRules}>Save Rules</button>
+            </div>
+
+            <h4>Quiet Hours</h4>
+            <div className="rule-row">
+                <input
+                    type="time"
+                    value={quietHours.start}
+                    onChange={(e) => setQuietHours({ ...quietHours, start: e.target.value })}
+                />
+                <span>to</span>
+                <input
+                    type="time"
+                    value={quietHours.end}
+                    onChange={(e) => setQuietHours({ ...quietHours, end: e.target.value })}
+                />
+                <input
+                    type="text"
+                    placeholder="Asia/Tehran"
+                    value={quietHours.timezone}
+                    onChange={(e) => setQuietHours({ ...quietHours, timezone: e.target.value })}
+                />
+                <input
+                    type="text"
+                    placeholder="Except severities"
+                    title="Severities that still page during quiet hours"
+                    value={quietHours.bypass}
+                    onChange={(e) => setQuietHours({ ...quietHours, bypass: e.target.value })}
+                />
+            </div>
+            <div className="preferences-actions">
+                <button className="btn-secondary" onClick={clearQuietHours}>Clear</button>
+                <button className="btn-primary" onClick={saveQuietHours}>Save Quiet Hours</button>
+            </div>
+            {message && <div className="preferences-message">{message}</div>}
+        </div>
+    );
+};
+
+export default NotificationPreferences;
@@ -3,6 +3,8 @@
 // const config = {
 //     api: {
 //         alertSummary: base_url + '/v0/alerts/firingCount',
+        alertSearch: base_url + '/v0/alerts/search',
+        alertStream: base_url + '/v0/alerts/stream',
 //         firingIssues: base_url + '/v0/alerts/?page=1&pagination=10&status=firing',
 //         resolvedIssues: base_url + '/v0/alerts/?page=1&pagination=10&status=resolved',
 //     },
@@ -46,6 +48,11 @@ const config = {
         userMe: base_url + '/v0/users/me',
         userVerify: base_url + '/v0/users/verify',
         userGroups: (userId) => base_url + `/v0/users/${userId}/groups`,
+        userContactRules: (userId) => base_url + `/v0/users/${userId}/contact-rules`,
+        userQuietHours: (userId) => base_url + `/v0/users/${userId}/quiet-hours`,
+        userContactMethods: (userId) => base_url + `/v0/users/${userId}/contact-methods`,
+        userContactMethod: (userId, methodId) => base_url + `/v0/users/${userId}/contact-methods/${methodId}`,
+        userTelegramLink: (userId) => base_url + `/v0/users/${userId}/telegram-link`,
 
         // Group endpoints
         groups: base_url + '/v0/groups',
@@ -56,7 +63,23 @@ const config = {
         // Provider endpoints
         providers: base_url + '/v0/providers',
 
+        // Maintenance endpoints
+        maintenance: base_url + '/v0/maintenance',
+        maintenanceUpcoming: base_url + '/v0/maintenance/upcoming',
+        maintenanceWindow: (windowId) => base_url + `/v0/maintenance/${windowId}`,
+
+        // Incident endpoints
+        incidents: base_url + '/v0/incidents',
+        incident: (incidentId) => base_url + `/v0/incidents/${incidentId}`,
+
+        // Status page endpoints
+        statusComponents: base_url + '/v0/status-components',
+        statusComponent: (componentId) => base_url + `/v0/status-components/${componentId}`,
+        statusPage: base_url + '/status',
+
         // Message endpoints
+        messages: base_url + '/v0/messages',
+        messageAnalytics: base_url + '/v0/messages/analytics',
         alertManagerMessage: base_url + '/v1/messages/alertmanager',
     },
 };
@@ -3,12 +3,17 @@ import apiService from '../utils/apiService';
 import Layout from '../components/Layout';
 import './AlertsPage.css';
 
+const emptySearch = { filter: '', q: '', sort: 'starts_at', order: 'desc' };
+
 const AlertsPage = () => {
     const [alerts, setAlerts] = useState({ critical: 0, high: 0, medium: 0, low: 0, page: 0, warning: 0 });
     const [allAlerts, setAllAlerts] = useState([]);
     const [loading, setLoading] = useState(true);
     const [error, setError] = useState(null);
     const [activeTab, setActiveTab] = useState('summary');
+    const [search, setSearch] = useState(emptySearch);
+    const [searchResult, setSearchResult] = useState({ alerts: [], total: 0, next_cursor: '' });
+    const [searchError, setSearchError] = useState(null);
 
     useEffect(() => {
         fetchAllAlertData();
@@ -58,6 +63,25 @@ const AlertsPage = () => {
         }
     };
 
+    const runSearch = async (cursor = '') => {
+        setSearchError(null);
+        try {
+            const data = await apiService.searchAlerts({ ...search, limit: 50, cursor });
+            setSearchResult((prev) => ({
+                alerts: cursor ? [...prev.alerts, ...(data.alerts || [])] : data.alerts || [],
+                total: data.total || 0,
+                next_cursor: data.next_cursor || '',
+            }));
+        } catch (e) {
+            setSearchError(e.message);
+        }
+    };
+
+    const handleSearch = (e) => {
+        e.preventDefault();
+        runSearch();
+    };
+
     const getSeverityClass = (severity) => {
         const classes = {
             critical: 'severity-critical',
@@ -119,6 +143,12 @@ const AlertsPage = () => {
                     >
                         Resolved Alerts ({resolvedAlerts.length})
                     </button>
+                    <button
+                        className={`tab ${activeTab === 'search' ? 'active' : ''}`}
+                        onClick={() => setActiveTab('search')}
+                    >
+                        Search
+                    </button>
                 </div>
 
                 {activeTab === 'summary' && (
@@ -238,6 +268,84 @@ const AlertsPage = () => {
                         )}
                     </div>
                 )}
+
+                {activeTab === 'search' && (
+                    <div className="alerts-list">
+                        <h2>Search Alerts</h2>
+                        <form className="alert-search-form" onSubmit={handleSearch}>
+                            <input
+                                type="text"
+                                className="alert-search-filter"
+                                placeholder='{severity="critical",team=~"db|infra"}'
+                                value={search.filter}
+                                onChange={(e) => setSearch({ ...search, filter: e.target.value })}
+                            />
+                            <input
+                                type="text"
+                                placeholder="Search name and description"
+                                value={search.q}
+                                onChange={(e) => setSearch({ ...search, q: e.target.value })}
+                            />
+                            <select value={search.sort} onChange={(e) => setSearch({ ...search, sort: e.target.value })}>
+                                <option value="starts_at">Started</option>
+                                <option value="created_at">Created</option>
+                                <option value="updated_at">Updated</option>
+                                <option value="severity">Severity</option>
+                                <option value="name">Name</option>
+                            </select>
+                            <select value={search.order} onChange={(e) => setSearch({ ...search, order: e.target.value })}>
+                                <option value="desc">Descending</option>
+                                <option value="asc">Ascending</option>
+                            </select>
+                            <button type="submit" className="btn-primary">Search</button>
+                        </form>
+                        {searchError && <div className="error-message">{searchError}</div>}
+                        <p className="alert-search-total">
+                            Showing {searchResult.alerts.length} of {searchResult.total} alerts
+                        </p>
+                        {searchResult.alerts.length > 0 && (
+                            <div className="alerts-table-container">
+                                <table className="alerts-table">
+                                    <thead>
+                                        <tr>
+                                            <th>Alert Name</th>
+                                            <th>Severity</th>
+                                            <th>Status</th>
+                                            <th>Started At</th>
+                                            <th>Labels</th>
+                                        </tr>
+                                    </thead>
+                                    <tbody>
+                                        {searchResult.alerts.map((alert) => (
+                                            <tr key={alert.id}>
+                                                <td className="alert-name" title={alert.description}>{alert.name}</td>
+                                                <td>
+                                                    <span className={`severity-badge ${getSeverityClass(alert.severity)}`}>
+                                                        {alert.severity || 'N/A'}
+                                                    </span>
+                                                </td>
+                                                <td>
+                                                    <span className={`status-badge ${alert.status}`}>{alert.status}</span>
+                                                </td>
+                                                <td>{new Date(alert.starts_at).toLocaleString()}</td>
+                                                <td className="alert-labels">
+                                                    {Object.entries(alert.labels || {}).map(([k, v]) => (
+                                                        <code key={k}>{k}={v}</code>
+                                                    ))}
+                                                </td>
+                                            </tr>
+                                        ))}
+                                    </tbody>
+                                </table>
+                            </div>
+                        )}
+                        {searchResult.next_cursor && (
+                            <button className="btn-secondary" onClick={() => runSearch(searchResult.next_cursor)}>
+                                Load more
+                            </button>
+                        )}
+                    </div>
+                )}
             </div>
         </Layout>
     );
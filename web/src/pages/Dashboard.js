@@ -1,5 +1,6 @@
-import React, { useState, useEffect } from 'react';
+import React, { useState, useEffect, useRef } from 'react';
 import apiService from '../utils/apiService';
+import { subscribeAlertStream, severityCounts } from '../utils/alertStream';
 import Layout from '../components/Layout';
 import './Dashboard.css';
 
@@ -12,31 +13,38 @@ const Dashboard = () => {
     const [loading, setLoading] = useState(true);
     const [error, setError] = useState(null);
 
+    const refreshRef = useRef(null);
+
     useEffect(() => {
         fetchDashboardData();
     }, [firingLimit, resolvedLimit]);
 
-    const fetchDashboardData = async () => {
-        setLoading(true);
+    // Alert changes are pushed by the server, the lists are reloaded once
+    // a burst of events is over.
+    useEffect(() => {
+        let timer = null;
+        const close = subscribeAlertStream((event) => {
+            if (event.type === 'firing_count') {
+                setAlerts(severityCounts(event.firing_count));
+                return;
+            }
+            clearTimeout(timer);
+            timer = setTimeout(() => refreshRef.current && refreshRef.current(), 1000);
+        });
+        return () => {
+            clearTimeout(timer);
+            close();
+        };
+    }, []);
+
+    const fetchDashboardData = async (silent = false) => {
+        if (!silent) setLoading(true);
         setError(null);
 
         try {
             // Fetch alert summary
             const alertData = await apiService.getAlertSummary();
-            const mappedData = {};
-            let totalActive = 0;
-
-            // Handle null or undefined severites array
-            if (alertData && alertData.severites && Array.isArray(alertData.severites)) {
-                alertData.severites.forEach(item => {
-                    if (item && item.severity && typeof item.count === 'number') {
-                        mappedData[item.severity] = item.count;
-                        totalActive += item.count;
-                    }
-                });
-            }
-
-            setAlerts(mappedData);
+            setAlerts(severityCounts(alertData && alertData.severites));
 
             // Fetch detailed firing alerts for critical and warning
             try {
@@ -71,6 +79,7 @@ const Dashboard = () => {
             setLoading(false);
         }
     };
+    refreshRef.current = () => fetchDashboardData(true);
 
     const getSeverityClass = (severity) => {
         const classes = {
@@ -0,0 +1,251 @@
+import React, { useState, useEffect } from 'react';
+import apiService from '../utils/apiService';
+import Layout from '../components/Layout';
+import './DeliveryAnalytics.css';
+
+const ranges = {
+    '24h': 24 * 60 * 60 * 1000,
+    '7d': 7 * 24 * 60 * 60 * 1000,
+    '30d': 30 * 24 * 60 * 60 * 1000,
+};
+
+const emptyFilters = {
+    range: '7d',
+    user_id: '',
+    group: '',
+    provider: '',
+    status: '',
+};
+
+const percent = (rate) => `${(rate * 100).toFixed(1)}%`;
+
+const duration = (seconds) => {
+    if (!seconds) return '-';
+    if (seconds < 60) return `${seconds.toFixed(1)}s`;
+    if (seconds < 3600) return `${(seconds / 60).toFixed(1)}m`;
+    return `${(seconds / 3600).toFixed(1)}h`;
+};
+
+// BarChart draws one horizontal bar per item, scaled to the largest value.
+const BarChart = ({ items, format }) => {
+    const max = Math.max(...items.map((i) => i.value), 0);
+    if (items.length === 0) {
+        return <p className="no-data">No data for the selected filters</p>;
+    }
+    return (
+        <div className="bar-chart">
+            {items.map((i) => (
+                <div className="bar-row" key={i.label}>
+                    <span className="bar-label" title={i.label}>{i.label}</span>
+                    <div className="bar-track">
+                        <div
+                            className={`bar-fill ${i.className || ''}`}
+                            style={{ width: max > 0 ? `${(i.value / max) * 100}%` : 0 }}
+                        />
+                    </div>
+                    <span className="bar-value">{format ? format(i.value) : i.value}</span>
+                </div>
+            ))}
+        </div>
+    );
+};
+
+const DeliveryAnalytics = () => {
+    const [filters, setFilters] = useState(emptyFilters);
+    const [analytics, setAnalytics] = useState(null);
+    const [messages, setMessages] = useState([]);
+    const [total, setTotal] = useState(0);
+    const [loading, setLoading] = useState(true);
+    const [error, setError] = useState(null);
+
+    useEffect(() => {
+        fetchAnalytics(emptyFilters);
+    }, []);
+
+    const fetchAnalytics = async (f) => {
+        setLoading(true);
+        setError(null);
+        const params = {
+            user_id: f.user_id,
+            group: f.group,
+            provider: f.provider,
+            status: f.status,
+            from: new Date(Date.now() - ranges[f.range]).toISOString(),
+            limit: 20,
+        };
+        try {
+            const [analyticsData, messageData] = await Promise.all([
+                apiService.getMessageAnalytics(params),
+                apiService.getMessages(params),
+            ]);
+            setAnalytics(analyticsData.analytics);
+            setMessages(messageData.messages || []);
+            setTotal(messageData.total || 0);
+        } catch (e) {
+            setError(e.message);
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    const handleApply = (e) => {
+        e.preventDefault();
+        fetchAnalytics(filters);
+    };
+
+    const providers = analytics ? analytics.providers : [];
+    const overall = analytics ? analytics.overall : null;
+
+    return (
+        <Layout>
+            <div className="delivery-page">
+                <div className="page-header">
+                    <h1>Delivery Analytics</h1>
+                    <p className="subtitle">Delivery rate, latency, failures and cost of sent notifications</p>
+                </div>
+
+                <form className="delivery-filters" onSubmit={handleApply}>
+                    <select
+                        value={filters.range}
+                        onChange={(e) => setFilters({ ...filters, range: e.target.value })}
+                    >
+                        <option value="24h">Last 24 hours</option>
+                        <option value="7d">Last 7 days</option>
+                        <option value="30d">Last 30 days</option>
+                    </select>
+                    <input
+                        type="text"
+                        placeholder="User ID"
+                        value={filters.user_id}
+                        onChange={(e) => setFilters({ ...filters, user_id: e.target.value })}
+                    />
+                    <input
+                        type="text"
+                        placeholder="Group"
+                        value={filters.group}
+                        onChange={(e) => setFilters({ ...filters, group: e.target.value })}
+                    />
+                    <input
+                        type="text"
+                        placeholder="Provider"
+                        value={filters.provider}
+                        onChange={(e) => setFilters({ ...filters, provider: e.target.value })}
+                    />
+                    <select
+                        value={filters.status}
+                        onChange={(e) => setFilters({ ...filters, status: e.target.value })}
+                    >
+                        <option value="">Any status</option>
+                        <option value="Pending">Pending</option>
+                        <option value="Sent">Sent</option>
+                        <option value="Delivered">Delivered</option>
+                        <option value="Failed">Failed</option>
+                    </select>
+                    <button type="submit" className="btn-primary">Apply</button>
+                </form>
+
+                {loading && <div className="loading">Loading delivery analytics...</div>}
+                {error && <div className="error-message">Error loading delivery analytics: {error}</div>}
+
+                {!loading && !error && overall && (
+                    <>
+                        <div className="delivery-summary">
+                            <div className="summary-card">
+                                <span className="summary-label">Messages</span>
+                                <span className="summary-value">{overall.total}</span>
+                            </div>
+                            <div className="summary-card">
+                                <span className="summary-label">Delivery rate</span>
+                                <span className="summary-value">{percent(overall.delivery_rate)}</span>
+                            </div>
+                            <div className="summary-card">
+                                <span className="summary-label">Median time to deliver</span>
+                                <span className="summary-value">{duration(overall.median_time_to_deliver_seconds)}</span>
+                            </div>
+                            <div className="summary-card">
+                                <span className="summary-label">Estimated cost</span>
+                                <span className="summary-value">{overall.estimated_cost.toLocaleString()}</span>
+                            </div>
+                        </div>
+
+                        <div className="delivery-charts">
+                            <section className="delivery-section">
+                                <h2>Delivery rate per provider</h2>
+                                <BarChart
+                                    items={providers.map((p) => ({ label: p.provider, value: p.delivery_rate }))}
+                                    format={percent}
+                                />
+                            </section>
+                            <section className="delivery-section">
+                                <h2>Median time to deliver</h2>
+                                <BarChart
+                                    items={providers
+                                        .filter((p) => p.delivered > 0)
+                                        .map((p) => ({ label: p.provider, value: p.median_time_to_deliver_seconds }))}
+                                    format={duration}
+                                />
+                            </section>
+                            <section className="delivery-section">
+                                <h2>Failures by error</h2>
+                                <BarChart
+                                    items={analytics.failures.map((f) => ({
+                                        label: `${f.provider}: ${f.error || 'unknown'}`,
+                                        value: f.count,
+                                        className: 'failed',
+                                    }))}
+                                />
+                            </section>
+                            <section className="delivery-section">
+                                <h2>Estimated cost per provider</h2>
+                                <BarChart
+                                    items={providers
+                                        .filter((p) => p.estimated_cost > 0)
+                                        .map((p) => ({ label: p.provider, value: p.estimated_cost }))}
+                                    format={(v) => v.toLocaleString()}
+                                />
+                            </section>
+                        </div>
+
+                        <section className="delivery-section">
+                            <h2>Recent messages ({messages.length} of {total})</h2>
+                            {messages.length === 0 ? (
+                                <p className="no-data">No messages for the selected filters</p>
+                            ) : (
+                                <table className="delivery-table">
+                                    <thead>
+                                        <tr>
+                                            <th>Created</th>
+                                            <th>Provider</th>
+                                            <th>Receptor</th>
+                                            <th>Group</th>
+                                            <th>Subject</th>
+                                            <th>Status</th>
+                                        </tr>
+                                    </thead>
+                                    <tbody>
+                                        {messages.map((m) => (
+                                            <tr key={m.id}>
+                                                <td>{new Date(m.created_at).toLocaleString()}</td>
+                                                <td>{m.provider}</td>
+                                                <td>{m.receptor}</td>
+                                                <td>{m.group || '-'}</td>
+                                                <td>{m.subject || '-'}</td>
+                                                <td title={m.response}>
+                                                    <span className={`message-status ${m.status.toLowerCase()}`}>
+                                                        {m.status}
+                                                    </span>
+                                                </td>
+                                            </tr>
+                                        ))}
+                                    </tbody>
+                                </table>
+                            )}
+                        </section>
+                    </>
+                )}
+            </div>
+        </Layout>
+    );
+};
+
+export default DeliveryAnalytics;
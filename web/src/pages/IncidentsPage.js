@@ -0,0 +1,335 @@
+import React, { useState, useEffect } from 'react';
+import apiService from '../utils/apiService';
+import Layout from '../components/Layout';
+import './IncidentsPage.css';
+
+const emptyIncident = {
+    title: '',
+    severity: 'critical',
+    assignee: '',
+    alert_ids: '',
+};
+
+const splitList = (value) => value
+    .split(',')
+    .map((v) => v.trim())
+    .filter((v) => v !== '');
+
+const eventText = (ev) => {
+    switch (ev.type) {
+    case 'created':
+        return ev.body;
+    case 'status':
+        return `Status changed to ${ev.body}`;
+    case 'assigned':
+        return ev.body ? `Assigned to ${ev.body}` : 'Unassigned';
+    case 'alert_linked':
+        return `Alert ${ev.body} linked`;
+    case 'alert_resolved':
+        return `Alert ${ev.body} resolved`;
+    default:
+        return ev.body;
+    }
+};
+
+const IncidentsPage = () => {
+    const [incidents, setIncidents] = useState([]);
+    const [statusFilter, setStatusFilter] = useState('');
+    const [selected, setSelected] = useState(null);
+    const [loading, setLoading] = useState(true);
+    const [error, setError] = useState(null);
+    const [showAddModal, setShowAddModal] = useState(false);
+    const [newIncident, setNewIncident] = useState(emptyIncident);
+    const [note, setNote] = useState('');
+    const [assignee, setAssignee] = useState('');
+    const [linkAlerts, setLinkAlerts] = useState('');
+
+    useEffect(() => {
+        fetchIncidents(statusFilter);
+    }, [statusFilter]);
+
+    const fetchIncidents = async (status) => {
+        setLoading(true);
+        setError(null);
+        try {
+            const data = await apiService.getIncidents({ status });
+            setIncidents(data.incidents || []);
+        } catch (e) {
+            setError(e.message);
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    const openIncident = async (incidentId) => {
+        try {
+            const data = await apiService.getIncident(incidentId);
+            setSelected(data.incident);
+            setAssignee(data.incident.assignee || '');
+        } catch (e) {
+            alert('Error loading incident: ' + e.message);
+        }
+    };
+
+    const refresh = async () => {
+        await fetchIncidents(statusFilter);
+        if (selected) await openIncident(selected.id);
+    };
+
+    const runAction = async (action, errorMessage) => {
+        try {
+            await action();
+            await refresh();
+        } catch (e) {
+            alert(`${errorMessage}: ${e.message}`);
+        }
+    };
+
+    const handleAddIncident = async (e) => {
+        e.preventDefault();
+        try {
+            await apiService.createIncident({
+                ...newIncident,
+                alert_ids: splitList(newIncident.alert_ids),
+            });
+            setShowAddModal(false);
+            setNewIncident(emptyIncident);
+            fetchIncidents(statusFilter);
+        } catch (e) {
+            alert('Error creating incident: ' + e.message);
+        }
+    };
+
+    const handleAddNote = (e) => {
+        e.preventDefault();
+        runAction(async () => {
+            await apiService.addIncidentNote(selected.id, note);
+            setNote('');
+        }, 'Error adding note');
+    };
+
+    const handleAssign = (e) => {
+        e.preventDefault();
+        runAction(() => apiService.assignIncident(selected.id, assignee), 'Error assigning incident');
+    };
+
+    const handleLinkAlerts = (e) => {
+        e.preventDefault();
+        runAction(async () => {
+            await apiService.linkIncidentAlerts(selected.id, splitList(linkAlerts));
+            setLinkAlerts('');
+        }, 'Error linking alerts');
+    };
+
+    return (
+        <Layout>
+            <div className="incidents-page">
+                <div className="page-header">
+                    <h1>Incidents</h1>
+                    <div className="header-actions">
+                        <select value={statusFilter} onChange={(e) => setStatusFilter(e.target.value)}>
+                            <option value="">All</option>
+                            <option value="triggered">Triggered</option>
+                            <option value="acknowledged">Acknowledged</option>
+                            <option value="resolved">Resolved</option>
+                        </select>
+                        <button className="btn-primary" onClick={() => setShowAddModal(true)}>
+                            + New Incident
+                        </button>
+                    </div>
+                </div>
+
+                {loading && <div className="loading">Loading incidents...</div>}
+                {error && <div className="error-message">Error loading incidents: {error}</div>}
+
+                {!loading && !error && (
+                    <div className="incidents-layout">
+                        <section className="incidents-section incidents-list">
+                            {incidents.length === 0 ? (
+                                <p className="no-data">No incidents</p>
+                            ) : (
+                                <table className="incidents-table">
+                                    <thead>
+                                        <tr>
+                                            <th>Title</th>
+                                            <th>Severity</th>
+                                            <th>Status</th>
+                                            <th>Created</th>
+                                        </tr>
+                                    </thead>
+                                    <tbody>
+                                        {incidents.map((inc) => (
+                                            <tr
+                                                key={inc.id}
+                                                className={selected && selected.id === inc.id ? 'selected' : ''}
+                                                onClick={() => openIncident(inc.id)}
+                                            >
+                                                <td>{inc.title}</td>
+                                                <td>{inc.severity}</td>
+                                                <td>
+                                                    <span className={`incident-status ${inc.status}`}>{inc.status}</span>
+                                                </td>
+                                                <td>{new Date(inc.created_at).toLocaleString()}</td>
+                                            </tr>
+                                        ))}
+                                    </tbody>
+                                </table>
+                            )}
+                        </section>
+
+                        {selected && (
+                            <section className="incidents-section incident-detail">
+                                <div className="incident-detail-header">
+                                    <h2>{selected.title}</h2>
+                                    <span className={`incident-status ${selected.status}`}>{selected.status}</span>
+                                </div>
+                                <p className="incident-meta">
+                                    Severity {selected.severity} · opened {new Date(selected.created_at).toLocaleString()}
+                                    {selected.assignee && ` · assigned to ${selected.assignee}`}
+                                </p>
+
+                                {selected.status !== 'resolved' && (
+                                    <div className="incident-actions">
+                                        {selected.status === 'triggered' && (
+                                            <button
+                                                className="btn-primary"
+                                                onClick={() => runAction(() => apiService.acknowledgeIncident(selected.id), 'Error acknowledging incident')}
+                                            >
+                                                Acknowledge
+                                            </button>
+                                        )}
+                                        <button
+                                            className="btn-secondary"
+                                            onClick={() => runAction(() => apiService.resolveIncident(selected.id), 'Error resolving incident')}
+                                        >
+                                            Resolve
+                                        </button>
+                                        <form className="inline-form" onSubmit={handleAssign}>
+                                            <input
+                                                type="text"
+                                                placeholder="Assignee user ID"
+                                                value={assignee}
+                                                onChange={(e) => setAssignee(e.target.value)}
+                                            />
+                                            <button type="submit" className="btn-secondary">Assign</button>
+                                        </form>
+                                    </div>
+                                )}
+
+                                <h3>Alerts ({selected.alerts.length})</h3>
+                                <table className="incidents-table">
+                                    <thead>
+                                        <tr>
+                                            <th>Name</th>
+                                            <th>Severity</th>
+                                            <th>Status</th>
+                                            <th>Started</th>
+                                        </tr>
+                                    </thead>
+                                    <tbody>
+                                        {selected.alerts.map((a) => (
+                                            <tr key={a.id}>
+                                                <td title={a.description}>{a.name}</td>
+                                                <td>{a.severity}</td>
+                                                <td>{a.status}</td>
+                                                <td>{new Date(a.starts_at).toLocaleString()}</td>
+                                            </tr>
+                                        ))}
+                                    </tbody>
+                                </table>
+                                {selected.status !== 'resolved' && (
+                                    <form className="inline-form" onSubmit={handleLinkAlerts}>
+                                        <input
+                                            type="text"
+                                            placeholder="Alert IDs (comma separated)"
+                                            value={linkAlerts}
+                                            onChange={(e) => setLinkAlerts(e.target.value)}
+                                            required
+                                        />
+                                        <button type="submit" className="btn-secondary">Link</button>
+                                    </form>
+                                )}
+
+                                <h3>Timeline</h3>
+                                <ul className="incident-timeline">
+                                    {selected.timeline.map((ev) => (
+                                        <li key={ev.id} className={`timeline-event ${ev.type}`}>
+                                            <span className="timeline-time">{new Date(ev.created_at).toLocaleString()}</span>
+                                            <span className="timeline-text">{eventText(ev)}</span>
+                                            {ev.author && <span className="timeline-author">{ev.author}</span>}
+                                        </li>
+                                    ))}
+                                </ul>
+                                <form className="note-form" onSubmit={handleAddNote}>
+                                    <textarea
+                                        placeholder="Add a note"
+                                        value={note}
+                                        onChange={(e) => setNote(e.target.value)}
+                                        required
+                                    />
+                                    <button type="submit" className="btn-primary">Add Note</button>
+                                </form>
+                            </section>
+                        )}
+                    </div>
+                )}
+
+                {/* Add Incident Modal */}
+                {showAddModal && (
+                    <div className="modal-overlay" onClick={() => setShowAddModal(false)}>
+                        <div className="modal" onClick={(e) => e.stopPropagation()}>
+                            <h2>New Incident</h2>
+                            <form onSubmit={handleAddIncident}>
+                                <div className="form-group">
+                                    <label>Title:</label>
+                                    <input
+                                        type="text"
+                                        value={newIncident.title}
+                                        onChange={(e) => setNewIncident({ ...newIncident, title: e.target.value })}
+                                        required
+                                        minLength="3"
+                                    />
+                                </div>
+                                <div className="form-group">
+                                    <label>Severity:</label>
+                                    <select
+                                        value={newIncident.severity}
+                                        onChange={(e) => setNewIncident({ ...newIncident, severity: e.target.value })}
+                                    >
+                                        <option value="critical">Critical</option>
+                                        <option value="warning">Warning</option>
+                                        <option value="info">Info</option>
+                                    </select>
+                                </div>
+                                <div className="form-group">
+                                    <label>Assignee user ID:</label>
+                                    <input
+                                        type="text"
+                                        value={newIncident.assignee}
+                                        onChange={(e) => setNewIncident({ ...newIncident, assignee: e.target.value })}
+                                    />
+                                </div>
+                                <div className="form-group">
+                                    <label>Alert IDs (comma separated):</label>
+                                    <input
+                                        type="text"
+                                        value={newIncident.alert_ids}
+                                        onChange={(e) => setNewIncident({ ...newIncident, alert_ids: e.target.value })}
+                                    />
+                                </div>
+                                <div className="modal-actions">
+                                    <button type="submit" className="btn-primary">Create Incident</button>
+                                    <button type="button" className="btn-secondary" onClick={() => setShowAddModal(false)}>
+                                        Cancel
+                                    </button>
+                                </div>
+                            </form>
+                        </div>
+                    </div>
+                )}
+            </div>
+        </Layout>
+    );
+};
+
+export default IncidentsPage;
@@ -0,0 +1,274 @@
+import React, { useState, useEffect } from 'react';
+import apiService from '../utils/apiService';
+import Layout from '../components/Layout';
+import { isAdmin } from '../utils/auth';
+import './MaintenancePage.css';
+
+const emptyWindow = {
+    name: '',
+    description: '',
+    schedule_type: 'cron',
+    schedule: '',
+    duration: '2h',
+    timezone: 'UTC',
+    matchers: '',
+    groups: '',
+};
+
+const splitList = (value) => value
+    .split(',')
+    .map((v) => v.trim())
+    .filter((v) => v !== '');
+
+const MaintenancePage = () => {
+    const [windows, setWindows] = useState([]);
+    const [upcoming, setUpcoming] = useState([]);
+    const [loading, setLoading] = useState(true);
+    const [error, setError] = useState(null);
+    const [showAddModal, setShowAddModal] = useState(false);
+    const [newWindow, setNewWindow] = useState(emptyWindow);
+    const userIsAdmin = isAdmin();
+
+    useEffect(() => {
+        fetchWindows();
+    }, []);
+
+    const fetchWindows = async () => {
+        setLoading(true);
+        setError(null);
+        try {
+            const [windowData, upcomingData] = await Promise.all([
+                apiService.getMaintenanceWindows(),
+                apiService.getUpcomingMaintenance(),
+            ]);
+            setWindows(windowData.windows || []);
+            setUpcoming(upcomingData.upcoming || []);
+        } catch (e) {
+            setError(e.message);
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    const handleAddWindow = async (e) => {
+        e.preventDefault();
+        try {
+            await apiService.createMaintenanceWindow({
+                ...newWindow,
+                matchers: splitList(newWindow.matchers),
+                groups: splitList(newWindow.groups),
+            });
+            setShowAddModal(false);
+            setNewWindow(emptyWindow);
+            fetchWindows();
+        } catch (e) {
+            alert('Error creating maintenance window: ' + e.message);
+        }
+    };
+
+    const handleDeleteWindow = async (w) => {
+        if (!window.confirm(`Are you sure you want to delete maintenance window: ${w.name}?`)) return;
+        try {
+            await apiService.deleteMaintenanceWindow(w.id);
+            fetchWindows();
+        } catch (e) {
+            alert('Error deleting maintenance window: ' + e.message);
+        }
+    };
+
+    if (loading) {
+        return (
+            <Layout>
+                <div className="loading">Loading maintenance windows...</div>
+            </Layout>
+        );
+    }
+
+    if (error) {
+        return (
+            <Layout>
+                <div className="error-message">Error loading maintenance windows: {error}</div>
+            </Layout>
+        );
+    }
+
+    return (
+        <Layout>
+            <div className="maintenance-page">
+                <div className="page-header">
+                    <h1>Maintenance Windows</h1>
+                    {userIsAdmin && (
+                        <button className="btn-primary" onClick={() => setShowAddModal(true)}>
+                            + Add Window
+                        </button>
+                    )}
+                </div>
+
+                <section className="maintenance-section">
+                    <h2>Upcoming</h2>
+                    {upcoming.length === 0 ? (
+                        <p className="no-data">No maintenance scheduled in the next 7 days</p>
+                    ) : (
+                        <table className="maintenance-table">
+                            <thead>
+                                <tr>
+                                    <th>Window</th>
+                                    <th>Starts</th>
+                                    <th>Ends</th>
+                                    <th>Groups</th>
+                                </tr>
+                            </thead>
+                            <tbody>
+                                {upcoming.map((o) => (
+                                    <tr key={`${o.window_id}-${o.starts_at}`}>
+                                        <td>{o.name}</td>
+                                        <td>{new Date(o.starts_at).toLocaleString()}</td>
+                                        <td>{new Date(o.ends_at).toLocaleString()}</td>
+                                        <td>{(o.groups || []).join(', ') || 'All'}</td>
+                                    </tr>
+                                ))}
+                            </tbody>
+                        </table>
+                    )}
+                </section>
+
+                <section className="maintenance-section">
+                    <h2>Windows</h2>
+                    <table className="maintenance-table">
+                        <thead>
+                            <tr>
+                                <th>Name</th>
+                                <th>Schedule</th>
+                                <th>Duration</th>
+                                <th>Timezone</th>
+                                <th>Matchers</th>
+                                <th>Groups</th>
+                                <th>State</th>
+                                {userIsAdmin && <th></th>}
+                            </tr>
+                        </thead>
+                        <tbody>
+                            {windows.map((w) => (
+                                <tr key={w.id}>
+                                    <td title={w.description}>{w.name}</td>
+                                    <td><code>{w.schedule_type}: {w.schedule}</code></td>
+                                    <td>{w.duration}</td>
+                                    <td>{w.timezone}</td>
+                                    <td>{(w.matchers || []).join(', ') || '-'}</td>
+                                    <td>{(w.groups || []).join(', ') || 'All'}</td>
+                                    <td>
+                                        <span className={`status-badge ${w.active ? 'active' : 'inactive'}`}>
+                                            {w.active ? 'Active' : 'Idle'}
+                                        </span>
+                                    </td>
+                                    {userIsAdmin && (
+                                        <td>
+                                            <button className="btn-delete" onClick={() => handleDeleteWindow(w)}>
+                                                Delete
+                                            </button>
+                                        </td>
+                                    )}
+                                </tr>
+                            ))}
+                        </tbody>
+                    </table>
+                </section>
+
+                {/* Add Window Modal */}
+                {showAddModal && (
+                    <div className="modal-overlay" onClick={() => setShowAddModal(false)}>
+                        <div className="modal" onClick={(e) => e.stopPropagation()}>
+                            <h2>Add Maintenance Window</h2>
+                            <form onSubmit={handleAddWindow}>
+                                <div className="form-group">
+                                    <label>Name:</label>
+                                    <input
+                                        type="text"
+                                        value={newWindow.name}
+                                        onChange={(e) => setNewWindow({ ...newWindow, name: e.target.value })}
+                                        required
+                                        minLength="3"
+                                    />
+                                </div>
+                                <div className="form-group">
+                                    <label>Description:</label>
+                                    <input
+                                        type="text"
+                                        value={newWindow.description}
+                                        onChange={(e) => setNewWindow({ ...newWindow, description: e.target.value })}
+                                    />
+                                </div>
+                                <div className="form-group">
+                                    <label>Schedule type:</label>
+                                    <select
+                                        value={newWindow.schedule_type}
+                                        onChange={(e) => setNewWindow({ ...newWindow, schedule_type: e.target.value })}
+                                    >
+                                        <option value="cron">Cron</option>
+                                        <option value="rrule">RRULE</option>
+                                    </select>
+                                </div>
+                                <div className="form-group">
+                                    <label>Schedule:</label>
+                                    <input
+                                        type="text"
+                                        placeholder={newWindow.schedule_type === 'cron'
+                                            ? '0 2 * * TUE'
+                                            : 'FREQ=WEEKLY;BYDAY=TU;BYHOUR=2;BYMINUTE=0;BYSECOND=0'}
+                                        value={newWindow.schedule}
+                                        onChange={(e) => setNewWindow({ ...newWindow, schedule: e.target.value })}
+                                        required
+                                    />
+                                </div>
+                                <div className="form-group">
+                                    <label>Duration:</label>
+                                    <input
+                                        type="text"
+                                        placeholder="2h"
+                                        value={newWindow.duration}
+                                        onChange={(e) => setNewWindow({ ...newWindow, duration: e.target.value })}
+                                        required
+                                    />
+                                </div>
+                                <div className="form-group">
+                                    <label>Timezone:</label>
+                                    <input
+                                        type="text"
+                                        placeholder="Asia/Tehran"
+                                        value={newWindow.timezone}
+                                        onChange={(e) => setNewWindow({ ...newWindow, timezone: e.target.value })}
+                                    />
+                                </div>
+                                <div className="form-group">
+                                    <label>Label matchers (comma separated):</label>
+                                    <input
+                                        type="text"
+                                        placeholder='severity="warning", alertname=~"Postgres.*"'
+                                        value={newWindow.matchers}
+                                        onChange={(e) => setNewWindow({ ...newWindow, matchers: e.target.value })}
+                                    />
+                                </div>
+                                <div className="form-group">
+                                    <label>Affected groups (comma separated, empty for all):</label>
+                                    <input
+                                        type="text"
+                                        value={newWindow.groups}
+                                        onChange={(e) => setNewWindow({ ...newWindow, groups: e.target.value })}
+                                    />
+                                </div>
+                                <div className="modal-actions">
+                                    <button type="submit" className="btn-primary">Add Window</button>
+                                    <button type="button" className="btn-secondary" onClick={() => setShowAddModal(false)}>
+                                        Cancel
+                                    </button>
+                                </div>
+                            </form>
+                        </div>
+                    </div>
+                )}
+            </div>
+        </Layout>
+    );
+};
+
+export default MaintenancePage;
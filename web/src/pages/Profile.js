@@ -1,10 +1,13 @@
 import React, { useState, useEffect } from 'react';
 import apiService from '../utils/apiService';
 import Layout from '../components/Layout';
+import NotificationPreferences from '../components/NotificationPreferences';
+import ContactMethods from '../components/ContactMethods';
 import './Profile.css';
 
 const Profile = () => {
     const [user, setUser] = useState(null);
+    const [userId, setUserId] = useState(null);
     const [loading, setLoading] = useState(true);
     const [error, setError] = useState(null);
     const [showEditModal, setShowEditModal] = useState(false);
@@ -29,6 +32,7 @@ const Profile = () => {
         try {
             const data = await apiService.getUserMe();
             setUser(data.user || data);
+            setUserId(data.user_id);
         } catch (e) {
             setError(e.message);
         } finally {
@@ -195,6 +199,16 @@ const Profile = () => {
                                     ✏️ Edit Profile
                                 </button>
                             </div>
+
+                            <div className="profile-section">
+                                <h3>Contact Methods</h3>
+                                <ContactMethods userId={userId} />
+                            </div>
+
+                            <div className="profile-section">
+                                <h3>Notification Preferences</h3>
+                                <NotificationPreferences userId={userId} />
+                            </div>
                         </div>
                     </div>
                 </div>
@@ -111,6 +111,14 @@ const ProvidersPage = () => {
                                             </span>
                                         </span>
                                     </div>
+                                    <div className="info-item">
+                                        <span className="label">Circuit:</span>
+                                        <span className="value">
+                                            <span className={`circuit-badge circuit-${provider.circuit || 'closed'}`}>
+                                                {provider.circuit || 'closed'}
+                                            </span>
+                                        </span>
+                                    </div>
                                     <div className="info-item">
                                         <span className="label">ID:</span>
                                         <span className="value small">{provider.id}</span>
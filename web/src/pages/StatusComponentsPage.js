@@ -0,0 +1,194 @@
+import React, { useState, useEffect } from 'react';
+import apiService from '../utils/apiService';
+import config from '../config';
+import Layout from '../components/Layout';
+import './StatusComponentsPage.css';
+
+const emptyComponent = {
+    name: '',
+    description: '',
+    matchers: '',
+    position: 0,
+};
+
+const splitList = (value) => value
+    .split('\n')
+    .map((v) => v.trim())
+    .filter((v) => v !== '');
+
+const StatusComponentsPage = () => {
+    const [components, setComponents] = useState([]);
+    const [loading, setLoading] = useState(true);
+    const [error, setError] = useState(null);
+    const [editing, setEditing] = useState(null);
+    const [form, setForm] = useState(emptyComponent);
+
+    useEffect(() => {
+        fetchComponents();
+    }, []);
+
+    const fetchComponents = async () => {
+        setLoading(true);
+        setError(null);
+        try {
+            const data = await apiService.getStatusComponents();
+            setComponents(data.components || []);
+        } catch (e) {
+            setError(e.message);
+        } finally {
+            setLoading(false);
+        }
+    };
+
+    const openModal = (component) => {
+        if (component) {
+            setForm({ ...component, matchers: (component.matchers || []).join('\n') });
+        } else {
+            setForm(emptyComponent);
+        }
+        setEditing(component || {});
+    };
+
+    const handleSubmit = async (e) => {
+        e.preventDefault();
+        const payload = {
+            name: form.name,
+            description: form.description,
+            matchers: splitList(form.matchers),
+            position: Number(form.position) || 0,
+        };
+        try {
+            if (editing.id) {
+                await apiService.updateStatusComponent(editing.id, payload);
+            } else {
+                await apiService.createStatusComponent(payload);
+            }
+            setEditing(null);
+            fetchComponents();
+        } catch (e) {
+            alert('Error saving component: ' + e.message);
+        }
+    };
+
+    const handleDelete = async (component) => {
+        if (!window.confirm(`Delete component "${component.name}"?`)) return;
+        try {
+            await apiService.deleteStatusComponent(component.id);
+            fetchComponents();
+        } catch (e) {
+            alert('Error deleting component: ' + e.message);
+        }
+    };
+
+    return (
+        <Layout>
+            <div className="status-components-page">
+                <div className="page-header">
+                    <h1>Status Page</h1>
+                    <div className="header-actions">
+                        <a className="btn-secondary" href={config.api.statusPage} target="_blank" rel="noreferrer">
+                            View public page
+                        </a>
+                        <button className="btn-primary" onClick={() => openModal(null)}>
+                            + New Component
+                        </button>
+                    </div>
+                </div>
+
+                {loading && <div className="loading">Loading components...</div>}
+                {error && <div className="error-message">Error loading components: {error}</div>}
+
+                {!loading && !error && (
+                    <section className="status-components-section">
+                        {components.length === 0 ? (
+                            <p className="no-data">No components, the public page shows no state</p>
+                        ) : (
+                            <table className="status-components-table">
+                                <thead>
+                                    <tr>
+                                        <th>Position</th>
+                                        <th>Name</th>
+                                        <th>Description</th>
+                                        <th>Matchers</th>
+                                        <th>Actions</th>
+                                    </tr>
+                                </thead>
+                                <tbody>
+                                    {components.map((c) => (
+                                        <tr key={c.id}>
+                                            <td>{c.position}</td>
+                                            <td>{c.name}</td>
+                                            <td>{c.description}</td>
+                                            <td>
+                                                {(c.matchers || []).map((m) => (
+                                                    <code key={m}>{m}</code>
+                                                ))}
+                                            </td>
+                                            <td className="actions">
+                                                <button className="btn-edit" onClick={() => openModal(c)}>Edit</button>
+                                                <button className="btn-delete" onClick={() => handleDelete(c)}>Delete</button>
+                                            </td>
+                                        </tr>
+                                    ))}
+                                </tbody>
+                            </table>
+                        )}
+                    </section>
+                )}
+
+                {editing && (
+                    <div className="modal-overlay" onClick={() => setEditing(null)}>
+                        <div className="modal" onClick={(e) => e.stopPropagation()}>
+                            <h2>{editing.id ? 'Edit Component' : 'New Component'}</h2>
+                            <form onSubmit={handleSubmit}>
+                                <div className="form-group">
+                                    <label>Name:</label>
+                                    <input
+                                        type="text"
+                                        value={form.name}
+                                        onChange={(e) => setForm({ ...form, name: e.target.value })}
+                                        required
+                                        minLength="2"
+                                    />
+                                </div>
+                                <div className="form-group">
+                                    <label>Description:</label>
+                                    <input
+                                        type="text"
+                                        value={form.description}
+                                        onChange={(e) => setForm({ ...form, description: e.target.value })}
+                                    />
+                                </div>
+                                <div className="form-group">
+                                    <label>Matchers (one per line):</label>
+                                    <textarea
+                                        value={form.matchers}
+                                        placeholder='service="api"'
+                                        onChange={(e) => setForm({ ...form, matchers: e.target.value })}
+                                        required
+                                    />
+                                </div>
+                                <div className="form-group">
+                                    <label>Position:</label>
+                                    <input
+                                        type="number"
+                                        value={form.position}
+                                        onChange={(e) => setForm({ ...form, position: e.target.value })}
+                                    />
+                                </div>
+                                <div className="modal-actions">
+                                    <button type="submit" className="btn-primary">Save</button>
+                                    <button type="button" className="btn-secondary" onClick={() => setEditing(null)}>
+                                        Cancel
+                                    </button>
+                                </div>
+                            </form>
+                        </div>
+                    </div>
+                )}
+            </div>
+        </Layout>
+    );
+};
+
+export default StatusComponentsPage;
@@ -0,0 +1,80 @@
+import config from '../config';
+import { getAuthToken } from './auth';
+
+const RECONNECT_DELAY = 5000;
+
+const parseEvent = (block) => {
+    let type = 'message';
+    const data = [];
+    block.split('\n').forEach((line) => {
+        if (line.startsWith('event:')) type = line.slice(6).trim();
+        if (line.startsWith('data:')) data.push(line.slice(5).trim());
+    });
+    if (data.length === 0) return null;
+    try {
+        return { ...JSON.parse(data.join('\n')), type };
+    } catch (e) {
+        return null;
+    }
+};
+
+/**
+ * Subscribe to the live alert stream. The stream is read with fetch so the
+ * JWT goes in the Authorization header, and it reconnects when it ends.
+ * Returns a function that closes the stream.
+ */
+export const subscribeAlertStream = (onEvent) => {
+    let controller = null;
+    let timer = null;
+    let closed = false;
+
+    const connect = async () => {
+        controller = new AbortController();
+        try {
+            const response = await fetch(config.api.alertStream, {
+                headers: { Authorization: `Bearer ${getAuthToken()}` },
+                signal: controller.signal,
+            });
+            // an expired token does not get better by retrying
+            if (response.status === 401) return;
+            if (!response.ok || !response.body) throw new Error(`HTTP ${response.status}`);
+
+            const reader = response.body.getReader();
+            const decoder = new TextDecoder();
+            let buffer = '';
+            for (;;) {
+                const { value, done } = await reader.read();
+                if (done) break;
+                buffer += decoder.decode(value, { stream: true });
+                const blocks = buffer.split('\n\n');
+                buffer = blocks.pop();
+                blocks.map(parseEvent).filter(Boolean).forEach(onEvent);
+            }
+        } catch (e) {
+            if (closed) return;
+            console.log('Alert stream error:', e);
+        }
+        if (!closed) timer = setTimeout(connect, RECONNECT_DELAY);
+    };
+
+    connect();
+    return () => {
+        closed = true;
+        clearTimeout(timer);
+        if (controller) controller.abort();
+    };
+};
+
+/**
+ * Map the firing count of a stream event or /v0/alerts/firingCount to
+ * counts by severity.
+ */
+export const severityCounts = (items) => {
+    const counts = {};
+    (Array.isArray(items) ? items : []).forEach((item) => {
+        if (item && item.severity && typeof item.count === 'number') {
+            counts[item.severity] = item.count;
+        }
+    });
+    return counts;
+};
@@ -95,6 +95,66 @@ class APIService {
         return this.fetch(config.api.userGroups(userId));
     }
 
+    async getContactPreferences(userId) {
+        return this.fetch(config.api.userContactRules(userId));
+    }
+
+    async setContactRules(userId, rules) {
+        return this.fetch(config.api.userContactRules(userId), {
+            method: 'PUT',
+            body: JSON.stringify({ rules }),
+        });
+    }
+
+    async setQuietHours(userId, quietHours) {
+        return this.fetch(config.api.userQuietHours(userId), {
+            method: 'PUT',
+            body: JSON.stringify(quietHours),
+        });
+    }
+
+    async deleteQuietHours(userId) {
+        return this.fetch(config.api.userQuietHours(userId), {
+            method: 'DELETE',
+        });
+    }
+
+    async getContactMethods(userId) {
+        return this.fetch(config.api.userContactMethods(userId));
+    }
+
+    async addContactMethod(userId, methodData) {
+        return this.fetch(config.api.userContactMethods(userId), {
+            method: 'POST',
+            body: JSON.stringify(methodData),
+        });
+    }
+
+    async deleteContactMethod(userId, methodId) {
+        return this.fetch(config.api.userContactMethod(userId, methodId), {
+            method: 'DELETE',
+        });
+    }
+
+    async resendContactMethodCode(userId, methodId) {
+        return this.fetch(`${config.api.userContactMethod(userId, methodId)}/resend`, {
+            method: 'POST',
+        });
+    }
+
+    async verifyContactMethod(userId, methodId, code) {
+        return this.fetch(`${config.api.userContactMethod(userId, methodId)}/verify`, {
+            method: 'POST',
+            body: JSON.stringify({ code }),
+        });
+    }
+
+    async createTelegramLink(userId) {
+        return this.fetch(config.api.userTelegramLink(userId), {
+            method: 'POST',
+        });
+    }
+
     // ============ Group Endpoints ============
     async getGroups() {
         return this.fetch(config.api.groups);
@@ -145,6 +205,14 @@ class APIService {
         return this.fetch(url);
     }
 
+    async searchAlerts(params = {}) {
+        const queryParams = new URLSearchParams();
+        ['filter', 'q', 'from', 'to', 'sort', 'order', 'limit', 'cursor'].forEach((key) => {
+            if (params[key]) queryParams.append(key, params[key]);
+        });
+        return this.fetch(`${config.api.alertSearch}?${queryParams.toString()}`);
+    }
+
     async getAlertSummary() {
         return this.fetch(config.api.alertSummary);
     }
@@ -174,7 +242,123 @@ class APIService {
         });
     }
 
+    // ============ Maintenance Endpoints ============
+    async getMaintenanceWindows() {
+        return this.fetch(config.api.maintenance);
+    }
+
+    async getUpcomingMaintenance() {
+        return this.fetch(config.api.maintenanceUpcoming);
+    }
+
+    async createMaintenanceWindow(windowData) {
+        return this.fetch(config.api.maintenance, {
+            method: 'POST',
+            body: JSON.stringify(windowData),
+        });
+    }
+
+    async deleteMaintenanceWindow(windowId) {
+        return this.fetch(config.api.maintenanceWindow(windowId), {
+            method: 'DELETE',
+        });
+    }
+
+    // ============ Incident Endpoints ============
+    async getIncidents(params = {}) {
+        const queryParams = new URLSearchParams();
+        if (params.status) queryParams.append('status', params.status);
+        if (params.limit) queryParams.append('limit', params.limit);
+        if (params.page) queryParams.append('page', params.page);
+        return this.fetch(`${config.api.incidents}?${queryParams.toString()}`);
+    }
+
+    async getIncident(incidentId) {
+        return this.fetch(config.api.incident(incidentId));
+    }
+
+    async createIncident(incidentData) {
+        return this.fetch(config.api.incidents, {
+            method: 'POST',
+            body: JSON.stringify(incidentData),
+        });
+    }
+
+    async acknowledgeIncident(incidentId) {
+        return this.fetch(`${config.api.incident(incidentId)}/acknowledge`, {
+            method: 'PUT',
+        });
+    }
+
+    async resolveIncident(incidentId) {
+        return this.fetch(`${config.api.incident(incidentId)}/resolve`, {
+            method: 'PUT',
+        });
+    }
+
+    async assignIncident(incidentId, assignee) {
+        return this.fetch(`${config.api.incident(incidentId)}/assignee`, {
+            method: 'PUT',
+            body: JSON.stringify({ assignee }),
+        });
+    }
+
+    async addIncidentNote(incidentId, body) {
+        return this.fetch(`${config.api.incident(incidentId)}/notes`, {
+            method: 'POST',
+            body: JSON.stringify({ body }),
+        });
+    }
+
+    async linkIncidentAlerts(incidentId, alertIds) {
+        return this.fetch(`${config.api.incident(incidentId)}/alerts`, {
+            method: 'POST',
+            body: JSON.stringify({ alert_ids: alertIds }),
+        });
+    }
+
+    // ============ Status Page Endpoints ============
+    async getStatusComponents() {
+        return this.fetch(config.api.statusComponents);
+    }
+
+    async createStatusComponent(componentData) {
+        return this.fetch(config.api.statusComponents, {
+            method: 'POST',
+            body: JSON.stringify(componentData),
+        });
+    }
+
+    async updateStatusComponent(componentId, componentData) {
+        return this.fetch(config.api.statusComponent(componentId), {
+            method: 'PUT',
+            body: JSON.stringify(componentData),
+        });
+    }
+
+    async deleteStatusComponent(componentId) {
+        return this.fetch(config.api.statusComponent(componentId), {
+            method: 'DELETE',
+        });
+    }
+
     // ============ Message Endpoints ============
+    messageQuery(params = {}) {
+        const queryParams = new URLSearchParams();
+        ['user_id', 'group', 'provider', 'status', 'from', 'to', 'limit', 'page'].forEach((key) => {
+            if (params[key]) queryParams.append(key, params[key]);
+        });
+        return queryParams.toString();
+    }
+
+    async getMessages(params = {}) {
+        return this.fetch(`${config.api.messages}?${this.messageQuery(params)}`);
+    }
+
+    async getMessageAnalytics(params = {}) {
+        return this.fetch(`${config.api.messageAnalytics}?${this.messageQuery(params)}`);
+    }
+
     async sendAlertManagerMessage(messageData) {
         return this.fetch(config.api.alertManagerMessage, {
             method: 'POST',